@@ -0,0 +1,57 @@
+-- MySQL dump 10.13  Distrib 5.7.44, for osx10.19 (x86_64)
+--
+-- Host: 127.0.0.1    Database: nemo
+-- ------------------------------------------------------
+-- Server version	5.7.44
+
+/*!40101 SET @OLD_CHARACTER_SET_CLIENT=@@CHARACTER_SET_CLIENT */;
+/*!40101 SET @OLD_CHARACTER_SET_RESULTS=@@CHARACTER_SET_RESULTS */;
+/*!40101 SET @OLD_COLLATION_CONNECTION=@@COLLATION_CONNECTION */;
+/*!40101 SET NAMES utf8 */;
+/*!40103 SET @OLD_TIME_ZONE=@@TIME_ZONE */;
+/*!40103 SET TIME_ZONE='+00:00' */;
+/*!40014 SET @OLD_UNIQUE_CHECKS=@@UNIQUE_CHECKS, UNIQUE_CHECKS=0 */;
+/*!40014 SET @OLD_FOREIGN_KEY_CHECKS=@@FOREIGN_KEY_CHECKS, FOREIGN_KEY_CHECKS=0 */;
+/*!40101 SET @OLD_SQL_MODE=@@SQL_MODE, SQL_MODE='NO_AUTO_VALUE_ON_ZERO' */;
+/*!40111 SET @OLD_SQL_NOTES=@@SQL_NOTES, SQL_NOTES=0 */;
+
+--
+-- Table structure for table `asset_change`
+--
+
+DROP TABLE IF EXISTS `asset_change`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `asset_change` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `workspace_id` int(11) NOT NULL,
+  `asset_type` varchar(20) NOT NULL,
+  `asset` varchar(200) NOT NULL,
+  `port` int(11) NOT NULL DEFAULT '0',
+  `change_type` varchar(20) NOT NULL,
+  `source` varchar(40) DEFAULT NULL,
+  `tag` varchar(40) DEFAULT NULL,
+  `content` text,
+  `old_content` text,
+  `task_id` varchar(36) DEFAULT NULL,
+  `create_datetime` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  KEY `index_asset_change_asset` (`workspace_id`,`asset_type`,`asset`),
+  KEY `index_asset_change_task_id` (`task_id`),
+  KEY `index_asset_change_create_datetime` (`create_datetime`),
+  CONSTRAINT `fk_asset_change_workspace_id` FOREIGN KEY (`workspace_id`) REFERENCES `workspace` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+
+/*!40103 SET TIME_ZONE=@OLD_TIME_ZONE */;
+
+/*!40101 SET SQL_MODE=@OLD_SQL_MODE */;
+/*!40014 SET FOREIGN_KEY_CHECKS=@OLD_FOREIGN_KEY_CHECKS */;
+/*!40014 SET UNIQUE_CHECKS=@OLD_UNIQUE_CHECKS */;
+/*!40101 SET CHARACTER_SET_CLIENT=@OLD_CHARACTER_SET_CLIENT */;
+/*!40101 SET CHARACTER_SET_RESULTS=@OLD_CHARACTER_SET_RESULTS */;
+/*!40101 SET COLLATION_CONNECTION=@OLD_COLLATION_CONNECTION */;
+/*!40111 SET SQL_NOTES=@OLD_SQL_NOTES */;
+
+-- Dump completed on 2024-06-01 10:21:35
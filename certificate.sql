@@ -0,0 +1,82 @@
+-- MySQL dump 10.13  Distrib 5.7.44, for osx10.19 (x86_64)
+--
+-- Host: 127.0.0.1    Database: nemo
+-- ------------------------------------------------------
+-- Server version	5.7.44
+
+/*!40101 SET @OLD_CHARACTER_SET_CLIENT=@@CHARACTER_SET_CLIENT */;
+/*!40101 SET @OLD_CHARACTER_SET_RESULTS=@@CHARACTER_SET_RESULTS */;
+/*!40101 SET @OLD_COLLATION_CONNECTION=@@COLLATION_CONNECTION */;
+/*!40101 SET NAMES utf8 */;
+/*!40103 SET @OLD_TIME_ZONE=@@TIME_ZONE */;
+/*!40103 SET TIME_ZONE='+00:00' */;
+/*!40014 SET @OLD_UNIQUE_CHECKS=@@UNIQUE_CHECKS, UNIQUE_CHECKS=0 */;
+/*!40014 SET @OLD_FOREIGN_KEY_CHECKS=@@FOREIGN_KEY_CHECKS, FOREIGN_KEY_CHECKS=0 */;
+/*!40101 SET @OLD_SQL_MODE=@@SQL_MODE, SQL_MODE='NO_AUTO_VALUE_ON_ZERO' */;
+/*!40111 SET @OLD_SQL_NOTES=@@SQL_NOTES, SQL_NOTES=0 */;
+
+--
+-- Table structure for table `certificate`
+--
+
+DROP TABLE IF EXISTS `certificate`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `certificate` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `workspace_id` int(11) NOT NULL,
+  `fingerprint` varchar(64) NOT NULL,
+  `serial` varchar(100) DEFAULT NULL,
+  `subject_cn` varchar(255) DEFAULT NULL,
+  `subject_dn` varchar(1000) DEFAULT NULL,
+  `subject_org` varchar(255) DEFAULT NULL,
+  `issuer_cn` varchar(255) DEFAULT NULL,
+  `issuer_dn` varchar(1000) DEFAULT NULL,
+  `issuer_org` varchar(255) DEFAULT NULL,
+  `san` mediumtext,
+  `not_before` datetime DEFAULT NULL,
+  `not_after` datetime DEFAULT NULL,
+  `create_datetime` datetime NOT NULL,
+  `update_datetime` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `index_certificate_fingerprint` (`workspace_id`,`fingerprint`),
+  KEY `index_certificate_not_after` (`not_after`),
+  CONSTRAINT `fk_certificate_workspace_id` FOREIGN KEY (`workspace_id`) REFERENCES `workspace` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Table structure for table `certificate_host`
+--
+
+DROP TABLE IF EXISTS `certificate_host`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `certificate_host` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `cert_id` int(10) unsigned NOT NULL,
+  `workspace_id` int(11) NOT NULL,
+  `host_type` varchar(20) NOT NULL,
+  `host` varchar(200) NOT NULL,
+  `port` int(11) NOT NULL,
+  `create_datetime` datetime NOT NULL,
+  `update_datetime` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `index_certificate_host_host` (`cert_id`,`host`,`port`),
+  KEY `index_certificate_host_workspace_host` (`workspace_id`,`host`),
+  CONSTRAINT `fk_certificate_host_cert_id` FOREIGN KEY (`cert_id`) REFERENCES `certificate` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+
+/*!40103 SET TIME_ZONE=@OLD_TIME_ZONE */;
+
+/*!40101 SET SQL_MODE=@OLD_SQL_MODE */;
+/*!40014 SET FOREIGN_KEY_CHECKS=@OLD_FOREIGN_KEY_CHECKS */;
+/*!40014 SET UNIQUE_CHECKS=@OLD_UNIQUE_CHECKS */;
+/*!40101 SET CHARACTER_SET_CLIENT=@OLD_CHARACTER_SET_CLIENT */;
+/*!40101 SET CHARACTER_SET_RESULTS=@OLD_CHARACTER_SET_RESULTS */;
+/*!40101 SET COLLATION_CONNECTION=@OLD_COLLATION_CONNECTION */;
+/*!40111 SET SQL_NOTES=@OLD_SQL_NOTES */;
+
+-- Dump completed on 2024-06-01 10:21:35
@@ -156,10 +156,12 @@ func main() {
 		comm.TLSCertFile = option.TLSCertFile
 		comm.TLSKeyFile = option.TLSKeyFile
 		go comm.StartRPCServer()
+		go comm.StartWorkerOfflineMonitor()
 		time.Sleep(time.Second * 1)
 	}
 	go comm.StartSaveRuntimeLog("server@nemo")
 	go comm.StartSyncElasticAssets()
+	go comm.StartResolverCheck()
 
 	loadCustomTaskWorkspace()
 	StartCronTask()
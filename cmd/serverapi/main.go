@@ -66,7 +66,7 @@ func filterLoginCheck(ctx *beegoContext.Context) {
 	if len(tokenString) == 0 {
 		ctx.Redirect(http.StatusFound, "/")
 	}
-	if jwtData := ctrl.ValidToken(ctrl.GetTokenValueFromHeader(tokenString)); jwtData == nil {
+	if jwtData := ctrl.ValidRequestToken(ctrl.GetTokenValueFromHeader(tokenString), ctx.Input.Header(ctrl.AccessTokenWorkspaceHeader), ctx.Input.IP()); jwtData == nil {
 		ctx.Redirect(http.StatusFound, "/")
 	}
 }
@@ -15,6 +15,114 @@
 /*!40101 SET @OLD_SQL_MODE=@@SQL_MODE, SQL_MODE='NO_AUTO_VALUE_ON_ZERO' */;
 /*!40111 SET @OLD_SQL_NOTES=@@SQL_NOTES, SQL_NOTES=0 */;
 
+--
+-- Table structure for table `asset_change`
+--
+
+DROP TABLE IF EXISTS `asset_change`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `asset_change` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `workspace_id` int(11) NOT NULL,
+  `asset_type` varchar(20) NOT NULL,
+  `asset` varchar(200) NOT NULL,
+  `port` int(11) NOT NULL DEFAULT '0',
+  `change_type` varchar(20) NOT NULL,
+  `source` varchar(40) DEFAULT NULL,
+  `tag` varchar(40) DEFAULT NULL,
+  `content` text,
+  `old_content` text,
+  `task_id` varchar(36) DEFAULT NULL,
+  `create_datetime` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  KEY `index_asset_change_asset` (`workspace_id`,`asset_type`,`asset`),
+  KEY `index_asset_change_task_id` (`task_id`),
+  KEY `index_asset_change_create_datetime` (`create_datetime`),
+  CONSTRAINT `fk_asset_change_workspace_id` FOREIGN KEY (`workspace_id`) REFERENCES `workspace` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Dumping data for table `asset_change`
+--
+
+LOCK TABLES `asset_change` WRITE;
+/*!40000 ALTER TABLE `asset_change` DISABLE KEYS */;
+/*!40000 ALTER TABLE `asset_change` ENABLE KEYS */;
+UNLOCK TABLES;
+
+--
+-- Table structure for table `certificate`
+--
+
+DROP TABLE IF EXISTS `certificate`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `certificate` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `workspace_id` int(11) NOT NULL,
+  `fingerprint` varchar(64) NOT NULL,
+  `serial` varchar(100) DEFAULT NULL,
+  `subject_cn` varchar(255) DEFAULT NULL,
+  `subject_dn` varchar(1000) DEFAULT NULL,
+  `subject_org` varchar(255) DEFAULT NULL,
+  `issuer_cn` varchar(255) DEFAULT NULL,
+  `issuer_dn` varchar(1000) DEFAULT NULL,
+  `issuer_org` varchar(255) DEFAULT NULL,
+  `san` mediumtext,
+  `not_before` datetime DEFAULT NULL,
+  `not_after` datetime DEFAULT NULL,
+  `create_datetime` datetime NOT NULL,
+  `update_datetime` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `index_certificate_fingerprint` (`workspace_id`,`fingerprint`),
+  KEY `index_certificate_not_after` (`not_after`),
+  CONSTRAINT `fk_certificate_workspace_id` FOREIGN KEY (`workspace_id`) REFERENCES `workspace` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Dumping data for table `certificate`
+--
+
+LOCK TABLES `certificate` WRITE;
+/*!40000 ALTER TABLE `certificate` DISABLE KEYS */;
+/*!40000 ALTER TABLE `certificate` ENABLE KEYS */;
+UNLOCK TABLES;
+
+--
+-- Table structure for table `certificate_host`
+--
+
+DROP TABLE IF EXISTS `certificate_host`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `certificate_host` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `cert_id` int(10) unsigned NOT NULL,
+  `workspace_id` int(11) NOT NULL,
+  `host_type` varchar(20) NOT NULL,
+  `host` varchar(200) NOT NULL,
+  `port` int(11) NOT NULL,
+  `create_datetime` datetime NOT NULL,
+  `update_datetime` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `index_certificate_host_host` (`cert_id`,`host`,`port`),
+  KEY `index_certificate_host_workspace_host` (`workspace_id`,`host`),
+  CONSTRAINT `fk_certificate_host_cert_id` FOREIGN KEY (`cert_id`) REFERENCES `certificate` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Dumping data for table `certificate_host`
+--
+
+LOCK TABLES `certificate_host` WRITE;
+/*!40000 ALTER TABLE `certificate_host` DISABLE KEYS */;
+/*!40000 ALTER TABLE `certificate_host` ENABLE KEYS */;
+UNLOCK TABLES;
+
 --
 -- Table structure for table `domain`
 --
@@ -28,6 +136,8 @@ CREATE TABLE `domain` (
   `org_id` int(10) unsigned DEFAULT NULL,
   `workspace_id` int(11) NOT NULL,
   `pin_index` int(11) NOT NULL DEFAULT '0',
+  `miss_count` int(11) NOT NULL DEFAULT '0',
+  `is_stale` tinyint(4) NOT NULL DEFAULT '0',
   `create_datetime` datetime NOT NULL,
   `update_datetime` datetime NOT NULL,
   PRIMARY KEY (`id`),
@@ -152,6 +262,8 @@ CREATE TABLE `ip` (
   `status` varchar(20) DEFAULT NULL,
   `workspace_id` int(11) NOT NULL,
   `pin_index` int(11) NOT NULL DEFAULT '0',
+  `miss_count` int(11) NOT NULL DEFAULT '0',
+  `is_stale` tinyint(4) NOT NULL DEFAULT '0',
   `create_datetime` datetime NOT NULL,
   `update_datetime` datetime NOT NULL,
   PRIMARY KEY (`id`),
@@ -295,6 +407,71 @@ LOCK TABLES `key_word` WRITE;
 /*!40000 ALTER TABLE `key_word` ENABLE KEYS */;
 UNLOCK TABLES;
 
+--
+-- Table structure for table `key_word_asset`
+--
+
+DROP TABLE IF EXISTS `key_word_asset`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `key_word_asset` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `key_word_id` int(10) unsigned NOT NULL,
+  `workspace_id` int(11) NOT NULL,
+  `asset_type` varchar(20) NOT NULL,
+  `asset` varchar(200) NOT NULL,
+  `engine` varchar(40) DEFAULT NULL,
+  `task_id` varchar(36) DEFAULT NULL,
+  `create_datetime` datetime NOT NULL,
+  `update_datetime` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `index_key_word_asset_asset` (`key_word_id`,`asset`),
+  CONSTRAINT `fk_key_word_asset_key_word_id` FOREIGN KEY (`key_word_id`) REFERENCES `key_word` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Dumping data for table `key_word_asset`
+--
+
+LOCK TABLES `key_word_asset` WRITE;
+/*!40000 ALTER TABLE `key_word_asset` DISABLE KEYS */;
+/*!40000 ALTER TABLE `key_word_asset` ENABLE KEYS */;
+UNLOCK TABLES;
+
+--
+-- Table structure for table `key_word_history`
+--
+
+DROP TABLE IF EXISTS `key_word_history`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `key_word_history` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `key_word_id` int(10) unsigned NOT NULL,
+  `workspace_id` int(11) NOT NULL,
+  `engine` varchar(40) DEFAULT NULL,
+  `task_id` varchar(36) DEFAULT NULL,
+  `total` int(11) NOT NULL DEFAULT '0',
+  `new_count` int(11) NOT NULL DEFAULT '0',
+  `new_asset` mediumtext,
+  `is_baseline` tinyint(4) NOT NULL DEFAULT '0',
+  `create_datetime` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  KEY `index_key_word_history_key_word_id` (`key_word_id`,`engine`),
+  CONSTRAINT `fk_key_word_history_key_word_id` FOREIGN KEY (`key_word_id`) REFERENCES `key_word` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Dumping data for table `key_word_history`
+--
+
+LOCK TABLES `key_word_history` WRITE;
+/*!40000 ALTER TABLE `key_word_history` DISABLE KEYS */;
+/*!40000 ALTER TABLE `key_word_history` ENABLE KEYS */;
+UNLOCK TABLES;
+
 --
 -- Table structure for table `organization`
 --
@@ -337,6 +514,8 @@ CREATE TABLE `port` (
   `ip_id` int(10) unsigned NOT NULL,
   `port` int(11) NOT NULL,
   `status` varchar(20) NOT NULL,
+  `miss_count` int(11) NOT NULL DEFAULT '0',
+  `is_stale` tinyint(4) NOT NULL DEFAULT '0',
   `create_datetime` datetime NOT NULL,
   `update_datetime` datetime NOT NULL,
   PRIMARY KEY (`id`),
@@ -521,6 +700,37 @@ LOCK TABLES `task_run` WRITE;
 /*!40000 ALTER TABLE `task_run` ENABLE KEYS */;
 UNLOCK TABLES;
 
+--
+-- Table structure for table `task_target`
+--
+
+DROP TABLE IF EXISTS `task_target`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `task_target` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `target_id` char(36) NOT NULL,
+  `target` longtext NOT NULL,
+  `target_count` int(11) NOT NULL DEFAULT '0',
+  `workspace_id` int(11) NOT NULL,
+  `create_datetime` datetime NOT NULL,
+  `update_datetime` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `index_task_target_id` (`target_id`),
+  KEY `fk_task_target_workspace_id` (`workspace_id`),
+  CONSTRAINT `fk_task_target_workspace_id` FOREIGN KEY (`workspace_id`) REFERENCES `workspace` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Dumping data for table `task_target`
+--
+
+LOCK TABLES `task_target` WRITE;
+/*!40000 ALTER TABLE `task_target` DISABLE KEYS */;
+/*!40000 ALTER TABLE `task_target` ENABLE KEYS */;
+UNLOCK TABLES;
+
 --
 -- Table structure for table `user`
 --
@@ -531,11 +741,13 @@ DROP TABLE IF EXISTS `user`;
 CREATE TABLE `user` (
   `id` int(11) NOT NULL AUTO_INCREMENT,
   `user_name` varchar(100) NOT NULL,
-  `user_password` char(48) NOT NULL,
+  `user_password` varchar(200) NOT NULL,
   `user_description` varchar(200) DEFAULT NULL,
   `user_role` varchar(40) NOT NULL,
   `state` varchar(40) NOT NULL,
   `sort_order` int(11) NOT NULL,
+  `login_failed` int(11) NOT NULL DEFAULT '0',
+  `locked_datetime` datetime DEFAULT NULL,
   `create_datetime` datetime NOT NULL,
   `update_datetime` datetime NOT NULL,
   PRIMARY KEY (`id`),
@@ -549,10 +761,37 @@ CREATE TABLE `user` (
 
 LOCK TABLES `user` WRITE;
 /*!40000 ALTER TABLE `user` DISABLE KEYS */;
-INSERT INTO `user` VALUES (1,'nemo','648ce596dba3b408b523d3d1189b15070123456789abcdef','默认超级管理员','superadmin','enable',100,'2023-02-26 11:43:20','2023-03-02 15:40:23');
+INSERT INTO `user` VALUES (1,'nemo','648ce596dba3b408b523d3d1189b15070123456789abcdef','默认超级管理员','superadmin','enable',100,0,NULL,'2023-02-26 11:43:20','2023-03-02 15:40:23');
 /*!40000 ALTER TABLE `user` ENABLE KEYS */;
 UNLOCK TABLES;
 
+--
+-- Table structure for table `user_token`
+--
+
+DROP TABLE IF EXISTS `user_token`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `user_token` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `user_id` int(11) NOT NULL,
+  `token_name` varchar(100) NOT NULL,
+  `token_hash` char(64) NOT NULL,
+  `token_prefix` varchar(20) NOT NULL,
+  `workspace` varchar(500) NOT NULL,
+  `state` varchar(40) NOT NULL,
+  `expire_datetime` datetime DEFAULT NULL,
+  `last_used_datetime` datetime DEFAULT NULL,
+  `last_used_ip` varchar(100) DEFAULT NULL,
+  `create_datetime` datetime NOT NULL,
+  `update_datetime` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `index_user_token_hash` (`token_hash`),
+  KEY `fk_user_token_userid` (`user_id`),
+  CONSTRAINT `fk_user_token_userid` FOREIGN KEY (`user_id`) REFERENCES `user` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
 --
 -- Table structure for table `user_workspace`
 --
@@ -600,11 +839,20 @@ CREATE TABLE `vulnerability` (
   `source` varchar(40) NOT NULL,
   `extra` varchar(4000) DEFAULT NULL,
   `hash` char(32) NOT NULL,
+  `vul_key` char(32) NOT NULL DEFAULT '',
+  `vul_name` varchar(200) NOT NULL DEFAULT '',
+  `cve` varchar(200) NOT NULL DEFAULT '',
+  `cwe` varchar(200) NOT NULL DEFAULT '',
+  `engines` varchar(100) NOT NULL DEFAULT '',
+  `status` varchar(20) NOT NULL DEFAULT 'new',
+  `severity` varchar(20) NOT NULL DEFAULT '',
+  `assignee` varchar(100) NOT NULL DEFAULT '',
   `workspace_id` int(11) NOT NULL,
   `create_datetime` datetime NOT NULL,
   `update_datetime` datetime NOT NULL,
   PRIMARY KEY (`id`),
   KEY `fk_vul_workspace_id` (`workspace_id`),
+  KEY `index_vul_key` (`vul_key`),
   CONSTRAINT `fk_vul_workspace_id` FOREIGN KEY (`workspace_id`) REFERENCES `workspace` (`id`) ON DELETE CASCADE
 ) ENGINE=InnoDB AUTO_INCREMENT=22 DEFAULT CHARSET=utf8;
 /*!40101 SET character_set_client = @saved_cs_client */;
@@ -618,6 +866,66 @@ LOCK TABLES `vulnerability` WRITE;
 /*!40000 ALTER TABLE `vulnerability` ENABLE KEYS */;
 UNLOCK TABLES;
 
+--
+-- Table structure for table `vulnerability_comment`
+--
+
+DROP TABLE IF EXISTS `vulnerability_comment`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `vulnerability_comment` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `r_id` int(10) unsigned NOT NULL,
+  `user_name` varchar(100) NOT NULL DEFAULT '',
+  `old_status` varchar(20) NOT NULL DEFAULT '',
+  `new_status` varchar(20) NOT NULL DEFAULT '',
+  `content` varchar(4000) NOT NULL DEFAULT '',
+  `create_datetime` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  KEY `fk_vulnerability_comment_r_id` (`r_id`),
+  CONSTRAINT `fk_vulnerability_comment_r_id` FOREIGN KEY (`r_id`) REFERENCES `vulnerability` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Dumping data for table `vulnerability_comment`
+--
+
+LOCK TABLES `vulnerability_comment` WRITE;
+/*!40000 ALTER TABLE `vulnerability_comment` DISABLE KEYS */;
+/*!40000 ALTER TABLE `vulnerability_comment` ENABLE KEYS */;
+UNLOCK TABLES;
+
+--
+-- Table structure for table `vulnerability_mapping`
+--
+
+DROP TABLE IF EXISTS `vulnerability_mapping`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `vulnerability_mapping` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `name` varchar(200) NOT NULL,
+  `cve` varchar(200) NOT NULL DEFAULT '',
+  `cwe` varchar(200) NOT NULL DEFAULT '',
+  `severity` varchar(20) NOT NULL DEFAULT '',
+  `pocs` varchar(4000) NOT NULL DEFAULT '',
+  `create_datetime` datetime NOT NULL,
+  `update_datetime` datetime NOT NULL,
+  PRIMARY KEY (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Dumping data for table `vulnerability_mapping`
+--
+
+LOCK TABLES `vulnerability_mapping` WRITE;
+/*!40000 ALTER TABLE `vulnerability_mapping` DISABLE KEYS */;
+INSERT INTO `vulnerability_mapping` VALUES (1,'Apache Log4j2 远程代码执行漏洞','CVE-2021-44228','CWE-502','critical','{"xray":["poc-yaml-log4j-rce"],"nuclei":["CVE-2021-44228"],"goby":["Apache Log4j2 Remote Code Execution Vulnerability (CVE-2021-44228)"]}','2024-06-01 10:00:00','2024-06-01 10:00:00'),(2,'WebLogic XMLDecoder 反序列化漏洞','CVE-2017-10271','CWE-502','critical','{"xray":["poc-yaml-weblogic-cve-2017-10271"],"nuclei":["CVE-2017-10271"],"goby":["Weblogic wls-wsat XMLDecoder deserialization RCE (CVE-2017-10271)"]}','2024-06-01 10:00:00','2024-06-01 10:00:00'),(3,'Spring Framework 远程代码执行漏洞(Spring4Shell)','CVE-2022-22965','CWE-94','critical','{"xray":["poc-yaml-spring-cve-2022-22965"],"nuclei":["CVE-2022-22965"]}','2024-06-01 10:00:00','2024-06-01 10:00:00'),(4,'Apache Struts2 S2-045 远程代码执行漏洞','CVE-2017-5638','CWE-20','critical','{"xray":["poc-yaml-struts2_045"],"nuclei":["CVE-2017-5638"],"goby":["Struts2 S2-045 RCE (CVE-2017-5638)"]}','2024-06-01 10:00:00','2024-06-01 10:00:00'),(5,'Atlassian Confluence OGNL 远程代码执行漏洞','CVE-2022-26134','CWE-917','critical','{"xray":["poc-yaml-confluence-cve-2022-26134"],"nuclei":["CVE-2022-26134"]}','2024-06-01 10:00:00','2024-06-01 10:00:00'),(6,'ThinkPHP 5.0.23 远程代码执行漏洞','','CWE-77','critical','{"xray":["poc-yaml-thinkphp5023-method-rce"],"nuclei":["thinkphp-5023-rce"],"goby":["ThinkPHP 5.0.23 RCE"]}','2024-06-01 10:00:00','2024-06-01 10:00:00'),(7,'ThinkPHP 5 控制器远程代码执行漏洞','','CWE-77','critical','{"xray":["poc-yaml-thinkphp5-controller-rce"],"nuclei":["thinkphp-5022-rce"],"goby":["ThinkPHP 5.0.22/5.1.29 RCE"]}','2024-06-01 10:00:00','2024-06-01 10:00:00'),(8,'DNS域传送漏洞','','CWE-200','high','{"dnscheck":["dns-zone-transfer"]}','2024-06-01 10:00:00','2024-06-01 10:00:00'),(9,'DNS服务器开放递归解析','','CWE-406','medium','{"dnscheck":["dns-open-resolver"]}','2024-06-01 10:00:00','2024-06-01 10:00:00');
+/*!40000 ALTER TABLE `vulnerability_mapping` ENABLE KEYS */;
+UNLOCK TABLES;
+
 --
 -- Table structure for table `workspace`
 --
@@ -649,6 +957,7 @@ LOCK TABLES `workspace` WRITE;
 INSERT INTO `workspace` VALUES (1,'默认','b0c79065-7ff7-32ae-cc18-864ccd8f7717','默认工作空间','enable',100,'2023-02-26 11:40:00','2023-02-26 11:40:05');
 /*!40000 ALTER TABLE `workspace` ENABLE KEYS */;
 UNLOCK TABLES;
+
 /*!40103 SET TIME_ZONE=@OLD_TIME_ZONE */;
 
 --
@@ -778,6 +1087,37 @@ CREATE TABLE `wiki_space` (
                               CONSTRAINT `wiki_space___fk_workspace_id` FOREIGN KEY (`workspace_id`) REFERENCES `workspace` (`id`) ON DELETE CASCADE
 ) ENGINE=InnoDB AUTO_INCREMENT=4 DEFAULT CHARSET=utf8mb4;
 /*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Table structure for table `workflow`
+--
+
+DROP TABLE IF EXISTS `workflow`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `workflow` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `name` varchar(100) NOT NULL,
+  `description` varchar(500) DEFAULT NULL,
+  `definition` text NOT NULL,
+  `sort_order` int(11) NOT NULL DEFAULT '100',
+  `create_datetime` datetime NOT NULL,
+  `update_datetime` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `index_workflow_name` (`name`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Dumping data for table `workflow`
+--
+
+LOCK TABLES `workflow` WRITE;
+/*!40000 ALTER TABLE `workflow` DISABLE KEYS */;
+INSERT INTO `workflow` VALUES (1,'xportscan','端口扫描->指纹识别->漏洞扫描','name: xportscan\ndescription: 端口扫描->指纹识别->漏洞扫描\nstages:\n  - name: portscan\n    task: portscan\n  - name: fingerprint\n    task: fingerprint\n    depends: [portscan]\n  - name: nuclei\n    task: nuclei\n    depends: [fingerprint]\n',100,'2024-06-01 00:00:00','2024-06-01 00:00:00'),(2,'xdomainscan','子域名枚举->端口扫描->指纹识别->漏洞扫描','name: xdomainscan\ndescription: 子域名枚举->端口扫描->指纹识别->漏洞扫描\nstages:\n  - name: subfinder\n    task: subfinder\n  - name: portscan\n    task: portscan\n    depends: [subfinder]\n    options:\n      port: --top-ports 100\n  - name: fingerprint\n    task: fingerprint\n    depends: [portscan]\n  - name: xray\n    task: xray\n    depends: [fingerprint]\n',100,'2024-06-01 00:00:00','2024-06-01 00:00:00');
+/*!40000 ALTER TABLE `workflow` ENABLE KEYS */;
+UNLOCK TABLES;
+
 /*!40103 SET TIME_ZONE=@OLD_TIME_ZONE */;
 
 /*!40101 SET SQL_MODE=@OLD_SQL_MODE */;
@@ -0,0 +1,85 @@
+-- MySQL dump 10.13  Distrib 5.7.44, for osx10.19 (x86_64)
+--
+-- Host: 127.0.0.1    Database: nemo
+-- ------------------------------------------------------
+-- Server version	5.7.44
+
+/*!40101 SET @OLD_CHARACTER_SET_CLIENT=@@CHARACTER_SET_CLIENT */;
+/*!40101 SET @OLD_CHARACTER_SET_RESULTS=@@CHARACTER_SET_RESULTS */;
+/*!40101 SET @OLD_COLLATION_CONNECTION=@@COLLATION_CONNECTION */;
+/*!40101 SET NAMES utf8 */;
+/*!40103 SET @OLD_TIME_ZONE=@@TIME_ZONE */;
+/*!40103 SET TIME_ZONE='+00:00' */;
+/*!40014 SET @OLD_UNIQUE_CHECKS=@@UNIQUE_CHECKS, UNIQUE_CHECKS=0 */;
+/*!40014 SET @OLD_FOREIGN_KEY_CHECKS=@@FOREIGN_KEY_CHECKS, FOREIGN_KEY_CHECKS=0 */;
+/*!40101 SET @OLD_SQL_MODE=@@SQL_MODE, SQL_MODE='NO_AUTO_VALUE_ON_ZERO' */;
+/*!40111 SET @OLD_SQL_NOTES=@@SQL_NOTES, SQL_NOTES=0 */;
+
+--
+-- Table structure for table `key_word_asset`
+--
+
+DROP TABLE IF EXISTS `key_word_asset`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `key_word_asset` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `key_word_id` int(10) unsigned NOT NULL,
+  `workspace_id` int(11) NOT NULL,
+  `asset_type` varchar(20) NOT NULL,
+  `asset` varchar(200) NOT NULL,
+  `engine` varchar(40) DEFAULT NULL,
+  `task_id` varchar(36) DEFAULT NULL,
+  `create_datetime` datetime NOT NULL,
+  `update_datetime` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `index_key_word_asset_asset` (`key_word_id`,`asset`),
+  CONSTRAINT `fk_key_word_asset_key_word_id` FOREIGN KEY (`key_word_id`) REFERENCES `key_word` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Dumping data for table `key_word_asset`
+--
+
+LOCK TABLES `key_word_asset` WRITE;
+/*!40000 ALTER TABLE `key_word_asset` DISABLE KEYS */;
+/*!40000 ALTER TABLE `key_word_asset` ENABLE KEYS */;
+UNLOCK TABLES;
+
+--
+-- Table structure for table `key_word_history`
+--
+
+DROP TABLE IF EXISTS `key_word_history`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `key_word_history` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `key_word_id` int(10) unsigned NOT NULL,
+  `workspace_id` int(11) NOT NULL,
+  `engine` varchar(40) DEFAULT NULL,
+  `task_id` varchar(36) DEFAULT NULL,
+  `total` int(11) NOT NULL DEFAULT '0',
+  `new_count` int(11) NOT NULL DEFAULT '0',
+  `new_asset` mediumtext,
+  `is_baseline` tinyint(4) NOT NULL DEFAULT '0',
+  `create_datetime` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  KEY `index_key_word_history_key_word_id` (`key_word_id`,`engine`),
+  CONSTRAINT `fk_key_word_history_key_word_id` FOREIGN KEY (`key_word_id`) REFERENCES `key_word` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+
+/*!40103 SET TIME_ZONE=@OLD_TIME_ZONE */;
+
+/*!40101 SET SQL_MODE=@OLD_SQL_MODE */;
+/*!40014 SET FOREIGN_KEY_CHECKS=@OLD_FOREIGN_KEY_CHECKS */;
+/*!40014 SET UNIQUE_CHECKS=@OLD_UNIQUE_CHECKS */;
+/*!40101 SET CHARACTER_SET_CLIENT=@OLD_CHARACTER_SET_CLIENT */;
+/*!40101 SET CHARACTER_SET_RESULTS=@OLD_CHARACTER_SET_RESULTS */;
+/*!40101 SET COLLATION_CONNECTION=@OLD_COLLATION_CONNECTION */;
+/*!40111 SET SQL_NOTES=@OLD_SQL_NOTES */;
+
+-- Dump completed on 2024-06-01 10:21:35
@@ -3,6 +3,7 @@ package comm
 import (
 	"fmt"
 	"github.com/hanc00l/nemo_go/pkg/logging"
+	"github.com/hanc00l/nemo_go/pkg/notify"
 	"github.com/hanc00l/nemo_go/pkg/task/ampq"
 	"github.com/shirou/gopsutil/v3/load"
 	"github.com/shirou/gopsutil/v3/mem"
@@ -21,9 +22,14 @@ type KeepAliveDaemonInfo struct {
 	WorkerRunOption        *WorkerOption
 }
 
+// workerOfflineTime 超过该时间未同步心跳的worker视为离线
+const workerOfflineTime = 5 * time.Minute
+
 var (
 	WorkerStatusMutex sync.Mutex
 	WorkerStatus      = make(map[string]*ampq.WorkerStatus)
+	// workerOffline 已发布离线事件的worker
+	workerOffline = make(map[string]struct{})
 )
 
 // DoKeepAlive worker请求keepAlive
@@ -56,3 +62,52 @@ func DoDaemonKeepAlive() (replay KeepAliveDaemonInfo, err error) {
 	}
 	return
 }
+
+// StartWorkerOfflineMonitor server定时检查worker的心跳，对离线的worker发布事件
+func StartWorkerOfflineMonitor() {
+	for {
+		time.Sleep(time.Minute)
+		checkWorkerOffline()
+	}
+}
+
+// checkWorkerOffline 检查worker是否离线，每次离线只发布一次事件
+func checkWorkerOffline() {
+	WorkerStatusMutex.Lock()
+	defer WorkerStatusMutex.Unlock()
+
+	for name := range workerOffline {
+		if _, ok := WorkerStatus[name]; !ok {
+			delete(workerOffline, name)
+		}
+	}
+	for name, v := range WorkerStatus {
+		if time.Since(v.UpdateTime) < workerOfflineTime {
+			delete(workerOffline, name)
+			continue
+		}
+		publishWorkerOffline(name, v)
+	}
+}
+
+// RemoveWorkerStatus 移除离线的worker，移除前未发布离线事件的则发布；调用时需持有WorkerStatusMutex
+func RemoveWorkerStatus(name string) {
+	if v, ok := WorkerStatus[name]; ok {
+		publishWorkerOffline(name, v)
+		delete(WorkerStatus, name)
+	}
+	delete(workerOffline, name)
+}
+
+// publishWorkerOffline 发布worker离线事件，每次离线只发布一次
+func publishWorkerOffline(name string, v *ampq.WorkerStatus) {
+	if _, ok := workerOffline[name]; ok {
+		return
+	}
+	workerOffline[name] = struct{}{}
+	notify.Publish(notify.Event{
+		Type:    notify.EventWorkerOffline,
+		Target:  name,
+		Content: fmt.Sprintf("offline,last alive:%s", v.UpdateTime.Format("2006-01-02 15:04:05")),
+	})
+}
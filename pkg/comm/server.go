@@ -12,6 +12,7 @@ import (
 	"github.com/hanc00l/nemo_go/pkg/es"
 	"github.com/hanc00l/nemo_go/pkg/filesync"
 	"github.com/hanc00l/nemo_go/pkg/logging"
+	"github.com/hanc00l/nemo_go/pkg/task/domainscan"
 	"github.com/hanc00l/nemo_go/pkg/utils"
 	"github.com/smallnest/rpcx/protocol"
 	"github.com/smallnest/rpcx/server"
@@ -148,6 +149,23 @@ func StartSaveRuntimeLog(source string) {
 	}
 }
 
+// StartResolverCheck 定时检查DNS服务器列表，生成可用的DNS服务器列表，文件变化后由文件同步更新到worker
+func StartResolverCheck() {
+	resolverConfig := conf.GlobalServerConfig().Resolver
+	if resolverConfig.Interval <= 0 {
+		return
+	}
+	for {
+		msg, err := domainscan.CheckResolver(resolverConfig.Trusted, resolverConfig.ProbeDomain, time.Duration(resolverConfig.MaxLatency)*time.Millisecond)
+		if err != nil {
+			logging.RuntimeLog.Errorf("check resolver fail:%v", err)
+		} else {
+			logging.RuntimeLog.Infof("check resolver finish,%s", msg)
+		}
+		time.Sleep(time.Duration(resolverConfig.Interval) * time.Minute)
+	}
+}
+
 func StartSyncElasticAssets() {
 	// 同步elastic assets
 	if !es.CheckElasticConn() {
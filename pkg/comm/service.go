@@ -10,7 +10,9 @@ import (
 	"github.com/hanc00l/nemo_go/pkg/conf"
 	"github.com/hanc00l/nemo_go/pkg/db"
 	"github.com/hanc00l/nemo_go/pkg/logging"
+	"github.com/hanc00l/nemo_go/pkg/notify"
 	"github.com/hanc00l/nemo_go/pkg/task/ampq"
+	"github.com/hanc00l/nemo_go/pkg/task/dirscan"
 	"github.com/hanc00l/nemo_go/pkg/task/domainscan"
 	"github.com/hanc00l/nemo_go/pkg/task/fingerprint"
 	"github.com/hanc00l/nemo_go/pkg/task/onlineapi"
@@ -30,6 +32,9 @@ import (
 	"time"
 )
 
+// keyWordNotifyAssetLimit 关键词监控通知中列出的新增资产的最大数量
+const keyWordNotifyAssetLimit = 50
+
 // Service RPC服务
 type Service struct{}
 
@@ -44,6 +49,14 @@ type ScanResultArgs struct {
 	VulnerabilityResult []pocscan.Result
 }
 
+// DirscanResultArgs 目录扫描结果请求参数
+type DirscanResultArgs struct {
+	TaskID     string
+	MainTaskId string
+	Config     dirscan.Config
+	UrlResult  map[string][]dirscan.PathResult
+}
+
 // ScreenshotResultArgs screenshot结果请求参数
 type ScreenshotResultArgs struct {
 	MainTaskId  string
@@ -86,6 +99,11 @@ type LoadDomainOpenedPortArgs struct {
 	Target      map[string]struct{}
 }
 
+type LoadSubDomainArgs struct {
+	WorkspaceId int
+	Target      string
+}
+
 type MainTaskResultMap struct {
 	IPResult         map[string]map[int]interface{}
 	DomainResult     map[string]interface{}
@@ -95,6 +113,21 @@ type MainTaskResultMap struct {
 	PortNew          int
 	DomainNew        int
 	VulnerabilityNew int
+	// 新增的漏洞（target vulname），最多保存MainTaskNewListMax条
+	VulnerabilityNewList []string
+}
+
+// MainTaskNewListMax 任务中保存及通知的新增资产列表的最大数量
+const MainTaskNewListMax = 100
+
+// KeyWordMonitorArgs 关键词一次检索得到的资产
+type KeyWordMonitorArgs struct {
+	KeyWordId   int
+	WorkspaceId int
+	Engine      string
+	TaskId      string
+	IPPort      []string
+	Domain      []string
 }
 
 type RuntimeLogArgs struct {
@@ -107,8 +140,11 @@ var (
 	globalXClient      client.XClient
 	globalXClientMutex sync.Mutex
 	// 数据库操作的同步锁
-	saveIPMutex     sync.RWMutex
-	saveDomainMutex sync.RWMutex
+	saveIPMutex      sync.RWMutex
+	saveDomainMutex  sync.RWMutex
+	saveKeyWordMutex sync.Mutex
+	// 漏洞按漏洞标识查询后再新增，需要串行执行
+	saveVulnerabilityMutex sync.Mutex
 	// MainTaskResult 缓存汇总各个子任务、保存任务的结果
 	MainTaskResult      map[string]MainTaskResultMap
 	MainTaskResultMutex sync.Mutex
@@ -148,8 +184,12 @@ func (s *Service) SaveScanResult(ctx context.Context, args *ScanResultArgs, repl
 			IPResult: args.IPResult,
 		}
 
+		args.IPConfig.TaskId = args.TaskID
 		saveIPMutex.Lock()
 		msg = append(msg, r.SaveResult(*args.IPConfig))
+		if staleMsg := r.Reconcile(*args.IPConfig, conf.GlobalServerConfig().Task.StaleMissCount); staleMsg != "" {
+			msg = append(msg, staleMsg)
+		}
 		saveIPMutex.Unlock()
 
 		if len(args.IPResult) > 0 {
@@ -161,6 +201,7 @@ func (s *Service) SaveScanResult(ctx context.Context, args *ScanResultArgs, repl
 			DomainResult: args.DomainResult,
 		}
 
+		args.DomainConfig.TaskId = args.TaskID
 		saveDomainMutex.Lock()
 		msg = append(msg, r.SaveResult(*args.DomainConfig))
 		saveDomainMutex.Unlock()
@@ -169,13 +210,67 @@ func (s *Service) SaveScanResult(ctx context.Context, args *ScanResultArgs, repl
 			saveTaskResult(args.TaskID, args.DomainResult)
 		}
 	}
+	// 保存TLS证书，证书SAN中的候选域名作为新的域名保存
+	if certConfig, ok := makeCertificateConfig(args); ok {
+		saveDomainMutex.Lock()
+		certMsg, candidates := fingerprint.SaveCertificateResult(certConfig, args.IPResult, args.DomainResult)
+		saveDomainMutex.Unlock()
+		if certMsg != "" {
+			msg = append(msg, certMsg)
+		}
+		if len(candidates) > 0 {
+			newCertificateDomainTask(args, certConfig, candidates)
+		}
+	}
+	// 域名任务中DNS检查发现的漏洞
+	var newVul []string
+	if len(args.VulnerabilityResult) > 0 {
+		var vulMsg string
+		saveVulnerabilityMutex.Lock()
+		vulMsg, newVul = pocscan.SaveResultWithNew(args.VulnerabilityResult)
+		saveVulnerabilityMutex.Unlock()
+		msg = append(msg, vulMsg)
+	}
 	saveMainTaskResult(args.MainTaskId, args.IPResult, args.DomainResult, args.VulnerabilityResult, 0)
 	*replay = strings.Join(msg, ",")
 	saveMainTaskNewResult(args.MainTaskId, *replay)
+	saveMainTaskNewVulnerability(args.MainTaskId, newVul)
 
 	return nil
 }
 
+// newCertificateDomainTask 对证书SAN中的候选域名启动域名解析任务
+func newCertificateDomainTask(args *ScanResultArgs, config domainscan.Config, domains []string) {
+	if args.MainTaskId == "" {
+		return
+	}
+	resolveConfig := domainscan.Config{
+		Target:      strings.Join(domains, ","),
+		OrgId:       config.OrgId,
+		WorkspaceId: config.WorkspaceId,
+	}
+	configJSON, _ := json.Marshal(resolveConfig)
+	if _, err := serverapi.NewRunTask("domainscan", string(configJSON), args.MainTaskId, args.TaskID); err != nil {
+		logging.RuntimeLog.Errorf("start certificate domain resolve fail:%s", err.Error())
+	}
+}
+
+// makeCertificateConfig 生成保存证书及候选域名的参数
+func makeCertificateConfig(args *ScanResultArgs) (config domainscan.Config, ok bool) {
+	config.TaskId = args.TaskID
+	if args.IPConfig != nil && len(args.IPResult) > 0 {
+		config.WorkspaceId = args.IPConfig.WorkspaceId
+		config.OrgId = args.IPConfig.OrgId
+		ok = true
+	}
+	if args.DomainConfig != nil && len(args.DomainResult) > 0 {
+		config.WorkspaceId = args.DomainConfig.WorkspaceId
+		config.OrgId = args.DomainConfig.OrgId
+		ok = true
+	}
+	return
+}
+
 // SaveScreenshotResult 保存Screenshot的结果到Server
 func (s *Service) SaveScreenshotResult(ctx context.Context, args *ScreenshotResultArgs, replay *string) error {
 	ss := fingerprint.NewScreenShot()
@@ -213,13 +308,27 @@ func (s *Service) SaveIconImageResult(ctx context.Context, args *IconHashResultA
 	return nil
 }
 
-// SaveVulnerabilityResult 保存漏洞结果
+// SaveVulnerabilityResult 保存漏洞结果，同一漏洞被多个扫描引擎发现时合并为一条记录
 func (s *Service) SaveVulnerabilityResult(ctx context.Context, args *ScanResultArgs, replay *string) error {
-	*replay = pocscan.SaveResult(args.VulnerabilityResult)
+	var newVul []string
+	saveVulnerabilityMutex.Lock()
+	*replay, newVul = pocscan.SaveResultWithNew(args.VulnerabilityResult)
+	saveVulnerabilityMutex.Unlock()
 	if len(args.VulnerabilityResult) > 0 {
 		saveTaskResult(args.TaskID, args.VulnerabilityResult)
 		saveMainTaskResult(args.MainTaskId, nil, nil, args.VulnerabilityResult, 0)
 		saveMainTaskNewResult(args.MainTaskId, *replay)
+		saveMainTaskNewVulnerability(args.MainTaskId, newVul)
+	}
+	return nil
+}
+
+// SaveDirscanResult 保存目录扫描的结果
+func (s *Service) SaveDirscanResult(ctx context.Context, args *DirscanResultArgs, replay *string) error {
+	r := dirscan.Result{UrlResult: args.UrlResult}
+	*replay = r.SaveResult(args.Config)
+	if len(args.UrlResult) > 0 {
+		saveTaskResult(args.TaskID, args.UrlResult)
 	}
 	return nil
 }
@@ -264,6 +373,71 @@ func (s *Service) SaveWhoisResult(ctx context.Context, args *map[string]*whoispa
 	return nil
 }
 
+// OnlineAPICacheArgs 在线API查询结果缓存的请求参数
+type OnlineAPICacheArgs struct {
+	Key  string
+	TTL  int
+	Item *onlineapi.OnlineAPICacheItem
+}
+
+// LoadOnlineAPICache 获取server缓存的在线API查询结果，没有缓存时返回空的结果
+func (s *Service) LoadOnlineAPICache(ctx context.Context, args *OnlineAPICacheArgs, replay *onlineapi.OnlineAPICacheItem) error {
+	if item, ok := onlineapi.GetOnlineAPICache().Load(args.Key, args.TTL); ok {
+		*replay = *item
+	}
+	return nil
+}
+
+// SaveOnlineAPICache 保存在线API查询结果到server的缓存中
+func (s *Service) SaveOnlineAPICache(ctx context.Context, args *OnlineAPICacheArgs, replay *string) error {
+	if args.Item == nil {
+		*replay = "onlineapi cache:0"
+		return nil
+	}
+	// 同时清除已过期的缓存，避免缓存文件不断增大
+	if ttl := conf.GlobalWorkerConfig().API.CacheTTL; ttl > 0 {
+		onlineapi.GetOnlineAPICache().Purge(ttl)
+	}
+	onlineapi.GetOnlineAPICache().Save(args.Key, args.Item)
+	*replay = "onlineapi cache:1"
+
+	return nil
+}
+
+// APIKeyStatusArgs worker上报的在线API key使用情况
+type APIKeyStatusArgs struct {
+	WorkerName string
+	Status     []onlineapi.APIKeyStatus
+}
+
+// SaveAPIKeyStatus 保存worker上报的在线API key使用情况，用于在配置页面中显示
+func (s *Service) SaveAPIKeyStatus(ctx context.Context, args *APIKeyStatusArgs, replay *string) error {
+	onlineapi.SaveWorkerAPIKeyStatus(args.WorkerName, args.Status)
+	*replay = fmt.Sprintf("apikey:%d", len(args.Status))
+	return nil
+}
+
+// SaveKeyWordMonitorResult 保存关键词检索的资产及历史，并对新增的资产发送通知
+func (s *Service) SaveKeyWordMonitorResult(ctx context.Context, args *KeyWordMonitorArgs, replay *string) error {
+	if args.KeyWordId <= 0 {
+		*replay = "keywordNew:0"
+		return nil
+	}
+	saveKeyWordMutex.Lock()
+	r, isBaseline := db.SaveKeyWordMonitorResult(args.KeyWordId, args.WorkspaceId, args.Engine, args.TaskId, args.IPPort, args.Domain)
+	saveKeyWordMutex.Unlock()
+
+	if isBaseline {
+		*replay = fmt.Sprintf("keywordBaseline:%d", r.Total)
+		return nil
+	}
+	*replay = fmt.Sprintf("keywordNew:%d", r.NewCount())
+	if r.NewCount() > 0 {
+		go notify.Send(formatKeyWordMonitorMessage(args.KeyWordId, args.Engine, r))
+	}
+	return nil
+}
+
 // CheckTask 检查任务在数据库中的状态：任务是否存在、是否被取消，任务状态、结果
 func (s *Service) CheckTask(ctx context.Context, args *string, replay *TaskStatusArgs) error {
 	taskRun := &db.TaskRun{TaskId: *args}
@@ -518,6 +692,29 @@ func (s *Service) LoadDomainOpenedPort(ctx context.Context, args *LoadDomainOpen
 	return nil
 }
 
+// LoadSubDomain 读取工作空间中目标域名已有的全部子域名
+func (s *Service) LoadSubDomain(ctx context.Context, args *LoadSubDomainArgs, replay *[]string) error {
+	var result []string
+	domainMap := make(map[string]struct{})
+	for _, t := range strings.Split(args.Target, ",") {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if t == "" {
+			continue
+		}
+		domainDb := db.Domain{}
+		domains, _ := domainDb.Gets(map[string]interface{}{"domain_suffix": t, "workspace_id": args.WorkspaceId}, 0, 0, false)
+		for _, domain := range domains {
+			if _, ok := domainMap[domain.DomainName]; ok || domain.DomainName == t {
+				continue
+			}
+			domainMap[domain.DomainName] = struct{}{}
+			result = append(result, domain.DomainName)
+		}
+	}
+	*replay = result
+	return nil
+}
+
 // SaveRuntimeLog 保存RuntimeLog
 func (s *Service) SaveRuntimeLog(ctx context.Context, args *RuntimeLogArgs, replay *string) error {
 	if len(args.Source) == 0 || len(args.LogMessage) == 0 {
@@ -608,6 +805,27 @@ func saveTaskResult(taskID string, result interface{}) {
 	}
 }
 
+// formatKeyWordMonitorMessage 生成关键词新增资产的通知消息，只包含本次新增的资产
+func formatKeyWordMonitorMessage(keyWordId int, engine string, r db.KeyWordMonitorResult) string {
+	kw := db.KeyWord{Id: keyWordId}
+	kw.Get()
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("keyword monitor->%s,engine:%s,total:%d,new:%d", kw.KeyWord, engine, r.Total, r.NewCount()))
+	var count int
+	for _, assets := range [][]string{r.NewIPPort, r.NewDomain} {
+		for _, asset := range assets {
+			if count >= keyWordNotifyAssetLimit {
+				sb.WriteString(fmt.Sprintf("  \n...(%d more)", r.NewCount()-count))
+				return sb.String()
+			}
+			sb.WriteString("  \n")
+			sb.WriteString(asset)
+			count++
+		}
+	}
+	return sb.String()
+}
+
 // saveMainTaskResult 保存runtask的任务结果到maintask的缓存中
 func saveMainTaskResult(taskId string, ipResult map[string]*portscan.IPResult, domainResult map[string]*domainscan.DomainResult, vulResult []pocscan.Result, screenshotResult int) {
 	MainTaskResultMutex.Lock()
@@ -684,13 +902,34 @@ func saveMainTaskNewResult(mainTaskId, msg string) {
 	return
 }
 
-// checkWorkerStatus 对超过指定时间未同步的的worker，移除相关信息
+// saveMainTaskNewVulnerability 保存任务中新增的漏洞
+func saveMainTaskNewVulnerability(mainTaskId string, newVul []string) {
+	if len(newVul) == 0 {
+		return
+	}
+	MainTaskResultMutex.Lock()
+	defer MainTaskResultMutex.Unlock()
+
+	taskObj, ok := MainTaskResult[mainTaskId]
+	if !ok {
+		return
+	}
+	for _, v := range newVul {
+		if len(taskObj.VulnerabilityNewList) >= MainTaskNewListMax {
+			break
+		}
+		taskObj.VulnerabilityNewList = append(taskObj.VulnerabilityNewList, v)
+	}
+	MainTaskResult[mainTaskId] = taskObj
+}
+
+// checkWorkerStatus 对超过指定时间未同步的的worker，移除相关信息；调用时需持有WorkerStatusMutex
 func checkWorkerStatus() {
 	for _, v := range WorkerStatus {
 		// 如果worker资源（cpu、内存）比较低，会在大并发任务的时候导致很长一段时间阻塞同步
 		// 因此将worker不存活的时间调整为超过12个小时
 		if time.Now().Sub(v.UpdateTime).Hours() > 12 {
-			delete(WorkerStatus, v.WorkerName)
+			RemoveWorkerStatus(v.WorkerName)
 		}
 	}
 }
@@ -85,36 +85,43 @@ func GlobalWorkerConfig() *Worker {
 }
 
 type Server struct {
-	Web      Web               `yaml:"web"`
-	Rpc      RPC               `yaml:"rpc"`
-	FileSync RPC               `yaml:"fileSync"`
-	WebAPI   WebAPI            `yaml:"api"`
-	Database Database          `yaml:"database"`
-	Rabbitmq Rabbitmq          `yaml:"rabbitmq"`
-	Elastic  ElasticSearch     `yaml:"elastic"`
-	Task     Task              `yaml:"task"`
-	Notify   map[string]Notify `yaml:"notify"`
-	Wiki     Wiki              `yaml:"wiki"`
+	Web        Web               `yaml:"web"`
+	Rpc        RPC               `yaml:"rpc"`
+	FileSync   RPC               `yaml:"fileSync"`
+	WebAPI     WebAPI            `yaml:"api"`
+	Database   Database          `yaml:"database"`
+	Rabbitmq   Rabbitmq          `yaml:"rabbitmq"`
+	Elastic    ElasticSearch     `yaml:"elastic"`
+	Task       Task              `yaml:"task"`
+	Notify     map[string]Notify `yaml:"notify"`
+	NotifyRule []NotifyRule      `yaml:"notifyRule"`
+	Wiki       Wiki              `yaml:"wiki"`
+	Password   Password          `yaml:"password"`
+	Resolver   ResolverCheck     `yaml:"resolver"`
 }
 
 type Worker struct {
-	Rpc         RPC         `yaml:"rpc"`
-	FileSync    RPC         `yaml:"fileSync"`
-	Rabbitmq    Rabbitmq    `yaml:"rabbitmq"`
-	API         API         `yaml:"api"`
-	Portscan    Portscan    `yaml:"portscan"`
-	Fingerprint Fingerprint `yaml:"fingerprint"`
-	Domainscan  Domainscan  `yaml:"domainscan"`
-	OnlineAPI   OnlineAPI   `yaml:"onlineapi"`
-	Pocscan     Pocscan     `yaml:"pocscan"`
-	Proxy       Proxy       `yaml:"proxy"`
-	Filter      Filter      `yaml:"filter"`
+	Rpc         RPC              `yaml:"rpc"`
+	FileSync    RPC              `yaml:"fileSync"`
+	Rabbitmq    Rabbitmq         `yaml:"rabbitmq"`
+	API         API              `yaml:"api"`
+	Portscan    Portscan         `yaml:"portscan"`
+	Fingerprint Fingerprint      `yaml:"fingerprint"`
+	Domainscan  Domainscan       `yaml:"domainscan"`
+	OnlineAPI   OnlineAPI        `yaml:"onlineapi"`
+	Pocscan     Pocscan          `yaml:"pocscan"`
+	Dirscan     Dirscan          `yaml:"dirscan"`
+	Proxy       Proxy            `yaml:"proxy"`
+	Filter      Filter           `yaml:"filter"`
+	Retry       map[string]Retry `yaml:"retry"`
 }
 
 type Web struct {
 	Host     string `yaml:"host"`
 	Port     int    `yaml:"port"`
 	WebFiles string `yaml:"webfiles"`
+	// 外部访问web的地址，用于消息通知中的链接
+	URL string `yaml:"url,omitempty"`
 }
 
 type WebAPI struct {
@@ -151,15 +158,39 @@ type ElasticSearch struct {
 type Task struct {
 	IpSliceNumber   int `yaml:"ipSliceNumber"`
 	PortSliceNumber int `yaml:"portSliceNumber"`
+	StaleMissCount  int `yaml:"staleMissCount"` // 资产连续多少次扫描未发现时标记为失效，0为不标记
+}
+
+type ResolverCheck struct {
+	Interval    int      `yaml:"interval"`    // 检查DNS服务器列表的间隔（分钟），0为不检查
+	MaxLatency  int      `yaml:"maxLatency"`  // 可用的DNS服务器的最大平均响应时间（毫秒），0为不限制
+	Trusted     []string `yaml:"trusted"`     // 获取基准解析结果的可信DNS服务器
+	ProbeDomain string   `yaml:"probeDomain"` // 检查解析结果的域名
+}
+
+type Password struct {
+	Algorithm    string `yaml:"algorithm"`    // 密码hash算法：argon2id或bcrypt
+	MinLength    int    `yaml:"minLength"`    // 密码最小长度
+	Complexity   int    `yaml:"complexity"`   // 至少包含的字符类型数（大写字母、小写字母、数字、特殊字符）
+	MaxFailures  int    `yaml:"maxFailures"`  // 连续登录失败锁定的次数，0为不锁定
+	LockDuration int    `yaml:"lockDuration"` // 锁定的时长（秒）
 }
 
 type API struct {
-	SearchPageSize   int    `yaml:"searchPageSize"`
-	SearchLimitCount int    `yaml:"searchLimitCount"`
-	Fofa             APIKey `yaml:"fofa"`
-	ICP              APIKey `yaml:"icp"`
-	Quake            APIKey `yaml:"quake"`
-	Hunter           APIKey `yaml:"hunter"`
+	SearchPageSize   int `yaml:"searchPageSize"`
+	SearchLimitCount int `yaml:"searchLimitCount"`
+	// SearchPointBudget 每个任务允许消耗的额度，0表示不限制
+	SearchPointBudget int `yaml:"searchPointBudget"`
+	// CacheTTL 查询结果在server缓存的有效时间（小时），0表示不使用缓存
+	CacheTTL int    `yaml:"cacheTTL"`
+	Fofa     APIKey `yaml:"fofa"`
+	ICP      APIKey `yaml:"icp"`
+	Quake    APIKey `yaml:"quake"`
+	Hunter   APIKey `yaml:"hunter"`
+	Shodan   APIKey `yaml:"shodan"`
+	Censys   APIKey `yaml:"censys"`
+	ZoomEye  APIKey `yaml:"zoomeye"`
+	Netlas   APIKey `yaml:"netlas"`
 }
 
 type APIKey struct {
@@ -196,18 +227,31 @@ type Pocscan struct {
 	} `yaml:"goby"`
 }
 
+type Dirscan struct {
+	Wordlist      string `yaml:"wordlist"`      // 字典文件，位于thirdparty/dict
+	Extensions    string `yaml:"extensions"`    // 替换字典中%EXT%的扩展名，以逗号分隔
+	ExcludeStatus string `yaml:"excludeStatus"` // 过滤的状态码，如"404,500-599"
+	ExcludeLength string `yaml:"excludeLength"` // 过滤的响应长度
+	MaxHits       int    `yaml:"maxHits"`       // 单个URL的命中数量超过该值时丢弃结果
+}
+
 type Domainscan struct {
-	Resolver           string `yaml:"resolver"`
-	Wordlist           string `yaml:"wordlist"`
-	ProviderConfig     string `yaml:"providerConfig"`
-	IsSubDomainFinder  bool   `yaml:"subfinder"`
-	IsSubDomainBrute   bool   `yaml:"subdomainBrute"`
-	IsSubdomainCrawler bool   `yaml:"subdomainCrawler"`
-	IsIgnoreCDN        bool   `yaml:"ignoreCDN"`
-	IsIgnoreOutofChina bool   `yaml:"ignoreOutofChina"`
-	IsPortScan         bool   `yaml:"portscan"`
-	IsWhois            bool   `yaml:"whois"`
-	IsICP              bool   `yaml:"icp"`
+	Resolver           string   `yaml:"resolver"`
+	Wordlist           string   `yaml:"wordlist"`
+	PermuteWordlist    string   `yaml:"permuteWordlist"`
+	DoHResolver        []string `yaml:"dohResolver"` // UDP/53不可用时使用的DoH（https://）或DoT（tls://）服务器
+	ProviderConfig     string   `yaml:"providerConfig"`
+	IsSubDomainFinder  bool     `yaml:"subfinder"`
+	IsSubDomainBrute   bool     `yaml:"subdomainBrute"`
+	IsSubdomainCrawler bool     `yaml:"subdomainCrawler"`
+	IsIgnoreCDN        bool     `yaml:"ignoreCDN"`
+	IsIgnoreOutofChina bool     `yaml:"ignoreOutofChina"`
+	IsPortScan         bool     `yaml:"portscan"`
+	IsWhois            bool     `yaml:"whois"`
+	IsICP              bool     `yaml:"icp"`
+	IsDNSRecord        bool     `yaml:"dnsRecord"`         // 收集主域名的MX、TXT、NS、SOA、SRV及CAA记录
+	IsDNSCheck         bool     `yaml:"dnsCheck"`          // 检查NS的域传送及开放递归解析
+	OpenResolverProbe  string   `yaml:"openResolverProbe"` // 检查开放递归解析时查询的域名
 }
 
 type OnlineAPI struct {
@@ -218,6 +262,43 @@ type OnlineAPI struct {
 
 type Notify struct {
 	Token string `yaml:"token"`
+	// webhook、slack的地址
+	URL string `yaml:"url,omitempty"`
+	// webhook的HMAC签名密钥
+	Secret string `yaml:"secret,omitempty"`
+	// 邮件的收件人、telegram的chat_id
+	Recipients []string `yaml:"recipients,omitempty"`
+	// smtp邮件服务器
+	Host     string `yaml:"host,omitempty"`
+	Port     int    `yaml:"port,omitempty"`
+	Username string `yaml:"username,omitempty"`
+	Password string `yaml:"password,omitempty"`
+	From     string `yaml:"from,omitempty"`
+	// 任务通知的消息标题、Go模板及消息格式（text、markdown、card）
+	Title    string `yaml:"title,omitempty"`
+	Template string `yaml:"template,omitempty"`
+	Format   string `yaml:"format,omitempty"`
+}
+
+// NotifyRule 事件通知的规则：匹配的事件在聚合时间内合并为一条消息发送
+type NotifyRule struct {
+	Name string `yaml:"name"`
+	// 事件类型：vulnerability、port、domain、worker_offline
+	Event string `yaml:"event"`
+	// 限定的工作空间及组织，为0时不限定
+	WorkspaceId int `yaml:"workspaceId,omitempty"`
+	OrgId       int `yaml:"orgId,omitempty"`
+	// 漏洞的最低严重程度
+	Severity string `yaml:"severity,omitempty"`
+	// 新增开放的端口
+	Ports []int `yaml:"ports,omitempty"`
+	// 新增子域名的上级域名
+	Domains []string `yaml:"domains,omitempty"`
+	// 发送的消息通知，为空时使用全部已配置的通知
+	Senders []string `yaml:"senders,omitempty"`
+	// 聚合的时间间隔（分钟）
+	Interval int  `yaml:"interval,omitempty"`
+	Disable  bool `yaml:"disable,omitempty"`
 }
 
 type Proxy struct {
@@ -232,6 +313,13 @@ type Wiki struct {
 	} `yaml:"feishu"`
 }
 
+type Retry struct {
+	MaxAttempts int      `yaml:"maxAttempts"`
+	Delay       int      `yaml:"delay"`
+	MaxDelay    int      `yaml:"maxDelay"`
+	Errors      []string `yaml:"errors"`
+}
+
 type Filter struct {
 	MaxPortPerIp   int    `yaml:"maxPortPerIp"`
 	MaxDomainPerIp int    `yaml:"maxDomainPerIp"`
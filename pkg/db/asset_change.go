@@ -0,0 +1,237 @@
+package db
+
+import (
+	"gorm.io/gorm"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	AssetChangeAdded    = "added"
+	AssetChangeRemoved  = "removed"
+	AssetChangeModified = "modified"
+	AssetChangeStale    = "stale"
+
+	AssetTypeIP     = "ip"
+	AssetTypeDomain = "domain"
+)
+
+// AssetChange 资产的变更记录：IP、端口、域名及其属性的新增、删除和修改
+type AssetChange struct {
+	Id             int       `gorm:"primaryKey"`
+	WorkspaceId    int       `gorm:"column:workspace_id"`
+	AssetType      string    `gorm:"column:asset_type"`
+	Asset          string    `gorm:"column:asset"`
+	Port           int       `gorm:"column:port"`
+	ChangeType     string    `gorm:"column:change_type"`
+	Source         string    `gorm:"column:source"`
+	Tag            string    `gorm:"column:tag"`
+	Content        string    `gorm:"column:content"`
+	OldContent     string    `gorm:"column:old_content"`
+	TaskId         string    `gorm:"column:task_id"`
+	CreateDatetime time.Time `gorm:"column:create_datetime"`
+}
+
+func (*AssetChange) TableName() string {
+	return "asset_change"
+}
+
+// Add 插入一条新的记录
+func (c *AssetChange) Add() (success bool) {
+	c.CreateDatetime = time.Now()
+	if len(c.Content) > AttrContentSize {
+		c.Content = c.Content[:AttrContentSize]
+	}
+	if len(c.OldContent) > AttrContentSize {
+		c.OldContent = c.OldContent[:AttrContentSize]
+	}
+
+	db := GetDB()
+	defer CloseDB(db)
+	if result := db.Create(c); result.RowsAffected > 0 {
+		return true
+	} else {
+		return false
+	}
+}
+
+// makeWhere 根据查询条件的不同的字段，组合生成count和search的查询条件
+func (c *AssetChange) makeWhere(searchMap map[string]interface{}) *gorm.DB {
+	db := GetDB()
+	for column, value := range searchMap {
+		switch column {
+		case "since":
+			db = db.Where("create_datetime >= ?", value)
+		case "date_delta":
+			db = makeDateDelta(value.(int), "create_datetime", db)
+		case "main_task_id":
+			// maintask的所有runtask产生的变更
+			runTask := GetDB().Model(&TaskRun{}).Select("task_id").Where("main_id = ?", value)
+			db = db.Where("task_id in (?)", runTask)
+		default:
+			db = db.Where(column, value)
+		}
+	}
+	return db
+}
+
+// Gets 根据指定的条件，查询满足要求的记录，按时间倒序
+func (c *AssetChange) Gets(searchMap map[string]interface{}, page, rowsPerPage int) (results []AssetChange, count int) {
+	orderBy := "create_datetime desc,id desc"
+
+	db := c.makeWhere(searchMap).Model(c)
+	defer CloseDB(db)
+	//统计满足条件的总记录数
+	var total int64
+	db.Count(&total)
+	//获取分页查询结果
+	if rowsPerPage > 0 && page > 0 {
+		db = db.Offset((page - 1) * rowsPerPage).Limit(rowsPerPage)
+	}
+	db.Order(orderBy).Find(&results)
+
+	return results, int(total)
+}
+
+// AssetChangeRecorder 对比资产属性保存前后的内容，生成变更记录
+type AssetChangeRecorder struct {
+	WorkspaceId int
+	AssetType   string
+	Asset       string
+	Port        int
+	TaskId      string
+	oldAttrs    map[string]map[string]time.Time
+	newAttrs    map[string][]string
+	keys        []string
+}
+
+// assetChangeAttrWindow 最近一次保存的属性的时间范围，范围内的属性作为资产当前的属性
+const assetChangeAttrWindow = 10 * time.Minute
+
+// assetChangeIgnoreTag 内容易变、不需要记录变更的属性
+var assetChangeIgnoreTag = map[string]struct{}{
+	"httpx": {},
+	"SOA":   {},
+}
+
+// NewAssetChangeRecorder 创建资产变更记录对象
+func NewAssetChangeRecorder(workspaceId int, assetType, asset string, port int, taskId string) *AssetChangeRecorder {
+	return &AssetChangeRecorder{
+		WorkspaceId: workspaceId,
+		AssetType:   assetType,
+		Asset:       asset,
+		Port:        port,
+		TaskId:      taskId,
+		oldAttrs:    make(map[string]map[string]time.Time),
+		newAttrs:    make(map[string][]string),
+	}
+}
+
+// SetOldAttr 保存前已有的属性及其更新时间
+func (r *AssetChangeRecorder) SetOldAttr(source, tag, content string, updateTime time.Time) {
+	key := source + "\t" + tag
+	if _, ok := r.oldAttrs[key]; !ok {
+		r.oldAttrs[key] = make(map[string]time.Time)
+	}
+	r.oldAttrs[key][content] = updateTime
+}
+
+// currentOldAttrs 获取已有属性中最近一次保存的内容
+func (r *AssetChangeRecorder) currentOldAttrs(key string) (contents map[string]struct{}) {
+	contents = make(map[string]struct{})
+	var latest time.Time
+	for _, t := range r.oldAttrs[key] {
+		if t.After(latest) {
+			latest = t
+		}
+	}
+	for content, t := range r.oldAttrs[key] {
+		if !t.Before(latest.Add(-assetChangeAttrWindow)) {
+			contents[content] = struct{}{}
+		}
+	}
+	return
+}
+
+// SetNewAttr 本次保存的属性
+func (r *AssetChangeRecorder) SetNewAttr(source, tag, content string) {
+	key := source + "\t" + tag
+	if _, ok := r.newAttrs[key]; !ok {
+		r.keys = append(r.keys, key)
+	}
+	for _, c := range r.newAttrs[key] {
+		if c == content {
+			return
+		}
+	}
+	r.newAttrs[key] = append(r.newAttrs[key], content)
+}
+
+// NewChange 生成一条变更记录
+func (r *AssetChangeRecorder) NewChange(changeType, source, tag, content, oldContent string) AssetChange {
+	return AssetChange{
+		WorkspaceId: r.WorkspaceId,
+		AssetType:   r.AssetType,
+		Asset:       r.Asset,
+		Port:        r.Port,
+		ChangeType:  changeType,
+		Source:      source,
+		Tag:         tag,
+		Content:     content,
+		OldContent:  oldContent,
+		TaskId:      r.TaskId,
+	}
+}
+
+// Changes 按来源和属性名，将新的属性与最近一次保存的属性比较：
+// 没有旧内容的属性为新增；有新内容且部分旧内容已不存在的属性为修改；旧内容仍然存在则只记录新增的内容
+func (r *AssetChangeRecorder) Changes() (changes []AssetChange) {
+	for _, key := range r.keys {
+		source, tag, _ := strings.Cut(key, "\t")
+		if _, ok := assetChangeIgnoreTag[tag]; ok {
+			continue
+		}
+		oldContents := r.currentOldAttrs(key)
+		var added []string
+		newContents := make(map[string]struct{})
+		for _, content := range r.newAttrs[key] {
+			newContents[content] = struct{}{}
+			if _, ok := oldContents[content]; !ok {
+				added = append(added, content)
+			}
+		}
+		if len(added) == 0 {
+			continue
+		}
+		var removed []string
+		for content := range oldContents {
+			if _, ok := newContents[content]; !ok {
+				removed = append(removed, content)
+			}
+		}
+		if len(removed) > 0 {
+			sort.Strings(removed)
+			changes = append(changes, r.NewChange(AssetChangeModified, source, tag, strings.Join(added, "\n"), strings.Join(removed, "\n")))
+			continue
+		}
+		for _, content := range added {
+			changes = append(changes, r.NewChange(AssetChangeAdded, source, tag, content, ""))
+		}
+	}
+	return
+}
+
+// Save 保存属性的变更记录
+func (r *AssetChangeRecorder) Save() {
+	for _, c := range r.Changes() {
+		c.Add()
+	}
+}
+
+// SaveAssetChange 保存一条变更记录
+func SaveAssetChange(workspaceId int, assetType, asset string, port int, changeType, source, tag, content, oldContent, taskId string) bool {
+	r := NewAssetChangeRecorder(workspaceId, assetType, asset, port, taskId)
+	c := r.NewChange(changeType, source, tag, content, oldContent)
+	return c.Add()
+}
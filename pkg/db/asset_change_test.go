@@ -0,0 +1,54 @@
+package db
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAssetChange_Gets(t *testing.T) {
+	obj := AssetChange{
+		WorkspaceId: 1,
+		AssetType:   AssetTypeIP,
+		Asset:       "192.168.1.1",
+		Port:        80,
+		ChangeType:  AssetChangeModified,
+		Source:      "httpx",
+		Tag:         "title",
+		Content:     "new title",
+		OldContent:  "old title",
+	}
+	t.Log(obj.Add())
+
+	searchMap := map[string]interface{}{"workspace_id": 1, "asset_type": AssetTypeIP, "asset": "192.168.1.1"}
+	results, count := obj.Gets(searchMap, 1, 10)
+	t.Log(count)
+	for _, r := range results {
+		t.Log(r.Id, r.Port, r.ChangeType, r.Tag, r.OldContent, r.Content, r.CreateDatetime)
+	}
+}
+
+func TestAssetChangeRecorder_Changes(t *testing.T) {
+	now := time.Now()
+	r := NewAssetChangeRecorder(1, AssetTypeDomain, "www.example.com", 0, "task-id")
+	// 历史上解析过的IP不作为当前的内容
+	r.SetOldAttr("domainscan", "A", "1.1.1.1", now.Add(-24*time.Hour))
+	r.SetOldAttr("domainscan", "A", "2.2.2.2", now)
+	r.SetOldAttr("domainscan", "A", "3.3.3.3", now)
+	r.SetOldAttr("httpx", "title", "home", now)
+	r.SetNewAttr("domainscan", "A", "1.1.1.1")
+	r.SetNewAttr("domainscan", "A", "2.2.2.2")
+	r.SetNewAttr("httpx", "title", "home")
+	r.SetNewAttr("httpx", "server", "nginx")
+	r.SetNewAttr("httpx", "httpx", "{}")
+
+	changes := r.Changes()
+	if len(changes) != 2 {
+		t.Fatalf("invalid changes:%v", changes)
+	}
+	if changes[0].ChangeType != AssetChangeModified || changes[0].Content != "1.1.1.1" || changes[0].OldContent != "3.3.3.3" {
+		t.Errorf("invalid modified change:%v", changes[0])
+	}
+	if changes[1].ChangeType != AssetChangeAdded || changes[1].Tag != "server" || changes[1].TaskId != "task-id" {
+		t.Errorf("invalid added change:%v", changes[1])
+	}
+}
@@ -0,0 +1,229 @@
+package db
+
+import (
+	"fmt"
+	"gorm.io/gorm"
+	"strings"
+	"time"
+)
+
+// Certificate TLS证书
+type Certificate struct {
+	Id             int        `gorm:"primaryKey"`
+	WorkspaceId    int        `gorm:"column:workspace_id"`
+	Fingerprint    string     `gorm:"column:fingerprint"`
+	Serial         string     `gorm:"column:serial"`
+	SubjectCN      string     `gorm:"column:subject_cn"`
+	SubjectDN      string     `gorm:"column:subject_dn"`
+	SubjectOrg     string     `gorm:"column:subject_org"`
+	IssuerCN       string     `gorm:"column:issuer_cn"`
+	IssuerDN       string     `gorm:"column:issuer_dn"`
+	IssuerOrg      string     `gorm:"column:issuer_org"`
+	SAN            string     `gorm:"column:san"`
+	NotBefore      *time.Time `gorm:"column:not_before"`
+	NotAfter       *time.Time `gorm:"column:not_after"`
+	CreateDatetime time.Time  `gorm:"column:create_datetime"`
+	UpdateDatetime time.Time  `gorm:"column:update_datetime"`
+}
+
+// CertificateHost 使用证书的IP端口或域名
+type CertificateHost struct {
+	Id             int       `gorm:"primaryKey"`
+	CertId         int       `gorm:"column:cert_id"`
+	WorkspaceId    int       `gorm:"column:workspace_id"`
+	HostType       string    `gorm:"column:host_type"`
+	Host           string    `gorm:"column:host"`
+	Port           int       `gorm:"column:port"`
+	CreateDatetime time.Time `gorm:"column:create_datetime"`
+	UpdateDatetime time.Time `gorm:"column:update_datetime"`
+}
+
+func (*Certificate) TableName() string {
+	return "certificate"
+}
+
+func (*CertificateHost) TableName() string {
+	return "certificate_host"
+}
+
+// ExpireDays 证书距离过期的天数，已过期为负数
+func (c *Certificate) ExpireDays(now time.Time) int {
+	if c.NotAfter == nil {
+		return 0
+	}
+	return int(c.NotAfter.Sub(now).Hours() / 24)
+}
+
+// SANs 证书的SAN列表
+func (c *Certificate) SANs() (sans []string) {
+	for _, s := range strings.Split(c.SAN, "\n") {
+		if s = strings.TrimSpace(s); s != "" {
+			sans = append(sans, s)
+		}
+	}
+	return
+}
+
+// Add 插入一条新的记录
+func (c *Certificate) Add() (success bool) {
+	c.CreateDatetime = time.Now()
+	c.UpdateDatetime = time.Now()
+
+	db := GetDB()
+	defer CloseDB(db)
+	if result := db.Create(c); result.RowsAffected > 0 {
+		return true
+	} else {
+		return false
+	}
+}
+
+// Get 根据ID查询记录
+func (c *Certificate) Get() (success bool) {
+	db := GetDB()
+	defer CloseDB(db)
+	if result := db.First(c, c.Id); result.RowsAffected > 0 {
+		return true
+	} else {
+		return false
+	}
+}
+
+// GetByFingerprint 根据工作空间和指纹精确查询一条记录
+func (c *Certificate) GetByFingerprint() (success bool) {
+	db := GetDB()
+	defer CloseDB(db)
+	if result := db.Where("workspace_id", c.WorkspaceId).Where("fingerprint", c.Fingerprint).First(c); result.RowsAffected > 0 {
+		return true
+	} else {
+		return false
+	}
+}
+
+// Update 更新指定ID的一条记录，列名和内容位于map中
+func (c *Certificate) Update(updateMap map[string]interface{}) (success bool) {
+	updateMap["update_datetime"] = time.Now()
+
+	db := GetDB()
+	defer CloseDB(db)
+	if result := db.Model(c).Updates(updateMap); result.RowsAffected > 0 {
+		return true
+	} else {
+		return false
+	}
+}
+
+// Delete 删除指定主键ID的一条记录，关联的主机由外键级联删除
+func (c *Certificate) Delete() (success bool) {
+	db := GetDB()
+	defer CloseDB(db)
+	if result := db.Delete(c, c.Id); result.RowsAffected > 0 {
+		return true
+	} else {
+		return false
+	}
+}
+
+// SaveOrUpdate 保存证书，证书已存在时更新最后发现的时间
+func (c *Certificate) SaveOrUpdate() (success bool, isAdd bool) {
+	oldRecord := &Certificate{WorkspaceId: c.WorkspaceId, Fingerprint: c.Fingerprint}
+	if oldRecord.GetByFingerprint() {
+		c.Id = oldRecord.Id
+		return c.Update(map[string]interface{}{}), false
+	}
+	return c.Add(), true
+}
+
+// Count 统计指定查询条件的记录数量
+func (c *Certificate) Count(searchMap map[string]interface{}) (count int) {
+	db := c.makeWhere(searchMap).Model(c)
+	defer CloseDB(db)
+	var result int64
+	db.Count(&result)
+	return int(result)
+}
+
+// makeWhere 根据查询条件的不同的字段，组合生成count和search的查询条件
+func (c *Certificate) makeWhere(searchMap map[string]interface{}) *gorm.DB {
+	db := GetDB()
+	for column, value := range searchMap {
+		switch column {
+		case "domain":
+			db = db.Where("subject_cn like ? or san like ?", fmt.Sprintf("%%%s%%", value), fmt.Sprintf("%%%s%%", value))
+		case "issuer":
+			db = db.Where("issuer_cn like ? or issuer_org like ?", fmt.Sprintf("%%%s%%", value), fmt.Sprintf("%%%s%%", value))
+		case "host":
+			certHost := GetDB().Model(&CertificateHost{}).Select("cert_id").Distinct("cert_id").Where("host like ?", fmt.Sprintf("%%%s%%", value))
+			db = db.Where("id in (?)", certHost)
+		case "expire_days":
+			// 指定天数内过期（不包括已过期）的证书
+			db = db.Where("not_after between ? and ?", time.Now(), time.Now().AddDate(0, 0, value.(int)))
+		case "expired":
+			db = db.Where("not_after < ?", time.Now())
+		case "date_delta":
+			db = makeDateDelta(value.(int), "update_datetime", db)
+		default:
+			db = db.Where(column, value)
+		}
+	}
+	return db
+}
+
+// Gets 根据指定的条件，查询满足要求的记录，按过期时间排序
+func (c *Certificate) Gets(searchMap map[string]interface{}, page, rowsPerPage int) (results []Certificate, count int) {
+	orderBy := "not_after asc,id desc"
+
+	db := c.makeWhere(searchMap).Model(c)
+	defer CloseDB(db)
+	//统计满足条件的总记录数
+	var total int64
+	db.Count(&total)
+	//获取分页查询结果
+	if rowsPerPage > 0 && page > 0 {
+		db = db.Offset((page - 1) * rowsPerPage).Limit(rowsPerPage)
+	}
+	db.Order(orderBy).Find(&results)
+
+	return results, int(total)
+}
+
+// SaveOrUpdate 保存证书关联的主机，已存在时更新最后发现的时间
+func (h *CertificateHost) SaveOrUpdate() (success bool) {
+	db := GetDB()
+	defer CloseDB(db)
+
+	oldRecord := CertificateHost{}
+	if result := db.Where("cert_id", h.CertId).Where("host", h.Host).Where("port", h.Port).First(&oldRecord); result.RowsAffected > 0 {
+		h.Id = oldRecord.Id
+		h.UpdateDatetime = time.Now()
+		return db.Model(h).Updates(map[string]interface{}{"update_datetime": h.UpdateDatetime}).RowsAffected > 0
+	}
+	h.CreateDatetime = time.Now()
+	h.UpdateDatetime = time.Now()
+	return db.Create(h).RowsAffected > 0
+}
+
+// GetsByCertId 查询使用证书的全部主机
+func (h *CertificateHost) GetsByCertId() (results []CertificateHost) {
+	db := GetDB()
+	defer CloseDB(db)
+	db.Where("cert_id", h.CertId).Order("host,port").Find(&results)
+	return
+}
+
+// GetsByHost 查询IP或域名使用的证书记录
+func (h *CertificateHost) GetsByHost() (results []CertificateHost) {
+	db := GetDB()
+	defer CloseDB(db)
+	db.Where("workspace_id", h.WorkspaceId).Where("host", h.Host).Order("port").Find(&results)
+	return
+}
+
+// CountByCertId 统计使用证书的主机数量
+func (h *CertificateHost) CountByCertId() (count int) {
+	db := GetDB()
+	defer CloseDB(db)
+	var result int64
+	db.Model(h).Where("cert_id", h.CertId).Count(&result)
+	return int(result)
+}
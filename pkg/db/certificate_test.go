@@ -0,0 +1,33 @@
+package db
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCertificate_ExpireDays(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	notAfter := now.Add(30*24*time.Hour + time.Hour)
+	expired := now.Add(-48 * time.Hour)
+	tests := []struct {
+		cert Certificate
+		want int
+	}{
+		{Certificate{NotAfter: &notAfter}, 30},
+		{Certificate{NotAfter: &expired}, -2},
+		{Certificate{}, 0},
+	}
+	for _, tt := range tests {
+		if got := tt.cert.ExpireDays(now); got != tt.want {
+			t.Errorf("got %d, want %d", got, tt.want)
+		}
+	}
+}
+
+func TestCertificate_SANs(t *testing.T) {
+	c := Certificate{SAN: "www.example.com\n\n *.example.com \n"}
+	sans := c.SANs()
+	if len(sans) != 2 || sans[0] != "www.example.com" || sans[1] != "*.example.com" {
+		t.Errorf("unexpected sans:%v", sans)
+	}
+}
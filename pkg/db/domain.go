@@ -13,6 +13,8 @@ type Domain struct {
 	OrgId          *int      `gorm:"column:org_id"` //使用指针可以处理数据库的NULL（go中传递nil）
 	WorkspaceId    int       `gorm:"column:workspace_id"`
 	PinIndex       int       `gorm:"column:pin_index"`
+	MissCount      int       `gorm:"column:miss_count"`
+	IsStale        bool      `gorm:"column:is_stale"`
 	CreateDatetime time.Time `gorm:"column:create_datetime"`
 	UpdateDatetime time.Time `gorm:"column:update_datetime"`
 }
@@ -74,6 +76,19 @@ func (domain *Domain) Update(updateMap map[string]interface{}) (success bool) {
 	}
 }
 
+// UpdateMissCount 更新连续扫描未发现的次数及是否失效，不改变更新时间（即最后一次发现的时间）
+func (domain *Domain) UpdateMissCount(missCount int, isStale bool) (success bool) {
+	db := GetDB()
+	defer CloseDB(db)
+	if result := db.Model(domain).UpdateColumns(map[string]interface{}{"miss_count": missCount, "is_stale": isStale}); result.Error == nil {
+		domain.MissCount = missCount
+		domain.IsStale = isStale
+		return true
+	} else {
+		return false
+	}
+}
+
 // Delete 删除指定主键ID的一条记录
 func (domain *Domain) Delete() (success bool) {
 	db := GetDB()
@@ -103,6 +118,9 @@ func (domain *Domain) makeWhere(searchMap map[string]interface{}) *gorm.DB {
 		switch column {
 		case "domain":
 			db = makeLike(value, column, db)
+		case "domain_suffix":
+			// 域名及其子域名
+			db = db.Where("domain = ? or domain like ?", value, fmt.Sprintf("%%.%s", value))
 		case "ip":
 			domainAttr := GetDB().Model(&DomainAttr{}).Select("r_id").Distinct("r_id").Where("tag='A' or tag='AAAA'").Where("content like ?", fmt.Sprintf("%%%s%%", value))
 			db = db.Where("id in (?)", domainAttr)
@@ -117,6 +135,10 @@ func (domain *Domain) makeWhere(searchMap map[string]interface{}) *gorm.DB {
 			CloseDB(memoContent)
 		case "date_delta":
 			db = makeDateDelta(value.(int), "update_datetime", db)
+		case "stale":
+			db = db.Where("is_stale = ?", value)
+		case "last_seen_before":
+			db = db.Where("update_datetime < ?", value)
 		case "create_date_delta":
 			db = makeDateDelta(value.(int), "create_datetime", db)
 		case "content":
@@ -166,7 +188,7 @@ func (domain *Domain) SaveOrUpdate() (success bool, isAdd bool) {
 	oldRecord := &Domain{DomainName: domain.DomainName, WorkspaceId: domain.WorkspaceId}
 	//如果记录已存在，则更新指定的字段
 	if oldRecord.GetByDomain() {
-		updateMap := make(map[string]interface{})
+		updateMap := map[string]interface{}{"miss_count": 0, "is_stale": false}
 		if domain.OrgId != nil && *domain.OrgId != 0 {
 			updateMap["org_id"] = domain.OrgId
 		}
@@ -38,6 +38,18 @@ func (domainAttr *DomainAttr) Add() (success bool) {
 	}
 }
 
+// Get 根据ID查询记录
+func (domainAttr *DomainAttr) Get() (success bool) {
+	db := GetDB()
+	defer CloseDB(db)
+
+	if result := db.First(domainAttr, domainAttr.Id); result.RowsAffected > 0 {
+		return true
+	} else {
+		return false
+	}
+}
+
 // GetByDomainAttr 根据域名的属性查询一条记录
 func (domainAttr *DomainAttr) GetByDomainAttr() (success bool) {
 	db := GetDB()
@@ -18,6 +18,8 @@ type Ip struct {
 	Status         string    `gorm:"column:status"`
 	WorkspaceId    int       `gorm:"column:workspace_id"`
 	PinIndex       int       `gorm:"column:pin_index"`
+	MissCount      int       `gorm:"column:miss_count"`
+	IsStale        bool      `gorm:"column:is_stale"`
 	CreateDatetime time.Time `gorm:"column:create_datetime"`
 	UpdateDatetime time.Time `gorm:"column:update_datetime"`
 }
@@ -87,6 +89,19 @@ func (ip *Ip) Update(updateMap map[string]interface{}) (success bool) {
 	}
 }
 
+// UpdateMissCount 更新连续扫描未发现的次数及是否失效，不改变更新时间（即最后一次发现的时间）
+func (ip *Ip) UpdateMissCount(missCount int, isStale bool) (success bool) {
+	db := GetDB()
+	defer CloseDB(db)
+	if result := db.Model(ip).UpdateColumns(map[string]interface{}{"miss_count": missCount, "is_stale": isStale}); result.Error == nil {
+		ip.MissCount = missCount
+		ip.IsStale = isStale
+		return true
+	} else {
+		return false
+	}
+}
+
 // Delete 删除指定主键ID的一条记录
 func (ip *Ip) Delete() (success bool) {
 	db := GetDB()
@@ -132,7 +147,7 @@ func (ip *Ip) SaveOrUpdate() (success bool, isAdd bool) {
 	oldRecord := &Ip{IpName: ip.IpName, WorkspaceId: ip.WorkspaceId}
 	//如果记录已存在，则更新指定的字段
 	if oldRecord.GetByIp() {
-		updateMap := map[string]interface{}{}
+		updateMap := map[string]interface{}{"miss_count": 0, "is_stale": false}
 		if ip.Status != "" {
 			updateMap["status"] = ip.Status
 		}
@@ -208,6 +223,13 @@ func (ip *Ip) makeWhere(searchMap map[string]interface{}) *gorm.DB {
 			CloseDB(memoContent)
 		case "date_delta":
 			db = makeDateDelta(value.(int), "update_datetime", db)
+		case "stale":
+			// IP本身或其中的端口已失效
+			stalePort := GetDB().Model(&Port{}).Select("ip_id").Distinct("ip_id").Where("is_stale = ?", value)
+			db = db.Where("is_stale = ? or id in (?)", value, stalePort)
+			CloseDB(stalePort)
+		case "last_seen_before":
+			db = db.Where("update_datetime < ?", value)
 		case "create_date_delta":
 			daysToHour := 24 * value.(int)
 			dayDelta, err := time.ParseDuration(fmt.Sprintf("-%dh", daysToHour))
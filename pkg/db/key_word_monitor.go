@@ -0,0 +1,225 @@
+package db
+
+import (
+	"gorm.io/gorm"
+	"sort"
+	"strings"
+	"time"
+)
+
+// keyWordHistoryAssetSize 历史记录中保存的新增资产的最大长度
+const keyWordHistoryAssetSize = 65535
+
+// KeyWordAsset 关键词监控发现的资产指纹：IP:端口或域名
+type KeyWordAsset struct {
+	Id             int       `gorm:"primaryKey"`
+	KeyWordId      int       `gorm:"column:key_word_id"`
+	WorkspaceId    int       `gorm:"column:workspace_id"`
+	AssetType      string    `gorm:"column:asset_type"`
+	Asset          string    `gorm:"column:asset"`
+	Engine         string    `gorm:"column:engine"`
+	TaskId         string    `gorm:"column:task_id"`
+	CreateDatetime time.Time `gorm:"column:create_datetime"`
+	UpdateDatetime time.Time `gorm:"column:update_datetime"`
+}
+
+func (*KeyWordAsset) TableName() string {
+	return "key_word_asset"
+}
+
+// Add 插入一条新的记录
+func (a *KeyWordAsset) Add() (success bool) {
+	a.CreateDatetime = time.Now()
+	a.UpdateDatetime = time.Now()
+
+	db := GetDB()
+	defer CloseDB(db)
+	if result := db.Create(a); result.RowsAffected > 0 {
+		return true
+	} else {
+		return false
+	}
+}
+
+// GetsByKeyWordId 获取关键词已发现的全部资产
+func (a *KeyWordAsset) GetsByKeyWordId(keyWordId int) (results []KeyWordAsset) {
+	db := GetDB()
+	defer CloseDB(db)
+
+	db.Where("key_word_id", keyWordId).Find(&results)
+	return
+}
+
+// UpdateSeen 更新关键词再次发现的资产的最后发现时间
+func (a *KeyWordAsset) UpdateSeen(keyWordId int, assets []string) (success bool) {
+	if len(assets) == 0 {
+		return false
+	}
+	db := GetDB()
+	defer CloseDB(db)
+	if result := db.Model(a).Where("key_word_id = ? AND asset IN ?", keyWordId, assets).Update("update_datetime", time.Now()); result.RowsAffected > 0 {
+		return true
+	} else {
+		return false
+	}
+}
+
+// KeyWordHistory 关键词每次检索的结果及新增的资产
+type KeyWordHistory struct {
+	Id             int       `gorm:"primaryKey"`
+	KeyWordId      int       `gorm:"column:key_word_id"`
+	WorkspaceId    int       `gorm:"column:workspace_id"`
+	Engine         string    `gorm:"column:engine"`
+	TaskId         string    `gorm:"column:task_id"`
+	Total          int       `gorm:"column:total"`
+	NewCount       int       `gorm:"column:new_count"`
+	NewAsset       string    `gorm:"column:new_asset"`
+	IsBaseline     bool      `gorm:"column:is_baseline"`
+	CreateDatetime time.Time `gorm:"column:create_datetime"`
+}
+
+func (*KeyWordHistory) TableName() string {
+	return "key_word_history"
+}
+
+// Add 插入一条新的记录
+func (h *KeyWordHistory) Add() (success bool) {
+	h.CreateDatetime = time.Now()
+	if len(h.NewAsset) > keyWordHistoryAssetSize {
+		h.NewAsset = h.NewAsset[:keyWordHistoryAssetSize]
+	}
+
+	db := GetDB()
+	defer CloseDB(db)
+	if result := db.Create(h); result.RowsAffected > 0 {
+		return true
+	} else {
+		return false
+	}
+}
+
+// Count 统计满足条件的记录数
+func (h *KeyWordHistory) Count(searchMap map[string]interface{}) (count int) {
+	db := h.makeWhere(searchMap).Model(h)
+	defer CloseDB(db)
+
+	var total int64
+	db.Count(&total)
+	return int(total)
+}
+
+// makeWhere 根据查询条件的不同的字段，组合生成count和search的查询条件
+func (h *KeyWordHistory) makeWhere(searchMap map[string]interface{}) *gorm.DB {
+	db := GetDB()
+	for column, value := range searchMap {
+		switch column {
+		case "date_delta":
+			db = makeDateDelta(value.(int), "create_datetime", db)
+		default:
+			db = db.Where(column, value)
+		}
+	}
+	return db
+}
+
+// Gets 根据指定的条件，查询满足要求的记录，按时间倒序
+func (h *KeyWordHistory) Gets(searchMap map[string]interface{}, page, rowsPerPage int) (results []KeyWordHistory, count int) {
+	orderBy := "create_datetime desc,id desc"
+
+	db := h.makeWhere(searchMap).Model(h)
+	defer CloseDB(db)
+	//统计满足条件的总记录数
+	var total int64
+	db.Count(&total)
+	//获取分页查询结果
+	if rowsPerPage > 0 && page > 0 {
+		db = db.Offset((page - 1) * rowsPerPage).Limit(rowsPerPage)
+	}
+	db.Order(orderBy).Find(&results)
+
+	return results, int(total)
+}
+
+// KeyWordMonitorResult 关键词一次检索的资产与已发现资产的比较结果
+type KeyWordMonitorResult struct {
+	Total     int
+	NewIPPort []string
+	NewDomain []string
+	Seen      []string
+}
+
+// NewCount 新增的资产数量
+func (r *KeyWordMonitorResult) NewCount() int {
+	return len(r.NewIPPort) + len(r.NewDomain)
+}
+
+// DiffKeyWordAsset 将本次检索的IP:端口及域名与已发现的资产比较，得到新增及再次发现的资产
+func DiffKeyWordAsset(existed map[string]struct{}, ipPorts, domains []string) (r KeyWordMonitorResult) {
+	checked := make(map[string]struct{})
+	diff := func(assets []string) (newAssets []string) {
+		for _, asset := range assets {
+			asset = strings.ToLower(strings.TrimSpace(asset))
+			if asset == "" {
+				continue
+			}
+			if _, ok := checked[asset]; ok {
+				continue
+			}
+			checked[asset] = struct{}{}
+			r.Total++
+			if _, ok := existed[asset]; ok {
+				r.Seen = append(r.Seen, asset)
+			} else {
+				newAssets = append(newAssets, asset)
+			}
+		}
+		sort.Strings(newAssets)
+		return
+	}
+	r.NewIPPort = diff(ipPorts)
+	r.NewDomain = diff(domains)
+	return
+}
+
+// SaveKeyWordMonitorResult 保存关键词一次检索的结果：记录新增的资产及检索历史；
+// 关键词在该搜索引擎上的首次检索作为基线，不作为新增的资产
+func SaveKeyWordMonitorResult(keyWordId, workspaceId int, engine, taskId string, ipPorts, domains []string) (r KeyWordMonitorResult, isBaseline bool) {
+	asset := KeyWordAsset{}
+	existed := make(map[string]struct{})
+	for _, row := range asset.GetsByKeyWordId(keyWordId) {
+		existed[row.Asset] = struct{}{}
+	}
+	history := KeyWordHistory{}
+	isBaseline = history.Count(map[string]interface{}{"key_word_id": keyWordId, "engine": engine}) == 0
+
+	r = DiffKeyWordAsset(existed, ipPorts, domains)
+	for assetType, newAssets := range map[string][]string{AssetTypeIP: r.NewIPPort, AssetTypeDomain: r.NewDomain} {
+		for _, a := range newAssets {
+			newAsset := KeyWordAsset{
+				KeyWordId:   keyWordId,
+				WorkspaceId: workspaceId,
+				AssetType:   assetType,
+				Asset:       a,
+				Engine:      engine,
+				TaskId:      taskId,
+			}
+			newAsset.Add()
+		}
+	}
+	asset.UpdateSeen(keyWordId, r.Seen)
+
+	history = KeyWordHistory{
+		KeyWordId:   keyWordId,
+		WorkspaceId: workspaceId,
+		Engine:      engine,
+		TaskId:      taskId,
+		Total:       r.Total,
+		IsBaseline:  isBaseline,
+	}
+	if !isBaseline {
+		history.NewCount = r.NewCount()
+		history.NewAsset = strings.Join(append(append([]string{}, r.NewIPPort...), r.NewDomain...), "\n")
+	}
+	history.Add()
+	return
+}
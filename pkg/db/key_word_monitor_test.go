@@ -0,0 +1,14 @@
+package db
+
+import "testing"
+
+func TestDiffKeyWordAsset(t *testing.T) {
+	existed := map[string]struct{}{"192.0.2.1:80": {}, "www.example.com": {}}
+	r := DiffKeyWordAsset(existed, []string{"192.0.2.1:80", "192.0.2.2:443", "192.0.2.2:443"}, []string{"WWW.example.com", "new.example.com", ""})
+	if r.Total != 4 || r.NewCount() != 2 || len(r.Seen) != 2 {
+		t.Fatalf("unexpected result:%+v", r)
+	}
+	if r.NewIPPort[0] != "192.0.2.2:443" || r.NewDomain[0] != "new.example.com" {
+		t.Errorf("unexpected new asset:%v %v", r.NewIPPort, r.NewDomain)
+	}
+}
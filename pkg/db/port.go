@@ -7,6 +7,8 @@ type Port struct {
 	IpId           int       `gorm:"column:ip_id"`
 	PortNum        int       `gorm:"column:port"`
 	Status         string    `gorm:"column:status"`
+	MissCount      int       `gorm:"column:miss_count"`
+	IsStale        bool      `gorm:"column:is_stale"`
 	CreateDatetime time.Time `gorm:"column:create_datetime"`
 	UpdateDatetime time.Time `gorm:"column:update_datetime"`
 }
@@ -29,6 +31,18 @@ func (port *Port) Add() (success bool) {
 	}
 }
 
+// Get 根据ID查询记录
+func (port *Port) Get() (success bool) {
+	db := GetDB()
+	defer CloseDB(db)
+
+	if result := db.First(port, port.Id); result.RowsAffected > 0 {
+		return true
+	} else {
+		return false
+	}
+}
+
 // GetByIPPort 根据IP和Port查询指定记录
 func (port *Port) GetByIPPort() (success bool) {
 	db := GetDB()
@@ -64,11 +78,24 @@ func (port *Port) Update(updatedMap map[string]interface{}) (success bool) {
 	}
 }
 
+// UpdateMissCount 更新连续扫描未发现的次数及是否失效，不改变更新时间（即最后一次发现的时间）
+func (port *Port) UpdateMissCount(missCount int, isStale bool) (success bool) {
+	db := GetDB()
+	defer CloseDB(db)
+	if result := db.Model(port).UpdateColumns(map[string]interface{}{"miss_count": missCount, "is_stale": isStale}); result.Error == nil {
+		port.MissCount = missCount
+		port.IsStale = isStale
+		return true
+	} else {
+		return false
+	}
+}
+
 // SaveOrUpdate 保存、更新一条记录
 func (port *Port) SaveOrUpdate() (success bool, isNew bool) {
 	oldRecord := &Port{IpId: port.IpId, PortNum: port.PortNum}
 	if oldRecord.GetByIPPort() {
-		updateMap := map[string]interface{}{}
+		updateMap := map[string]interface{}{"miss_count": 0, "is_stale": false}
 		if port.Status != "" {
 			updateMap["status"] = port.Status
 		}
@@ -36,6 +36,18 @@ func (portAttr *PortAttr) Add() (success bool) {
 	}
 }
 
+// Get 根据ID查询记录
+func (portAttr *PortAttr) Get() (success bool) {
+	db := GetDB()
+	defer CloseDB(db)
+
+	if result := db.First(portAttr, portAttr.Id); result.RowsAffected > 0 {
+		return true
+	} else {
+		return false
+	}
+}
+
 // GetByPortAttr 根据端口和属性查询一条记录
 func (portAttr *PortAttr) GetByPortAttr() (success bool) {
 	db := GetDB()
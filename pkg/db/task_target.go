@@ -0,0 +1,67 @@
+package db
+
+import (
+	"time"
+)
+
+// TaskTarget 任务的目标集合：任务的目标过多时，目标保存在该表中，任务参数中只保存对目标集合的引用
+type TaskTarget struct {
+	Id             int       `gorm:"primaryKey"`
+	TargetId       string    `gorm:"column:target_id"`
+	Target         string    `gorm:"column:target"`
+	TargetCount    int       `gorm:"column:target_count"`
+	WorkspaceId    int       `gorm:"column:workspace_id"`
+	CreateDatetime time.Time `gorm:"column:create_datetime"`
+	UpdateDatetime time.Time `gorm:"column:update_datetime"`
+}
+
+func (*TaskTarget) TableName() string {
+	return "task_target"
+}
+
+// Add 插入一条新的记录，返回主键ID及成功标志
+func (t *TaskTarget) Add() (success bool) {
+	t.CreateDatetime = time.Now()
+	t.UpdateDatetime = time.Now()
+
+	db := GetDB()
+	defer CloseDB(db)
+	if result := db.Create(t); result.RowsAffected > 0 {
+		return true
+	} else {
+		return false
+	}
+}
+
+// GetByTargetId 根据目标集合的ID查询记录
+func (t *TaskTarget) GetByTargetId() (success bool) {
+	db := GetDB()
+	defer CloseDB(db)
+	if result := db.Where("target_id", t.TargetId).First(t); result.RowsAffected > 0 {
+		return true
+	} else {
+		return false
+	}
+}
+
+// GetCountByTargetId 根据目标集合的ID查询记录，不包括目标内容
+func (t *TaskTarget) GetCountByTargetId() (success bool) {
+	db := GetDB()
+	defer CloseDB(db)
+	if result := db.Select("id", "target_id", "target_count", "workspace_id", "create_datetime", "update_datetime").Where("target_id", t.TargetId).First(t); result.RowsAffected > 0 {
+		return true
+	} else {
+		return false
+	}
+}
+
+// DeleteByTargetId 删除指定目标集合ID的记录
+func (t *TaskTarget) DeleteByTargetId() (success bool) {
+	db := GetDB()
+	defer CloseDB(db)
+	if result := db.Where("target_id", t.TargetId).Delete(t); result.RowsAffected > 0 {
+		return true
+	} else {
+		return false
+	}
+}
@@ -3,15 +3,17 @@ package db
 import "time"
 
 type User struct {
-	Id              int       `gorm:"primaryKey"`
-	UserName        string    `gorm:"column:user_name"`
-	UserPassword    string    `gorm:"column:user_password"`
-	UserDescription string    `gorm:"column:user_description"`
-	UserRole        string    `gorm:"column:user_role"`
-	State           string    `gorm:"column:state"`
-	SortOrder       int       `gorm:"column:sort_order"`
-	CreateDatetime  time.Time `gorm:"column:create_datetime"`
-	UpdateDatetime  time.Time `gorm:"column:update_datetime"`
+	Id              int        `gorm:"primaryKey"`
+	UserName        string     `gorm:"column:user_name"`
+	UserPassword    string     `gorm:"column:user_password"`
+	UserDescription string     `gorm:"column:user_description"`
+	UserRole        string     `gorm:"column:user_role"`
+	State           string     `gorm:"column:state"`
+	SortOrder       int        `gorm:"column:sort_order"`
+	LoginFailed     int        `gorm:"column:login_failed"`
+	LockedDatetime  *time.Time `gorm:"column:locked_datetime"`
+	CreateDatetime  time.Time  `gorm:"column:create_datetime"`
+	UpdateDatetime  time.Time  `gorm:"column:update_datetime"`
 }
 
 // TableName 设置数据库关联的表名
@@ -105,6 +107,19 @@ func (u *User) Update(updatedMap map[string]interface{}) (success bool) {
 	}
 }
 
+// UpdateLoginFailed 更新用户连续登录失败的次数及锁定时间
+func (u *User) UpdateLoginFailed(loginFailed int, lockedDatetime *time.Time) (success bool) {
+	db := GetDB()
+	defer CloseDB(db)
+	if result := db.Model(u).UpdateColumns(map[string]interface{}{"login_failed": loginFailed, "locked_datetime": lockedDatetime}); result.RowsAffected == 1 {
+		u.LoginFailed = loginFailed
+		u.LockedDatetime = lockedDatetime
+		return true
+	} else {
+		return false
+	}
+}
+
 // Delete 删除指定主键ID的一条记录
 func (u *User) Delete() (success bool) {
 	db := GetDB()
@@ -0,0 +1,112 @@
+package db
+
+import (
+	"time"
+)
+
+type UserToken struct {
+	Id               int        `gorm:"primaryKey"`
+	UserId           int        `gorm:"column:user_id"`
+	TokenName        string     `gorm:"column:token_name"`
+	TokenHash        string     `gorm:"column:token_hash"`
+	TokenPrefix      string     `gorm:"column:token_prefix"`
+	Workspace        string     `gorm:"column:workspace"`
+	State            string     `gorm:"column:state"`
+	ExpireDatetime   *time.Time `gorm:"column:expire_datetime"`
+	LastUsedDatetime *time.Time `gorm:"column:last_used_datetime"`
+	LastUsedIP       string     `gorm:"column:last_used_ip"`
+	CreateDatetime   time.Time  `gorm:"column:create_datetime"`
+	UpdateDatetime   time.Time  `gorm:"column:update_datetime"`
+}
+
+func (*UserToken) TableName() string {
+	return "user_token"
+}
+
+// Add 插入一条新的记录，返回主键ID及成功标志
+func (t *UserToken) Add() (success bool) {
+	t.CreateDatetime = time.Now()
+	t.UpdateDatetime = time.Now()
+
+	db := GetDB()
+	defer CloseDB(db)
+	if result := db.Create(t); result.RowsAffected > 0 {
+		return true
+	} else {
+		return false
+	}
+}
+
+// Get 根据ID查询记录
+func (t *UserToken) Get() (success bool) {
+	db := GetDB()
+	defer CloseDB(db)
+
+	if result := db.First(t, t.Id); result.RowsAffected > 0 {
+		return true
+	} else {
+		return false
+	}
+}
+
+// GetByHash 根据token的hash查询记录
+func (t *UserToken) GetByHash() (success bool) {
+	db := GetDB()
+	defer CloseDB(db)
+
+	if result := db.Where("token_hash", t.TokenHash).First(t); result.RowsAffected > 0 {
+		return true
+	} else {
+		return false
+	}
+}
+
+// GetsByUserId 获取指定用户的全部token
+func (t *UserToken) GetsByUserId(userId int) (results []UserToken) {
+	orderBy := "create_datetime desc"
+
+	db := GetDB()
+	defer CloseDB(db)
+	db.Order(orderBy).Where("user_id", userId).Find(&results)
+
+	return
+}
+
+// Update 更新指定ID的一条记录，列名和内容位于map中
+func (t *UserToken) Update(updateMap map[string]interface{}) (success bool) {
+	updateMap["update_datetime"] = time.Now()
+
+	db := GetDB()
+	defer CloseDB(db)
+	if result := db.Model(t).Updates(updateMap); result.RowsAffected > 0 {
+		return true
+	} else {
+		return false
+	}
+}
+
+// UpdateLastUsed 更新token最近使用的时间和IP
+func (t *UserToken) UpdateLastUsed(ip string) (success bool) {
+	now := time.Now()
+
+	db := GetDB()
+	defer CloseDB(db)
+	if result := db.Model(t).UpdateColumns(map[string]interface{}{"last_used_datetime": now, "last_used_ip": ip}); result.RowsAffected > 0 {
+		t.LastUsedDatetime = &now
+		t.LastUsedIP = ip
+		return true
+	} else {
+		return false
+	}
+}
+
+// Delete 删除指定主键ID的一条记录
+func (t *UserToken) Delete() (success bool) {
+	db := GetDB()
+	defer CloseDB(db)
+	if result := db.Delete(t, t.Id); result.RowsAffected > 0 {
+		return true
+	} else {
+		return false
+	}
+}
@@ -4,9 +4,36 @@ import (
 	"fmt"
 	"github.com/hanc00l/nemo_go/pkg/utils"
 	"gorm.io/gorm"
+	"strings"
 	"time"
 )
 
+const (
+	VulStatusNew           = "new"
+	VulStatusConfirmed     = "confirmed"
+	VulStatusFalsePositive = "false-positive"
+	VulStatusFixed         = "fixed"
+	VulStatusRiskAccepted  = "risk-accepted"
+
+	VulSeverityCritical = "critical"
+	VulSeverityHigh     = "high"
+	VulSeverityMedium   = "medium"
+	VulSeverityLow      = "low"
+	VulSeverityInfo     = "info"
+)
+
+// VulStatusTransition 漏洞状态允许的变更：key为当前状态，value为可以变更的状态
+var VulStatusTransition = map[string][]string{
+	VulStatusNew:           {VulStatusConfirmed, VulStatusFalsePositive},
+	VulStatusConfirmed:     {VulStatusFixed, VulStatusRiskAccepted, VulStatusFalsePositive},
+	VulStatusFalsePositive: {VulStatusNew},
+	VulStatusFixed:         {VulStatusNew, VulStatusConfirmed},
+	VulStatusRiskAccepted:  {VulStatusConfirmed, VulStatusFixed},
+}
+
+// VulSeverityList 漏洞的严重程度，按从高到低排列
+var VulSeverityList = []string{VulSeverityCritical, VulSeverityHigh, VulSeverityMedium, VulSeverityLow, VulSeverityInfo}
+
 type Vulnerability struct {
 	Id             int       `gorm:"primaryKey"`
 	Target         string    `gorm:"column:target"`
@@ -15,11 +42,42 @@ type Vulnerability struct {
 	Source         string    `gorm:"column:source"`
 	Extra          string    `gorm:"column:extra"`
 	Hash           string    `gorm:"column:hash"`
+	VulKey         string    `gorm:"column:vul_key"`
+	VulName        string    `gorm:"column:vul_name"`
+	CVE            string    `gorm:"column:cve"`
+	CWE            string    `gorm:"column:cwe"`
+	Engines        string    `gorm:"column:engines"`
+	Status         string    `gorm:"column:status"`
+	Severity       string    `gorm:"column:severity"`
+	Assignee       string    `gorm:"column:assignee"`
 	WorkspaceId    int       `gorm:"column:workspace_id"`
 	CreateDatetime time.Time `gorm:"column:create_datetime"`
 	UpdateDatetime time.Time `gorm:"column:update_datetime"`
 }
 
+// CheckVulStatusTransition 检查漏洞状态的变更是否允许
+func CheckVulStatusTransition(oldStatus, newStatus string) bool {
+	if oldStatus == "" {
+		oldStatus = VulStatusNew
+	}
+	for _, s := range VulStatusTransition[oldStatus] {
+		if s == newStatus {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckVulSeverity 检查漏洞的严重程度是否有效
+func CheckVulSeverity(severity string) bool {
+	for _, s := range VulSeverityList {
+		if s == severity {
+			return true
+		}
+	}
+	return false
+}
+
 func (*Vulnerability) TableName() string {
 	return "vulnerability"
 }
@@ -29,6 +87,12 @@ func (vul *Vulnerability) Add() (success bool) {
 	vul.CreateDatetime = time.Now()
 	vul.UpdateDatetime = time.Now()
 	vul.Hash = utils.MD5(fmt.Sprintf("%s%s%s%s", vul.Target, vul.Url, vul.PocFile, vul.Source))
+	if vul.Status == "" {
+		vul.Status = VulStatusNew
+	}
+	if vul.Engines == "" {
+		vul.Engines = vul.Source
+	}
 
 	db := GetDB()
 	defer CloseDB(db)
@@ -64,6 +128,20 @@ func (vul *Vulnerability) GetByVulnerability() (success bool) {
 	}
 }
 
+// GetByVulKey 根据跨扫描引擎的漏洞标识查询一条记录
+func (vul *Vulnerability) GetByVulKey() (success bool) {
+	db := GetDB()
+	defer CloseDB(db)
+	if vul.WorkspaceId > 0 {
+		db = db.Where("workspace_id", vul.WorkspaceId)
+	}
+	if result := db.Where("vul_key = ?", vul.VulKey).First(vul); result.RowsAffected > 0 {
+		return true
+	} else {
+		return false
+	}
+}
+
 // GetsByTarget 根据Target查询记录，返回查询结果数组
 func (vul *Vulnerability) GetsByTarget() (results []Vulnerability) {
 	orderBy := "update_datetime desc"
@@ -119,6 +197,12 @@ func (vul *Vulnerability) makeWhere(searchMap map[string]interface{}) *gorm.DB {
 			db = makeLike(value, column, db)
 		case "date_delta":
 			db = makeDateDelta(value.(int), "update_datetime", db)
+		case "assignee":
+			db = makeLike(value, column, db)
+		case "cve":
+			db = makeLike(value, column, db)
+		case "vul_name":
+			db = makeLike(value, column, db)
 		default:
 			db = db.Where(column, value)
 		}
@@ -144,7 +228,7 @@ func (vul *Vulnerability) Gets(searchMap map[string]interface{}, page, rowsPerPa
 	return results, int(total)
 }
 
-// SaveOrUpdate 保存、更新一条记录
+// SaveOrUpdate 保存、更新一条记录：先按同一扫描引擎的漏洞匹配，再按跨扫描引擎的漏洞标识匹配
 func (vul *Vulnerability) SaveOrUpdate() (success bool, isAdd bool) {
 	oldRecord := &Vulnerability{
 		Target:  vul.Target,
@@ -153,14 +237,69 @@ func (vul *Vulnerability) SaveOrUpdate() (success bool, isAdd bool) {
 		Source:  vul.Source,
 		Extra:   vul.Extra,
 	}
-	if oldRecord.GetByVulnerability() {
-		updateMap := map[string]interface{}{}
-		if vul.Extra != "" {
-			updateMap["extra"] = vul.Extra
-		}
-		vul.Id = oldRecord.Id
-		return vul.Update(updateMap), false
-	} else {
+	found := oldRecord.GetByVulnerability()
+	if !found && vul.VulKey != "" {
+		oldRecord = &Vulnerability{VulKey: vul.VulKey, WorkspaceId: vul.WorkspaceId}
+		found = oldRecord.GetByVulKey()
+	}
+	if !found {
 		return vul.Add(), true
 	}
+	updateMap := map[string]interface{}{}
+	// 其它扫描引擎发现的同一漏洞，保留原有的详情
+	if vul.Extra != "" && oldRecord.Source == vul.Source {
+		updateMap["extra"] = vul.Extra
+	}
+	if vul.Severity != "" && oldRecord.Severity == "" {
+		updateMap["severity"] = vul.Severity
+	}
+	if vul.VulKey != "" && oldRecord.VulKey == "" {
+		updateMap["vul_key"] = vul.VulKey
+	}
+	if vul.VulName != "" && (oldRecord.VulName == "" || oldRecord.VulName == oldRecord.PocFile) {
+		updateMap["vul_name"] = vul.VulName
+	}
+	if cve := mergeCommaList(oldRecord.CVE, vul.CVE); cve != oldRecord.CVE {
+		updateMap["cve"] = cve
+	}
+	if cwe := mergeCommaList(oldRecord.CWE, vul.CWE); cwe != oldRecord.CWE {
+		updateMap["cwe"] = cwe
+	}
+	oldEngines := oldRecord.Engines
+	if oldEngines == "" {
+		oldEngines = oldRecord.Source
+	}
+	if engines := mergeCommaList(oldEngines, vul.Source); engines != oldRecord.Engines {
+		updateMap["engines"] = engines
+	}
+	vul.Id = oldRecord.Id
+	// 已修复的漏洞再次被发现，重新打开
+	if oldRecord.Status == VulStatusFixed {
+		updateMap["status"] = VulStatusNew
+		comment := VulnerabilityComment{
+			RelatedId: oldRecord.Id,
+			OldStatus: oldRecord.Status,
+			NewStatus: VulStatusNew,
+			Content:   "漏洞被再次发现",
+		}
+		comment.Add()
+	}
+	return vul.Update(updateMap), false
+}
+
+// mergeCommaList 合并以逗号分隔的列表，保持原有的顺序并去重
+func mergeCommaList(list string, values string) string {
+	var result []string
+	set := make(map[string]struct{})
+	for _, v := range strings.Split(list+","+values, ",") {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+		if _, ok := set[v]; !ok {
+			set[v] = struct{}{}
+			result = append(result, v)
+		}
+	}
+	return strings.Join(result, ",")
 }
@@ -0,0 +1,42 @@
+package db
+
+import "time"
+
+// VulnerabilityComment 漏洞的处理记录：评论及状态的变更
+type VulnerabilityComment struct {
+	Id             int       `gorm:"primaryKey"`
+	RelatedId      int       `gorm:"column:r_id"`
+	UserName       string    `gorm:"column:user_name"`
+	OldStatus      string    `gorm:"column:old_status"`
+	NewStatus      string    `gorm:"column:new_status"`
+	Content        string    `gorm:"column:content"`
+	CreateDatetime time.Time `gorm:"column:create_datetime"`
+}
+
+func (*VulnerabilityComment) TableName() string {
+	return "vulnerability_comment"
+}
+
+// Add 插入一条新的记录
+func (comment *VulnerabilityComment) Add() (success bool) {
+	comment.CreateDatetime = time.Now()
+	if len(comment.Content) > AttrContentSize {
+		comment.Content = comment.Content[:AttrContentSize]
+	}
+
+	db := GetDB()
+	defer CloseDB(db)
+	if result := db.Create(comment); result.RowsAffected > 0 {
+		return true
+	} else {
+		return false
+	}
+}
+
+// GetsByRelatedId 根据漏洞的ID查询处理记录，按时间顺序排列
+func (comment *VulnerabilityComment) GetsByRelatedId() (results []VulnerabilityComment) {
+	db := GetDB()
+	defer CloseDB(db)
+	db.Where("r_id", comment.RelatedId).Order("create_datetime,id").Find(&results)
+	return
+}
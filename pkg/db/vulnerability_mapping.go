@@ -0,0 +1,84 @@
+package db
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// VulnerabilityMapping 漏洞的标准化定义，及各扫描引擎对应的poc
+type VulnerabilityMapping struct {
+	Id             int       `gorm:"primaryKey"`
+	Name           string    `gorm:"column:name"`
+	CVE            string    `gorm:"column:cve"`
+	CWE            string    `gorm:"column:cwe"`
+	Severity       string    `gorm:"column:severity"`
+	Pocs           string    `gorm:"column:pocs"` //JSON格式：扫描引擎->poc列表
+	CreateDatetime time.Time `gorm:"column:create_datetime"`
+	UpdateDatetime time.Time `gorm:"column:update_datetime"`
+}
+
+func (*VulnerabilityMapping) TableName() string {
+	return "vulnerability_mapping"
+}
+
+// Add 插入一条新的记录
+func (mapping *VulnerabilityMapping) Add() (success bool) {
+	mapping.CreateDatetime = time.Now()
+	mapping.UpdateDatetime = time.Now()
+
+	db := GetDB()
+	defer CloseDB(db)
+	if result := db.Create(mapping); result.RowsAffected > 0 {
+		return true
+	} else {
+		return false
+	}
+}
+
+// Delete 删除指定ID的一条记录
+func (mapping *VulnerabilityMapping) Delete() (success bool) {
+	db := GetDB()
+	defer CloseDB(db)
+	if result := db.Delete(mapping, mapping.Id); result.RowsAffected > 0 {
+		return true
+	} else {
+		return false
+	}
+}
+
+// GetsAll 获取全部的映射记录
+func (mapping *VulnerabilityMapping) GetsAll() (results []VulnerabilityMapping) {
+	db := GetDB()
+	defer CloseDB(db)
+	db.Order("id").Find(&results)
+	return
+}
+
+// GetCVE 获取CVE编号列表
+func (mapping *VulnerabilityMapping) GetCVE() []string {
+	return splitMappingField(mapping.CVE)
+}
+
+// GetCWE 获取CWE编号列表
+func (mapping *VulnerabilityMapping) GetCWE() []string {
+	return splitMappingField(mapping.CWE)
+}
+
+// GetPocs 获取各扫描引擎对应的poc
+func (mapping *VulnerabilityMapping) GetPocs() (pocs map[string][]string) {
+	pocs = make(map[string][]string)
+	if mapping.Pocs != "" {
+		json.Unmarshal([]byte(mapping.Pocs), &pocs)
+	}
+	return
+}
+
+func splitMappingField(s string) (values []string) {
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return
+}
@@ -14,3 +14,37 @@ func TestVulnerability_Add(t *testing.T) {
 	}
 	t.Log(vul.SaveOrUpdate())
 }
+
+func TestCheckVulStatusTransition(t *testing.T) {
+	tests := []struct {
+		oldStatus, newStatus string
+		want                 bool
+	}{
+		{"", VulStatusConfirmed, true},
+		{VulStatusNew, VulStatusConfirmed, true},
+		{VulStatusNew, VulStatusFixed, false},
+		{VulStatusConfirmed, VulStatusFixed, true},
+		{VulStatusConfirmed, VulStatusConfirmed, false},
+		{VulStatusFalsePositive, VulStatusNew, true},
+		{VulStatusFixed, VulStatusRiskAccepted, false},
+		{VulStatusRiskAccepted, VulStatusFixed, true},
+		{VulStatusNew, "unknown", false},
+	}
+	for _, tt := range tests {
+		if got := CheckVulStatusTransition(tt.oldStatus, tt.newStatus); got != tt.want {
+			t.Errorf("%s -> %s: got %v, want %v", tt.oldStatus, tt.newStatus, got, tt.want)
+		}
+	}
+}
+
+func TestMergeCommaList(t *testing.T) {
+	if s := mergeCommaList("xray", "nuclei"); s != "xray,nuclei" {
+		t.Error(s)
+	}
+	if s := mergeCommaList("xray,nuclei", "xray"); s != "xray,nuclei" {
+		t.Error(s)
+	}
+	if s := mergeCommaList("", "goby"); s != "goby" {
+		t.Error(s)
+	}
+}
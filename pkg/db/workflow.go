@@ -0,0 +1,116 @@
+package db
+
+import (
+	"gorm.io/gorm"
+	"time"
+)
+
+type Workflow struct {
+	Id             int       `gorm:"primaryKey"`
+	Name           string    `gorm:"column:name"`
+	Description    string    `gorm:"column:description"`
+	Definition     string    `gorm:"column:definition"`
+	SortOrder      int       `gorm:"column:sort_order"`
+	CreateDatetime time.Time `gorm:"column:create_datetime"`
+	UpdateDatetime time.Time `gorm:"column:update_datetime"`
+}
+
+func (*Workflow) TableName() string {
+	return "workflow"
+}
+
+// Add 插入一条新的记录，返回主键ID及成功标志
+func (w *Workflow) Add() (success bool) {
+	w.CreateDatetime = time.Now()
+	w.UpdateDatetime = time.Now()
+
+	db := GetDB()
+	defer CloseDB(db)
+	if result := db.Create(w); result.RowsAffected > 0 {
+		return true
+	} else {
+		return false
+	}
+}
+
+// Get 根据ID查询记录
+func (w *Workflow) Get() (success bool) {
+	db := GetDB()
+	defer CloseDB(db)
+
+	if result := db.First(w, w.Id); result.RowsAffected > 0 {
+		return true
+	} else {
+		return false
+	}
+}
+
+// GetByName 根据名称查询记录
+func (w *Workflow) GetByName() (success bool) {
+	db := GetDB()
+	defer CloseDB(db)
+
+	if result := db.Where("name", w.Name).First(w); result.RowsAffected > 0 {
+		return true
+	} else {
+		return false
+	}
+}
+
+// Update 更新指定ID的一条记录，列名和内容位于map中
+func (w *Workflow) Update(updateMap map[string]interface{}) (success bool) {
+	updateMap["update_datetime"] = time.Now()
+
+	db := GetDB()
+	defer CloseDB(db)
+	if result := db.Model(w).Updates(updateMap); result.RowsAffected > 0 {
+		return true
+	} else {
+		return false
+	}
+}
+
+// Delete 删除指定主键ID的一条记录
+func (w *Workflow) Delete() (success bool) {
+	db := GetDB()
+	defer CloseDB(db)
+	if result := db.Delete(w, w.Id); result.RowsAffected > 0 {
+		return true
+	} else {
+		return false
+	}
+}
+
+// makeWhere 根据查询条件的不同的字段，组合生成count和search的查询条件
+func (w *Workflow) makeWhere(searchMap map[string]interface{}) *gorm.DB {
+	db := GetDB()
+	for column, value := range searchMap {
+		switch column {
+		case "name":
+			db = makeLike(value, column, db)
+		case "description":
+			db = makeLike(value, column, db)
+		default:
+			db = db.Where(column, value)
+		}
+	}
+	return db
+}
+
+// Gets 根据指定的条件，查询满足要求的记录
+func (w *Workflow) Gets(searchMap map[string]interface{}, page, rowsPerPage int) (results []Workflow, count int) {
+	orderBy := "sort_order desc,name"
+
+	db := w.makeWhere(searchMap).Model(w)
+	defer CloseDB(db)
+	//统计满足条件的总记录数
+	var total int64
+	db.Count(&total)
+	//获取分页查询结果
+	if rowsPerPage > 0 && page > 0 {
+		db = db.Offset((page - 1) * rowsPerPage).Limit(rowsPerPage)
+	}
+	db.Order(orderBy).Find(&results)
+
+	return results, int(total)
+}
@@ -8,7 +8,7 @@ import (
 var syncFileList = []string{"worker_linux_amd64", "version.txt", "conf", "thirdparty"}
 
 // syncFileBlackList 不需要、禁止同步的文件黑名单
-var syncFileBlackList = []string{"conf/server.yml", "conf/app.conf"}
+var syncFileBlackList = []string{"conf/server.yml", "conf/app.conf", "thirdparty/onlineapi/onlineapi.cache"}
 
 var (
 	// TLSEnabled 是否启用TLS加密
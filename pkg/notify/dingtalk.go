@@ -1,15 +1,16 @@
 package notify
 
 import (
-	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
-	"net/http"
+	"github.com/hanc00l/nemo_go/pkg/conf"
 )
 
 // https://open.dingtalk.com/document/group/custom-robot-access
+// https://open.dingtalk.com/document/orgapp/custom-bot-send-message-type
+
+var dingTalkAPIUrl = "https://oapi.dingtalk.com"
 
 type DingTalk struct {
 }
@@ -19,28 +20,50 @@ type DingTalkResponseInfo struct {
 	Message string `json:"errmsg"`
 }
 
-func (d *DingTalk) Send(token string, message string) (err error) {
-	url := fmt.Sprintf("https://oapi.dingtalk.com/robot/send?access_token=%s", token)
+func (d *DingTalk) Send(config conf.Notify, message string) (err error) {
+	switch config.Format {
+	case FormatMarkdown, FormatCard:
+		return d.SendCard(config, message, "")
+	}
 	//-d '{"msgtype": "text","text": {"content":"Nemo任务通知：\n我就是我, 是不一样的烟火"}}'
 	text := make(map[string]string)
-	text["content"] = fmt.Sprintf("Nemo任务通知：\n%s", message)
+	text["content"] = textMessage(config, message)
 	data := make(map[string]interface{})
 	data["text"] = text
 	data["msgtype"] = "text"
-	b, _ := json.Marshal(data)
-	var resp *http.Response
-	if resp, err = http.Post(url, "application/json", bytes.NewBuffer(b)); err != nil {
-		return
+	return d.post(config, data)
+}
+
+// SendCard 发送markdown消息，有链接时发送带按钮的actionCard消息
+func (d *DingTalk) SendCard(config conf.Notify, message, link string) (err error) {
+	data := make(map[string]interface{})
+	if config.Format == FormatCard && len(link) > 0 {
+		data["msgtype"] = "actionCard"
+		data["actionCard"] = map[string]string{
+			"title":       messageTitle(config),
+			"text":        message,
+			"singleTitle": cardButtonText,
+			"singleURL":   link,
+		}
+	} else {
+		data["msgtype"] = "markdown"
+		data["markdown"] = map[string]string{
+			"title": messageTitle(config),
+			"text":  message,
+		}
 	}
-	defer func(Body io.ReadCloser) {
-		err = Body.Close()
-	}(resp.Body)
-	var responseData []byte
-	if responseData, err = io.ReadAll(resp.Body); err != nil {
+	return d.post(config, data)
+}
+
+func (d *DingTalk) post(config conf.Notify, data map[string]interface{}) (err error) {
+	url := fmt.Sprintf("%s/robot/send?access_token=%s", dingTalkAPIUrl, config.Token)
+	b, _ := json.Marshal(data)
+	_, content, err := postJSON(url, b, nil)
+	if err != nil {
 		return
 	}
 	var msgData DingTalkResponseInfo
-	if err = json.Unmarshal(responseData, &msgData); err != nil {
+	if err = json.Unmarshal(content, &msgData); err != nil {
 		return
 	}
 	//{"errcode":0,"errmsg":"ok"}
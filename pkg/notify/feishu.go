@@ -1,15 +1,16 @@
 package notify
 
 import (
-	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
-	"net/http"
+	"github.com/hanc00l/nemo_go/pkg/conf"
 )
 
 // https://open.feishu.cn/document/ukTMukTMukTM/ucTM5YjL3ETO24yNxkjN
+// https://open.feishu.cn/document/common-capabilities/message-card/message-cards-content/using-markdown-tags
+
+var feishuAPIUrl = "https://open.feishu.cn"
 
 type Feishu struct {
 }
@@ -19,34 +20,68 @@ type FeishuResponseInfo struct {
 	Message string `json:"StatusMessage"`
 }
 
-func (f *Feishu) Send(token string, message string) (err error) {
-	url := fmt.Sprintf("https://open.feishu.cn/open-apis/bot/v2/hook/%s", token)
+func (f *Feishu) Send(config conf.Notify, message string) (err error) {
+	switch config.Format {
+	case FormatMarkdown, FormatCard:
+		// 飞书的文本消息不支持markdown，使用消息卡片
+		return f.SendCard(config, message, "")
+	}
 	//-d '{"msg_type":"text","content":{"text":"request example"}}' \
 	content := make(map[string]string)
-	content["text"] = fmt.Sprintf("Nemo任务通知：\n%s", message)
+	content["text"] = textMessage(config, message)
 	data := make(map[string]interface{})
 	data["content"] = content
 	data["msg_type"] = "text"
-	b, _ := json.Marshal(data)
-	var resp *http.Response
-	if resp, err = http.Post(url, "application/json", bytes.NewBuffer(b)); err != nil {
-		return
+	return f.post(config, data)
+}
+
+// SendCard 发送markdown内容的消息卡片，有链接时增加按钮
+func (f *Feishu) SendCard(config conf.Notify, message, link string) (err error) {
+	elements := []interface{}{
+		map[string]string{"tag": "markdown", "content": message},
 	}
-	defer func(Body io.ReadCloser) {
-		err = Body.Close()
-	}(resp.Body)
-	var responseData []byte
-	if responseData, err = io.ReadAll(resp.Body); err != nil {
+	if len(link) > 0 {
+		elements = append(elements, map[string]interface{}{
+			"tag": "action",
+			"actions": []interface{}{
+				map[string]interface{}{
+					"tag":  "button",
+					"type": "primary",
+					"url":  link,
+					"text": map[string]string{"tag": "plain_text", "content": cardButtonText},
+				},
+			},
+		})
+	}
+	card := map[string]interface{}{
+		"config": map[string]bool{"wide_screen_mode": true},
+		"header": map[string]interface{}{
+			"template": "blue",
+			"title":    map[string]string{"tag": "plain_text", "content": messageTitle(config)},
+		},
+		"elements": elements,
+	}
+	data := make(map[string]interface{})
+	data["msg_type"] = "interactive"
+	data["card"] = card
+	return f.post(config, data)
+}
+
+func (f *Feishu) post(config conf.Notify, data map[string]interface{}) (err error) {
+	url := fmt.Sprintf("%s/open-apis/bot/v2/hook/%s", feishuAPIUrl, config.Token)
+	b, _ := json.Marshal(data)
+	_, content, err := postJSON(url, b, nil)
+	if err != nil {
 		return
 	}
 	var msgData FeishuResponseInfo
-	if err = json.Unmarshal(responseData, &msgData); err != nil {
+	if err = json.Unmarshal(content, &msgData); err != nil {
 		return
 	}
 	// "Extra": null, "StatusCode": 0, "StatusMessage": "success"
 	// "code": 9499, "msg": "Bad Request", "data": {}
 	if msgData.Code != 0 && msgData.Message != "success" {
-		err = errors.New(string(responseData))
+		err = errors.New(string(content))
 	}
 	return
 }
@@ -0,0 +1,243 @@
+package notify
+
+import (
+	"fmt"
+	"github.com/hanc00l/nemo_go/pkg/conf"
+	"github.com/hanc00l/nemo_go/pkg/db"
+	"github.com/hanc00l/nemo_go/pkg/logging"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	EventVulnerability = "vulnerability"
+	EventPort          = "port"
+	EventDomain        = "domain"
+	EventWorkerOffline = "worker_offline"
+)
+
+const (
+	// defaultRuleInterval 规则默认的聚合时间间隔（分钟）
+	defaultRuleInterval = 5
+	// ruleMaxEvents 一条消息中列出的最大事件数量
+	ruleMaxEvents = 50
+)
+
+// Event 资产或系统的事件
+type Event struct {
+	Type        string
+	WorkspaceId int
+	OrgId       *int
+	Target      string
+	Port        int
+	Severity    string
+	Content     string
+}
+
+// String 事件的描述
+func (e Event) String() string {
+	switch e.Type {
+	case EventVulnerability:
+		return fmt.Sprintf("[%s]%s %s", e.Severity, e.Target, e.Content)
+	case EventPort:
+		if e.Content != "" {
+			return fmt.Sprintf("%s:%d %s", e.Target, e.Port, e.Content)
+		}
+		return fmt.Sprintf("%s:%d", e.Target, e.Port)
+	case EventWorkerOffline:
+		return fmt.Sprintf("%s %s", e.Target, e.Content)
+	}
+	return e.Target
+}
+
+// ruleBuffer 规则在聚合时间内匹配的事件
+type ruleBuffer struct {
+	rule    conf.NotifyRule
+	events  []Event
+	seen    map[string]struct{}
+	dropped int
+}
+
+// ruleAggregator 按规则聚合事件
+type ruleAggregator struct {
+	sync.Mutex
+	buffers map[string]*ruleBuffer
+}
+
+var aggregator = &ruleAggregator{buffers: make(map[string]*ruleBuffer)}
+
+// Publish 发布一个事件，匹配通知规则的事件在聚合时间结束后发送
+func Publish(e Event) {
+	for i, rule := range conf.GlobalServerConfig().NotifyRule {
+		if !MatchRule(rule, e) {
+			continue
+		}
+		key := fmt.Sprintf("%d:%s", i, rule.Name)
+		if aggregator.add(key, rule, e) {
+			interval := rule.Interval
+			if interval <= 0 {
+				interval = defaultRuleInterval
+			}
+			time.AfterFunc(time.Duration(interval)*time.Minute, func() {
+				if b := aggregator.flush(key); b != nil {
+					sendRuleMessage(b.rule, formatRuleMessage(b))
+				}
+			})
+		}
+	}
+}
+
+// add 将事件加入规则的聚合中，返回是否为新的聚合
+func (a *ruleAggregator) add(key string, rule conf.NotifyRule, e Event) (isNew bool) {
+	a.Lock()
+	defer a.Unlock()
+
+	b, ok := a.buffers[key]
+	if !ok {
+		b = &ruleBuffer{rule: rule, seen: make(map[string]struct{})}
+		a.buffers[key] = b
+		isNew = true
+	}
+	s := e.String()
+	if _, ok = b.seen[s]; ok {
+		return
+	}
+	b.seen[s] = struct{}{}
+	if len(b.events) < ruleMaxEvents {
+		b.events = append(b.events, e)
+	} else {
+		b.dropped++
+	}
+	return
+}
+
+// flush 取出规则聚合的事件
+func (a *ruleAggregator) flush(key string) *ruleBuffer {
+	a.Lock()
+	defer a.Unlock()
+
+	b, ok := a.buffers[key]
+	if !ok {
+		return nil
+	}
+	delete(a.buffers, key)
+	return b
+}
+
+// MatchRule 判断事件是否匹配通知规则
+func MatchRule(rule conf.NotifyRule, e Event) bool {
+	if rule.Disable || rule.Event != e.Type {
+		return false
+	}
+	if rule.WorkspaceId > 0 && e.Type != EventWorkerOffline && rule.WorkspaceId != e.WorkspaceId {
+		return false
+	}
+	if rule.OrgId > 0 && (e.OrgId == nil || rule.OrgId != *e.OrgId) {
+		return false
+	}
+	switch e.Type {
+	case EventVulnerability:
+		if rule.Severity != "" && severityRank(e.Severity) > severityRank(rule.Severity) {
+			return false
+		}
+	case EventPort:
+		if len(rule.Ports) > 0 {
+			var found bool
+			for _, p := range rule.Ports {
+				if p == e.Port {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return false
+			}
+		}
+	case EventDomain:
+		if len(rule.Domains) > 0 {
+			var found bool
+			domain := strings.ToLower(e.Target)
+			for _, d := range rule.Domains {
+				d = strings.ToLower(strings.TrimSpace(d))
+				if domain == d || strings.HasSuffix(domain, "."+d) {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// CheckRule 检查通知规则的配置是否有效
+func CheckRule(rule conf.NotifyRule) error {
+	switch rule.Event {
+	case EventVulnerability, EventPort, EventDomain, EventWorkerOffline:
+	default:
+		return fmt.Errorf("invalid event:%s", rule.Event)
+	}
+	if rule.Severity != "" && !db.CheckVulSeverity(rule.Severity) {
+		return fmt.Errorf("invalid severity:%s", rule.Severity)
+	}
+	for _, s := range rule.Senders {
+		if newSender(s) == nil {
+			return fmt.Errorf("invalid notify sender:%s", s)
+		}
+	}
+	if rule.Interval < 0 {
+		return fmt.Errorf("invalid interval:%d", rule.Interval)
+	}
+	return nil
+}
+
+// severityRank 漏洞严重程度的排序，越严重值越小，未知的等级排在最后
+func severityRank(severity string) int {
+	for i, s := range db.VulSeverityList {
+		if s == severity {
+			return i
+		}
+	}
+	return len(db.VulSeverityList)
+}
+
+// formatRuleMessage 生成规则聚合的事件消息
+func formatRuleMessage(b *ruleBuffer) string {
+	var sb strings.Builder
+	name := b.rule.Name
+	if name == "" {
+		name = b.rule.Event
+	}
+	sb.WriteString(fmt.Sprintf("%s->event:%s,count:%d", name, b.rule.Event, len(b.events)+b.dropped))
+	lines := make([]string, 0, len(b.events))
+	for _, e := range b.events {
+		lines = append(lines, e.String())
+	}
+	sort.Strings(lines)
+	for _, line := range lines {
+		sb.WriteString("  \n")
+		sb.WriteString(line)
+	}
+	if b.dropped > 0 {
+		sb.WriteString(fmt.Sprintf("  \n...(%d more)", b.dropped))
+	}
+	return sb.String()
+}
+
+// sendRuleMessage 按规则指定的消息通知发送消息
+func sendRuleMessage(rule conf.NotifyRule, message string) {
+	if len(rule.Senders) == 0 {
+		Send(message)
+		return
+	}
+	for _, sender := range rule.Senders {
+		if err := SendTo(sender, message); err != nil {
+			logging.CLILog.Errorf("%s:%v", sender, err)
+			logging.RuntimeLog.Errorf("%s:%v", sender, err)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package notify
+
+import (
+	"fmt"
+	"github.com/hanc00l/nemo_go/pkg/conf"
+	"strings"
+	"testing"
+)
+
+func TestMatchRule(t *testing.T) {
+	orgId := 2
+	tests := []struct {
+		rule  conf.NotifyRule
+		event Event
+		want  bool
+	}{
+		{conf.NotifyRule{Event: EventVulnerability, WorkspaceId: 1, Severity: "high"}, Event{Type: EventVulnerability, WorkspaceId: 1, Severity: "critical"}, true},
+		{conf.NotifyRule{Event: EventVulnerability, WorkspaceId: 1, Severity: "high"}, Event{Type: EventVulnerability, WorkspaceId: 1, Severity: "medium"}, false},
+		{conf.NotifyRule{Event: EventVulnerability, WorkspaceId: 1}, Event{Type: EventVulnerability, WorkspaceId: 3, Severity: "high"}, false},
+		{conf.NotifyRule{Event: EventPort, OrgId: 2, Ports: []int{3389, 6379}}, Event{Type: EventPort, OrgId: &orgId, Port: 6379}, true},
+		{conf.NotifyRule{Event: EventPort, OrgId: 2, Ports: []int{3389, 6379}}, Event{Type: EventPort, Port: 6379}, false},
+		{conf.NotifyRule{Event: EventPort, Ports: []int{3389}}, Event{Type: EventPort, Port: 80}, false},
+		{conf.NotifyRule{Event: EventDomain, Domains: []string{"example.com"}}, Event{Type: EventDomain, Target: "www.Example.com"}, true},
+		{conf.NotifyRule{Event: EventDomain, Domains: []string{"example.com"}}, Event{Type: EventDomain, Target: "badexample.com"}, false},
+		{conf.NotifyRule{Event: EventWorkerOffline, WorkspaceId: 1}, Event{Type: EventWorkerOffline, Target: "worker1"}, true},
+		{conf.NotifyRule{Event: EventDomain, Disable: true}, Event{Type: EventDomain, Target: "www.example.com"}, false},
+	}
+	for i, tt := range tests {
+		if got := MatchRule(tt.rule, tt.event); got != tt.want {
+			t.Errorf("%d:got %v, want %v", i, got, tt.want)
+		}
+	}
+}
+
+func TestRuleAggregator(t *testing.T) {
+	a := &ruleAggregator{buffers: make(map[string]*ruleBuffer)}
+	rule := conf.NotifyRule{Name: "port", Event: EventPort}
+	if !a.add("0:port", rule, Event{Type: EventPort, Target: "192.0.2.1", Port: 3389}) {
+		t.Error("first event should start a new aggregation")
+	}
+	if a.add("0:port", rule, Event{Type: EventPort, Target: "192.0.2.1", Port: 3389}) {
+		t.Error("second event should be aggregated")
+	}
+	for i := 0; i < ruleMaxEvents+5; i++ {
+		a.add("0:port", rule, Event{Type: EventPort, Target: fmt.Sprintf("192.0.2.%d", i+2), Port: 6379})
+	}
+	b := a.flush("0:port")
+	if b == nil || len(b.events) != ruleMaxEvents || b.dropped != 6 {
+		t.Fatalf("unexpected buffer:%+v", b)
+	}
+	message := formatRuleMessage(b)
+	if !strings.HasPrefix(message, "port->event:port,count:56") || !strings.HasSuffix(message, "...(6 more)") {
+		t.Errorf("unexpected message:%s", message)
+	}
+	if a.flush("0:port") != nil {
+		t.Error("flushed buffer should be removed")
+	}
+}
+
+func TestCheckRule(t *testing.T) {
+	if err := CheckRule(conf.NotifyRule{Event: EventVulnerability, Severity: "high", Senders: []string{"smtp"}}); err != nil {
+		t.Error(err)
+	}
+	for _, rule := range []conf.NotifyRule{
+		{Event: "unknown"},
+		{Event: EventVulnerability, Severity: "urgent"},
+		{Event: EventPort, Senders: []string{"sms"}},
+	} {
+		if err := CheckRule(rule); err == nil {
+			t.Errorf("rule should be invalid:%+v", rule)
+		}
+	}
+}
@@ -1,47 +1,140 @@
 package notify
 
 import (
+	"bytes"
+	"fmt"
 	"github.com/hanc00l/nemo_go/pkg/conf"
 	"github.com/hanc00l/nemo_go/pkg/logging"
+	"io"
+	"net/http"
 	"sync"
+	"time"
 )
 
 // Sender 各个API的消息通知调用handler
 type Sender interface {
-	Send(token string, message string) (err error)
+	Send(config conf.Notify, message string) (err error)
 }
 
-// Send 根据server的配置，调用各个接口handler发送消息通知
-func Send(message string) {
+// CardSender 支持卡片消息的handler，link为卡片中按钮的链接
+type CardSender interface {
+	SendCard(config conf.Notify, message, link string) (err error)
+}
+
+// httpClient 消息通知的HTTP请求
+var httpClient = &http.Client{Timeout: 30 * time.Second}
+
+// newSender 根据名称获取消息通知的handler
+func newSender(senderName string) Sender {
+	switch senderName {
+	case "serverchan":
+		return new(ServerChan)
+	case "dingtalk":
+		return new(DingTalk)
+	case "feishu":
+		return new(Feishu)
+	case "webhook":
+		return new(Webhook)
+	case "smtp":
+		return new(SMTP)
+	case "slack":
+		return new(Slack)
+	case "telegram":
+		return new(Telegram)
+	case "wecom":
+		return new(WeCom)
+	}
+	return nil
+}
+
+// isEnabled 消息通知是否已配置
+func isEnabled(senderName string, config conf.Notify) bool {
+	switch senderName {
+	case "webhook", "slack":
+		return len(config.URL) > 0
+	case "smtp":
+		return len(config.Host) > 0 && len(config.Recipients) > 0
+	case "telegram":
+		return len(config.Token) > 0 && len(config.Recipients) > 0
+	default:
+		return len(config.Token) > 0
+	}
+}
+
+// SendAll 根据server的配置，调用所有已配置的接口handler发送消息通知，返回各个接口的发送结果
+func SendAll(message string) (results map[string]error) {
+	return sendAll(func(senderName string, sender Sender, config conf.Notify) error {
+		return sender.Send(config, message)
+	})
+}
+
+// sendAll 对所有已配置的接口handler调用send发送消息通知，返回各个接口的发送结果
+func sendAll(send func(senderName string, sender Sender, config conf.Notify) error) (results map[string]error) {
+	results = make(map[string]error)
 	// 采用多线程同时发送模式
+	var mutex sync.Mutex
 	swg := sync.WaitGroup{}
 	for senderName, config := range conf.GlobalServerConfig().Notify {
-		if len(config.Token) == 0 {
+		if !isEnabled(senderName, config) {
 			continue
 		}
-		var sender Sender
-		switch senderName {
-		case "serverchan":
-			sender = new(ServerChan)
-		case "dingtalk":
-			sender = new(DingTalk)
-		case "feishu":
-			sender = new(Feishu)
-		default:
-			msg := "invalid notify sender"
-			logging.RuntimeLog.Error(msg)
-			logging.CLILog.Error(msg)
+		sender := newSender(senderName)
+		if sender == nil {
+			results[senderName] = fmt.Errorf("invalid notify sender:%s", senderName)
 			continue
 		}
 		//send message
 		swg.Add(1)
-		go func(s Sender, token string) {
+		go func(name string, s Sender, c conf.Notify) {
 			defer swg.Done()
-			if err := s.Send(token, message); err != nil {
-				logging.CLILog.Error(err)
-				logging.RuntimeLog.Error(err)
-			}
-		}(sender, config.Token)
+			err := send(name, s, c)
+			mutex.Lock()
+			results[name] = err
+			mutex.Unlock()
+		}(senderName, sender, config)
 	}
 	swg.Wait()
+	return
+}
+
+// Send 根据server的配置，调用各个接口handler发送消息通知
+func Send(message string) {
+	for senderName, err := range SendAll(message) {
+		if err != nil {
+			logging.CLILog.Errorf("%s:%v", senderName, err)
+			logging.RuntimeLog.Errorf("%s:%v", senderName, err)
+		}
+	}
+}
+
+// SendTo 调用指定的接口handler发送消息通知
+func SendTo(senderName string, message string) (err error) {
+	config, ok := conf.GlobalServerConfig().Notify[senderName]
+	if !ok || !isEnabled(senderName, config) {
+		return fmt.Errorf("notify sender not configured:%s", senderName)
+	}
+	sender := newSender(senderName)
+	if sender == nil {
+		return fmt.Errorf("invalid notify sender:%s", senderName)
+	}
+	return sender.Send(config, message)
+}
+
+// postJSON 以JSON格式提交数据，返回响应的状态码及内容
+func postJSON(url string, body []byte, header map[string]string) (statusCode int, content []byte, err error) {
+	request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return
+	}
+	request.Header.Set("Content-Type", "application/json")
+	for k, v := range header {
+		request.Header.Set(k, v)
+	}
+	resp, err := httpClient.Do(request)
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+	content, err = io.ReadAll(resp.Body)
+	return resp.StatusCode, content, err
 }
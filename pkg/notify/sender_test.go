@@ -0,0 +1,142 @@
+package notify
+
+import (
+	"encoding/json"
+	"github.com/hanc00l/nemo_go/pkg/conf"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWebhook_Send(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.Header.Get("X-Nemo-Signature") != "sha256="+SignWebhook("secret", r.Header.Get("X-Nemo-Timestamp"), body) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var msg WebhookMessage
+		if json.Unmarshal(body, &msg) != nil || msg.Message != "test" {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer ts.Close()
+
+	w := new(Webhook)
+	if err := w.Send(conf.Notify{URL: ts.URL, Secret: "secret"}, "test"); err != nil {
+		t.Error(err)
+	}
+	if err := w.Send(conf.Notify{URL: ts.URL, Secret: "wrong"}, "test"); err == nil {
+		t.Error("wrong secret should fail")
+	}
+}
+
+func TestTelegram_Send(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var data map[string]string
+		json.NewDecoder(r.Body).Decode(&data)
+		if r.URL.Path != "/botbot-token/sendMessage" || data["chat_id"] != "10001" {
+			json.NewEncoder(w).Encode(TelegramResponseInfo{Ok: false, Description: "Bad Request: chat not found"})
+			return
+		}
+		json.NewEncoder(w).Encode(TelegramResponseInfo{Ok: true})
+	}))
+	defer ts.Close()
+	telegramAPIUrl = ts.URL
+
+	tg := new(Telegram)
+	if err := tg.Send(conf.Notify{Token: "bot-token", Recipients: []string{"10001"}}, "test"); err != nil {
+		t.Error(err)
+	}
+	err := tg.Send(conf.Notify{Token: "bot-token", Recipients: []string{"10001", "10002"}}, "test")
+	if err == nil || !strings.Contains(err.Error(), "10002") {
+		t.Errorf("unexpected error:%v", err)
+	}
+}
+
+func TestDingTalk_SendCard(t *testing.T) {
+	var data map[string]interface{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&data)
+		json.NewEncoder(w).Encode(DingTalkResponseInfo{Code: 0, Message: "ok"})
+	}))
+	defer ts.Close()
+	dingTalkAPIUrl = ts.URL
+
+	d := new(DingTalk)
+	config := conf.Notify{Token: "token", Title: "Nemo task", Format: FormatCard}
+	if err := d.SendCard(config, "**portscan**", "https://nemo.example.com"); err != nil {
+		t.Fatal(err)
+	}
+	card, _ := data["actionCard"].(map[string]interface{})
+	if data["msgtype"] != "actionCard" || card["title"] != "Nemo task" || card["singleURL"] != "https://nemo.example.com" {
+		t.Errorf("unexpected card:%v", data)
+	}
+	// 没有链接时发送markdown消息
+	if err := d.Send(config, "**portscan**"); err != nil {
+		t.Fatal(err)
+	}
+	if data["msgtype"] != "markdown" {
+		t.Errorf("unexpected message:%v", data)
+	}
+}
+
+func TestFeishu_SendCard(t *testing.T) {
+	var data map[string]interface{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&data)
+		json.NewEncoder(w).Encode(FeishuResponseInfo{Code: 0, Message: "success"})
+	}))
+	defer ts.Close()
+	feishuAPIUrl = ts.URL
+
+	f := new(Feishu)
+	if err := f.SendCard(conf.Notify{Token: "token"}, "**portscan**", "https://nemo.example.com"); err != nil {
+		t.Fatal(err)
+	}
+	card, _ := data["card"].(map[string]interface{})
+	elements, _ := card["elements"].([]interface{})
+	if data["msg_type"] != "interactive" || len(elements) != 2 {
+		t.Errorf("unexpected card:%v", data)
+	}
+	if err := f.Send(conf.Notify{Token: "token"}, "test"); err != nil {
+		t.Fatal(err)
+	}
+	if data["msg_type"] != "text" {
+		t.Errorf("unexpected message:%v", data)
+	}
+}
+
+func TestIsEnabled(t *testing.T) {
+	tests := []struct {
+		sender string
+		config conf.Notify
+		want   bool
+	}{
+		{"dingtalk", conf.Notify{Token: "token"}, true},
+		{"slack", conf.Notify{Token: "token"}, false},
+		{"slack", conf.Notify{URL: "https://hooks.slack.com/services/xxx"}, true},
+		{"smtp", conf.Notify{Host: "smtp.example.com"}, false},
+		{"smtp", conf.Notify{Host: "smtp.example.com", Recipients: []string{"a@example.com"}}, true},
+		{"telegram", conf.Notify{Token: "token"}, false},
+	}
+	for _, tt := range tests {
+		if got := isEnabled(tt.sender, tt.config); got != tt.want {
+			t.Errorf("%s:%+v got %v", tt.sender, tt.config, got)
+		}
+	}
+}
+
+func TestMakeMail(t *testing.T) {
+	mail := string(makeMail("nemo@example.com", []string{"a@example.com", "b@example.com"}, "Nemo任务通知", strings.Repeat("任务", 50)))
+	if !strings.Contains(mail, "To: a@example.com, b@example.com\r\n") || !strings.Contains(mail, "Subject: =?UTF-8?b?") {
+		t.Errorf("unexpected mail header:%s", mail)
+	}
+	for _, line := range strings.Split(mail, "\r\n") {
+		if len(line) > 78 {
+			t.Errorf("line too long:%d", len(line))
+		}
+	}
+}
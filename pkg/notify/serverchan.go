@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"github.com/hanc00l/nemo_go/pkg/conf"
 	"io"
 	"net/http"
 	"net/url"
@@ -21,9 +22,9 @@ type ServerChanResponseInfo struct {
 	Info    string `json:"info"`
 }
 
-func (s *ServerChan) Send(token string, message string) (err error) {
-	u := fmt.Sprintf("https://sctapi.ftqq.com/%s.send", token)
-	data := fmt.Sprintf("title=Nemo任务通知&&desp=%s", url.QueryEscape(message))
+func (s *ServerChan) Send(config conf.Notify, message string) (err error) {
+	u := fmt.Sprintf("https://sctapi.ftqq.com/%s.send", config.Token)
+	data := fmt.Sprintf("title=%s&&desp=%s", url.QueryEscape(messageTitle(config)), url.QueryEscape(message))
 	var resp *http.Response
 	if resp, err = http.Post(u, "application/x-www-form-urlencoded", strings.NewReader(data)); err != nil {
 		return
@@ -0,0 +1,29 @@
+package notify
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/hanc00l/nemo_go/pkg/conf"
+	"net/http"
+)
+
+// https://api.slack.com/messaging/webhooks
+
+type Slack struct {
+}
+
+func (s *Slack) Send(config conf.Notify, message string) (err error) {
+	//-d '{"text":"Hello, World!"}'
+	data := make(map[string]string)
+	data["text"] = textMessage(config, message)
+	b, _ := json.Marshal(data)
+	statusCode, content, err := postJSON(config.URL, b, nil)
+	if err != nil {
+		return
+	}
+	// 成功时返回ok，失败时返回错误信息，如：invalid_token、no_service
+	if statusCode != http.StatusOK {
+		err = errors.New(string(content))
+	}
+	return
+}
@@ -0,0 +1,93 @@
+package notify
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"github.com/hanc00l/nemo_go/pkg/conf"
+	"mime"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SMTP 邮件通知，465端口使用SSL连接，其它端口在服务器支持时使用STARTTLS
+
+type SMTP struct {
+}
+
+func (s *SMTP) Send(config conf.Notify, message string) (err error) {
+	port := config.Port
+	if port == 0 {
+		port = 25
+	}
+	from := config.From
+	if len(from) == 0 {
+		from = config.Username
+	}
+	addr := net.JoinHostPort(config.Host, strconv.Itoa(port))
+	var auth smtp.Auth
+	if len(config.Username) > 0 {
+		auth = smtp.PlainAuth("", config.Username, config.Password, config.Host)
+	}
+	mail := makeMail(from, config.Recipients, messageTitle(config), message)
+	if port != 465 {
+		return smtp.SendMail(addr, auth, from, config.Recipients, mail)
+	}
+
+	conn, err := tls.DialWithDialer(&net.Dialer{Timeout: 30 * time.Second}, "tcp", addr, &tls.Config{ServerName: config.Host})
+	if err != nil {
+		return
+	}
+	c, err := smtp.NewClient(conn, config.Host)
+	if err != nil {
+		return
+	}
+	defer c.Close()
+	if auth != nil {
+		if err = c.Auth(auth); err != nil {
+			return
+		}
+	}
+	if err = c.Mail(from); err != nil {
+		return
+	}
+	for _, to := range config.Recipients {
+		if err = c.Rcpt(to); err != nil {
+			return
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return
+	}
+	if _, err = w.Write(mail); err != nil {
+		return
+	}
+	if err = w.Close(); err != nil {
+		return
+	}
+	return c.Quit()
+}
+
+// makeMail 生成UTF-8编码的纯文本邮件内容
+func makeMail(from string, to []string, subject, message string) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("From: %s\r\n", from))
+	buf.WriteString(fmt.Sprintf("To: %s\r\n", strings.Join(to, ", ")))
+	buf.WriteString(fmt.Sprintf("Subject: %s\r\n", mime.BEncoding.Encode("UTF-8", subject)))
+	buf.WriteString(fmt.Sprintf("Date: %s\r\n", time.Now().Format(time.RFC1123Z)))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: base64\r\n\r\n")
+	body := base64.StdEncoding.EncodeToString([]byte(message))
+	for len(body) > 76 {
+		buf.WriteString(body[:76] + "\r\n")
+		body = body[76:]
+	}
+	buf.WriteString(body + "\r\n")
+	return buf.Bytes()
+}
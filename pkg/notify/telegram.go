@@ -0,0 +1,51 @@
+package notify
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/hanc00l/nemo_go/pkg/conf"
+)
+
+// https://core.telegram.org/bots/api#sendmessage
+
+var telegramAPIUrl = "https://api.telegram.org"
+
+type Telegram struct {
+}
+
+type TelegramResponseInfo struct {
+	Ok          bool   `json:"ok"`
+	Description string `json:"description"`
+}
+
+func (t *Telegram) Send(config conf.Notify, message string) (err error) {
+	url := fmt.Sprintf("%s/bot%s/sendMessage", telegramAPIUrl, config.Token)
+	// 依次发送给每一个chat_id
+	var errs []error
+	for _, chatId := range config.Recipients {
+		data := make(map[string]string)
+		data["chat_id"] = chatId
+		data["text"] = textMessage(config, message)
+		if config.Format == FormatMarkdown {
+			data["parse_mode"] = "Markdown"
+		}
+		b, _ := json.Marshal(data)
+		_, content, err1 := postJSON(url, b, nil)
+		if err1 != nil {
+			errs = append(errs, err1)
+			continue
+		}
+		var msgData TelegramResponseInfo
+		if err1 = json.Unmarshal(content, &msgData); err1 != nil {
+			errs = append(errs, err1)
+			continue
+		}
+		//{"ok":true,"result":{...}}
+		//{"ok":false,"error_code":400,"description":"Bad Request: chat not found"}
+		if !msgData.Ok {
+			errs = append(errs, fmt.Errorf("%s:%s", chatId, msgData.Description))
+		}
+	}
+	return errors.Join(errs...)
+}
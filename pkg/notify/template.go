@@ -0,0 +1,142 @@
+package notify
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/hanc00l/nemo_go/pkg/conf"
+	"github.com/hanc00l/nemo_go/pkg/db"
+	"github.com/hanc00l/nemo_go/pkg/logging"
+	"strings"
+	"text/template"
+)
+
+const (
+	FormatText     = "text"
+	FormatMarkdown = "markdown"
+	FormatCard     = "card"
+)
+
+const (
+	// defaultTitle 默认的消息标题
+	defaultTitle = "Nemo任务通知"
+	// cardButtonText 卡片消息中链接按钮的文字
+	cardButtonText = "Open in Nemo"
+)
+
+// defaultTaskTemplate 未配置模板时的任务通知内容
+const defaultTaskTemplate = `{{.Task.TaskName}}->runtime:{{.Runtime}},runtask:{{.Task.ProgressMessage}}  
+target->{{.Target}}{{if .Task.Result}}  
+result->{{.Task.Result}}{{end}}{{if .URL}}  
+link->{{.URL}}{{end}}`
+
+// TaskResult 任务结果的数量及新增的数量
+type TaskResult struct {
+	IP               int
+	Port             int
+	Domain           int
+	Vulnerability    int
+	Screenshot       int
+	IPNew            int
+	PortNew          int
+	DomainNew        int
+	VulnerabilityNew int
+}
+
+// TaskMessage 任务通知模板中可使用的数据
+type TaskMessage struct {
+	Task    db.TaskMain
+	Target  string
+	Runtime string
+	Result  TaskResult
+	// 新增的IP、端口（ip:port）、域名及漏洞
+	NewIP            []string
+	NewPort          []string
+	NewDomain        []string
+	NewVulnerability []string
+	// 任务详情及web的链接，需配置web.url
+	URL    string
+	WebURL string
+}
+
+// templateFuncs 模板中可使用的函数
+var templateFuncs = template.FuncMap{
+	"join": strings.Join,
+	// limit 取列表的前n项
+	"limit": func(n int, list []string) []string {
+		if n >= 0 && len(list) > n {
+			return list[:n]
+		}
+		return list
+	},
+	// more 列表超过n项时剩余的数量
+	"more": func(n int, list []string) int {
+		if n >= 0 && len(list) > n {
+			return len(list) - n
+		}
+		return 0
+	},
+}
+
+// messageTitle 消息的标题
+func messageTitle(config conf.Notify) string {
+	if len(config.Title) > 0 {
+		return config.Title
+	}
+	return defaultTitle
+}
+
+// textMessage 带标题的文本消息内容
+func textMessage(config conf.Notify, message string) string {
+	if len(config.Title) > 0 {
+		return fmt.Sprintf("%s\n%s", config.Title, message)
+	}
+	return fmt.Sprintf("%s：\n%s", defaultTitle, message)
+}
+
+// CheckTemplate 检查消息模板的语法
+func CheckTemplate(text string) (err error) {
+	_, err = template.New("notify").Funcs(templateFuncs).Parse(text)
+	return
+}
+
+// RenderTaskMessage 使用消息通知配置的模板生成任务通知内容
+func RenderTaskMessage(config conf.Notify, data TaskMessage) (message string, err error) {
+	text := config.Template
+	if len(strings.TrimSpace(text)) == 0 {
+		text = defaultTaskTemplate
+	}
+	t, err := template.New("notify").Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return
+	}
+	var buf bytes.Buffer
+	if err = t.Execute(&buf, data); err != nil {
+		return
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
+
+// SendTask 按各个接口的模板及消息格式发送任务通知
+func SendTask(data TaskMessage) {
+	results := sendAll(func(senderName string, sender Sender, config conf.Notify) error {
+		message, err := RenderTaskMessage(config, data)
+		if err != nil {
+			// 模板错误时使用默认模板，保证通知能够送达
+			logging.RuntimeLog.Errorf("%s template:%v", senderName, err)
+			config.Template = ""
+			if message, err = RenderTaskMessage(config, data); err != nil {
+				return err
+			}
+		}
+		if cs, ok := sender.(CardSender); ok && config.Format == FormatCard {
+			return cs.SendCard(config, message, data.URL)
+		}
+		return sender.Send(config, message)
+	})
+	for senderName, err := range results {
+		if err != nil {
+			logging.CLILog.Errorf("%s:%v", senderName, err)
+			logging.RuntimeLog.Errorf("%s:%v", senderName, err)
+		}
+	}
+}
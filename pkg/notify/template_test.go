@@ -0,0 +1,56 @@
+package notify
+
+import (
+	"github.com/hanc00l/nemo_go/pkg/conf"
+	"github.com/hanc00l/nemo_go/pkg/db"
+	"testing"
+)
+
+func TestRenderTaskMessage(t *testing.T) {
+	data := TaskMessage{
+		Task:      db.TaskMain{TaskName: "portscan", ProgressMessage: "0/0/9", Result: "ip:10(+2),port:20"},
+		Target:    "192.0.2.0/24",
+		Runtime:   "25s",
+		Result:    TaskResult{IP: 10, Port: 20, IPNew: 2},
+		NewIP:     []string{"192.0.2.1", "192.0.2.2", "192.0.2.3"},
+		NewDomain: []string{"www.example.com"},
+	}
+	message, err := RenderTaskMessage(conf.Notify{}, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if message != "portscan->runtime:25s,runtask:0/0/9  \ntarget->192.0.2.0/24  \nresult->ip:10(+2),port:20" {
+		t.Errorf("unexpected default message:%q", message)
+	}
+
+	data.URL = "https://nemo.example.com/task-info-main?task_id=1"
+	template := `**{{.Task.TaskName}}** finished in {{.Runtime}}, new IP: {{.Result.IPNew}}
+{{range limit 2 .NewIP}}- {{.}}
+{{end}}{{with more 2 .NewIP}}...and {{.}} more
+{{end}}domains: {{join .NewDomain ", "}}
+[detail]({{.URL}})`
+	message, err = RenderTaskMessage(conf.Notify{Template: template}, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "**portscan** finished in 25s, new IP: 2\n- 192.0.2.1\n- 192.0.2.2\n...and 1 more\ndomains: www.example.com\n[detail](https://nemo.example.com/task-info-main?task_id=1)"
+	if message != want {
+		t.Errorf("unexpected message:%q", message)
+	}
+
+	if _, err = RenderTaskMessage(conf.Notify{Template: "{{.Unknown}}"}, data); err == nil {
+		t.Error("unknown field should fail")
+	}
+	if err = CheckTemplate("{{range .NewIP}}"); err == nil {
+		t.Error("invalid template should fail")
+	}
+}
+
+func TestTextMessage(t *testing.T) {
+	if m := textMessage(conf.Notify{}, "test"); m != "Nemo任务通知：\ntest" {
+		t.Errorf("unexpected message:%q", m)
+	}
+	if m := textMessage(conf.Notify{Title: "Nemo task"}, "test"); m != "Nemo task\ntest" {
+		t.Errorf("unexpected message:%q", m)
+	}
+}
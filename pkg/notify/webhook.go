@@ -0,0 +1,56 @@
+package notify
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/hanc00l/nemo_go/pkg/conf"
+	"strconv"
+	"time"
+)
+
+// Webhook 通用的JSON webhook
+// 配置了secret时，请求头X-Nemo-Signature为HMAC-SHA256(secret, X-Nemo-Timestamp + "." + body)的十六进制值，格式为sha256=xxx
+
+type Webhook struct {
+}
+
+type WebhookMessage struct {
+	Title     string `json:"title"`
+	Message   string `json:"message"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+func (w *Webhook) Send(config conf.Notify, message string) (err error) {
+	timestamp := time.Now().Unix()
+	b, _ := json.Marshal(WebhookMessage{
+		Title:     messageTitle(config),
+		Message:   message,
+		Timestamp: timestamp,
+	})
+	header := make(map[string]string)
+	if len(config.Secret) > 0 {
+		ts := strconv.FormatInt(timestamp, 10)
+		header["X-Nemo-Timestamp"] = ts
+		header["X-Nemo-Signature"] = "sha256=" + SignWebhook(config.Secret, ts, b)
+	}
+	statusCode, content, err := postJSON(config.URL, b, header)
+	if err != nil {
+		return
+	}
+	if statusCode < 200 || statusCode >= 300 {
+		err = fmt.Errorf("webhook status code:%d,%s", statusCode, string(content))
+	}
+	return
+}
+
+// SignWebhook 计算webhook请求的签名，接收方可使用相同的方法校验
+func SignWebhook(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
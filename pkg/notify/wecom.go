@@ -0,0 +1,49 @@
+package notify
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/hanc00l/nemo_go/pkg/conf"
+)
+
+// https://developer.work.weixin.qq.com/document/path/91770
+
+var weComAPIUrl = "https://qyapi.weixin.qq.com"
+
+type WeCom struct {
+}
+
+type WeComResponseInfo struct {
+	Code    int    `json:"errcode"`
+	Message string `json:"errmsg"`
+}
+
+func (w *WeCom) Send(config conf.Notify, message string) (err error) {
+	url := fmt.Sprintf("%s/cgi-bin/webhook/send?key=%s", weComAPIUrl, config.Token)
+	//-d '{"msgtype": "text","text": {"content": "hello world"}}'
+	msgType := "text"
+	if config.Format == FormatMarkdown {
+		msgType = "markdown"
+	}
+	text := make(map[string]string)
+	text["content"] = textMessage(config, message)
+	data := make(map[string]interface{})
+	data[msgType] = text
+	data["msgtype"] = msgType
+	b, _ := json.Marshal(data)
+	_, content, err := postJSON(url, b, nil)
+	if err != nil {
+		return
+	}
+	var msgData WeComResponseInfo
+	if err = json.Unmarshal(content, &msgData); err != nil {
+		return
+	}
+	//{"errcode":0,"errmsg":"ok"}
+	//{"errcode":93000,"errmsg":"invalid webhook url"}
+	if msgData.Code != 0 {
+		err = errors.New(msgData.Message)
+	}
+	return
+}
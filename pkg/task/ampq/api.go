@@ -22,7 +22,7 @@ const (
 	FAILURE  string = tasks.StateFailure  //任务执行完成，结果为FAILURE
 	RECEIVED string = tasks.StateReceived //未使用
 	PENDING  string = tasks.StatePending  //未使用
-	RETRY    string = tasks.StateRetry    //未使用
+	RETRY    string = tasks.StateRetry    //任务执行失败，等待重新执行
 
 	TopicActive  = "active"
 	TopicFinger  = "finger"
@@ -79,11 +79,16 @@ var taskTopicDefineMap = map[string]string{
 	"domainscan":        TopicActive,
 	"subfinder":         TopicPassive,
 	"subdomainbrute":    TopicPassive,
+	"subdomainpermute":  TopicPassive,
 	"subdomaincrawler":  TopicActive,
 	"iplocation":        TopicPassive,
 	"fofa":              TopicPassive,
 	"quake":             TopicPassive,
 	"hunter":            TopicPassive,
+	"shodan":            TopicPassive,
+	"censys":            TopicPassive,
+	"zoomeye":           TopicPassive,
+	"netlas":            TopicPassive,
 	"xray":              TopicPocscan,
 	"nuclei":            TopicPocscan,
 	"goby":              TopicPocscan,
@@ -95,6 +100,10 @@ var taskTopicDefineMap = map[string]string{
 	"xfofa":             TopicPassive,
 	"xquake":            TopicPassive,
 	"xhunter":           TopicPassive,
+	"xshodan":           TopicPassive,
+	"xcensys":           TopicPassive,
+	"xzoomeye":          TopicPassive,
+	"xnetlas":           TopicPassive,
 	"xdomainscan":       TopicPassive,
 	"xsubfinder":        TopicPassive,
 	"xsubdomainbrute":   TopicPassive,
@@ -104,6 +113,7 @@ var taskTopicDefineMap = map[string]string{
 	"xnuclei":           TopicPocscan,
 	"xgoby":             TopicPocscan,
 	"xorgscan":          TopicActive,
+	"dirscan":           TopicActive,
 	//test:
 	"test": TopicCustom,
 }
@@ -211,10 +211,16 @@ func (c *CDNCheck) CheckCName(domain string) (isCDN bool, CDNName string, CName
 	if cname == domain {
 		return
 	}
+	isCDN, CDNName = c.CheckCNameTarget(cname)
+	return isCDN, CDNName, cname
+}
+
+// CheckCNameTarget 检查CNAME的目标域名，判断是否是CDN
+func (c *CDNCheck) CheckCNameTarget(cname string) (isCDN bool, CDNName string) {
 	for _, cn := range cnames {
 		if strings.Index(cname, cn) >= 0 {
-			return true, cn, cname
+			return true, cn
 		}
 	}
-	return false, "", cname
+	return false, ""
 }
@@ -0,0 +1,279 @@
+package dirscan
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/hanc00l/nemo_go/pkg/conf"
+	"github.com/hanc00l/nemo_go/pkg/logging"
+	"github.com/hanc00l/nemo_go/pkg/utils"
+	"github.com/remeh/sizedwaitgroup"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	extensionTag       = "%EXT%"
+	defaultMaxHits     = 50
+	maxReadBodyLength  = 1024 * 1024
+	wildcardPathLength = 16
+)
+
+var (
+	dirscanThreadNumber = make(map[string]int)
+)
+
+type Config struct {
+	Target      string `json:"target"`
+	WorkspaceId int    `json:"workspaceId"`
+	IsProxy     bool   `json:"proxy"`
+}
+
+// PathResult 发现的路径
+type PathResult struct {
+	Path     string `json:"path"`
+	Status   int    `json:"status"`
+	Length   int    `json:"length"`
+	Redirect string `json:"redirect,omitempty"`
+}
+
+// Result 以URL为key的扫描结果
+type Result struct {
+	sync.RWMutex `json:"-"`
+	UrlResult    map[string][]PathResult `json:"urlResult"`
+}
+
+type DirScan struct {
+	Config Config
+	Result Result
+	// 扫描参数，由worker的配置文件生成
+	Wordlist      []string
+	Blacklist     map[int]map[string]struct{}
+	ExcludeStatus map[int]struct{}
+	ExcludeLength map[int]struct{}
+	MaxHits       int
+}
+
+func init() {
+	dirscanThreadNumber[conf.HighPerformance] = 20
+	dirscanThreadNumber[conf.NormalPerformance] = 10
+}
+
+// NewDirScan 创建dirscan对象
+func NewDirScan(config Config) *DirScan {
+	d := &DirScan{Config: config}
+	d.Result.UrlResult = make(map[string][]PathResult)
+	d.MaxHits = defaultMaxHits
+
+	return d
+}
+
+// LoadOption 从worker的配置文件中加载字典、黑名单及过滤条件
+func (d *DirScan) LoadOption() (err error) {
+	option := conf.GlobalWorkerConfig().Dirscan
+	dictPath := filepath.Join(conf.GetRootPath(), "thirdparty/dict")
+	wordlist, err := readLines(filepath.Join(dictPath, option.Wordlist))
+	if err != nil {
+		logging.RuntimeLog.Error(err)
+		return err
+	}
+	d.Wordlist = ExpandWordlist(wordlist, strings.Split(option.Extensions, ","))
+	d.Blacklist = make(map[int]map[string]struct{})
+	for _, status := range []int{http.StatusBadRequest, http.StatusForbidden} {
+		lines, err := readLines(filepath.Join(dictPath, fmt.Sprintf("%d_blacklist.txt", status)))
+		if err != nil {
+			logging.RuntimeLog.Warning(err)
+			continue
+		}
+		d.Blacklist[status] = make(map[string]struct{})
+		for _, line := range lines {
+			d.Blacklist[status][strings.TrimPrefix(line, "/")] = struct{}{}
+		}
+	}
+	d.ExcludeStatus = ParseNumberList(option.ExcludeStatus)
+	d.ExcludeLength = ParseNumberList(option.ExcludeLength)
+	if option.MaxHits > 0 {
+		d.MaxHits = option.MaxHits
+	}
+	return nil
+}
+
+// Do 执行目录扫描
+func (d *DirScan) Do() {
+	for _, line := range strings.Split(d.Config.Target, ",") {
+		u := strings.TrimSuffix(strings.TrimSpace(line), "/")
+		if u == "" {
+			continue
+		}
+		if result := d.scanUrl(u); len(result) > 0 {
+			d.Result.Lock()
+			d.Result.UrlResult[u] = result
+			d.Result.Unlock()
+		}
+	}
+}
+
+// scanUrl 对一个URL进行目录扫描
+func (d *DirScan) scanUrl(u string) (result []PathResult) {
+	client := utils.GetProxyHttpClient(d.Config.IsProxy)
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	// 用随机路径获取泛解析（所有路径返回相同内容）的基准响应
+	wildcard, wildcardErr := d.request(client, u, utils.GetRandomString2(wildcardPathLength))
+
+	var mutex sync.Mutex
+	var stop bool
+	swg := sizedwaitgroup.New(dirscanThreadNumber[conf.WorkerPerformanceMode])
+	for _, path := range d.Wordlist {
+		mutex.Lock()
+		isStop := stop
+		mutex.Unlock()
+		if isStop {
+			break
+		}
+		swg.Add()
+		go func(path string) {
+			defer swg.Done()
+			r, err := d.request(client, u, path)
+			if err != nil || d.IsExcluded(r) {
+				return
+			}
+			if wildcardErr == nil && r.Status == wildcard.Status && r.Length == wildcard.Length {
+				return
+			}
+			mutex.Lock()
+			defer mutex.Unlock()
+			result = append(result, r)
+			if len(result) > d.MaxHits {
+				stop = true
+			}
+		}(path)
+	}
+	swg.Wait()
+	// 命中数量超过阈值，一般是WAF或统一的错误页面，结果不可信
+	if len(result) > d.MaxHits {
+		logging.RuntimeLog.Warningf("%s dirscan hits exceed %d,discard...", u, d.MaxHits)
+		logging.CLILog.Warningf("%s dirscan hits exceed %d,discard...", u, d.MaxHits)
+		return nil
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Path < result[j].Path
+	})
+	return
+}
+
+// request 请求一个路径，返回状态码、响应长度和跳转地址
+func (d *DirScan) request(client *http.Client, u, path string) (result PathResult, err error) {
+	request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/%s", u, path), nil)
+	if err != nil {
+		return
+	}
+	request.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/118.0.0.0 Safari/537.36")
+	resp, err := client.Do(request)
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxReadBodyLength))
+	result = PathResult{
+		Path:     path,
+		Status:   resp.StatusCode,
+		Length:   len(body),
+		Redirect: resp.Header.Get("Location"),
+	}
+	return
+}
+
+// IsExcluded 根据状态码、响应长度及400/403黑名单检查结果是否需要过滤
+func (d *DirScan) IsExcluded(r PathResult) bool {
+	if _, ok := d.ExcludeStatus[r.Status]; ok {
+		return true
+	}
+	if _, ok := d.ExcludeLength[r.Length]; ok {
+		return true
+	}
+	if blacklist, ok := d.Blacklist[r.Status]; ok {
+		if _, ok = blacklist[r.Path]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// ExpandWordlist 将字典中的%EXT%替换为指定的扩展名，并去除重复的路径
+func ExpandWordlist(wordlist []string, extensions []string) (result []string) {
+	paths := make(map[string]struct{})
+	add := func(path string) {
+		path = strings.TrimPrefix(path, "/")
+		if _, ok := paths[path]; ok || path == "" {
+			return
+		}
+		paths[path] = struct{}{}
+		result = append(result, path)
+	}
+	for _, line := range wordlist {
+		if !strings.Contains(line, extensionTag) {
+			add(line)
+			continue
+		}
+		for _, ext := range extensions {
+			if ext = strings.TrimPrefix(strings.TrimSpace(ext), "."); ext != "" {
+				add(strings.ReplaceAll(line, extensionTag, ext))
+			}
+		}
+	}
+	return
+}
+
+// ParseNumberList 解析以逗号分隔的数字及范围，如"404,500-599"
+func ParseNumberList(s string) (result map[int]struct{}) {
+	result = make(map[int]struct{})
+	for _, v := range strings.Split(s, ",") {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+		if start, end, found := strings.Cut(v, "-"); found {
+			startNum, err1 := strconv.Atoi(strings.TrimSpace(start))
+			endNum, err2 := strconv.Atoi(strings.TrimSpace(end))
+			if err1 != nil || err2 != nil {
+				logging.RuntimeLog.Warningf("invalid number range:%s", v)
+				continue
+			}
+			for i := startNum; i <= endNum; i++ {
+				result[i] = struct{}{}
+			}
+		} else if num, err := strconv.Atoi(v); err == nil {
+			result[num] = struct{}{}
+		} else {
+			logging.RuntimeLog.Warningf("invalid number:%s", v)
+		}
+	}
+	return
+}
+
+// readLines 读取文件的所有非空行，忽略#开头的注释
+func readLines(pathFile string) (lines []string, err error) {
+	inputFile, err := os.Open(pathFile)
+	if err != nil {
+		return
+	}
+	defer inputFile.Close()
+
+	scanner := bufio.NewScanner(inputFile)
+	for scanner.Scan() {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		lines = append(lines, text)
+	}
+	err = scanner.Err()
+	return
+}
@@ -0,0 +1,85 @@
+package dirscan
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestExpandWordlist(t *testing.T) {
+	result := ExpandWordlist([]string{"admin", "/admin", "index.%EXT%", "%EXT%.bak", ""}, []string{"php", ".jsp", " "})
+	expected := []string{"admin", "index.php", "index.jsp", "php.bak", "jsp.bak"}
+	if len(result) != len(expected) {
+		t.Fatalf("invalid wordlist:%v", result)
+	}
+	for i := range expected {
+		if result[i] != expected[i] {
+			t.Errorf("expect %s,got %s", expected[i], result[i])
+		}
+	}
+}
+
+func TestParseNumberList(t *testing.T) {
+	result := ParseNumberList("404, 500-503,abc,")
+	if len(result) != 5 {
+		t.Errorf("invalid number list:%v", result)
+	}
+	for _, n := range []int{404, 500, 501, 502, 503} {
+		if _, ok := result[n]; !ok {
+			t.Errorf("%d not in list", n)
+		}
+	}
+}
+
+func TestDirScan_Do(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/admin":
+			http.Redirect(w, r, "/admin/", http.StatusFound)
+		case "/.htaccess":
+			w.WriteHeader(http.StatusForbidden)
+		case "/backup.zip":
+			w.Write([]byte("backup"))
+		case "/empty":
+			w.WriteHeader(http.StatusOK)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	d := NewDirScan(Config{Target: server.URL + "/"})
+	d.Wordlist = []string{"admin", ".htaccess", "backup.zip", "empty", "notexist"}
+	d.Blacklist = map[int]map[string]struct{}{http.StatusForbidden: {".htaccess": {}}}
+	d.ExcludeStatus = ParseNumberList("404")
+	d.ExcludeLength = ParseNumberList("0")
+	d.Do()
+
+	result := d.Result.UrlResult[server.URL]
+	if len(result) != 2 {
+		t.Fatalf("invalid result:%v", result)
+	}
+	if result[0].Path != "admin" || result[0].Status != http.StatusFound || result[0].Redirect != "/admin/" {
+		t.Errorf("invalid redirect result:%v", result[0])
+	}
+	if result[1].Path != "backup.zip" || result[1].Status != http.StatusOK || result[1].Length != 6 {
+		t.Errorf("invalid result:%v", result[1])
+	}
+	t.Log(FormatPathResult(server.URL, result))
+}
+
+func TestDirScan_MaxHits(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// 不同的路径返回不同长度的内容，用于模拟无法通过泛解析基准过滤的情况
+		w.Write([]byte(r.URL.Path))
+	}))
+	defer server.Close()
+
+	d := NewDirScan(Config{Target: server.URL})
+	d.Wordlist = []string{"a", "bb", "ccc", "dddd"}
+	d.MaxHits = 2
+	d.Do()
+	if len(d.Result.UrlResult) != 0 {
+		t.Errorf("result should be discarded:%v", d.Result.UrlResult)
+	}
+}
@@ -0,0 +1,100 @@
+package dirscan
+
+import (
+	"fmt"
+	"github.com/hanc00l/nemo_go/pkg/db"
+	"github.com/hanc00l/nemo_go/pkg/logging"
+	"github.com/hanc00l/nemo_go/pkg/utils"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const (
+	SourceDirscan = "dirscan"
+	TagDirscan    = "dirscan"
+)
+
+// FormatPathResult 将发现的路径格式化为保存的内容，每个路径一行
+func FormatPathResult(u string, result []PathResult) string {
+	var lines []string
+	for _, r := range result {
+		line := fmt.Sprintf("[%d] [%d] %s/%s", r.Status, r.Length, u, r.Path)
+		if r.Redirect != "" {
+			line = fmt.Sprintf("%s -> %s", line, r.Redirect)
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// SaveResult 保存目录扫描结果：IP的结果保存到ip_http，域名的结果保存到domain_http
+func (r *Result) SaveResult(config Config) string {
+	var resultCount int
+	for u, pathResult := range r.UrlResult {
+		if len(pathResult) == 0 {
+			continue
+		}
+		host, port := parseUrlHostPort(u)
+		if host == "" || port == 0 {
+			logging.RuntimeLog.Warningf("invalid dirscan url:%s", u)
+			continue
+		}
+		content := FormatPathResult(u, pathResult)
+		if len(content) > db.HttpBodyContentSize {
+			content = content[:db.HttpBodyContentSize]
+		}
+		if utils.CheckIPV4(host) || utils.CheckIPV6(host) {
+			ip := db.Ip{IpName: host, WorkspaceId: config.WorkspaceId}
+			if !ip.GetByIp() {
+				continue
+			}
+			p := db.Port{IpId: ip.Id, PortNum: port}
+			if !p.GetByIPPort() {
+				continue
+			}
+			httpInfo := db.IpHttp{
+				RelatedId: p.Id,
+				Source:    SourceDirscan,
+				Tag:       TagDirscan,
+				Content:   content,
+			}
+			if httpInfo.SaveOrUpdate() {
+				resultCount += len(pathResult)
+			}
+		} else {
+			domain := db.Domain{DomainName: host, WorkspaceId: config.WorkspaceId}
+			if !domain.GetByDomain() {
+				continue
+			}
+			httpInfo := db.DomainHttp{
+				RelatedId: domain.Id,
+				Port:      port,
+				Source:    SourceDirscan,
+				Tag:       TagDirscan,
+				Content:   content,
+			}
+			if httpInfo.SaveOrUpdate() {
+				resultCount += len(pathResult)
+			}
+		}
+	}
+	return fmt.Sprintf("dirscan:%d", resultCount)
+}
+
+// parseUrlHostPort 获取URL的主机名和端口，未指定端口时根据协议返回默认端口
+func parseUrlHostPort(u string) (host string, port int) {
+	p, err := url.Parse(u)
+	if err != nil || p.Host == "" {
+		return utils.ParseHostPort(u)
+	}
+	host = p.Hostname()
+	if port, _ = strconv.Atoi(p.Port()); port == 0 {
+		if p.Scheme == "https" {
+			port = 443
+		} else {
+			port = 80
+		}
+	}
+	return
+}
@@ -0,0 +1,233 @@
+package domainscan
+
+import (
+	"fmt"
+	"github.com/hanc00l/nemo_go/pkg/conf"
+	"github.com/hanc00l/nemo_go/pkg/logging"
+	"github.com/hanc00l/nemo_go/pkg/task/pocscan"
+	"github.com/miekg/dns"
+	"github.com/remeh/sizedwaitgroup"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// AXFRSource 域传送获取的子域名的来源
+	AXFRSource     = "axfr"
+	dnsCheckSource = "dnscheck"
+	// PocZoneTransfer 域传送漏洞
+	PocZoneTransfer = "dns-zone-transfer"
+	// PocOpenResolver 开放递归解析
+	PocOpenResolver = "dns-open-resolver"
+	// defaultOpenResolverProbe 未配置时检查递归解析查询的域名，不属于被检查的NS管理的区域
+	defaultOpenResolverProbe = "www.baidu.com"
+	axfrTimeout              = 30 * time.Second
+	axfrRecordMax            = 50000
+)
+
+// DNSCheck 对域名的NS进行域传送（AXFR）及开放递归解析的检查
+type DNSCheck struct {
+	sync.Mutex
+	Config    Config
+	VulResult []pocscan.Result
+	dnsRecord *DNSRecord
+	// 检查递归解析时查询的域名
+	probeDomain string
+	// 已检查过递归解析的NS地址
+	checkedResolver map[string]struct{}
+}
+
+// NewDNSCheck 创建DNSCheck对象
+func NewDNSCheck(config Config) *DNSCheck {
+	probeDomain := strings.TrimSpace(conf.GlobalWorkerConfig().Domainscan.OpenResolverProbe)
+	if probeDomain == "" {
+		probeDomain = defaultOpenResolverProbe
+	}
+	return &DNSCheck{
+		Config:          config,
+		dnsRecord:       NewDNSRecord(),
+		probeDomain:     probeDomain,
+		checkedResolver: make(map[string]struct{}),
+	}
+}
+
+// Do 检查结果中有NS记录的域名，域传送获取的子域名保存到结果中
+func (c *DNSCheck) Do(result *Result) {
+	zones := make(map[string][]string)
+	result.RLock()
+	for domain, domainResult := range result.DomainResult {
+		for _, attr := range domainResult.DomainAttrs {
+			if attr.Tag == "NS" {
+				zones[domain] = append(zones[domain], attr.Content)
+			}
+		}
+	}
+	result.RUnlock()
+	// 未收集DNS记录时，查询任务目标域名的NS
+	for _, line := range strings.Split(c.Config.Target, ",") {
+		domain := strings.ToLower(strings.TrimSpace(line))
+		if _, ok := zones[domain]; ok || domain == "" || net.ParseIP(domain) != nil || strings.Contains(domain, "/") {
+			continue
+		}
+		if nameServers := c.dnsRecord.Query(domain, dns.TypeNS); len(nameServers) > 0 {
+			zones[domain] = nameServers
+		}
+	}
+
+	swg := sizedwaitgroup.New(dnsCheckThreadNumber[conf.WorkerPerformanceMode])
+	for zone, nameServers := range zones {
+		for _, ns := range nameServers {
+			swg.Add()
+			go func(z, n string) {
+				defer swg.Done()
+				c.checkNameServer(result, z, n)
+			}(zone, ns)
+		}
+	}
+	swg.Wait()
+}
+
+// checkNameServer 检查一个区域的NS的全部地址
+func (c *DNSCheck) checkNameServer(result *Result, zone, ns string) {
+	var isTransferred bool
+	for _, ip := range c.dnsRecord.Query(ns, dns.TypeA) {
+		server := net.JoinHostPort(ip, "53")
+		if !isTransferred {
+			// 传送中途出错时已获取的记录同样说明允许域传送
+			if records, _ := ZoneTransfer(zone, server); len(records) > 0 {
+				isTransferred = true
+				c.saveZoneTransfer(result, zone, ns, server, records)
+			}
+		}
+		if c.isResolverChecked(server) {
+			continue
+		}
+		if IsOpenResolver(server, c.probeDomain) {
+			logging.RuntimeLog.Warningf("name server %s(%s) is an open resolver", ns, server)
+			c.addVulnerability(pocscan.Result{
+				Target:   ns,
+				Url:      server,
+				PocFile:  PocOpenResolver,
+				Extra:    fmt.Sprintf("name server %s(%s) of %s resolves %s recursively", ns, server, zone, c.probeDomain),
+				Severity: "medium",
+			})
+		}
+	}
+}
+
+// isResolverChecked 同一个NS地址只检查一次递归解析
+func (c *DNSCheck) isResolverChecked(server string) bool {
+	c.Lock()
+	defer c.Unlock()
+
+	if _, ok := c.checkedResolver[server]; ok {
+		return true
+	}
+	c.checkedResolver[server] = struct{}{}
+	return false
+}
+
+// saveZoneTransfer 保存域传送获取的子域名及漏洞
+func (c *DNSCheck) saveZoneTransfer(result *Result, zone, ns, server string, records []dns.RR) {
+	logging.RuntimeLog.Warningf("name server %s(%s) allows zone transfer of %s, records:%d", ns, server, zone, len(records))
+	c.addVulnerability(pocscan.Result{
+		Target:   zone,
+		Url:      fmt.Sprintf("%s:53", ns),
+		PocFile:  PocZoneTransfer,
+		Extra:    fmt.Sprintf("name server %s(%s) allows AXFR of %s, %d records returned", ns, server, zone, len(records)),
+		Severity: "high",
+	})
+
+	c.Lock()
+	defer c.Unlock()
+	for _, rr := range records {
+		domain, dar, ok := parseZoneRecord(zone, rr)
+		if !ok {
+			continue
+		}
+		if !result.HasDomain(domain) {
+			result.SetDomain(domain)
+		}
+		result.SetDomainAttr(domain, dar)
+	}
+}
+
+// addVulnerability 增加一个检查发现的漏洞
+func (c *DNSCheck) addVulnerability(vul pocscan.Result) {
+	c.Lock()
+	defer c.Unlock()
+
+	vul.Source = dnsCheckSource
+	vul.WorkspaceId = c.Config.WorkspaceId
+	c.VulResult = append(c.VulResult, vul)
+}
+
+// parseZoneRecord 将域传送的一条A、AAAA或CNAME记录转换为子域名的属性
+func parseZoneRecord(zone string, rr dns.RR) (domain string, dar DomainAttrResult, ok bool) {
+	domain = strings.ToLower(trimDot(rr.Header().Name))
+	if domain == zone || !dns.IsSubDomain(dns.Fqdn(zone), dns.Fqdn(domain)) {
+		return
+	}
+	// 通配符及服务记录不是主机
+	if strings.Contains(domain, "*") || strings.HasPrefix(domain, "_") || strings.Contains(domain, "._") {
+		return
+	}
+	dar.Source = AXFRSource
+	switch r := rr.(type) {
+	case *dns.A:
+		dar.Tag = "A"
+		dar.Content = r.A.String()
+	case *dns.AAAA:
+		dar.Tag = "AAAA"
+		dar.Content = r.AAAA.String()
+	case *dns.CNAME:
+		dar.Tag = "CNAME"
+		dar.Content = trimDot(r.Target)
+	default:
+		return
+	}
+	return domain, dar, true
+}
+
+// ZoneTransfer 向NS请求区域的域传送，返回区域的全部记录
+func ZoneTransfer(zone, server string) (records []dns.RR, err error) {
+	m := new(dns.Msg)
+	m.SetAxfr(dns.Fqdn(zone))
+	t := &dns.Transfer{DialTimeout: dnsRecordTimeout, ReadTimeout: axfrTimeout}
+	envelopes, err := t.In(m, server)
+	if err != nil {
+		return nil, err
+	}
+	// 需读取完channel中的全部数据，否则传送的协程无法退出
+	for e := range envelopes {
+		if e.Error != nil {
+			err = e.Error
+			continue
+		}
+		if len(records) < axfrRecordMax {
+			records = append(records, e.RR...)
+		}
+	}
+	// 允许域传送时返回的记录以SOA开始
+	if len(records) > 0 {
+		if _, ok := records[0].(*dns.SOA); !ok {
+			return nil, err
+		}
+	}
+	return
+}
+
+// IsOpenResolver 检查DNS服务器是否对任意来源提供递归解析，probeDomain为查询的域名
+func IsOpenResolver(server, probeDomain string) bool {
+	m := new(dns.Msg)
+	m.SetQuestion(dns.Fqdn(probeDomain), dns.TypeA)
+	m.RecursionDesired = true
+	client := &dns.Client{Timeout: dnsRecordTimeout}
+	in, _, err := client.Exchange(m, server)
+	if err != nil || in == nil {
+		return false
+	}
+	return in.Rcode == dns.RcodeSuccess && in.RecursionAvailable && len(in.Answer) > 0
+}
@@ -0,0 +1,107 @@
+package domainscan
+
+import (
+	"github.com/miekg/dns"
+	"net"
+	"testing"
+)
+
+// startTestDNSServer 启动本地的DNS服务，允许example.com的域传送并对其它域名提供递归解析
+func startTestDNSServer(t *testing.T, netType string) string {
+	handler := dns.HandlerFunc(func(w dns.ResponseWriter, r *dns.Msg) {
+		m := new(dns.Msg)
+		m.SetReply(r)
+		q := r.Question[0]
+		if q.Qtype == dns.TypeAXFR {
+			if q.Name != "example.com." {
+				m.SetRcode(r, dns.RcodeRefused)
+				w.WriteMsg(m)
+				return
+			}
+			for _, s := range []string{
+				"example.com. 300 IN SOA ns1.example.com. admin.example.com. 1 7200 3600 1209600 300",
+				"example.com. 300 IN NS ns1.example.com.",
+				"www.example.com. 300 IN A 192.0.2.1",
+				"mail.example.com. 300 IN AAAA 2001:db8::1",
+				"cdn.example.com. 300 IN CNAME cdn.example.net.",
+				"*.dev.example.com. 300 IN A 192.0.2.2",
+				"_sip._tcp.example.com. 300 IN SRV 10 60 5060 sip.example.com.",
+				"example.com. 300 IN SOA ns1.example.com. admin.example.com. 1 7200 3600 1209600 300",
+			} {
+				rr, _ := dns.NewRR(s)
+				m.Answer = append(m.Answer, rr)
+			}
+		} else {
+			m.RecursionAvailable = true
+			rr, _ := dns.NewRR(q.Name + " 300 IN A 192.0.2.100")
+			m.Answer = append(m.Answer, rr)
+		}
+		w.WriteMsg(m)
+	})
+	var server *dns.Server
+	if netType == "tcp" {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		server = &dns.Server{Listener: l, Handler: handler}
+	} else {
+		pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		server = &dns.Server{PacketConn: pc, Handler: handler}
+	}
+	started := make(chan struct{})
+	server.NotifyStartedFunc = func() { close(started) }
+	go server.ActivateAndServe()
+	<-started
+	t.Cleanup(func() { server.Shutdown() })
+	if server.Listener != nil {
+		return server.Listener.Addr().String()
+	}
+	return server.PacketConn.LocalAddr().String()
+}
+
+func TestZoneTransfer(t *testing.T) {
+	server := startTestDNSServer(t, "tcp")
+	records, err := ZoneTransfer("example.com", server)
+	if err != nil {
+		t.Fatal(err)
+	}
+	domains := make(map[string]DomainAttrResult)
+	for _, rr := range records {
+		if domain, dar, ok := parseZoneRecord("example.com", rr); ok {
+			domains[domain] = dar
+		}
+	}
+	expected := map[string]string{
+		"www.example.com":  "A 192.0.2.1",
+		"mail.example.com": "AAAA 2001:db8::1",
+		"cdn.example.com":  "CNAME cdn.example.net",
+	}
+	if len(domains) != len(expected) {
+		t.Fatalf("got %v", domains)
+	}
+	for domain, v := range expected {
+		dar := domains[domain]
+		if dar.Source != AXFRSource || dar.Tag+" "+dar.Content != v {
+			t.Errorf("%s: got %v, expected %s", domain, dar, v)
+		}
+	}
+	// 拒绝域传送
+	records, _ = ZoneTransfer("example.org", server)
+	if len(records) > 0 {
+		t.Errorf("zone transfer of example.org should be refused")
+	}
+}
+
+func TestIsOpenResolver(t *testing.T) {
+	server := startTestDNSServer(t, "udp")
+	if !IsOpenResolver(server, defaultOpenResolverProbe) {
+		t.Error("should be open resolver")
+	}
+	if IsOpenResolver("127.0.0.1:1", defaultOpenResolverProbe) {
+		t.Error("should not be open resolver")
+	}
+}
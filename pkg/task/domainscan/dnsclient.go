@@ -0,0 +1,108 @@
+package domainscan
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/miekg/dns"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// dohPrefix DNS over HTTPS服务器的前缀，如https://dns.alidns.com/dns-query
+	dohPrefix = "https://"
+	// dotPrefix DNS over TLS服务器的前缀，如tls://223.5.5.5:853
+	dotPrefix   = "tls://"
+	dotPort     = "853"
+	dohMimeType = "application/dns-message"
+)
+
+var dohClient = &http.Client{Timeout: dnsRecordTimeout}
+
+// IsEncryptedResolver 是否为DoH或DoT的DNS服务器
+func IsEncryptedResolver(resolver string) bool {
+	return strings.HasPrefix(resolver, dohPrefix) || strings.HasPrefix(resolver, dotPrefix)
+}
+
+// ExchangeDNS 向DNS服务器发送查询；支持UDP（截断时使用TCP重试）、DoT（tls://）及DoH（https://）
+func ExchangeDNS(m *dns.Msg, resolver string) (in *dns.Msg, rtt time.Duration, err error) {
+	switch {
+	case strings.HasPrefix(resolver, dohPrefix):
+		return exchangeDoH(m, resolver)
+	case strings.HasPrefix(resolver, dotPrefix):
+		client := &dns.Client{Net: "tcp-tls", Timeout: dnsRecordTimeout}
+		return client.Exchange(m, strings.TrimPrefix(resolver, dotPrefix))
+	}
+	client := &dns.Client{Timeout: dnsRecordTimeout}
+	in, rtt, err = client.Exchange(m, resolver)
+	if err == nil && in.Truncated {
+		tcpClient := &dns.Client{Net: "tcp", Timeout: dnsRecordTimeout}
+		in, rtt, err = tcpClient.Exchange(m, resolver)
+	}
+	return
+}
+
+// exchangeDoH 使用DNS over HTTPS（RFC8484）的POST方式发送查询
+func exchangeDoH(m *dns.Msg, resolver string) (in *dns.Msg, rtt time.Duration, err error) {
+	// RFC8484建议DoH请求的ID为0，以便于缓存
+	query := m.Copy()
+	query.Id = 0
+	data, err := query.Pack()
+	if err != nil {
+		return
+	}
+	req, err := http.NewRequest(http.MethodPost, resolver, bytes.NewReader(data))
+	if err != nil {
+		return
+	}
+	req.Header.Set("Content-Type", dohMimeType)
+	req.Header.Set("Accept", dohMimeType)
+
+	start := time.Now()
+	resp, err := dohClient.Do(req)
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, 0, fmt.Errorf("doh status:%d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, dns.MaxMsgSize))
+	if err != nil {
+		return
+	}
+	rtt = time.Since(start)
+	in = new(dns.Msg)
+	if err = in.Unpack(body); err != nil {
+		return nil, 0, err
+	}
+	in.Id = m.Id
+	return
+}
+
+// formatEncryptedResolver 格式化DoH及DoT的DNS服务器，DoT默认使用853端口
+func formatEncryptedResolver(s string) (string, error) {
+	if strings.HasPrefix(s, dohPrefix) {
+		u, err := url.Parse(s)
+		if err != nil || u.Host == "" {
+			return "", errors.New("invalid doh resolver")
+		}
+		return s, nil
+	}
+	host := strings.TrimPrefix(s, dotPrefix)
+	if h, port, err := net.SplitHostPort(host); err == nil {
+		if h == "" || port == "" {
+			return "", errors.New("invalid dot resolver")
+		}
+		return dotPrefix + net.JoinHostPort(h, port), nil
+	}
+	if host == "" || strings.ContainsAny(host, "/ ") {
+		return "", errors.New("invalid dot resolver")
+	}
+	return dotPrefix + net.JoinHostPort(strings.Trim(host, "[]"), dotPort), nil
+}
@@ -0,0 +1,319 @@
+package domainscan
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/hanc00l/nemo_go/pkg/conf"
+	"github.com/hanc00l/nemo_go/pkg/logging"
+	"github.com/hanc00l/nemo_go/pkg/task/custom"
+	"github.com/hanc00l/nemo_go/pkg/utils"
+	"github.com/miekg/dns"
+	"github.com/remeh/sizedwaitgroup"
+	"math/rand"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	dnsRecordTimeout = 3 * time.Second
+	dnsRecordRetries = 3
+	// udpBlockedCheckInterval UDP/53是否可用的检查结果的缓存时间
+	udpBlockedCheckInterval = 10 * time.Minute
+	// resolveListThreadNumber 使用DoH/DoT解析域名列表的并发数
+	resolveListThreadNumber = 50
+)
+
+var (
+	udpBlockedMutex     sync.Mutex
+	udpBlocked          bool
+	udpBlockedCheckTime time.Time
+)
+
+// DNSRecordTags 保存为域名属性的DNS记录类型
+var DNSRecordTags = []string{"NS", "SOA", "MX", "SPF", "DMARC", "TXT", "SRV", "CAA"}
+
+// srvServices 区域的常见SRV服务
+var srvServices = []string{
+	"_sip._tcp", "_sip._udp", "_sips._tcp", "_sipfederationtls._tcp",
+	"_xmpp-client._tcp", "_xmpp-server._tcp",
+	"_ldap._tcp", "_kerberos._tcp", "_kerberos._udp", "_kpasswd._tcp",
+	"_autodiscover._tcp", "_submission._tcp", "_imap._tcp", "_imaps._tcp", "_pop3s._tcp",
+	"_caldavs._tcp", "_carddavs._tcp", "_h323cs._tcp", "_minecraft._tcp",
+}
+
+// DNSRecord 通过配置的DNS服务器查询域名的MX、TXT、NS、SOA、SRV及CAA记录
+type DNSRecord struct {
+	resolvers []string
+	// encrypted 是否使用DoH/DoT的DNS服务器（UDP/53不可用时）
+	encrypted bool
+}
+
+// NewDNSRecord 创建DNSRecord对象；worker的UDP/53不可用时，使用配置的DoH/DoT服务器
+func NewDNSRecord() *DNSRecord {
+	resolvers := LoadResolvers(GetResolverFile())
+	if len(resolvers) == 0 {
+		// 没有配置时使用系统的DNS服务器
+		if cc, err := dns.ClientConfigFromFile("/etc/resolv.conf"); err == nil {
+			for _, s := range cc.Servers {
+				resolvers = append(resolvers, net.JoinHostPort(s, cc.Port))
+			}
+		}
+	}
+	d := &DNSRecord{resolvers: resolvers}
+	if isUDPBlocked(resolvers) {
+		var encryptedResolvers []string
+		for _, r := range conf.GlobalWorkerConfig().Domainscan.DoHResolver {
+			if resolver := formatResolver(strings.TrimSpace(r)); resolver != "" && IsEncryptedResolver(resolver) {
+				encryptedResolvers = append(encryptedResolvers, resolver)
+			}
+		}
+		if len(encryptedResolvers) > 0 {
+			logging.RuntimeLog.Warningf("udp/53 is blocked,use doh/dot resolver:%s", strings.Join(encryptedResolvers, ","))
+			d.resolvers = encryptedResolvers
+			d.encrypted = true
+		} else {
+			logging.RuntimeLog.Warning("udp/53 is blocked and no doh/dot resolver configured")
+		}
+	}
+	return d
+}
+
+// isUDPBlocked 检查worker的UDP/53是否不可用：列表中的DNS服务器均无响应时认为被阻断，检查结果缓存一段时间
+func isUDPBlocked(resolvers []string) bool {
+	udpBlockedMutex.Lock()
+	defer udpBlockedMutex.Unlock()
+
+	if time.Since(udpBlockedCheckTime) < udpBlockedCheckInterval {
+		return udpBlocked
+	}
+	m := new(dns.Msg)
+	m.SetQuestion(".", dns.TypeNS)
+	client := &dns.Client{Timeout: dnsRecordTimeout}
+	var probed int
+	blocked := true
+	for _, resolver := range resolvers {
+		if IsEncryptedResolver(resolver) {
+			continue
+		}
+		if _, _, err := client.Exchange(m, resolver); err == nil {
+			blocked = false
+			break
+		}
+		if probed++; probed >= dnsRecordRetries {
+			break
+		}
+	}
+	// 没有可检查的DNS服务器时不认为被阻断
+	udpBlocked = blocked && probed > 0
+	udpBlockedCheckTime = time.Now()
+	return udpBlocked
+}
+
+// LoadResolvers 读取DNS服务器列表文件，每行一个IP或IP:端口，或者DoH（https://）、DoT（tls://）的服务器
+func LoadResolvers(resolverFile string) (resolvers []string) {
+	f, err := os.Open(resolverFile)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if resolver := formatResolver(line); resolver != "" {
+			resolvers = append(resolvers, resolver)
+		}
+	}
+	return
+}
+
+// formatResolver 将DNS服务器格式化为IP:端口，DoH及DoT的服务器保留前缀
+func formatResolver(s string) string {
+	if IsEncryptedResolver(s) {
+		resolver, err := formatEncryptedResolver(s)
+		if err != nil {
+			return ""
+		}
+		return resolver
+	}
+	if utils.CheckIP(s) {
+		return net.JoinHostPort(s, "53")
+	}
+	host, port, err := net.SplitHostPort(s)
+	if err != nil || !utils.CheckIP(host) || port == "" {
+		return ""
+	}
+	return net.JoinHostPort(host, port)
+}
+
+// Lookup 查询一个域名的DNS记录；域名为区域的顶点（有SOA记录）时，同时查询NS、SRV及DMARC记录
+func (d *DNSRecord) Lookup(domain string) (results []DomainAttrResult) {
+	soa := d.Query(domain, dns.TypeSOA)
+	for _, rr := range soa {
+		results = append(results, DomainAttrResult{Source: "domainscan", Tag: "SOA", Content: rr})
+	}
+	for _, qtype := range []uint16{dns.TypeMX, dns.TypeTXT, dns.TypeCAA} {
+		for _, rr := range d.Query(domain, qtype) {
+			results = append(results, DomainAttrResult{Source: "domainscan", Tag: dns.TypeToString[qtype], Content: rr})
+			if qtype == dns.TypeTXT {
+				for _, include := range ParseSPFInclude(rr) {
+					results = append(results, DomainAttrResult{Source: "domainscan", Tag: "SPF", Content: include})
+				}
+			}
+		}
+	}
+	if len(soa) == 0 {
+		return
+	}
+	for _, rr := range d.Query(domain, dns.TypeNS) {
+		results = append(results, DomainAttrResult{Source: "domainscan", Tag: "NS", Content: rr})
+	}
+	for _, rr := range d.Query("_dmarc."+domain, dns.TypeTXT) {
+		if strings.HasPrefix(strings.ToLower(rr), "v=dmarc1") {
+			results = append(results, DomainAttrResult{Source: "domainscan", Tag: "DMARC", Content: rr})
+		}
+	}
+	for _, service := range srvServices {
+		for _, rr := range d.Query(fmt.Sprintf("%s.%s", service, domain), dns.TypeSRV) {
+			results = append(results, DomainAttrResult{Source: "domainscan", Tag: "SRV", Content: fmt.Sprintf("%s %s", service, rr)})
+		}
+	}
+	return
+}
+
+// Query 查询域名指定类型的记录，返回格式化后的记录内容
+func (d *DNSRecord) Query(domain string, qtype uint16) (records []string) {
+	in := d.exchange(domain, qtype)
+	if in == nil {
+		return
+	}
+	fqdn := dns.Fqdn(domain)
+	// 只取域名自身的记录，CNAME指向的目标的记录不属于该域名
+	for _, rr := range in.Answer {
+		if rr.Header().Rrtype != qtype || !strings.EqualFold(rr.Header().Name, fqdn) {
+			continue
+		}
+		if content := FormatDNSRecord(rr); content != "" {
+			records = append(records, content)
+		}
+	}
+	return
+}
+
+// ResolveHost 查询域名的A、AAAA记录及最终的CNAME，用于UDP/53不可用时代替系统的域名解析，以及泛解析的检查
+func (d *DNSRecord) ResolveHost(domain string) (CName string, Host []string) {
+	for _, qtype := range []uint16{dns.TypeA, dns.TypeAAAA} {
+		in := d.exchange(domain, qtype)
+		if in == nil {
+			continue
+		}
+		for _, rr := range in.Answer {
+			switch r := rr.(type) {
+			case *dns.A:
+				Host = append(Host, r.A.String())
+			case *dns.AAAA:
+				Host = append(Host, r.AAAA.String())
+			case *dns.CNAME:
+				// CNAME链的最后一个目标
+				CName = trimDot(r.Target)
+			}
+		}
+	}
+	return
+}
+
+// ResolveList 解析域名列表，有解析结果的域名保存到结果中；用于UDP/53不可用、无法调用massdns时
+func (d *DNSRecord) ResolveList(domains []string, result *Result) {
+	swg := sizedwaitgroup.New(resolveListThreadNumber)
+	blackDomain := custom.NewBlackTargetCheck(custom.CheckDomain)
+	for _, domain := range domains {
+		if blackDomain.CheckBlack(domain) {
+			continue
+		}
+		swg.Add()
+		go func(domain string) {
+			defer swg.Done()
+			// 只查询A记录（包括CNAME），减少DoH/DoT的请求数量
+			if in := d.exchange(domain, dns.TypeA); in == nil || in.Rcode != dns.RcodeSuccess || len(in.Answer) == 0 {
+				return
+			}
+			if !result.HasDomain(domain) {
+				result.SetDomain(domain)
+			}
+		}(domain)
+	}
+	swg.Wait()
+}
+
+// exchange 依次使用DNS服务器查询，返回第一个有效的响应
+func (d *DNSRecord) exchange(domain string, qtype uint16) *dns.Msg {
+	if len(d.resolvers) == 0 {
+		return nil
+	}
+	m := new(dns.Msg)
+	m.SetQuestion(dns.Fqdn(domain), qtype)
+	m.SetEdns0(4096, false)
+
+	start := rand.Intn(len(d.resolvers))
+	for i := 0; i < dnsRecordRetries && i < len(d.resolvers); i++ {
+		in, _, err := ExchangeDNS(m, d.resolvers[(start+i)%len(d.resolvers)])
+		if err != nil || in == nil {
+			continue
+		}
+		if in.Rcode != dns.RcodeSuccess && in.Rcode != dns.RcodeNameError {
+			continue
+		}
+		return in
+	}
+	return nil
+}
+
+// FormatDNSRecord 将一条DNS记录格式化为属性的内容
+func FormatDNSRecord(rr dns.RR) string {
+	switch r := rr.(type) {
+	case *dns.MX:
+		return fmt.Sprintf("%d %s", r.Preference, trimDot(r.Mx))
+	case *dns.TXT:
+		return strings.Join(r.Txt, "")
+	case *dns.NS:
+		return trimDot(r.Ns)
+	case *dns.SOA:
+		return fmt.Sprintf("%s %s %d %d %d %d %d", trimDot(r.Ns), trimDot(r.Mbox), r.Serial, r.Refresh, r.Retry, r.Expire, r.Minttl)
+	case *dns.SRV:
+		return fmt.Sprintf("%d %d %d %s", r.Priority, r.Weight, r.Port, trimDot(r.Target))
+	case *dns.CAA:
+		return fmt.Sprintf("%d %s \"%s\"", r.Flag, r.Tag, r.Value)
+	}
+	return ""
+}
+
+// ParseSPFInclude 从SPF记录中提取include及redirect的域名
+func ParseSPFInclude(txt string) (domains []string) {
+	fields := strings.Fields(txt)
+	if len(fields) == 0 || strings.ToLower(fields[0]) != "v=spf1" {
+		return
+	}
+	for _, field := range fields[1:] {
+		f := strings.ToLower(strings.TrimLeft(field, "+-~?"))
+		var domain string
+		if strings.HasPrefix(f, "include:") {
+			domain = strings.TrimPrefix(f, "include:")
+		} else if strings.HasPrefix(f, "redirect=") {
+			domain = strings.TrimPrefix(f, "redirect=")
+		}
+		if domain = trimDot(domain); domain != "" {
+			domains = append(domains, domain)
+		}
+	}
+	return
+}
+
+func trimDot(s string) string {
+	return strings.TrimSuffix(s, ".")
+}
@@ -0,0 +1,59 @@
+package domainscan
+
+import (
+	"github.com/miekg/dns"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFormatDNSRecord(t *testing.T) {
+	datas := map[string]string{
+		"example.com. 300 IN MX 10 mx1.example.com.":                                                   "10 mx1.example.com",
+		"example.com. 300 IN TXT \"v=spf1 include:_spf.example.net\" \" ~all\"":                        "v=spf1 include:_spf.example.net ~all",
+		"example.com. 300 IN NS ns1.example.com.":                                                      "ns1.example.com",
+		"_sip._tcp.example.com. 300 IN SRV 10 60 5060 sip.example.com.":                                "10 60 5060 sip.example.com",
+		"example.com. 300 IN CAA 0 issue \"letsencrypt.org\"":                                          "0 issue \"letsencrypt.org\"",
+		"example.com. 300 IN SOA ns1.example.com. admin.example.com. 2024010101 7200 3600 1209600 300": "ns1.example.com admin.example.com 2024010101 7200 3600 1209600 300",
+	}
+	for s, expected := range datas {
+		rr, err := dns.NewRR(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if content := FormatDNSRecord(rr); content != expected {
+			t.Errorf("%s: got %s, expected %s", s, content, expected)
+		}
+	}
+}
+
+func TestParseSPFInclude(t *testing.T) {
+	domains := ParseSPFInclude("v=spf1 ip4:1.2.3.4 include:_spf.google.com ~include:spf.example.net. redirect=_spf.example.com -all")
+	expected := []string{"_spf.google.com", "spf.example.net", "_spf.example.com"}
+	if len(domains) != len(expected) {
+		t.Fatalf("got %v", domains)
+	}
+	for i := range expected {
+		if domains[i] != expected[i] {
+			t.Errorf("got %s, expected %s", domains[i], expected[i])
+		}
+	}
+	if len(ParseSPFInclude("google-site-verification=abc include:x.com")) != 0 {
+		t.Error("not spf record")
+	}
+}
+
+func TestLoadResolvers(t *testing.T) {
+	resolverFile := filepath.Join(t.TempDir(), "resolver.txt")
+	os.WriteFile(resolverFile, []byte("223.5.5.5\n# comment\n\n8.8.8.8:5353\n2001:4860:4860::8888\nbad.resolver\n"), 0644)
+	resolvers := LoadResolvers(resolverFile)
+	expected := []string{"223.5.5.5:53", "8.8.8.8:5353", "[2001:4860:4860::8888]:53"}
+	if len(resolvers) != len(expected) {
+		t.Fatalf("got %v", resolvers)
+	}
+	for i := range expected {
+		if resolvers[i] != expected[i] {
+			t.Errorf("got %s, expected %s", resolvers[i], expected[i])
+		}
+	}
+}
@@ -14,18 +14,22 @@ import (
 )
 
 type Massdns struct {
-	Config Config
-	Result Result
+	Config    Config
+	Result    Result
+	Wildcard  *WildcardFilter
+	dnsRecord *DNSRecord
 }
 
 // NewMassdns 创建Massdns对象
 func NewMassdns(config Config) *Massdns {
-	return &Massdns{Config: config}
+	return &Massdns{Config: config, Wildcard: NewWildcardFilter()}
 }
 
 // Do 执行Massdns任务
 func (m *Massdns) Do() {
 	m.Result.DomainResult = make(map[string]*DomainResult)
+	m.dnsRecord = NewDNSRecord()
+	m.Wildcard.useDNSRecord(m.dnsRecord)
 	swg := sizedwaitgroup.New(massdnsThreadNumber[conf.WorkerPerformanceMode])
 	blackDomain := custom.NewBlackTargetCheck(custom.CheckDomain)
 	for _, line := range strings.Split(m.Config.Target, ",") {
@@ -40,7 +44,7 @@ func (m *Massdns) Do() {
 		swg.Add()
 		go func(d string) {
 			defer swg.Done()
-			m.RunMassdns(domain)
+			m.RunMassdns(d)
 		}(domain)
 	}
 	swg.Wait()
@@ -48,47 +52,51 @@ func (m *Massdns) Do() {
 
 // parseResult 解析子域名枚举结果文件
 func (m *Massdns) parseResult(outputTempFile string) {
-	content, err := os.ReadFile(outputTempFile)
-	if err != nil {
-		return
-	}
-	blackDomain := custom.NewBlackTargetCheck(custom.CheckDomain)
-	for _, line := range strings.Split(string(content), "\n") {
-		domain := strings.TrimSpace(line)
-		if domain == "" {
-			continue
-		}
-		if blackDomain.CheckBlack(domain) {
-			logging.RuntimeLog.Warningf("%s is in blacklist,skip...", domain)
-			continue
-		}
-		if !m.Result.HasDomain(domain) {
-			m.Result.SetDomain(domain)
-		}
-	}
+	parseMassdnsResult(outputTempFile, &m.Result)
 }
 
 // RunMassdns runs the massdns tool on the list of inputs
 func (m *Massdns) RunMassdns(domain string) {
+	// 爆破前检查域名的泛解析，爆破结果解析后再根据泛解析结果进行过滤
+	if m.Wildcard.Detect(domain) {
+		logging.RuntimeLog.Warningf("%s is wildcard dns,brute result will be filtered", domain)
+	}
+	wordlist := filepath.Join(conf.GetRootPath(), "thirdparty/dict", conf.GlobalWorkerConfig().Domainscan.Wordlist)
+	// UDP/53不可用时massdns无法使用，通过DoH/DoT解析字典生成的子域名
+	if m.dnsRecord != nil && m.dnsRecord.encrypted {
+		var domains []string
+		for _, word := range loadWordlist(wordlist) {
+			domains = append(domains, fmt.Sprintf("%s.%s", strings.ToLower(word), domain))
+		}
+		m.dnsRecord.ResolveList(domains, &m.Result)
+		return
+	}
 	tempOutputFile := utils.GetTempPathFileName()
 	defer os.Remove(tempOutputFile)
 
+	if runMassdnsRunner(domain, wordlist, "", tempOutputFile) {
+		m.parseResult(tempOutputFile)
+	}
+}
+
+// runMassdnsRunner 调用shuffledns的runner，使用字典爆破域名的子域名（wordlist），或者解析子域名列表文件（subdomainsList）
+func runMassdnsRunner(domain, wordlist, subdomainsList, outputFile string) bool {
 	tempDir, err := os.MkdirTemp("", utils.GetRandomString2(8))
 	if err != nil {
 		logging.RuntimeLog.Error(err)
 		logging.CLILog.Error(err)
-		return
+		return false
 	}
 	defer os.RemoveAll(tempDir)
 
 	options := &runner.Options{
 		Directory:          tempDir,
 		Domain:             domain,
-		SubdomainsList:     "",
-		ResolversFile:      filepath.Join(conf.GetRootPath(), "thirdparty/dict", conf.GlobalWorkerConfig().Domainscan.Resolver),
-		Wordlist:           filepath.Join(conf.GetRootPath(), "thirdparty/dict", conf.GlobalWorkerConfig().Domainscan.Wordlist),
+		SubdomainsList:     subdomainsList,
+		ResolversFile:      GetResolverFile(),
+		Wordlist:           wordlist,
 		MassdnsPath:        filepath.Join(conf.GetRootPath(), "thirdparty/massdns", utils.GetThirdpartyBinNameByPlatform(utils.MassDns)),
-		Output:             tempOutputFile,
+		Output:             outputFile,
 		Json:               false,
 		Silent:             false,
 		Version:            false,
@@ -107,8 +115,30 @@ func (m *Massdns) RunMassdns(domain string) {
 		msg := fmt.Sprintf("Could not create runner: %s", err)
 		logging.RuntimeLog.Errorf(msg)
 		logging.CLILog.Errorf(msg)
+		return false
 	}
-
 	massdnsRunner.RunEnumeration()
-	m.parseResult(tempOutputFile)
+	return true
+}
+
+// parseMassdnsResult 解析massdns的结果文件，子域名保存到结果中
+func parseMassdnsResult(outputFile string, result *Result) {
+	content, err := os.ReadFile(outputFile)
+	if err != nil {
+		return
+	}
+	blackDomain := custom.NewBlackTargetCheck(custom.CheckDomain)
+	for _, line := range strings.Split(string(content), "\n") {
+		domain := strings.TrimSpace(line)
+		if domain == "" {
+			continue
+		}
+		if blackDomain.CheckBlack(domain) {
+			logging.RuntimeLog.Warningf("%s is in blacklist,skip...", domain)
+			continue
+		}
+		if !result.HasDomain(domain) {
+			result.SetDomain(domain)
+		}
+	}
 }
@@ -0,0 +1,333 @@
+package domainscan
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/hanc00l/nemo_go/pkg/conf"
+	"github.com/hanc00l/nemo_go/pkg/logging"
+	"github.com/hanc00l/nemo_go/pkg/task/custom"
+	"github.com/hanc00l/nemo_go/pkg/utils"
+	"github.com/remeh/sizedwaitgroup"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// permuteKnownMax 每个域名用于生成变换的已有子域名的最大数量
+	permuteKnownMax = 2000
+	// permuteWordMax 从已有子域名中提取的单词的最大数量
+	permuteWordMax = 200
+	// permuteCandidateMax 每个域名生成的候选子域名的最大数量
+	permuteCandidateMax = 500000
+	// permuteNumberDelta 子域名中数字递增、递减的范围
+	permuteNumberDelta = 3
+)
+
+// permuteEnvTokens 环境标识，子域名中的环境标识相互替换
+var permuteEnvTokens = []string{"dev", "test", "uat", "sit", "qa", "pre", "stage", "staging", "prod", "beta", "demo", "bak", "old", "new"}
+
+var (
+	permuteNumberRegex = regexp.MustCompile(`\d+`)
+	permuteLabelRegex  = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+	// 从已有子域名中提取的单词只保留字母
+	permuteWordRegex = regexp.MustCompile(`^[a-z]{2,}$`)
+)
+
+// Permute 对工作空间中已有的子域名进行变换（插入及拼接单词、数字递增递减、替换环境标识），解析生成的候选子域名
+type Permute struct {
+	Config   Config
+	Result   Result
+	Wildcard *WildcardFilter
+	// KnownDomains 工作空间中已有的子域名
+	KnownDomains []string
+	dnsRecord    *DNSRecord
+}
+
+// NewPermute 创建Permute对象
+func NewPermute(config Config) *Permute {
+	return &Permute{Config: config, Wildcard: NewWildcardFilter()}
+}
+
+// Do 执行子域名变换任务
+func (p *Permute) Do() {
+	p.Result.DomainResult = make(map[string]*DomainResult)
+	p.dnsRecord = NewDNSRecord()
+	p.Wildcard.useDNSRecord(p.dnsRecord)
+	words := loadPermuteWords()
+	swg := sizedwaitgroup.New(massdnsThreadNumber[conf.WorkerPerformanceMode])
+	blackDomain := custom.NewBlackTargetCheck(custom.CheckDomain)
+	for _, line := range strings.Split(p.Config.Target, ",") {
+		domain := strings.ToLower(strings.TrimSpace(line))
+		if domain == "" || utils.CheckIPOrSubnet(domain) {
+			continue
+		}
+		if blackDomain.CheckBlack(domain) {
+			logging.RuntimeLog.Warningf("%s is in blacklist,skip...", domain)
+			continue
+		}
+		var known []string
+		for _, k := range p.KnownDomains {
+			if strings.HasSuffix(k, "."+domain) {
+				known = append(known, k)
+			}
+		}
+		if len(known) == 0 {
+			logging.RuntimeLog.Infof("%s has no known subdomain for permutation,skip...", domain)
+			continue
+		}
+		swg.Add()
+		go func(d string, k []string) {
+			defer swg.Done()
+			p.RunPermute(d, k, words)
+		}(domain, known)
+	}
+	swg.Wait()
+}
+
+// RunPermute 生成一个域名的候选子域名，并调用massdns进行解析
+func (p *Permute) RunPermute(domain string, known []string, words []string) {
+	candidates := GeneratePermutation(domain, known, words)
+	if len(candidates) == 0 {
+		return
+	}
+	logging.RuntimeLog.Infof("%s permutation: known %d,candidate %d", domain, len(known), len(candidates))
+	// 泛解析的域名，解析结果在解析后根据泛解析结果进行过滤
+	if p.Wildcard.Detect(domain) {
+		logging.RuntimeLog.Warningf("%s is wildcard dns,permutation result will be filtered", domain)
+	}
+	// UDP/53不可用时massdns无法使用，通过DoH/DoT解析
+	if p.dnsRecord != nil && p.dnsRecord.encrypted {
+		p.dnsRecord.ResolveList(candidates, &p.Result)
+		return
+	}
+	inputFile := utils.GetTempPathFileName()
+	defer os.Remove(inputFile)
+	if err := os.WriteFile(inputFile, []byte(strings.Join(candidates, "\n")), 0644); err != nil {
+		logging.RuntimeLog.Error(err)
+		logging.CLILog.Error(err)
+		return
+	}
+	outputFile := utils.GetTempPathFileName()
+	defer os.Remove(outputFile)
+
+	if runMassdnsRunner(domain, "", inputFile, outputFile) {
+		parseMassdnsResult(outputFile, &p.Result)
+	}
+}
+
+// GeneratePermutation 根据已有的子域名及单词生成候选子域名，结果中不包括已有的子域名
+func GeneratePermutation(domain string, known []string, words []string) (candidates []string) {
+	domain = strings.ToLower(domain)
+	knownMap := make(map[string]struct{})
+	var prefixes [][]string
+	for _, k := range known {
+		k = strings.ToLower(strings.TrimSuffix(k, "."))
+		if _, ok := knownMap[k]; ok || !strings.HasSuffix(k, "."+domain) {
+			continue
+		}
+		knownMap[k] = struct{}{}
+		prefix := strings.TrimSuffix(k, "."+domain)
+		if strings.ContainsAny(prefix, "*_") {
+			continue
+		}
+		prefixes = append(prefixes, strings.Split(prefix, "."))
+	}
+	if len(prefixes) > permuteKnownMax {
+		prefixes = prefixes[:permuteKnownMax]
+	}
+	allWords := mergePermuteWords(words, prefixes)
+
+	candidateMap := make(map[string]struct{})
+	add := func(labels []string) bool {
+		for _, label := range labels {
+			if !permuteLabelRegex.MatchString(label) {
+				return true
+			}
+		}
+		c := strings.Join(labels, ".") + "." + domain
+		if len(c) > 253 {
+			return true
+		}
+		if _, ok := knownMap[c]; ok {
+			return true
+		}
+		if _, ok := candidateMap[c]; !ok {
+			candidateMap[c] = struct{}{}
+			candidates = append(candidates, c)
+		}
+		return len(candidates) < permuteCandidateMax
+	}
+	for _, labels := range prefixes {
+		for _, mutated := range permuteNumber(labels) {
+			if !add(mutated) {
+				return
+			}
+		}
+		for _, mutated := range permuteEnvToken(labels) {
+			if !add(mutated) {
+				return
+			}
+		}
+		for _, w := range allWords {
+			for _, mutated := range permuteWord(labels, w) {
+				if !add(mutated) {
+					return
+				}
+			}
+		}
+	}
+	return
+}
+
+// permuteWord 在子域名的各级之间插入单词，以及在第一级的前后拼接单词
+func permuteWord(labels []string, word string) (results [][]string) {
+	for i := 0; i <= len(labels); i++ {
+		mutated := make([]string, 0, len(labels)+1)
+		mutated = append(mutated, labels[:i]...)
+		mutated = append(mutated, word)
+		mutated = append(mutated, labels[i:]...)
+		results = append(results, mutated)
+	}
+	for _, first := range []string{word + "-" + labels[0], labels[0] + "-" + word, word + labels[0], labels[0] + word} {
+		results = append(results, replaceLabel(labels, 0, first))
+	}
+	return
+}
+
+// permuteNumber 子域名中的数字进行递增和递减，保留数字的位数
+func permuteNumber(labels []string) (results [][]string) {
+	for i, label := range labels {
+		for _, loc := range permuteNumberRegex.FindAllStringIndex(label, -1) {
+			numStr := label[loc[0]:loc[1]]
+			num, err := strconv.Atoi(numStr)
+			if err != nil {
+				continue
+			}
+			for delta := -permuteNumberDelta; delta <= permuteNumberDelta; delta++ {
+				if delta == 0 || num+delta < 0 {
+					continue
+				}
+				newNum := fmt.Sprintf("%0*d", len(numStr), num+delta)
+				results = append(results, replaceLabel(labels, i, label[:loc[0]]+newNum+label[loc[1]:]))
+			}
+		}
+	}
+	return
+}
+
+// permuteEnvToken 将子域名中的环境标识替换为其它的环境标识
+func permuteEnvToken(labels []string) (results [][]string) {
+	for i, label := range labels {
+		parts := strings.Split(label, "-")
+		for j, part := range parts {
+			if !isPermuteEnvToken(part) {
+				continue
+			}
+			for _, token := range permuteEnvTokens {
+				if token == part {
+					continue
+				}
+				newParts := append([]string{}, parts...)
+				newParts[j] = token
+				results = append(results, replaceLabel(labels, i, strings.Join(newParts, "-")))
+			}
+		}
+	}
+	return
+}
+
+// isPermuteEnvToken 是否为环境标识
+func isPermuteEnvToken(s string) bool {
+	for _, token := range permuteEnvTokens {
+		if s == token {
+			return true
+		}
+	}
+	return false
+}
+
+// replaceLabel 返回替换了指定一级的新子域名
+func replaceLabel(labels []string, index int, label string) []string {
+	mutated := append([]string{}, labels...)
+	mutated[index] = label
+	return mutated
+}
+
+// mergePermuteWords 合并字典的单词、环境标识以及从已有子域名中提取的单词
+func mergePermuteWords(words []string, prefixes [][]string) (results []string) {
+	wordMap := make(map[string]struct{})
+	addWord := func(w string) {
+		if _, ok := wordMap[w]; !ok && permuteLabelRegex.MatchString(w) {
+			wordMap[w] = struct{}{}
+			results = append(results, w)
+		}
+	}
+	for _, w := range words {
+		addWord(strings.ToLower(w))
+	}
+	for _, w := range permuteEnvTokens {
+		addWord(w)
+	}
+	// 已有子域名中出现次数最多的单词
+	wordCount := make(map[string]int)
+	for _, labels := range prefixes {
+		for _, label := range labels {
+			for _, part := range strings.Split(label, "-") {
+				part = strings.TrimRight(part, "0123456789")
+				if permuteWordRegex.MatchString(part) {
+					wordCount[part]++
+				}
+			}
+		}
+	}
+	knownWords := make([]string, 0, len(wordCount))
+	for w := range wordCount {
+		knownWords = append(knownWords, w)
+	}
+	sort.Slice(knownWords, func(i, j int) bool {
+		if wordCount[knownWords[i]] != wordCount[knownWords[j]] {
+			return wordCount[knownWords[i]] > wordCount[knownWords[j]]
+		}
+		return knownWords[i] < knownWords[j]
+	})
+	if len(knownWords) > permuteWordMax {
+		knownWords = knownWords[:permuteWordMax]
+	}
+	for _, w := range knownWords {
+		addWord(w)
+	}
+	return
+}
+
+// loadPermuteWords 读取子域名变换的字典
+func loadPermuteWords() (words []string) {
+	wordlist := conf.GlobalWorkerConfig().Domainscan.PermuteWordlist
+	if wordlist == "" {
+		return
+	}
+	return loadWordlist(filepath.Join(conf.GetRootPath(), "thirdparty/dict", wordlist))
+}
+
+// loadWordlist 读取字典文件，忽略空行及#开始的注释行
+func loadWordlist(wordlistFile string) (words []string) {
+	f, err := os.Open(wordlistFile)
+	if err != nil {
+		logging.RuntimeLog.Error(err)
+		return
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		words = append(words, line)
+	}
+	return
+}
@@ -0,0 +1,56 @@
+package domainscan
+
+import (
+	"testing"
+)
+
+func TestGeneratePermutation(t *testing.T) {
+	known := []string{"www2.example.com", "api.dev.example.com", "mail.example.com", "*.example.com", "other.com"}
+	candidates := GeneratePermutation("example.com", known, []string{"admin"})
+	candidateMap := make(map[string]struct{})
+	for _, c := range candidates {
+		candidateMap[c] = struct{}{}
+	}
+	expected := []string{
+		// 数字递增递减
+		"www0.example.com", "www1.example.com", "www3.example.com", "www5.example.com",
+		// 环境标识替换
+		"api.test.example.com", "api.uat.example.com",
+		// 插入单词
+		"admin.mail.example.com", "mail.admin.example.com", "api.admin.dev.example.com",
+		// 拼接单词
+		"mail-admin.example.com", "admin-mail.example.com", "mailadmin.example.com",
+		// 已有子域名中提取的单词
+		"api.mail.example.com", "www2-dev.example.com",
+	}
+	for _, e := range expected {
+		if _, ok := candidateMap[e]; !ok {
+			t.Errorf("%s not generated", e)
+		}
+	}
+	for _, k := range append(known, "www-1.example.com") {
+		if _, ok := candidateMap[k]; ok {
+			t.Errorf("%s should not be generated", k)
+		}
+	}
+	if len(candidates) != len(candidateMap) {
+		t.Errorf("duplicated candidates: %d/%d", len(candidates), len(candidateMap))
+	}
+}
+
+func TestPermuteNumber(t *testing.T) {
+	results := permuteNumber([]string{"node01", "db"})
+	var got []string
+	for _, labels := range results {
+		got = append(got, labels[0])
+	}
+	expected := []string{"node00", "node02", "node03", "node04"}
+	if len(got) != len(expected) {
+		t.Fatalf("got %v, expected %v", got, expected)
+	}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Errorf("got %v, expected %v", got, expected)
+		}
+	}
+}
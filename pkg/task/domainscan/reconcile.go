@@ -0,0 +1,124 @@
+package domainscan
+
+import (
+	"fmt"
+	"github.com/hanc00l/nemo_go/pkg/db"
+	"github.com/hanc00l/nemo_go/pkg/task/custom"
+	"github.com/remeh/sizedwaitgroup"
+	"strings"
+	"sync"
+)
+
+// Reconcile 对比域名扫描的目标范围与maintask所有子任务的扫描结果，由server在maintask完成后执行一次；
+// 范围内已有但本次未发现的域名重新解析，仍无法解析时增加未发现次数，连续未发现的次数达到staleMissCount时标记为失效；
+// 启用子域名枚举时范围包括目标的已有子域名
+func (r *Result) Reconcile(config Config, staleMissCount int) string {
+	if staleMissCount <= 0 || config.Target == "" {
+		return ""
+	}
+	blackDomain := custom.NewBlackTargetCheck(custom.CheckDomain)
+	isSubDomain := config.IsSubDomainFinder || config.IsSubDomainBrute
+
+	var missDomains []db.Domain
+	var missDomainNames []string
+	for _, domain := range getScopeDomains(config.Target, config.WorkspaceId, isSubDomain) {
+		if r.HasDomain(domain.DomainName) || blackDomain.CheckBlack(domain.DomainName) {
+			continue
+		}
+		missDomains = append(missDomains, domain)
+		missDomainNames = append(missDomainNames, domain.DomainName)
+	}
+	if len(missDomains) == 0 {
+		return ""
+	}
+	// 其它方式（如FOFA、证书SAN、域传送）发现的域名不一定在本次的结果中，能重新解析的域名不作为未发现
+	alive := resolveAliveDomains(config.Target, missDomainNames)
+
+	var staleDomain int
+	for i := range missDomains {
+		domain := &missDomains[i]
+		if _, ok := alive[domain.DomainName]; ok {
+			if domain.MissCount > 0 || domain.IsStale {
+				domain.UpdateMissCount(0, false)
+			}
+			continue
+		}
+		missCount := domain.MissCount + 1
+		wasStale := domain.IsStale
+		if !domain.UpdateMissCount(missCount, missCount >= staleMissCount) || !domain.IsStale || wasStale {
+			continue
+		}
+		db.SaveAssetChange(config.WorkspaceId, db.AssetTypeDomain, domain.DomainName, 0, db.AssetChangeStale, "", "domain", fmt.Sprintf("miss:%d", missCount), "", config.TaskId)
+		staleDomain++
+	}
+	if staleDomain > 0 {
+		return fmt.Sprintf("domainStale:%d", staleDomain)
+	}
+	return ""
+}
+
+// getScopeDomains 获取扫描目标范围内已有的域名记录
+func getScopeDomains(target string, workspaceId int, isSubDomain bool) (results []db.Domain) {
+	domainIdMap := make(map[int]struct{})
+	for _, t := range strings.Split(target, ",") {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if t == "" {
+			continue
+		}
+		var domains []db.Domain
+		if isSubDomain {
+			domain := db.Domain{}
+			domains, _ = domain.Gets(map[string]interface{}{"domain": t, "workspace_id": workspaceId}, 0, 0, false)
+		} else {
+			domain := db.Domain{DomainName: t, WorkspaceId: workspaceId}
+			if domain.GetByDomain() {
+				domains = append(domains, domain)
+			}
+		}
+		for _, domain := range domains {
+			if _, ok := domainIdMap[domain.Id]; ok {
+				continue
+			}
+			// 模糊查询的结果中只保留目标本身及其子域名
+			if domain.DomainName != t && !strings.HasSuffix(domain.DomainName, "."+t) {
+				continue
+			}
+			domainIdMap[domain.Id] = struct{}{}
+			results = append(results, domain)
+		}
+	}
+	return
+}
+
+// resolveAliveDomains 重新解析域名，返回仍能解析的域名；泛解析的目标下与泛解析结果相同的子域名不作为能解析
+func resolveAliveDomains(target string, domains []string) map[string]struct{} {
+	dnsRecord := NewDNSRecord()
+	wildcard := NewWildcardFilter()
+	wildcard.useDNSRecord(dnsRecord)
+	for _, t := range strings.Split(target, ",") {
+		if t = strings.ToLower(strings.TrimSpace(t)); t != "" {
+			wildcard.Detect(t)
+		}
+	}
+	alive := make(map[string]struct{})
+	var mutex sync.Mutex
+	swg := sizedwaitgroup.New(resolveListThreadNumber)
+	for _, domain := range domains {
+		swg.Add()
+		go func(domain string) {
+			defer swg.Done()
+			cname, hosts := dnsRecord.ResolveHost(domain)
+			if len(hosts) == 0 {
+				return
+			}
+			if _, ok := wildcard.Match(domain, hosts, cname); ok {
+				return
+			}
+			mutex.Lock()
+			alive[domain] = struct{}{}
+			mutex.Unlock()
+		}(domain)
+	}
+	swg.Wait()
+	return alive
+}
@@ -1,6 +1,7 @@
 package domainscan
 
 import (
+	"errors"
 	"github.com/hanc00l/nemo_go/pkg/conf"
 	"github.com/hanc00l/nemo_go/pkg/logging"
 	"github.com/hanc00l/nemo_go/pkg/task/custom"
@@ -8,11 +9,20 @@ import (
 	"github.com/remeh/sizedwaitgroup"
 	"net"
 	"strings"
+	"sync"
 )
 
 type Resolve struct {
-	Config Config
-	Result Result
+	Config    Config
+	Result    Result
+	dnsRecord *DNSRecord
+	// isDNSRecord 是否收集主域名的MX、TXT、NS、SOA、SRV及CAA记录
+	isDNSRecord bool
+	// 系统DNS解析失败时使用的DoH/DoT解析，只在需要时创建一次
+	encryptedOnce   sync.Once
+	encryptedRecord *DNSRecord
+	targets         map[string]struct{}
+	tld             TldExtract
 }
 
 // NewResolve 创建resolve对象
@@ -23,6 +33,16 @@ func NewResolve(config Config) *Resolve {
 // Do 执行域名解析
 func (r *Resolve) Do() {
 	swg := sizedwaitgroup.New(resolveThreadNumber[conf.WorkerPerformanceMode])
+	if r.isDNSRecord = conf.GlobalWorkerConfig().Domainscan.IsDNSRecord; r.isDNSRecord {
+		r.dnsRecord = NewDNSRecord()
+		r.targets = make(map[string]struct{})
+		for _, line := range strings.Split(r.Config.Target, ",") {
+			if domain := strings.ToLower(strings.TrimSpace(line)); domain != "" {
+				r.targets[domain] = struct{}{}
+			}
+		}
+		r.tld = NewTldExtract()
+	}
 	blackDomain := custom.NewBlackTargetCheck(custom.CheckDomain)
 	// 如果Result中已有map[domain]*DomainResult，则遍历并解析域名
 	if r.Result.DomainResult != nil {
@@ -64,7 +84,22 @@ func (r *Resolve) RunResolve(domain string) {
 	if !r.Result.HasDomain(domain) {
 		r.Result.SetDomain(domain)
 	}
-	_, host := ResolveDomain(domain)
+	var cname string
+	var host []string
+	var encrypted bool
+	if r.dnsRecord != nil && r.dnsRecord.encrypted {
+		// UDP/53不可用时使用DoH/DoT解析
+		cname, host = r.dnsRecord.ResolveHost(domain)
+		encrypted = true
+	} else {
+		var err error
+		if host, err = net.LookupHost(domain); err != nil && !isNotFound(err) {
+			if d := r.getEncryptedRecord(); d != nil {
+				cname, host = d.ResolveHost(domain)
+				encrypted = true
+			}
+		}
+	}
 	if len(host) > 0 {
 		for _, h := range host {
 			dar := DomainAttrResult{
@@ -84,7 +119,14 @@ func (r *Resolve) RunResolve(domain string) {
 	}
 	//CDN检查
 	cdnCheck := custom.NewCDNCheck()
-	isCDN, cdnName, CName := cdnCheck.CheckCName(domain)
+	var isCDN bool
+	var cdnName, CName string
+	if encrypted {
+		CName = cname
+		isCDN, cdnName = cdnCheck.CheckCNameTarget(cname)
+	} else {
+		isCDN, cdnName, CName = cdnCheck.CheckCName(domain)
+	}
 	if isCDN {
 		r.Result.SetDomainAttr(domain, DomainAttrResult{
 			Source:  "domainscan",
@@ -99,6 +141,44 @@ func (r *Resolve) RunResolve(domain string) {
 			Content: CName,
 		})
 	}
+	// MX、TXT、NS、SOA、SRV及CAA记录
+	if r.dnsRecord != nil && r.isMainDomain(domain) {
+		for _, dar := range r.dnsRecord.Lookup(domain) {
+			r.Result.SetDomainAttr(domain, dar)
+		}
+	}
+}
+
+// getEncryptedRecord 系统DNS解析失败时才检查UDP/53是否可用，不可用时返回使用DoH/DoT的DNSRecord，否则返回nil
+func (r *Resolve) getEncryptedRecord() *DNSRecord {
+	r.encryptedOnce.Do(func() {
+		// 收集DNS记录时已检查过
+		if r.dnsRecord != nil {
+			return
+		}
+		if d := NewDNSRecord(); d.encrypted {
+			r.encryptedRecord = d
+		}
+	})
+	return r.encryptedRecord
+}
+
+// isNotFound 域名不存在的解析错误
+func isNotFound(err error) bool {
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr) && dnsErr.IsNotFound
+}
+
+// isMainDomain 是否需要收集DNS记录：只收集任务目标的域名及主域名（区域的顶点），不收集爆破等方式得到的子域名
+func (r *Resolve) isMainDomain(domain string) bool {
+	if !r.isDNSRecord {
+		return false
+	}
+	domain = strings.ToLower(domain)
+	if _, ok := r.targets[domain]; ok {
+		return true
+	}
+	return r.tld.extract != nil && r.tld.ExtractFLD(domain) == domain
 }
 
 // ResolveDomain 解析一个域名的A记录和CNAME记录
@@ -23,3 +23,18 @@ func TestResolveDomainIPV6(t *testing.T) {
 		}
 	}
 }
+
+func TestResolve_isMainDomain(t *testing.T) {
+	r := NewResolve(Config{})
+	if r.isMainDomain("example.com") {
+		t.Error("dns record should be disabled by default")
+	}
+	r.isDNSRecord = true
+	r.targets = map[string]struct{}{"example.com": {}}
+	if !r.isMainDomain("Example.com") {
+		t.Error("target domain should collect dns record")
+	}
+	if r.isMainDomain("www.example.com") {
+		t.Error("subdomain should not collect dns record")
+	}
+}
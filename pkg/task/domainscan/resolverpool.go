@@ -0,0 +1,245 @@
+package domainscan
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/hanc00l/nemo_go/pkg/conf"
+	"github.com/hanc00l/nemo_go/pkg/logging"
+	"github.com/hanc00l/nemo_go/pkg/utils"
+	"github.com/miekg/dns"
+	"github.com/remeh/sizedwaitgroup"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// ResolverCheckedFile 检查后可用的DNS服务器列表，位于thirdparty/dict，由server生成并同步到worker
+	ResolverCheckedFile = "resolver_checked.txt"
+	// resolverCheckRounds 每个DNS服务器检查的次数
+	resolverCheckRounds = 3
+	// resolverCheckThreadNumber 检查DNS服务器的并发数
+	resolverCheckThreadNumber = 20
+	// defaultResolverProbeDomain 检查解析结果的默认域名
+	defaultResolverProbeDomain = "www.baidu.com"
+)
+
+// ResolverCheckResult 一个DNS服务器的检查结果
+type ResolverCheckResult struct {
+	Resolver string
+	// Alive 是否有响应
+	Alive bool
+	// Correct 解析结果与可信DNS服务器一致，且不存在的域名返回NXDOMAIN
+	Correct bool
+	// Latency 平均响应时间
+	Latency time.Duration
+	Message string
+}
+
+// ResolverChecker 检查DNS服务器的可用性、解析结果的正确性及响应时间
+type ResolverChecker struct {
+	ProbeDomain string
+	MaxLatency  time.Duration
+	// baseline 可信DNS服务器对检查域名的解析结果（IP及CNAME）
+	baseline map[string]struct{}
+	// baselineNX 可信DNS服务器对不存在的域名是否返回NXDOMAIN
+	baselineNX bool
+}
+
+// NewResolverChecker 创建ResolverChecker对象，并使用可信的DNS服务器获取基准的解析结果
+func NewResolverChecker(trusted []string, probeDomain string, maxLatency time.Duration) *ResolverChecker {
+	if probeDomain == "" {
+		probeDomain = defaultResolverProbeDomain
+	}
+	c := &ResolverChecker{
+		ProbeDomain: probeDomain,
+		MaxLatency:  maxLatency,
+		baseline:    make(map[string]struct{}),
+	}
+	for _, t := range trusted {
+		resolver := formatResolver(strings.TrimSpace(t))
+		if resolver == "" {
+			continue
+		}
+		if in, _, err := c.query(resolver, probeDomain); err == nil && in.Rcode == dns.RcodeSuccess {
+			for answer := range parseResolverAnswer(in) {
+				c.baseline[answer] = struct{}{}
+			}
+		}
+		if in, _, err := c.query(resolver, c.nxDomain()); err == nil && in.Rcode == dns.RcodeNameError {
+			c.baselineNX = true
+		}
+	}
+	if len(c.baseline) == 0 {
+		logging.RuntimeLog.Warningf("get baseline of %s from trusted resolver fail,skip answer check", probeDomain)
+	}
+	return c
+}
+
+// Check 检查一个DNS服务器
+func (c *ResolverChecker) Check(resolver string) (r ResolverCheckResult) {
+	r.Resolver = resolver
+	var success int
+	var latency time.Duration
+	var answers map[string]struct{}
+	for i := 0; i < resolverCheckRounds; i++ {
+		in, rtt, err := c.query(resolver, c.ProbeDomain)
+		if err != nil || in == nil {
+			continue
+		}
+		success++
+		latency += rtt
+		if in.Rcode == dns.RcodeSuccess && answers == nil {
+			answers = parseResolverAnswer(in)
+		}
+	}
+	if success == 0 {
+		r.Message = "no response"
+		return
+	}
+	r.Alive = true
+	r.Latency = latency / time.Duration(success)
+	if len(answers) == 0 {
+		r.Message = "no answer"
+		return
+	}
+	if len(c.baseline) > 0 && !isAnswerMatched(answers, c.baseline) {
+		r.Message = fmt.Sprintf("answer mismatch:%s", strings.Join(utils.SetToSlice(answers), ","))
+		return
+	}
+	// 对不存在的域名返回解析结果的DNS服务器会污染子域名爆破的结果
+	if c.baselineNX {
+		in, _, err := c.query(resolver, c.nxDomain())
+		if err != nil || in == nil {
+			r.Message = "nxdomain no response"
+			return
+		}
+		if in.Rcode != dns.RcodeNameError || len(in.Answer) > 0 {
+			r.Message = "nxdomain hijacked"
+			return
+		}
+	}
+	r.Correct = true
+	if c.MaxLatency > 0 && r.Latency > c.MaxLatency {
+		r.Message = fmt.Sprintf("latency %dms", r.Latency.Milliseconds())
+	}
+	return
+}
+
+// Usable 检查结果是否可用
+func (c *ResolverChecker) Usable(r ResolverCheckResult) bool {
+	return r.Alive && r.Correct && (c.MaxLatency <= 0 || r.Latency <= c.MaxLatency)
+}
+
+// CheckAll 并发检查DNS服务器列表
+func (c *ResolverChecker) CheckAll(resolvers []string) (results []ResolverCheckResult) {
+	results = make([]ResolverCheckResult, len(resolvers))
+	swg := sizedwaitgroup.New(resolverCheckThreadNumber)
+	for i, resolver := range resolvers {
+		swg.Add()
+		go func(i int, resolver string) {
+			defer swg.Done()
+			results[i] = c.Check(resolver)
+		}(i, resolver)
+	}
+	swg.Wait()
+	return
+}
+
+// query 查询域名的A记录
+func (c *ResolverChecker) query(resolver, domain string) (*dns.Msg, time.Duration, error) {
+	m := new(dns.Msg)
+	m.SetQuestion(dns.Fqdn(domain), dns.TypeA)
+	m.RecursionDesired = true
+	return ExchangeDNS(m, resolver)
+}
+
+// nxDomain 检查域名下的随机的不存在的域名
+func (c *ResolverChecker) nxDomain() string {
+	return fmt.Sprintf("%s.%s", utils.GetRandomString2(16), c.ProbeDomain)
+}
+
+// parseResolverAnswer 解析结果中的IP及CNAME
+func parseResolverAnswer(in *dns.Msg) map[string]struct{} {
+	answers := make(map[string]struct{})
+	for _, rr := range in.Answer {
+		switch r := rr.(type) {
+		case *dns.A:
+			answers[r.A.String()] = struct{}{}
+		case *dns.CNAME:
+			answers[strings.ToLower(trimDot(r.Target))] = struct{}{}
+		}
+	}
+	return answers
+}
+
+// isAnswerMatched 解析结果与基准结果是否有相同的IP或CNAME（CDN的IP可能因DNS服务器而不同，但CNAME相同）
+func isAnswerMatched(answers, baseline map[string]struct{}) bool {
+	for answer := range answers {
+		if _, ok := baseline[answer]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// SaveCheckedResolvers 将可用的DNS服务器按响应时间排序后保存到文件，内容无变化时不写入（避免触发worker的文件同步）
+func SaveCheckedResolvers(file string, checker *ResolverChecker, results []ResolverCheckResult) (usable int, changed bool, err error) {
+	var usableResults []ResolverCheckResult
+	for _, r := range results {
+		// massdns只支持UDP的DNS服务器
+		if checker.Usable(r) && !IsEncryptedResolver(r.Resolver) {
+			usableResults = append(usableResults, r)
+		}
+	}
+	sort.SliceStable(usableResults, func(i, j int) bool {
+		return usableResults[i].Latency < usableResults[j].Latency
+	})
+	var buf bytes.Buffer
+	for _, r := range usableResults {
+		// 默认端口时只保存IP，与massdns的格式兼容
+		resolver := r.Resolver
+		if strings.HasSuffix(resolver, ":53") {
+			resolver = strings.Trim(strings.TrimSuffix(resolver, ":53"), "[]")
+		}
+		buf.WriteString(resolver + "\n")
+	}
+	if old, e := os.ReadFile(file); e == nil && bytes.Equal(old, buf.Bytes()) {
+		return len(usableResults), false, nil
+	}
+	if err = os.WriteFile(file, buf.Bytes(), 0644); err != nil {
+		return
+	}
+	return len(usableResults), true, nil
+}
+
+// GetResolverFile worker使用的DNS服务器列表文件：有检查后可用的DNS服务器列表时优先使用，否则使用配置的列表
+func GetResolverFile() string {
+	checkedFile := filepath.Join(conf.GetRootPath(), "thirdparty/dict", ResolverCheckedFile)
+	if fi, err := os.Stat(checkedFile); err == nil && fi.Size() > 0 {
+		return checkedFile
+	}
+	return filepath.Join(conf.GetRootPath(), "thirdparty/dict", conf.GlobalWorkerConfig().Domainscan.Resolver)
+}
+
+// CheckResolver 检查worker配置的DNS服务器列表，生成可用的DNS服务器列表；由server定时执行，生成的文件同步到worker
+func CheckResolver(trusted []string, probeDomain string, maxLatency time.Duration) (msg string, err error) {
+	resolvers := LoadResolvers(filepath.Join(conf.GetRootPath(), "thirdparty/dict", conf.GlobalWorkerConfig().Domainscan.Resolver))
+	if len(resolvers) == 0 {
+		return "", fmt.Errorf("no resolver to check")
+	}
+	checker := NewResolverChecker(trusted, probeDomain, maxLatency)
+	results := checker.CheckAll(resolvers)
+	for _, r := range results {
+		if !checker.Usable(r) {
+			logging.RuntimeLog.Infof("resolver %s unusable:%s", r.Resolver, r.Message)
+		}
+	}
+	usable, changed, err := SaveCheckedResolvers(filepath.Join(conf.GetRootPath(), "thirdparty/dict", ResolverCheckedFile), checker, results)
+	if err != nil {
+		return
+	}
+	return fmt.Sprintf("resolver:%d,usable:%d,changed:%v", len(resolvers), usable, changed), nil
+}
@@ -0,0 +1,134 @@
+package domainscan
+
+import (
+	"github.com/miekg/dns"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// resolverTestHandler 正常的DNS服务：www.example.com解析到192.0.2.1，其它域名返回NXDOMAIN；hijack时所有域名解析到192.0.2.100
+func resolverTestHandler(hijack bool) dns.HandlerFunc {
+	return func(w dns.ResponseWriter, r *dns.Msg) {
+		m := new(dns.Msg)
+		m.SetReply(r)
+		m.RecursionAvailable = true
+		q := r.Question[0]
+		if hijack {
+			rr, _ := dns.NewRR(q.Name + " 300 IN A 192.0.2.100")
+			m.Answer = append(m.Answer, rr)
+		} else if q.Name == "www.example.com." {
+			rr, _ := dns.NewRR("www.example.com. 300 IN CNAME web.example.net.")
+			m.Answer = append(m.Answer, rr)
+			rr, _ = dns.NewRR("web.example.net. 300 IN A 192.0.2.1")
+			m.Answer = append(m.Answer, rr)
+		} else {
+			m.SetRcode(r, dns.RcodeNameError)
+		}
+		w.WriteMsg(m)
+	}
+}
+
+// startResolverTestServer 启动本地的UDP DNS服务
+func startResolverTestServer(t *testing.T, hijack bool) string {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := &dns.Server{PacketConn: pc, Handler: resolverTestHandler(hijack)}
+	started := make(chan struct{})
+	server.NotifyStartedFunc = func() { close(started) }
+	go server.ActivateAndServe()
+	<-started
+	t.Cleanup(func() { server.Shutdown() })
+	return pc.LocalAddr().String()
+}
+
+func TestResolverChecker(t *testing.T) {
+	good := startResolverTestServer(t, false)
+	hijack := startResolverTestServer(t, true)
+
+	checker := NewResolverChecker([]string{good}, "www.example.com", time.Second)
+	results := checker.CheckAll([]string{good, hijack, "127.0.0.1:1"})
+	expected := []bool{true, false, false}
+	for i, r := range results {
+		if checker.Usable(r) != expected[i] {
+			t.Errorf("%s: usable %v, message:%s", r.Resolver, checker.Usable(r), r.Message)
+		}
+	}
+	if !results[1].Alive || results[1].Correct {
+		t.Errorf("hijack resolver:%v", results[1])
+	}
+	if results[2].Alive {
+		t.Errorf("dead resolver:%v", results[2])
+	}
+
+	resolverFile := filepath.Join(t.TempDir(), "resolver_checked.txt")
+	usable, changed, err := SaveCheckedResolvers(resolverFile, checker, results)
+	if err != nil || usable != 1 || !changed {
+		t.Fatalf("usable:%d changed:%v err:%v", usable, changed, err)
+	}
+	content, _ := os.ReadFile(resolverFile)
+	if string(content) != good+"\n" {
+		t.Errorf("got %s", content)
+	}
+	// 内容没有变化时不写入文件
+	if _, changed, _ = SaveCheckedResolvers(resolverFile, checker, results); changed {
+		t.Error("should not be changed")
+	}
+}
+
+func TestExchangeDoH(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != dohMimeType {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		data, _ := io.ReadAll(r.Body)
+		req := new(dns.Msg)
+		if err := req.Unpack(data); err != nil || req.Id != 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		m := new(dns.Msg)
+		m.SetReply(req)
+		if req.Question[0].Qtype == dns.TypeA {
+			rr, _ := dns.NewRR(req.Question[0].Name + " 300 IN A 192.0.2.1")
+			m.Answer = append(m.Answer, rr)
+		}
+		resp, _ := m.Pack()
+		w.Header().Set("Content-Type", dohMimeType)
+		w.Write(resp)
+	}))
+	defer server.Close()
+	oldClient := dohClient
+	dohClient = server.Client()
+	defer func() { dohClient = oldClient }()
+
+	d := &DNSRecord{resolvers: []string{server.URL + "/dns-query"}, encrypted: true}
+	_, hosts := d.ResolveHost("www.example.com")
+	if len(hosts) != 1 || hosts[0] != "192.0.2.1" {
+		t.Errorf("got %v", hosts)
+	}
+}
+
+func TestFormatEncryptedResolver(t *testing.T) {
+	datas := map[string]string{
+		"https://dns.alidns.com/dns-query": "https://dns.alidns.com/dns-query",
+		"tls://223.5.5.5":                  "tls://223.5.5.5:853",
+		"tls://dns.alidns.com:853":         "tls://dns.alidns.com:853",
+		"tls://2400:3200::1":               "tls://[2400:3200::1]:853",
+		"tls://[2400:3200::1]":             "tls://[2400:3200::1]:853",
+		"https://":                         "",
+	}
+	for s, expected := range datas {
+		if r := formatResolver(s); r != expected {
+			t.Errorf("%s: got %s, expected %s", s, r, expected)
+		}
+	}
+}
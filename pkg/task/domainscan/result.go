@@ -6,6 +6,7 @@ import (
 	"github.com/hanc00l/nemo_go/pkg/conf"
 	"github.com/hanc00l/nemo_go/pkg/db"
 	"github.com/hanc00l/nemo_go/pkg/logging"
+	"github.com/hanc00l/nemo_go/pkg/notify"
 	"github.com/hanc00l/nemo_go/pkg/task/custom"
 	"github.com/hanc00l/nemo_go/pkg/utils"
 	"strings"
@@ -22,6 +23,7 @@ var (
 	massdnsThreadNumber   = make(map[string]int)
 	massdnsRunnerThreads  = make(map[string]int)
 	crawlerThreadNumber   = make(map[string]int)
+	dnsCheckThreadNumber  = make(map[string]int)
 )
 
 // Config 端口扫描的参数配置
@@ -30,6 +32,7 @@ type Config struct {
 	OrgId              *int   `json:"orgId"`
 	IsSubDomainFinder  bool   `json:"subfinder"`
 	IsSubDomainBrute   bool   `json:"subdomainBrute"`
+	IsSubDomainPermute bool   `json:"subdomainPermute"`
 	IsCrawler          bool   `json:"crawler"`
 	IsHttpx            bool   `json:"httpx"`
 	IsIPPortScan       bool   `json:"portscan"`
@@ -43,6 +46,8 @@ type Config struct {
 	IsIgnoreOutofChina bool   `json:"ignoreoutofchina"`
 	WorkspaceId        int    `json:"workspaceId"`
 	IsProxy            bool   `json:"proxy"`
+	IsDNSCheck         bool   `json:"dnscheck,omitempty"` // 执行NS的域传送及递归解析检查，每个目标只在一个子任务中执行
+	TaskId             string `json:"-"`                  // 保存结果的任务ID，用于记录资产变更的来源
 }
 
 // DomainAttrResult 域名属性结果
@@ -90,6 +95,9 @@ func init() {
 	//
 	crawlerThreadNumber[conf.HighPerformance] = 2
 	crawlerThreadNumber[conf.NormalPerformance] = 1
+	//
+	dnsCheckThreadNumber[conf.HighPerformance] = 10
+	dnsCheckThreadNumber[conf.NormalPerformance] = 5
 
 }
 
@@ -139,18 +147,28 @@ func (r *Result) SaveResult(config Config) string {
 			OrgId:       config.OrgId,
 			WorkspaceId: config.WorkspaceId,
 		}
-		if ok, isNew := domain.SaveOrUpdate(); !ok {
+		ok, isNew := domain.SaveOrUpdate()
+		if !ok {
 			continue
-		} else {
-			if isNew {
-				newDomain++
-			}
+		}
+		if isNew {
+			newDomain++
+			db.SaveAssetChange(config.WorkspaceId, db.AssetTypeDomain, domainName, 0, db.AssetChangeAdded, "", "domain", domainName, "", config.TaskId)
+			notify.Publish(notify.Event{Type: notify.EventDomain, WorkspaceId: config.WorkspaceId, OrgId: config.OrgId, Target: domainName})
 		}
 		resultDomainCount++
 		// elastic assets
 		if conf.ElasticSyncAssetsChan != nil {
 			ElasticAssets = append(ElasticAssets, *domain)
 		}
+		// 记录域名属性的变更
+		changeRecorder := db.NewAssetChangeRecorder(config.WorkspaceId, db.AssetTypeDomain, domainName, 0, config.TaskId)
+		if !isNew {
+			oldDomainAttr := db.DomainAttr{RelatedId: domain.Id}
+			for _, attr := range oldDomainAttr.GetsByRelatedId() {
+				changeRecorder.SetOldAttr(attr.Source, attr.Tag, attr.Content, attr.UpdateDatetime)
+			}
+		}
 		// save domain attr
 		for _, domainAttrResult := range domainResult.DomainAttrs {
 			domainAttr := &db.DomainAttr{
@@ -165,7 +183,9 @@ func (r *Result) SaveResult(config Config) string {
 				domainAttr.Content = domainAttrResult.Content
 			}
 			domainAttr.SaveOrUpdate()
+			changeRecorder.SetNewAttr(domainAttr.Source, domainAttr.Tag, domainAttr.Content)
 		}
+		changeRecorder.Save()
 		//save http info
 		for _, httpInfoResult := range domainResult.HttpInfo {
 			httpInfo := &db.DomainHttp{
@@ -55,7 +55,7 @@ func (s *SubFinder) RunSubFinder(domain string) {
 	var cmdArgs []string
 	cmdArgs = append(cmdArgs,
 		"-d", domain, "-all", "-o", resultTempFile, "-disable-update-check",
-		"-rlist", filepath.Join(conf.GetRootPath(), "thirdparty/dict", conf.GlobalWorkerConfig().Domainscan.Resolver),
+		"-rlist", GetResolverFile(),
 		"-provider-config", filepath.Join(conf.GetRootPath(), "thirdparty/dict", conf.GlobalWorkerConfig().Domainscan.ProviderConfig),
 		"-no-color", "-v",
 		"-active", //RemoveWildcard
@@ -0,0 +1,233 @@
+package domainscan
+
+import (
+	"fmt"
+	"github.com/hanc00l/nemo_go/pkg/logging"
+	"github.com/hanc00l/nemo_go/pkg/utils"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	// WildcardTag 泛解析域名的属性
+	WildcardTag = "wildcard"
+	// wildcardProbeNumber 每一级检查泛解析时解析的随机子域名数量
+	wildcardProbeNumber = 3
+)
+
+// wildcardAnswer 随机子域名的解析结果
+type wildcardAnswer struct {
+	ips    map[string]struct{}
+	cnames map[string]struct{}
+}
+
+// WildcardFilter 检查域名的泛解析，并过滤与泛解析结果相同的子域名
+type WildcardFilter struct {
+	sync.Mutex
+	// 需要检查的根域名，只检查根域名及其下的各级子域名
+	roots map[string]struct{}
+	// 已检查的域名的泛解析结果，非泛解析时为nil
+	answers map[string]*wildcardAnswer
+	resolve func(domain string) (CName string, Host []string)
+}
+
+// NewWildcardFilter 创建WildcardFilter对象
+func NewWildcardFilter() *WildcardFilter {
+	return &WildcardFilter{
+		roots:   make(map[string]struct{}),
+		answers: make(map[string]*wildcardAnswer),
+		resolve: ResolveDomain,
+	}
+}
+
+// useDNSRecord 使用与爆破相同的DNS服务器检查泛解析（UDP/53不可用时为DoH/DoT），没有可用的DNS服务器时使用系统的域名解析
+func (w *WildcardFilter) useDNSRecord(d *DNSRecord) {
+	if d != nil && len(d.resolvers) > 0 {
+		w.resolve = d.ResolveHost
+	}
+}
+
+// Detect 解析域名下的随机子域名，检查域名是否为泛解析
+func (w *WildcardFilter) Detect(domain string) bool {
+	domain = strings.ToLower(domain)
+	w.Lock()
+	w.roots[domain] = struct{}{}
+	w.Unlock()
+
+	return w.detect(domain) != nil
+}
+
+// detect 检查一个域名的泛解析，结果进行缓存
+func (w *WildcardFilter) detect(domain string) *wildcardAnswer {
+	w.Lock()
+	if answer, ok := w.answers[domain]; ok {
+		w.Unlock()
+		return answer
+	}
+	w.Unlock()
+
+	answer := &wildcardAnswer{ips: make(map[string]struct{}), cnames: make(map[string]struct{})}
+	for i := 0; i < wildcardProbeNumber; i++ {
+		probe := fmt.Sprintf("%s.%s", utils.GetRandomString2(16), domain)
+		cname, hosts := w.resolve(probe)
+		for _, h := range hosts {
+			// 与Resolve保存的AAAA属性格式一致
+			if utils.CheckIPV6(h) {
+				h = utils.GetIPV6ParsedFormat(h)
+			}
+			answer.ips[h] = struct{}{}
+		}
+		if cname = strings.TrimSuffix(cname, "."); cname != "" && cname != probe {
+			answer.cnames[cname] = struct{}{}
+		}
+	}
+	if len(answer.ips) == 0 && len(answer.cnames) == 0 {
+		answer = nil
+	} else {
+		logging.RuntimeLog.Infof("wildcard dns found:%s", domain)
+	}
+	w.Lock()
+	w.answers[domain] = answer
+	w.Unlock()
+
+	return answer
+}
+
+// IsWildcard 返回域名是否为泛解析
+func (w *WildcardFilter) IsWildcard(domain string) bool {
+	w.Lock()
+	defer w.Unlock()
+
+	return w.answers[strings.ToLower(domain)] != nil
+}
+
+// WildcardContent 泛解析域名的属性内容
+func (w *WildcardFilter) WildcardContent(domain string) string {
+	w.Lock()
+	defer w.Unlock()
+
+	answer := w.answers[strings.ToLower(domain)]
+	if answer == nil {
+		return ""
+	}
+	var contents []string
+	if len(answer.ips) > 0 {
+		ips := utils.SetToSlice(answer.ips)
+		sort.Strings(ips)
+		contents = append(contents, "A:"+strings.Join(ips, ","))
+	}
+	if len(answer.cnames) > 0 {
+		cnames := utils.SetToSlice(answer.cnames)
+		sort.Strings(cnames)
+		contents = append(contents, "CNAME:"+strings.Join(cnames, ","))
+	}
+	return strings.Join(contents, ";")
+}
+
+// parents 域名在根域名下的各级父域名，由近到远；不在根域名下时返回空
+func (w *WildcardFilter) parents(domain string) (parents []string) {
+	w.Lock()
+	defer w.Unlock()
+
+	labels := strings.Split(strings.ToLower(domain), ".")
+	for i := 1; i < len(labels); i++ {
+		parent := strings.Join(labels[i:], ".")
+		parents = append(parents, parent)
+		if _, ok := w.roots[parent]; ok {
+			return
+		}
+	}
+	return nil
+}
+
+// Match 检查域名的解析结果是否与任一级父域名的泛解析结果相同，返回匹配的父域名
+func (w *WildcardFilter) Match(domain string, ips []string, cname string) (parent string, ok bool) {
+	if len(ips) == 0 && cname == "" {
+		return
+	}
+	for _, p := range w.parents(domain) {
+		answer := w.detect(p)
+		if answer == nil {
+			continue
+		}
+		if cname != "" {
+			if _, ok = answer.cnames[cname]; ok {
+				return p, true
+			}
+		}
+		if len(ips) > 0 {
+			ok = true
+			for _, ip := range ips {
+				if _, exist := answer.ips[ip]; !exist {
+					ok = false
+					break
+				}
+			}
+			if ok {
+				return p, true
+			}
+		}
+	}
+	return "", false
+}
+
+// Filter 过滤结果中与泛解析结果相同的子域名，并在泛解析的父域名中记录泛解析的属性
+func (w *WildcardFilter) Filter(result *Result) (filtered int) {
+	// 先复制域名的解析结果，检查泛解析时需要进行DNS查询，不持有结果的锁
+	type candidate struct {
+		domain string
+		ips    []string
+		cname  string
+	}
+	var candidates []candidate
+	result.RLock()
+	for domain, domainResult := range result.DomainResult {
+		c := candidate{domain: domain}
+		for _, attr := range domainResult.DomainAttrs {
+			if attr.Tag == "A" || attr.Tag == "AAAA" {
+				c.ips = append(c.ips, attr.Content)
+			} else if attr.Tag == "CNAME" {
+				c.cname = attr.Content
+			}
+		}
+		candidates = append(candidates, c)
+	}
+	result.RUnlock()
+
+	var removed []string
+	wildcardParents := make(map[string]struct{})
+	for _, c := range candidates {
+		if parent, ok := w.Match(c.domain, c.ips, c.cname); ok {
+			removed = append(removed, c.domain)
+			wildcardParents[parent] = struct{}{}
+		}
+	}
+	result.Lock()
+	for _, domain := range removed {
+		delete(result.DomainResult, domain)
+	}
+	result.Unlock()
+	// 根域名即使没有被过滤的子域名，也记录泛解析属性
+	w.Lock()
+	for root := range w.roots {
+		if w.answers[root] != nil {
+			wildcardParents[root] = struct{}{}
+		}
+	}
+	w.Unlock()
+	for parent := range wildcardParents {
+		if !result.HasDomain(parent) {
+			result.SetDomain(parent)
+		}
+		result.SetDomainAttr(parent, DomainAttrResult{
+			Source:  "domainscan",
+			Tag:     WildcardTag,
+			Content: w.WildcardContent(parent),
+		})
+	}
+	if len(removed) > 0 {
+		logging.RuntimeLog.Infof("wildcard dns filtered:%d", len(removed))
+	}
+	return len(removed)
+}
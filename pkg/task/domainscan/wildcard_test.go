@@ -0,0 +1,82 @@
+package domainscan
+
+import (
+	"strings"
+	"testing"
+)
+
+// newTestWildcardFilter 使用固定的解析结果：*.example.com解析到1.1.1.1，*.cdn.example.org的CNAME为wildcard.cdn.net
+func newTestWildcardFilter() *WildcardFilter {
+	w := NewWildcardFilter()
+	w.resolve = func(domain string) (CName string, Host []string) {
+		switch {
+		case strings.HasSuffix(domain, ".example.com"):
+			return domain + ".", []string{"1.1.1.1"}
+		case strings.HasSuffix(domain, ".cdn.example.org"):
+			return "wildcard.cdn.net.", []string{"2.2.2.2", "3.3.3.3"}
+		}
+		return "", nil
+	}
+	return w
+}
+
+func TestWildcardFilter_Detect(t *testing.T) {
+	w := newTestWildcardFilter()
+	if !w.Detect("example.com") {
+		t.Error("example.com should be wildcard")
+	}
+	if w.Detect("example.net") {
+		t.Error("example.net should not be wildcard")
+	}
+	if content := w.WildcardContent("example.com"); content != "A:1.1.1.1" {
+		t.Errorf("got %s", content)
+	}
+}
+
+func TestWildcardFilter_Filter(t *testing.T) {
+	w := newTestWildcardFilter()
+	w.Detect("example.com")
+	w.Detect("example.org")
+
+	result := Result{DomainResult: make(map[string]*DomainResult)}
+	datas := map[string][]DomainAttrResult{
+		"random.example.com": {{Tag: "A", Content: "1.1.1.1"}},
+		"www.example.com":    {{Tag: "A", Content: "1.1.1.1"}, {Tag: "A", Content: "4.4.4.4"}},
+		"a.cdn.example.org":  {{Tag: "CNAME", Content: "wildcard.cdn.net"}, {Tag: "A", Content: "2.2.2.2"}},
+		"www.example.org":    {{Tag: "A", Content: "2.2.2.2"}},
+		"www.example.net":    {{Tag: "A", Content: "1.1.1.1"}},
+	}
+	for domain, attrs := range datas {
+		result.SetDomain(domain)
+		for _, attr := range attrs {
+			result.SetDomainAttr(domain, attr)
+		}
+	}
+	if filtered := w.Filter(&result); filtered != 2 {
+		t.Errorf("filtered %d, expected 2", filtered)
+	}
+	for _, domain := range []string{"random.example.com", "a.cdn.example.org"} {
+		if result.HasDomain(domain) {
+			t.Errorf("%s should be filtered", domain)
+		}
+	}
+	for _, domain := range []string{"www.example.com", "www.example.org", "www.example.net"} {
+		if !result.HasDomain(domain) {
+			t.Errorf("%s should not be filtered", domain)
+		}
+	}
+	// 泛解析的域名记录泛解析属性
+	for domain, content := range map[string]string{"example.com": "A:1.1.1.1", "cdn.example.org": "A:2.2.2.2,3.3.3.3;CNAME:wildcard.cdn.net"} {
+		if !result.HasDomain(domain) {
+			t.Errorf("%s should be added", domain)
+			continue
+		}
+		attrs := result.DomainResult[domain].DomainAttrs
+		if len(attrs) != 1 || attrs[0].Tag != WildcardTag || attrs[0].Content != content {
+			t.Errorf("%s: %v", domain, attrs)
+		}
+	}
+	if result.HasDomain("example.org") {
+		t.Error("example.org should not be wildcard")
+	}
+}
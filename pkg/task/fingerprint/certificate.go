@@ -0,0 +1,197 @@
+package fingerprint
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/hanc00l/nemo_go/pkg/db"
+	"github.com/hanc00l/nemo_go/pkg/task/domainscan"
+	"github.com/hanc00l/nemo_go/pkg/task/portscan"
+	"github.com/hanc00l/nemo_go/pkg/utils"
+	"strconv"
+	"strings"
+)
+
+// certificateSource 从证书SAN获取的域名的属性来源
+const certificateSource = "tlscert"
+
+// certificateSaver 保存一次结果中的证书
+type certificateSaver struct {
+	workspaceId int
+	tld         domainscan.TldExtract
+	// 已保存的证书指纹与ID
+	certs    map[string]int
+	newCount int
+	// SAN中的域名及发现的主机
+	sanDomains map[string]string
+	// 主域名是否为工作空间中已有的域名
+	knownFLD map[string]bool
+}
+
+// ParseTLSData 解析httpx的tlsdata属性，没有证书指纹的数据无法作为证书保存
+func ParseTLSData(content string) (tls TLS, ok bool) {
+	if err := json.Unmarshal([]byte(content), &tls); err != nil {
+		return
+	}
+	if tls.FingerprintHash == nil || tls.FingerprintHash.SHA256 == "" {
+		return
+	}
+	return tls, true
+}
+
+// NewCertificate 生成证书的数据库记录
+func (t *TLS) NewCertificate(workspaceId int) db.Certificate {
+	return db.Certificate{
+		WorkspaceId: workspaceId,
+		Fingerprint: strings.ToLower(t.FingerprintHash.SHA256),
+		Serial:      t.Serial,
+		SubjectCN:   t.SubjectCommonName,
+		SubjectDN:   t.SubjectDistinguishedName,
+		SubjectOrg:  strings.Join(t.SubjectOrganization, ","),
+		IssuerCN:    t.IssuerCommonName,
+		IssuerDN:    t.IssuerDistinguishedName,
+		IssuerOrg:   strings.Join(t.IssuerOrganization, ","),
+		SAN:         strings.Join(t.SubjectDNSName, "\n"),
+		NotBefore:   t.NotBefore,
+		NotAfter:    t.NotAfter,
+	}
+}
+
+// NormalizeSAN 将证书的SAN转换为域名，通配符域名取其父域名
+func NormalizeSAN(san string) (domain string, ok bool) {
+	domain = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(san)), ".")
+	domain = strings.TrimPrefix(domain, "*.")
+	if domain == "" || strings.Contains(domain, "*") || utils.CheckIP(domain) || !utils.CheckDomain(domain) {
+		return "", false
+	}
+	return domain, true
+}
+
+// SaveCertificateResult 保存IP端口及域名结果中的TLS证书和使用证书的主机；
+// 证书SAN中与工作空间已有域名的主域名相同的新域名作为候选域名保存，并返回候选域名用于后续的域名解析
+func SaveCertificateResult(config domainscan.Config, ipResult map[string]*portscan.IPResult, domainResult map[string]*domainscan.DomainResult) (msg string, candidates []string) {
+	s := &certificateSaver{
+		workspaceId: config.WorkspaceId,
+		certs:       make(map[string]int),
+		sanDomains:  make(map[string]string),
+		knownFLD:    make(map[string]bool),
+	}
+	for ip, r := range ipResult {
+		if r == nil {
+			continue
+		}
+		for port, portResult := range r.Ports {
+			if portResult == nil {
+				continue
+			}
+			for _, attr := range portResult.PortAttrs {
+				if attr.Tag == "tlsdata" {
+					s.save(db.AssetTypeIP, ip, port, attr.Content)
+				}
+			}
+		}
+	}
+	for domain, r := range domainResult {
+		if r == nil {
+			continue
+		}
+		for _, attr := range r.DomainAttrs {
+			if attr.Tag == "tlsdata" {
+				s.save(db.AssetTypeDomain, domain, 0, attr.Content)
+			}
+		}
+	}
+	if len(s.certs) == 0 {
+		return
+	}
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("certificate:%d", len(s.certs)))
+	if s.newCount > 0 {
+		sb.WriteString(fmt.Sprintf(",certificateNew:%d", s.newCount))
+	}
+	// SAN的候选域名，结果中的域名的主域名也作为已有的域名
+	s.tld = domainscan.NewTldExtract()
+	for domain := range domainResult {
+		if fld := s.tld.ExtractFLD(domain); fld != "" {
+			s.knownFLD[fld] = true
+		}
+	}
+	sanResult := domainscan.Result{DomainResult: make(map[string]*domainscan.DomainResult)}
+	for domain, host := range s.sanDomains {
+		if _, ok := domainResult[domain]; ok || !s.isCandidate(domain) {
+			continue
+		}
+		sanResult.SetDomain(domain)
+		sanResult.SetDomainAttr(domain, domainscan.DomainAttrResult{Source: certificateSource, Tag: "san", Content: host})
+		candidates = append(candidates, domain)
+	}
+	if len(sanResult.DomainResult) > 0 {
+		sb.WriteString(",")
+		sb.WriteString(sanResult.SaveResult(config))
+	}
+	return sb.String(), candidates
+}
+
+// save 保存一个证书及使用证书的主机
+func (s *certificateSaver) save(hostType, host string, port int, content string) {
+	tls, ok := ParseTLSData(content)
+	if !ok {
+		return
+	}
+	// 域名的端口从TLS数据中获取
+	if port == 0 {
+		if p, err := strconv.Atoi(tls.Port); err == nil && p > 0 {
+			port = p
+		} else {
+			port = 443
+		}
+	}
+	cert := tls.NewCertificate(s.workspaceId)
+	certId, ok := s.certs[cert.Fingerprint]
+	if !ok {
+		success, isNew := cert.SaveOrUpdate()
+		if !success {
+			return
+		}
+		if isNew {
+			s.newCount++
+		}
+		certId = cert.Id
+		s.certs[cert.Fingerprint] = certId
+		for _, san := range tls.SubjectDNSName {
+			if domain, valid := NormalizeSAN(san); valid {
+				s.sanDomains[domain] = utils.FormatHostUrl("", host, port)
+			}
+		}
+	}
+	certHost := db.CertificateHost{
+		CertId:      certId,
+		WorkspaceId: s.workspaceId,
+		HostType:    hostType,
+		Host:        host,
+		Port:        port,
+	}
+	certHost.SaveOrUpdate()
+}
+
+// isCandidate SAN的域名是否为候选域名：域名尚未保存，并且主域名为工作空间中已有的域名
+func (s *certificateSaver) isCandidate(domain string) bool {
+	d := db.Domain{DomainName: domain, WorkspaceId: s.workspaceId}
+	if d.GetByDomain() {
+		return false
+	}
+	fld := s.tld.ExtractFLD(domain)
+	if fld == "" {
+		return false
+	}
+	known, ok := s.knownFLD[fld]
+	if !ok {
+		searchMap := map[string]interface{}{"domain_suffix": fld}
+		if s.workspaceId > 0 {
+			searchMap["workspace_id"] = s.workspaceId
+		}
+		domain := db.Domain{}
+		known = domain.Count(searchMap) > 0
+		s.knownFLD[fld] = known
+	}
+	return known
+}
@@ -0,0 +1,42 @@
+package fingerprint
+
+import "testing"
+
+func TestParseTLSData(t *testing.T) {
+	content := `{"host":"www.example.com","port":"8443","subject_an":["www.example.com","*.api.example.com"],"subject_cn":"www.example.com","subject_org":["Example Inc"],"issuer_cn":"R3","issuer_org":["Let's Encrypt"],"serial":"03:5A","not_before":"2024-01-01T00:00:00Z","not_after":"2024-03-31T00:00:00Z","fingerprint_hash":{"md5":"a","sha1":"b","sha256":"ABCDEF"}}`
+	tls, ok := ParseTLSData(content)
+	if !ok {
+		t.Fatal("parse tlsdata fail")
+	}
+	cert := tls.NewCertificate(1)
+	if cert.Fingerprint != "abcdef" || cert.IssuerOrg != "Let's Encrypt" || cert.NotAfter == nil || cert.NotAfter.Month() != 3 {
+		t.Errorf("unexpected certificate:%+v", cert)
+	}
+	if len(cert.SANs()) != 2 {
+		t.Errorf("unexpected san:%s", cert.SAN)
+	}
+	// 旧的tlsdata没有证书指纹
+	if _, ok = ParseTLSData(`{"subject_cn":"www.example.com"}`); ok {
+		t.Error("tlsdata without fingerprint should be ignored")
+	}
+}
+
+func TestNormalizeSAN(t *testing.T) {
+	tests := []struct {
+		san    string
+		domain string
+		ok     bool
+	}{
+		{"www.example.com", "www.example.com", true},
+		{"*.API.example.com.", "api.example.com", true},
+		{"192.0.2.1", "", false},
+		{"*.*.example.com", "", false},
+		{"localhost", "", false},
+	}
+	for _, tt := range tests {
+		domain, ok := NormalizeSAN(tt.san)
+		if domain != tt.domain || ok != tt.ok {
+			t.Errorf("%s:got %s %v", tt.san, domain, ok)
+		}
+	}
+}
@@ -24,6 +24,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 )
 
 var HttpxOutputDirectory string //全局的httpx保存响应的数据，用于自定义指纹匹配
@@ -75,12 +76,26 @@ type HttpxResult struct {
 }
 
 type TLS struct {
-	SubjectDNSName           []string `json:"subject_an,omitempty"`
-	SubjectCommonName        string   `json:"subject_cn,omitempty"`
-	SubjectDistinguishedName string   `json:"subject_dn,omitempty"`
-	SubjectOrganization      []string `json:"subject_org,omitempty"`
-	IssuerDistinguishedName  string   `json:"issuer_dn,omitempty"`
-	IssuerOrganization       []string `json:"issuer_org,omitempty"`
+	Host                     string              `json:"host,omitempty"`
+	Port                     string              `json:"port,omitempty"`
+	SubjectDNSName           []string            `json:"subject_an,omitempty"`
+	SubjectCommonName        string              `json:"subject_cn,omitempty"`
+	SubjectDistinguishedName string              `json:"subject_dn,omitempty"`
+	SubjectOrganization      []string            `json:"subject_org,omitempty"`
+	IssuerCommonName         string              `json:"issuer_cn,omitempty"`
+	IssuerDistinguishedName  string              `json:"issuer_dn,omitempty"`
+	IssuerOrganization       []string            `json:"issuer_org,omitempty"`
+	Serial                   string              `json:"serial,omitempty"`
+	NotBefore                *time.Time          `json:"not_before,omitempty"`
+	NotAfter                 *time.Time          `json:"not_after,omitempty"`
+	FingerprintHash          *TLSFingerprintHash `json:"fingerprint_hash,omitempty"`
+}
+
+// TLSFingerprintHash 证书的指纹
+type TLSFingerprintHash struct {
+	MD5    string `json:"md5,omitempty"`
+	SHA1   string `json:"sha1,omitempty"`
+	SHA256 string `json:"sha256,omitempty"`
 }
 
 // WebFingerPrint 匹配web_fingerprint_v3.json的指纹结构
@@ -0,0 +1,223 @@
+package onlineapi
+
+import (
+	"crypto/md5"
+	"encoding/json"
+	"fmt"
+	"github.com/hanc00l/nemo_go/pkg/conf"
+	"github.com/hanc00l/nemo_go/pkg/logging"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SearchCache 在线API查询结果的缓存，worker通过RPC使用server的缓存
+type SearchCache interface {
+	// Load 获取未过期（ttl小时内）的缓存
+	Load(key string, ttl int) (item *OnlineAPICacheItem, ok bool)
+	// Save 保存一页查询结果到缓存
+	Save(key string, item *OnlineAPICacheItem)
+}
+
+// OnlineAPICacheItem 一页查询结果的缓存
+type OnlineAPICacheItem struct {
+	Results   []onlineSearchResult `json:"results"`
+	SizeTotal int                  `json:"sizeTotal"`
+	Points    int                  `json:"points"`
+	CacheTime time.Time            `json:"cacheTime"`
+}
+
+// onlineAPICacheLine 缓存文件中的一行，每保存一页查询结果追加一行
+type onlineAPICacheLine struct {
+	Key  string              `json:"key"`
+	Item *OnlineAPICacheItem `json:"item"`
+}
+
+// OnlineAPICache server保存的在线API查询结果缓存
+type OnlineAPICache struct {
+	sync.Mutex
+	CacheMap map[string]*OnlineAPICacheItem
+}
+
+var (
+	onlineAPICache     *OnlineAPICache
+	onlineAPICacheOnce sync.Once
+)
+
+// GetOnlineAPICache 获取server的在线API查询结果缓存，首次调用时从缓存文件中加载
+func GetOnlineAPICache() *OnlineAPICache {
+	onlineAPICacheOnce.Do(func() {
+		onlineAPICache = &OnlineAPICache{CacheMap: make(map[string]*OnlineAPICacheItem)}
+		onlineAPICache.loadLocalCache()
+	})
+	return onlineAPICache
+}
+
+// MakeCacheKey 生成缓存的key：搜索引擎+规范化后的查询语法+页码
+func MakeCacheKey(apiName string, query string, pageIndex int, config OnlineAPIConfig) string {
+	// 合并多余的空白，其它内容保持不变（部份搜索引擎的值区分大小写）
+	normalizedQuery := strings.Join(strings.Fields(query), " ")
+	data := fmt.Sprintf("%s|%s|%s|%d|%d", apiName, normalizedQuery, config.SearchStartTime, config.SearchPageSize, pageIndex)
+	return fmt.Sprintf("%s:%x", apiName, md5.Sum([]byte(data)))
+}
+
+// Load 获取未过期的缓存
+func (c *OnlineAPICache) Load(key string, ttl int) (item *OnlineAPICacheItem, ok bool) {
+	c.Lock()
+	defer c.Unlock()
+
+	if item, ok = c.CacheMap[key]; !ok {
+		return nil, false
+	}
+	if ttl <= 0 || time.Since(item.CacheTime) > time.Duration(ttl)*time.Hour {
+		return nil, false
+	}
+	return item, true
+}
+
+// Save 保存缓存并追加到缓存文件
+func (c *OnlineAPICache) Save(key string, item *OnlineAPICacheItem) {
+	c.Lock()
+	defer c.Unlock()
+
+	if item.CacheTime.IsZero() {
+		item.CacheTime = time.Now()
+	}
+	c.CacheMap[key] = item
+	c.appendLocalCache(key, item)
+}
+
+// Invalidate 清除缓存，apiName为空时清除全部，返回清除的数量
+func (c *OnlineAPICache) Invalidate(apiName string) (count int) {
+	c.Lock()
+	defer c.Unlock()
+
+	for k := range c.CacheMap {
+		if apiName == "" || strings.HasPrefix(k, apiName+":") {
+			delete(c.CacheMap, k)
+			count++
+		}
+	}
+	c.saveLocalCache()
+	return
+}
+
+// Purge 清除超过ttl小时的缓存
+func (c *OnlineAPICache) Purge(ttl int) (count int) {
+	c.Lock()
+	defer c.Unlock()
+
+	for k, v := range c.CacheMap {
+		if time.Since(v.CacheTime) > time.Duration(ttl)*time.Hour {
+			delete(c.CacheMap, k)
+			count++
+		}
+	}
+	if count > 0 {
+		c.saveLocalCache()
+	}
+	return
+}
+
+// Count 缓存的数量
+func (c *OnlineAPICache) Count() int {
+	c.Lock()
+	defer c.Unlock()
+
+	return len(c.CacheMap)
+}
+
+// getLocalCacheFile 缓存文件的路径
+func getLocalCacheFile() string {
+	return filepath.Join(conf.GetRootPath(), "thirdparty/onlineapi/onlineapi.cache")
+}
+
+// loadLocalCache 从缓存文件中加载；同一个key有多行时以最后一行为准，加载后重写文件去除重复的行
+func (c *OnlineAPICache) loadLocalCache() {
+	f, err := os.Open(getLocalCacheFile())
+	if err != nil {
+		if !os.IsNotExist(err) {
+			logging.RuntimeLog.Errorf("could not open onlineapi cache file : %v", err)
+		}
+		return
+	}
+	defer f.Close()
+
+	var lines int
+	decoder := json.NewDecoder(f)
+	for {
+		var raw json.RawMessage
+		if err = decoder.Decode(&raw); err != nil {
+			// 最后一行可能因写入中断而不完整
+			if err != io.EOF {
+				logging.RuntimeLog.Errorf("read onlineapi cache fail:%v", err)
+			}
+			break
+		}
+		lines++
+		var line onlineAPICacheLine
+		if json.Unmarshal(raw, &line) == nil && line.Key != "" {
+			if line.Item != nil {
+				c.CacheMap[line.Key] = line.Item
+			}
+			continue
+		}
+		// 兼容原来整个map保存的格式
+		cacheMap := make(map[string]*OnlineAPICacheItem)
+		if err = json.Unmarshal(raw, &cacheMap); err != nil {
+			logging.RuntimeLog.Errorf("read onlineapi cache fail:%v", err)
+			continue
+		}
+		for k, v := range cacheMap {
+			c.CacheMap[k] = v
+		}
+		lines += len(cacheMap)
+	}
+	if lines > len(c.CacheMap) {
+		c.saveLocalCache()
+	}
+}
+
+// appendLocalCache 追加一页缓存到缓存文件，调用者需持有锁
+func (c *OnlineAPICache) appendLocalCache(key string, item *OnlineAPICacheItem) bool {
+	data, _ := json.Marshal(onlineAPICacheLine{Key: key, Item: item})
+	cacheFile := getLocalCacheFile()
+	if err := os.MkdirAll(filepath.Dir(cacheFile), 0777); err != nil {
+		logging.RuntimeLog.Errorf("save onlineapi cache fail:%v", err)
+		return false
+	}
+	f, err := os.OpenFile(cacheFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
+	if err != nil {
+		logging.RuntimeLog.Errorf("save onlineapi cache fail:%v", err)
+		return false
+	}
+	defer f.Close()
+	if _, err = f.Write(append(data, '\n')); err != nil {
+		logging.RuntimeLog.Errorf("save onlineapi cache fail:%v", err)
+		return false
+	}
+	return true
+}
+
+// saveLocalCache 重写整个缓存文件（用于清除缓存），调用者需持有锁
+func (c *OnlineAPICache) saveLocalCache() bool {
+	var sb strings.Builder
+	for k, v := range c.CacheMap {
+		data, _ := json.Marshal(onlineAPICacheLine{Key: k, Item: v})
+		sb.Write(data)
+		sb.WriteString("\n")
+	}
+	cacheFile := getLocalCacheFile()
+	if err := os.MkdirAll(filepath.Dir(cacheFile), 0777); err != nil {
+		logging.RuntimeLog.Errorf("save onlineapi cache fail:%v", err)
+		return false
+	}
+	if err := os.WriteFile(cacheFile, []byte(sb.String()), 0666); err != nil {
+		logging.RuntimeLog.Errorf("save onlineapi cache fail:%v", err)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,127 @@
+package onlineapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// memoryCache 测试使用的内存缓存
+type memoryCache struct {
+	items map[string]*OnlineAPICacheItem
+}
+
+func (m *memoryCache) Load(key string, ttl int) (*OnlineAPICacheItem, bool) {
+	item, ok := m.items[key]
+	return item, ok
+}
+
+func (m *memoryCache) Save(key string, item *OnlineAPICacheItem) {
+	m.items[key] = item
+}
+
+func TestMakeCacheKey(t *testing.T) {
+	config := OnlineAPIConfig{SearchPageSize: 100}
+	k1 := MakeCacheKey("fofa", `title="a"  &&   port="80"`, 1, config)
+	k2 := MakeCacheKey("fofa", ` title="a" && port="80" `, 1, config)
+	if k1 != k2 {
+		t.Errorf("normalized query key should be same:%s %s", k1, k2)
+	}
+	if k1 == MakeCacheKey("fofa", `title="a" && port="80"`, 2, config) {
+		t.Error("page should be part of key")
+	}
+	if k1 == MakeCacheKey("quake", `title="a" && port="80"`, 1, config) {
+		t.Error("engine should be part of key")
+	}
+	if k1 == MakeCacheKey("fofa", `title="A" && port="80"`, 1, config) {
+		t.Error("query value should be case sensitive")
+	}
+}
+
+func TestOnlineAPICache_Load(t *testing.T) {
+	c := &OnlineAPICache{CacheMap: map[string]*OnlineAPICacheItem{
+		"fofa:new": {SizeTotal: 1, CacheTime: time.Now()},
+		"fofa:old": {SizeTotal: 1, CacheTime: time.Now().Add(-48 * time.Hour)},
+	}}
+	if _, ok := c.Load("fofa:new", 24); !ok {
+		t.Error("new cache should be loaded")
+	}
+	if _, ok := c.Load("fofa:old", 24); ok {
+		t.Error("expired cache should not be loaded")
+	}
+	if _, ok := c.Load("fofa:new", 0); ok {
+		t.Error("ttl 0 should disable cache")
+	}
+}
+
+func TestOnlineAPICache_LocalFile(t *testing.T) {
+	wd, _ := os.Getwd()
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	c := &OnlineAPICache{CacheMap: make(map[string]*OnlineAPICacheItem)}
+	c.Save("fofa:1", &OnlineAPICacheItem{SizeTotal: 1})
+	c.Save("fofa:2", &OnlineAPICacheItem{SizeTotal: 2})
+	c.Save("fofa:1", &OnlineAPICacheItem{SizeTotal: 3})
+	// 每页追加一行
+	content, _ := os.ReadFile(getLocalCacheFile())
+	if lines := strings.Count(string(content), "\n"); lines != 3 {
+		t.Errorf("cache file lines:%d, want 3", lines)
+	}
+	loaded := &OnlineAPICache{CacheMap: make(map[string]*OnlineAPICacheItem)}
+	loaded.loadLocalCache()
+	if len(loaded.CacheMap) != 2 || loaded.CacheMap["fofa:1"].SizeTotal != 3 {
+		t.Errorf("loaded cache:%+v", loaded.CacheMap)
+	}
+	// 加载后去除重复的行
+	content, _ = os.ReadFile(getLocalCacheFile())
+	if lines := strings.Count(string(content), "\n"); lines != 2 {
+		t.Errorf("cache file lines:%d, want 2", lines)
+	}
+	// 兼容原来的格式
+	data, _ := json.Marshal(map[string]*OnlineAPICacheItem{"quake:1": {SizeTotal: 4}})
+	os.WriteFile(getLocalCacheFile(), data, 0666)
+	loaded = &OnlineAPICache{CacheMap: make(map[string]*OnlineAPICacheItem)}
+	loaded.loadLocalCache()
+	if item, ok := loaded.CacheMap["quake:1"]; !ok || item.SizeTotal != 4 {
+		t.Errorf("loaded cache:%+v", loaded.CacheMap)
+	}
+}
+
+func TestOnlineSearch_Cache(t *testing.T) {
+	var requests int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/resources-info" {
+			json.NewEncoder(w).Encode(map[string]interface{}{"quota_info": map[string]int{"remain_total_quota": 1000}})
+			return
+		}
+		requests++
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"total":   30,
+			"matches": []map[string]interface{}{{"ip": "192.0.2.1", "portinfo": map[string]interface{}{"port": 80}}},
+		})
+	}))
+	defer ts.Close()
+	zoomEyeAPIUrl = ts.URL
+
+	cache := &memoryCache{items: make(map[string]*OnlineAPICacheItem)}
+	s := newTestOnlineSearch("zoomeye", new(ZoomEye), "cache-key", zoomEyePageSize)
+	s.Cache, s.cacheTTL = cache, 24
+	s.Query("example.com", nil)
+	if requests != 2 || len(cache.items) != 2 || s.CachedPageNum != 0 {
+		t.Fatalf("requests:%d, cache:%d, cached page:%d", requests, len(cache.items), s.CachedPageNum)
+	}
+
+	s = newTestOnlineSearch("zoomeye", new(ZoomEye), "cache-key", zoomEyePageSize)
+	s.Cache, s.cacheTTL = cache, 24
+	s.Query("example.com", nil)
+	if requests != 2 || len(s.Result) != 2 || s.CachedPageNum != 2 || s.CachedPointSaved != 2 {
+		t.Errorf("requests:%d, result:%d, cached page:%d, saved:%d", requests, len(s.Result), s.CachedPageNum, s.CachedPointSaved)
+	}
+}
@@ -0,0 +1,286 @@
+package onlineapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/hanc00l/nemo_go/pkg/logging"
+	"github.com/hanc00l/nemo_go/pkg/task/domainscan"
+	"github.com/hanc00l/nemo_go/pkg/task/portscan"
+	"github.com/hanc00l/nemo_go/pkg/utils"
+	"gopkg.in/errgo.v2/fmt/errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var (
+	// censysAPIUrl censys的API地址
+	censysAPIUrl = "https://search.censys.io"
+	// censysPageSizeMax censys每页返回的最大数量
+	censysPageSizeMax = 100
+)
+
+// Censys censys的分页使用游标，需记录每个查询下一页的游标
+type Censys struct {
+	cursorMutex sync.Mutex
+	cursors     map[string]string
+}
+
+// censysHit censys查询结果的一个主机，与导出文件中的记录格式相同
+type censysHit struct {
+	IP       string `json:"ip"`
+	Services []struct {
+		Port                int    `json:"port"`
+		ServiceName         string `json:"service_name"`
+		ExtendedServiceName string `json:"extended_service_name"`
+		HTTP                *struct {
+			Response struct {
+				HtmlTitle string              `json:"html_title"`
+				Headers   map[string][]string `json:"headers"`
+			} `json:"response"`
+		} `json:"http"`
+	} `json:"services"`
+	Location struct {
+		CountryCode string `json:"country_code"`
+		City        string `json:"city"`
+	} `json:"location"`
+	DNS struct {
+		Names []string `json:"names"`
+	} `json:"dns"`
+}
+
+// CensysServiceInfo 查询结果的返回数据
+type CensysServiceInfo struct {
+	Code   int    `json:"code"`
+	Status string `json:"status"`
+	Error  string `json:"error"`
+	Result struct {
+		Total int         `json:"total"`
+		Hits  []censysHit `json:"hits"`
+		Links struct {
+			Next string `json:"next"`
+		} `json:"links"`
+	} `json:"result"`
+}
+
+func (c *Censys) MakeSearchSyntax(syntax map[SyntaxType]string, condition SyntaxType, checkMod SyntaxType, value string) string {
+	if checkMod == After {
+		// last_updated_at:[2023-01-01 to *]
+		return fmt.Sprintf("%s:[%s to *]", syntax[checkMod], value)
+	}
+	if condition == Not {
+		// not services.http.response.html_title:"百度"
+		return fmt.Sprintf("%s %s:\"%s\"", syntax[condition], syntax[checkMod], value)
+	}
+	// services.http.response.body:"百度"
+	return fmt.Sprintf("%s%s\"%s\"", syntax[checkMod], syntax[condition], value)
+}
+
+func (c *Censys) GetSyntaxMap() (syntax map[SyntaxType]string) {
+	syntax = make(map[SyntaxType]string)
+	syntax[And] = "and"
+	syntax[Or] = "or"
+	syntax[Equal] = ":"
+	syntax[Not] = "not"
+	syntax[After] = "last_updated_at"
+	syntax[Title] = "services.http.response.html_title"
+	syntax[Body] = "services.http.response.body"
+	syntax[Cert] = "services.tls.certificates.leaf_data.subject_dn"
+	syntax[IconHash] = syntaxNotSupport
+	syntax[Domain] = "dns.names"
+	syntax[IP] = "ip"
+	syntax[Port] = "services.port"
+	syntax[Org] = "autonomous_system.name"
+	syntax[Country] = "location.country_code"
+
+	return
+}
+
+func (c *Censys) GetQueryString(domain string, config OnlineAPIConfig, filterKeyword map[string]struct{}) (query string) {
+	if config.SearchByKeyWord {
+		query = config.Target
+	} else {
+		if utils.CheckIPOrSubnet(domain) {
+			query = fmt.Sprintf("ip:\"%s\"", domain)
+		} else {
+			query = fmt.Sprintf("dns.names:\"%s\" or dns.names:\"*.%s\"", domain, domain)
+		}
+	}
+	if words := c.getFilterTitleKeyword(filterKeyword); len(words) > 0 {
+		query = fmt.Sprintf("(%s) and (%s)", query, words)
+	}
+	if config.IsIgnoreOutofChina {
+		query = fmt.Sprintf("(%s) and location.country_code:\"CN\"", query)
+	}
+	if len(config.SearchStartTime) > 0 {
+		query = fmt.Sprintf("(%s) and last_updated_at:[%s to *]", query, config.SearchStartTime)
+	}
+	return
+}
+
+func (c *Censys) getFilterTitleKeyword(filterKeyword map[string]struct{}) string {
+	var words []string
+	for k := range filterKeyword {
+		words = append(words, fmt.Sprintf("not services.http.response.body:\"%s\"", k))
+	}
+
+	return strings.Join(words, " and ")
+}
+
+// GetQuota 查询key本月剩余的查询次数
+func (c *Censys) GetQuota(apiKey string, isProxy bool) (remaining int, err error) {
+	arr := strings.SplitN(apiKey, ":", 2)
+	if len(arr) != 2 {
+		return 0, errors.Newf("invalid censys key:%s", apiKey)
+	}
+	request, err := http.NewRequest(http.MethodGet, censysAPIUrl+"/api/v1/account", nil)
+	if err != nil {
+		return
+	}
+	request.SetBasicAuth(arr[0], arr[1])
+	var info struct {
+		Error string `json:"error"`
+		Quota *struct {
+			Used      int `json:"used"`
+			Allowance int `json:"allowance"`
+		} `json:"quota"`
+	}
+	if err = doQuotaRequest(request, isProxy, &info); err != nil {
+		return
+	}
+	if len(info.Error) > 0 || info.Quota == nil {
+		return 0, errors.Newf("Censys Account Error:%s", info.Error)
+	}
+	return info.Quota.Allowance - info.Quota.Used, nil
+}
+
+// Run 执行一次查询，apiKey的格式为API ID:Secret
+func (c *Censys) Run(query string, apiKey string, pageIndex int, pageSize int, config OnlineAPIConfig) (pageResult []onlineSearchResult, sizeTotal int, err error) {
+	arr := strings.SplitN(apiKey, ":", 2)
+	if len(arr) != 2 {
+		err = newAPIKeyError(keyErrorInvalid, "invalid censys key:%s", desensitizationKey(apiKey))
+		return
+	}
+	if pageSize > censysPageSizeMax {
+		pageSize = censysPageSizeMax
+	}
+	cursor, ok := c.getCursor(query, pageIndex)
+	if !ok {
+		err = errors.Newf("censys search no cursor for page:%d", pageIndex)
+		return
+	}
+	request, err := http.NewRequest(http.MethodGet, censysAPIUrl+"/api/v2/hosts/search", nil)
+	if err != nil {
+		return
+	}
+	params := make(url.Values)
+	params.Add("q", query)
+	params.Add("per_page", strconv.Itoa(pageSize))
+	if len(cursor) > 0 {
+		params.Add("cursor", cursor)
+	}
+	request.URL.RawQuery = params.Encode()
+	request.SetBasicAuth(arr[0], arr[1])
+	resp, err := utils.GetProxyHttpClient(config.IsProxy).Do(request)
+	if err != nil {
+		return
+	}
+	content, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return
+	}
+	var serviceInfo CensysServiceInfo
+	if err = json.Unmarshal(content, &serviceInfo); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			err = httpStatusKeyError(resp.StatusCode, "Censys Search Error:%d", resp.StatusCode)
+		}
+		return
+	}
+	if serviceInfo.Code != http.StatusOK {
+		err = httpStatusKeyError(serviceInfo.Code, "Censys Search Error:%s %s", serviceInfo.Status, serviceInfo.Error)
+		return
+	}
+	c.setCursor(query, pageIndex+1, serviceInfo.Result.Links.Next)
+	sizeTotal = serviceInfo.Result.Total
+	for _, hit := range serviceInfo.Result.Hits {
+		pageResult = append(pageResult, hit.toSearchResult()...)
+	}
+
+	return
+}
+
+// getCursor 获取查询指定页的游标，第一页的游标为空
+func (c *Censys) getCursor(query string, pageIndex int) (cursor string, ok bool) {
+	if pageIndex <= 1 {
+		return "", true
+	}
+	c.cursorMutex.Lock()
+	defer c.cursorMutex.Unlock()
+
+	cursor, ok = c.cursors[fmt.Sprintf("%s|%d", query, pageIndex)]
+	return
+}
+
+// setCursor 保存查询指定页的游标
+func (c *Censys) setCursor(query string, pageIndex int, cursor string) {
+	if len(cursor) == 0 {
+		return
+	}
+	c.cursorMutex.Lock()
+	defer c.cursorMutex.Unlock()
+
+	if c.cursors == nil {
+		c.cursors = make(map[string]string)
+	}
+	c.cursors[fmt.Sprintf("%s|%d", query, pageIndex)] = cursor
+}
+
+// toSearchResult 转换为搜索结果，每个服务及域名对应一条记录
+func (h *censysHit) toSearchResult() (results []onlineSearchResult) {
+	for _, service := range h.Services {
+		fsr := onlineSearchResult{
+			IP:      h.IP,
+			Port:    fmt.Sprintf("%d", service.Port),
+			Country: h.Location.CountryCode,
+			City:    h.Location.City,
+		}
+		if service.HTTP != nil {
+			fsr.Title = service.HTTP.Response.HtmlTitle
+			for k, v := range service.HTTP.Response.Headers {
+				if strings.EqualFold(k, "server") && len(v) > 0 {
+					fsr.Server = v[0]
+				}
+			}
+		} else if service.ExtendedServiceName != "" && service.ExtendedServiceName != "UNKNOWN" {
+			fsr.Server = strings.ToLower(service.ExtendedServiceName)
+		}
+		if len(h.DNS.Names) == 0 {
+			results = append(results, fsr)
+			continue
+		}
+		for _, name := range h.DNS.Names {
+			r := fsr
+			r.Domain = name
+			r.Host = name
+			results = append(results, r)
+		}
+	}
+	return
+}
+
+func (c *Censys) ParseContentResult(content []byte) (ipResult portscan.Result, domainResult domainscan.Result) {
+	var results []onlineSearchResult
+	parseJSONContent(content, func(data json.RawMessage) {
+		var hit censysHit
+		if err := json.Unmarshal(data, &hit); err != nil {
+			logging.RuntimeLog.Error(err)
+			return
+		}
+		results = append(results, hit.toSearchResult()...)
+	})
+	return makeContentResult(results, "censys")
+}
@@ -0,0 +1,70 @@
+package onlineapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCensys_Run(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "id" || pass != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"code":401,"status":"Unauthorized","error":"invalid credentials"}`))
+			return
+		}
+		ip, next := "192.0.2.1", "cursor-2"
+		if r.URL.Query().Get("cursor") == "cursor-2" {
+			ip, next = "192.0.2.2", ""
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"code":   200,
+			"status": "OK",
+			"result": map[string]interface{}{
+				"total": 2,
+				"hits": []map[string]interface{}{{
+					"ip":  ip,
+					"dns": map[string]interface{}{"names": []string{"www.example.com"}},
+					"services": []map[string]interface{}{
+						{"port": 443, "service_name": "HTTP", "http": map[string]interface{}{"response": map[string]interface{}{"html_title": "Example", "headers": map[string][]string{"Server": {"nginx"}}}}},
+						{"port": 22, "service_name": "SSH", "extended_service_name": "SSH"},
+					},
+				}},
+				"links": map[string]string{"next": next},
+			},
+		})
+	}))
+	defer ts.Close()
+	censysAPIUrl = ts.URL
+
+	s := newTestOnlineSearch("censys", new(Censys), "id:secret", 1)
+	s.Query("example.com", nil)
+	if len(s.Result) != 4 {
+		t.Fatalf("result count:%d", len(s.Result))
+	}
+	if s.Result[2].IP != "192.0.2.2" {
+		t.Error("cursor not used for page 2")
+	}
+	if s.Result[0].Server != "nginx" || s.Result[1].Server != "ssh" {
+		t.Errorf("server:%s,%s", s.Result[0].Server, s.Result[1].Server)
+	}
+
+	if _, _, err := new(Censys).Run("ip:192.0.2.1", "id:bad", 1, 100, OnlineAPIConfig{}); err == nil {
+		t.Error("invalid key should fail")
+	}
+	if _, _, err := new(Censys).Run("ip:192.0.2.1", "id", 1, 100, OnlineAPIConfig{}); err == nil {
+		t.Error("invalid key format should fail")
+	}
+}
+
+func TestCensys_ParseContentResult(t *testing.T) {
+	data := `[{"ip":"192.0.2.1","services":[{"port":80,"http":{"response":{"html_title":"A"}}}],"dns":{"names":["a.example.com"]}}]`
+	ipResult, domainResult := new(Censys).ParseContentResult([]byte(data))
+	if !ipResult.HasPort("192.0.2.1", 80) {
+		t.Errorf("ip result:%v", ipResult.IPResult)
+	}
+	if !domainResult.HasDomain("a.example.com") {
+		t.Error("domain not parsed")
+	}
+}
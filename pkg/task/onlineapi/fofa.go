@@ -36,6 +36,13 @@ func (f *FOFA) GetSyntaxMap() (syntax map[SyntaxType]string) {
 	syntax[After] = "after"
 	syntax[Title] = "title"
 	syntax[Body] = "body"
+	syntax[Cert] = "cert"
+	syntax[IconHash] = "icon_hash"
+	syntax[Domain] = "domain"
+	syntax[IP] = "ip"
+	syntax[Port] = "port"
+	syntax[Org] = "org"
+	syntax[Country] = "country"
 
 	return
 }
@@ -76,7 +83,7 @@ func (f *FOFA) Run(query string, apiKey string, pageIndex int, pageSize int, con
 	fields := "domain,host,ip,port,title,country,city,server,banner"
 	arr := strings.Split(apiKey, ":")
 	if len(arr) != 2 {
-		err = errors.Newf("invalid fofa key:%s", apiKey)
+		err = newAPIKeyError(keyErrorInvalid, "invalid fofa key:%s", desensitizationKey(apiKey))
 		return
 	}
 	request, err := http.NewRequest(http.MethodGet, "https://fofa.info/api/v1/search/all", nil)
@@ -97,11 +104,51 @@ func (f *FOFA) Run(query string, apiKey string, pageIndex int, pageSize int, con
 	}
 	content, err := io.ReadAll(resp.Body)
 	resp.Body.Close()
+	if err != nil {
+		return
+	}
 	pageResult, sizeTotal, err = f.parseFofaSearchResult(content)
+	if err != nil && resp.StatusCode != http.StatusOK {
+		if keyErr, ok := err.(*APIKeyError); !ok || keyErr.Kind == keyErrorUnknown {
+			err = httpStatusKeyError(resp.StatusCode, "FOFA Search Error:%d %v", resp.StatusCode, err)
+		}
+	}
 
 	return
 }
 
+// GetQuota 查询key剩余可获取的数据条数
+func (f *FOFA) GetQuota(apiKey string, isProxy bool) (remaining int, err error) {
+	arr := strings.Split(apiKey, ":")
+	if len(arr) != 2 {
+		return 0, errors.Newf("invalid fofa key:%s", apiKey)
+	}
+	request, err := http.NewRequest(http.MethodGet, "https://fofa.info/api/v1/info/my", nil)
+	if err != nil {
+		return
+	}
+	params := make(url.Values)
+	params.Add("email", arr[0])
+	params.Add("key", arr[1])
+	request.URL.RawQuery = params.Encode()
+	var info struct {
+		IsError        bool   `json:"error"`
+		ErrorMessage   string `json:"errmsg"`
+		RemainAPIData  int    `json:"remain_api_data"`
+		RemainAPIQuery int    `json:"remain_api_query"`
+	}
+	if err = doQuotaRequest(request, isProxy, &info); err != nil {
+		return
+	}
+	if info.IsError {
+		return 0, errors.New(info.ErrorMessage)
+	}
+	if info.RemainAPIQuery <= 0 {
+		return 0, nil
+	}
+	return info.RemainAPIData, nil
+}
+
 func (f *FOFA) parseFofaSearchResult(queryResult []byte) (result []onlineSearchResult, sizeTotal int, err error) {
 	r := fofaQueryResult{}
 	err = json.Unmarshal(queryResult, &r)
@@ -109,7 +156,7 @@ func (f *FOFA) parseFofaSearchResult(queryResult []byte) (result []onlineSearchR
 		return
 	}
 	if r.IsError {
-		err = errors.New(r.ErrorMessage)
+		err = fofaKeyError(r.ErrorMessage)
 		return
 	}
 	sizeTotal = r.Size
@@ -209,3 +256,19 @@ func (f *FOFA) ParseContentResult(content []byte) (ipResult portscan.Result, dom
 	}
 	return
 }
+
+// fofaKeyError 根据FOFA错误信息中的错误码（如：[-700] Account Invalid）判断key错误
+func fofaKeyError(message string) error {
+	kind := keyErrorUnknown
+	if strings.HasPrefix(message, "[") {
+		if end := strings.Index(message, "]"); end > 0 {
+			switch message[1:end] {
+			case "-700", "-701":
+				kind = keyErrorInvalid
+			case "820031":
+				kind = keyErrorExhausted
+			}
+		}
+	}
+	return newAPIKeyError(kind, "%s", message)
+}
@@ -10,7 +10,6 @@ import (
 	"github.com/hanc00l/nemo_go/pkg/task/domainscan"
 	"github.com/hanc00l/nemo_go/pkg/task/portscan"
 	"github.com/hanc00l/nemo_go/pkg/utils"
-	"gopkg.in/errgo.v2/fmt/errors"
 	"io"
 	"net/http"
 	"net/url"
@@ -54,9 +53,16 @@ func (h *Hunter) GetSyntaxMap() (syntax map[SyntaxType]string) {
 	syntax[Or] = "or"
 	syntax[Equal] = "="
 	syntax[Not] = "!="
-	syntax[After] = "(NOT SUPPORT YET)"
+	syntax[After] = syntaxNotSupport
 	syntax[Title] = "web.title"
 	syntax[Body] = "web.body"
+	syntax[Cert] = "cert"
+	syntax[IconHash] = syntaxNotSupport
+	syntax[Domain] = "domain"
+	syntax[IP] = "ip"
+	syntax[Port] = "ip.port"
+	syntax[Org] = syntaxNotSupport
+	syntax[Country] = "ip.country"
 
 	return
 }
@@ -128,12 +134,19 @@ func (h *Hunter) Run(query string, apiKey string, pageIndex int, pageSize int, c
 	}
 	err = json.Unmarshal(content, &serviceInfo)
 	if err != nil {
+		if resp.StatusCode != http.StatusOK {
+			err = httpStatusKeyError(resp.StatusCode, "Hunter Search Error:%d", resp.StatusCode)
+		}
 		return
 	}
 	if serviceInfo.Code != 200 {
-		err = errors.Newf("Hunter Search Error:%s", serviceInfo.Message)
+		err = newAPIKeyError(hunterKeyErrorKind(serviceInfo.Code), "Hunter Search Error:%d %s", serviceInfo.Code, serviceInfo.Message)
 		return
 	}
+	// hunter没有查询额度的接口，从查询结果中获取剩余积分
+	if restQuota, ok := parseQuotaNumber(serviceInfo.Data.RestQuota); ok {
+		updateAPIKeyRemaining("hunter", apiKey, restQuota)
+	}
 	sizeTotal = serviceInfo.Data.Total
 	for _, data := range serviceInfo.Data.Arr {
 		qsr := onlineSearchResult{
@@ -252,3 +265,14 @@ func (h *Hunter) ParseContentResult(content []byte) (ipResult portscan.Result, d
 	}
 	return
 }
+
+// hunterKeyErrorKind 根据Hunter返回的错误码判断key错误：401为key无效，40204、40205为积分用完
+func hunterKeyErrorKind(code int) keyErrorKind {
+	switch code {
+	case 401:
+		return keyErrorInvalid
+	case 40204, 40205:
+		return keyErrorExhausted
+	}
+	return keyErrorUnknown
+}
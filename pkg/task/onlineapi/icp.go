@@ -2,17 +2,16 @@ package onlineapi
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/hanc00l/nemo_go/pkg/conf"
 	"github.com/hanc00l/nemo_go/pkg/logging"
 	"github.com/hanc00l/nemo_go/pkg/task/domainscan"
 	"io"
-	"math/rand"
 	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
-	"time"
 )
 
 type ICPQuery struct {
@@ -91,8 +90,17 @@ func (i *ICPQuery) RunICPQuery(domain string) *ICPInfo {
 		logging.CLILog.Warning("no icp searchEngine key,search exit")
 		return nil
 	}
-	url := fmt.Sprintf("https://apidatav2.chinaz.com/single/icp?key=%s&domain=%s", i.selectOneAPIKey(), domain)
+	key := i.selectOneAPIKey()
+	if len(key) == 0 {
+		logging.RuntimeLog.Warning("no icp api key to available")
+		return nil
+	}
+	url := fmt.Sprintf("https://apidatav2.chinaz.com/single/icp?key=%s&domain=%s", key, domain)
 	resp, err := http.Get(url)
+	if err != nil {
+		logging.RuntimeLog.Error(err)
+		return nil
+	}
 	if resp.StatusCode != 200 {
 		logging.RuntimeLog.Errorf("get api status code:%v", resp.Status)
 		return nil
@@ -114,18 +122,17 @@ func (i *ICPQuery) RunICPQuery(domain string) *ICPInfo {
 		return &r.Result
 	} else {
 		logging.RuntimeLog.Error(r)
+		// StateCode小于0时为key无效或额度用完等错误
+		if r.StateCode < 0 {
+			updateAPIKeyError("icp", key, errors.New(r.Reason))
+		}
 	}
 	return nil
 }
 
+// selectOneAPIKey 选择一个查询的key，跳过额度用完或无效的key
 func (i *ICPQuery) selectOneAPIKey() string {
-	keys := strings.Split(conf.GlobalWorkerConfig().API.ICP.Key, ",")
-	if len(keys) == 0 {
-		return ""
-	}
-	r := rand.New(rand.NewSource(time.Now().UnixNano()))
-	n := r.Intn(len(keys))
-	return keys[n]
+	return selectAPIKey("icp", strings.Split(conf.GlobalWorkerConfig().API.ICP.Key, ","), "")
 }
 
 // loadICPCache 从本地缓存中加载ICP备案信息
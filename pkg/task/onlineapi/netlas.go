@@ -0,0 +1,216 @@
+package onlineapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/hanc00l/nemo_go/pkg/logging"
+	"github.com/hanc00l/nemo_go/pkg/task/domainscan"
+	"github.com/hanc00l/nemo_go/pkg/task/portscan"
+	"github.com/hanc00l/nemo_go/pkg/utils"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+var (
+	// netlasAPIUrl netlas的API地址
+	netlasAPIUrl = "https://app.netlas.io"
+	// netlasPageSize netlas每页返回的数量固定为20
+	netlasPageSize = 20
+)
+
+type Netlas struct {
+}
+
+// netlasData netlas查询结果的一条记录
+type netlasData struct {
+	IP       string   `json:"ip"`
+	Port     int      `json:"port"`
+	Host     string   `json:"host"`
+	Protocol string   `json:"protocol"`
+	Domain   []string `json:"domain"`
+	HTTP     *struct {
+		Title   string              `json:"title"`
+		Headers map[string][]string `json:"headers"`
+	} `json:"http"`
+	Geo struct {
+		Country string `json:"country"`
+		City    string `json:"city"`
+	} `json:"geo"`
+}
+
+// netlasItem 查询结果及导出文件中的一条记录
+type netlasItem struct {
+	Data netlasData `json:"data"`
+}
+
+// NetlasServiceInfo 查询结果的返回数据
+type NetlasServiceInfo struct {
+	Detail string       `json:"detail"`
+	Items  []netlasItem `json:"items"`
+}
+
+// NetlasCountInfo 查询结果数量的返回数据
+type NetlasCountInfo struct {
+	Detail string `json:"detail"`
+	Count  int    `json:"count"`
+}
+
+func (n *Netlas) MakeSearchSyntax(syntax map[SyntaxType]string, condition SyntaxType, checkMod SyntaxType, value string) string {
+	if checkMod == After {
+		// last_updated:[2023-01-01 TO *]
+		return fmt.Sprintf("%s:[%s TO *]", syntax[checkMod], value)
+	}
+	if condition == Not {
+		// NOT http.title:"百度"
+		return fmt.Sprintf("%s %s:\"%s\"", syntax[condition], syntax[checkMod], value)
+	}
+	// http.body:"百度"
+	return fmt.Sprintf("%s%s\"%s\"", syntax[checkMod], syntax[condition], value)
+}
+
+func (n *Netlas) GetSyntaxMap() (syntax map[SyntaxType]string) {
+	syntax = make(map[SyntaxType]string)
+	syntax[And] = "AND"
+	syntax[Or] = "OR"
+	syntax[Equal] = ":"
+	syntax[Not] = "NOT"
+	syntax[After] = "last_updated"
+	syntax[Title] = "http.title"
+	syntax[Body] = "http.body"
+	syntax[Cert] = "certificate.subject_dn"
+	syntax[IconHash] = syntaxNotSupport
+	syntax[Domain] = "host"
+	syntax[IP] = "ip"
+	syntax[Port] = "port"
+	syntax[Org] = syntaxNotSupport
+	syntax[Country] = "geo.country"
+
+	return
+}
+
+func (n *Netlas) GetQueryString(domain string, config OnlineAPIConfig, filterKeyword map[string]struct{}) (query string) {
+	if config.SearchByKeyWord {
+		query = config.Target
+	} else {
+		if utils.CheckIPOrSubnet(domain) {
+			query = fmt.Sprintf("ip:\"%s\"", domain)
+		} else {
+			query = fmt.Sprintf("host:\"%s\" OR host:*.%s", domain, domain)
+		}
+	}
+	if words := n.getFilterTitleKeyword(filterKeyword); len(words) > 0 {
+		query = fmt.Sprintf("(%s) AND (%s)", query, words)
+	}
+	if config.IsIgnoreOutofChina {
+		query = fmt.Sprintf("(%s) AND geo.country:\"CN\"", query)
+	}
+	if len(config.SearchStartTime) > 0 {
+		query = fmt.Sprintf("(%s) AND last_updated:[%s TO *]", query, config.SearchStartTime)
+	}
+	return
+}
+
+func (n *Netlas) getFilterTitleKeyword(filterKeyword map[string]struct{}) string {
+	var words []string
+	for k := range filterKeyword {
+		words = append(words, fmt.Sprintf("NOT http.body:\"%s\"", k))
+	}
+
+	return strings.Join(words, " AND ")
+}
+
+// Run 执行一次查询，结果的总数在查询第一页时通过单独的接口获取
+func (n *Netlas) Run(query string, apiKey string, pageIndex int, pageSize int, config OnlineAPIConfig) (pageResult []onlineSearchResult, sizeTotal int, err error) {
+	if pageIndex <= 1 {
+		var countInfo NetlasCountInfo
+		if err = n.doRequest("/api/responses_count/", url.Values{"q": {query}}, apiKey, config.IsProxy, &countInfo); err != nil {
+			return
+		}
+		sizeTotal = countInfo.Count
+	}
+	params := make(url.Values)
+	params.Add("q", query)
+	params.Add("start", strconv.Itoa((pageIndex-1)*netlasPageSize))
+	var serviceInfo NetlasServiceInfo
+	if err = n.doRequest("/api/responses/", params, apiKey, config.IsProxy, &serviceInfo); err != nil {
+		return
+	}
+	for _, item := range serviceInfo.Items {
+		pageResult = append(pageResult, item.Data.toSearchResult()...)
+	}
+
+	return
+}
+
+// doRequest 请求netlas的API并解析返回的数据
+func (n *Netlas) doRequest(path string, params url.Values, apiKey string, isProxy bool, v interface{}) (err error) {
+	request, err := http.NewRequest(http.MethodGet, netlasAPIUrl+path, nil)
+	if err != nil {
+		return
+	}
+	request.URL.RawQuery = params.Encode()
+	request.Header.Set("X-API-Key", apiKey)
+	resp, err := utils.GetProxyHttpClient(isProxy).Do(request)
+	if err != nil {
+		return
+	}
+	content, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return
+	}
+	if resp.StatusCode != http.StatusOK {
+		var errInfo NetlasCountInfo
+		json.Unmarshal(content, &errInfo)
+		return httpStatusKeyError(resp.StatusCode, "Netlas Search Error:%d %s", resp.StatusCode, errInfo.Detail)
+	}
+	return json.Unmarshal(content, v)
+}
+
+// toSearchResult 转换为搜索结果，每个域名对应一条记录
+func (d *netlasData) toSearchResult() (results []onlineSearchResult) {
+	fsr := onlineSearchResult{
+		IP:      d.IP,
+		Port:    fmt.Sprintf("%d", d.Port),
+		Country: d.Geo.Country,
+		City:    d.Geo.City,
+	}
+	if d.HTTP != nil {
+		fsr.Title = d.HTTP.Title
+		for k, v := range d.HTTP.Headers {
+			if strings.EqualFold(k, "server") && len(v) > 0 {
+				fsr.Server = v[0]
+			}
+		}
+	}
+	hosts := d.Domain
+	if len(hosts) == 0 && len(d.Host) > 0 && !utils.CheckIP(d.Host) {
+		hosts = []string{d.Host}
+	}
+	if len(hosts) == 0 {
+		return append(results, fsr)
+	}
+	for _, host := range hosts {
+		r := fsr
+		r.Domain = host
+		r.Host = host
+		results = append(results, r)
+	}
+	return
+}
+
+func (n *Netlas) ParseContentResult(content []byte) (ipResult portscan.Result, domainResult domainscan.Result) {
+	var results []onlineSearchResult
+	parseJSONContent(content, func(data json.RawMessage) {
+		var item netlasItem
+		if err := json.Unmarshal(data, &item); err != nil {
+			logging.RuntimeLog.Error(err)
+			return
+		}
+		results = append(results, item.Data.toSearchResult()...)
+	})
+	return makeContentResult(results, "netlas")
+}
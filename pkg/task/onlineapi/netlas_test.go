@@ -0,0 +1,59 @@
+package onlineapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+func TestNetlas_Run(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-API-Key") != "test-key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"detail":"Invalid API key"}`))
+			return
+		}
+		switch r.URL.Path {
+		case "/api/responses_count/":
+			w.Write([]byte(`{"count":30}`))
+		case "/api/responses/":
+			start, _ := strconv.Atoi(r.URL.Query().Get("start"))
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"items": []map[string]interface{}{
+					{"data": map[string]interface{}{"ip": fmt.Sprintf("192.0.2.%d", start), "port": 443, "domain": []string{"www.example.com"}, "http": map[string]interface{}{"title": "Example", "headers": map[string][]string{"server": {"nginx"}}}}},
+				},
+			})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+	netlasAPIUrl = ts.URL
+
+	s := newTestOnlineSearch("netlas", new(Netlas), "test-key", netlasPageSize)
+	s.Query("example.com", nil)
+	if len(s.Result) != 2 {
+		t.Fatalf("result count:%d", len(s.Result))
+	}
+	if s.Result[0].IP != "192.0.2.0" || s.Result[1].IP != "192.0.2.20" || s.Result[0].Server != "nginx" {
+		t.Errorf("result:%v", s.Result)
+	}
+
+	if _, _, err := new(Netlas).Run("host:example.com", "bad-key", 1, netlasPageSize, OnlineAPIConfig{}); err == nil {
+		t.Error("invalid key should fail")
+	}
+}
+
+func TestNetlas_ParseContentResult(t *testing.T) {
+	data := `{"data":{"ip":"192.0.2.1","port":8443,"host":"a.example.com","http":{"title":"A"}}}`
+	ipResult, domainResult := new(Netlas).ParseContentResult([]byte(data))
+	if !ipResult.HasPort("192.0.2.1", 8443) {
+		t.Errorf("ip result:%v", ipResult.IPResult)
+	}
+	if !domainResult.HasDomain("a.example.com") {
+		t.Error("domain not parsed")
+	}
+}
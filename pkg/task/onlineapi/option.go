@@ -24,4 +24,14 @@ const (
 	After
 	Title
 	Body
+	Cert
+	IconHash
+	Domain
+	IP
+	Port
+	Org
+	Country
 )
+
+// syntaxNotSupport 搜索引擎不支持的语法
+const syntaxNotSupport = "(NOT SUPPORT YET)"
@@ -121,9 +121,16 @@ func (q *Quake) GetSyntaxMap() (syntax map[SyntaxType]string) {
 	syntax[Or] = "OR"
 	syntax[Equal] = ":"
 	syntax[Not] = "NOT"
-	syntax[After] = "(NOT SUPPORT YET)"
+	syntax[After] = syntaxNotSupport
 	syntax[Title] = "title"
 	syntax[Body] = "body"
+	syntax[Cert] = "cert"
+	syntax[IconHash] = syntaxNotSupport
+	syntax[Domain] = "domain"
+	syntax[IP] = "ip"
+	syntax[Port] = "port"
+	syntax[Org] = "org"
+	syntax[Country] = "country"
 
 	return
 }
@@ -156,6 +163,31 @@ func (q *Quake) getFilterTitleKeyword(filterKeyword map[string]struct{}) string
 	return strings.Join(words, " AND ")
 }
 
+// GetQuota 查询key本月剩余的积分
+func (q *Quake) GetQuota(apiKey string, isProxy bool) (remaining int, err error) {
+	request, err := http.NewRequest(http.MethodGet, "https://quake.360.net/api/v3/user/info", nil)
+	if err != nil {
+		return
+	}
+	request.Header.Set("X-QuakeToken", apiKey)
+	request.Header.Add("User-Agent", userAgent)
+	var info struct {
+		Code    interface{} `json:"code"`
+		Message string      `json:"message"`
+		Data    struct {
+			MonthRemainingCredit int `json:"month_remaining_credit"`
+			ConstantCredit       int `json:"constant_credit"`
+		} `json:"data"`
+	}
+	if err = doQuotaRequest(request, isProxy, &info); err != nil {
+		return
+	}
+	if fmt.Sprintf("%v", info.Code) != "0" {
+		return 0, errors.New(fmt.Sprintf("quake user info error:%v %s", info.Code, info.Message))
+	}
+	return info.Data.MonthRemainingCredit + info.Data.ConstantCredit, nil
+}
+
 func (q *Quake) Run(query string, apiKey string, pageIndex int, pageSize int, config OnlineAPIConfig) (pageResult []onlineSearchResult, sizeTotal int, err error) {
 	data := quakePostData{
 		Query:       query,
@@ -188,7 +220,10 @@ func (q *Quake) Run(query string, apiKey string, pageIndex int, pageSize int, co
 			return
 		}
 		if strings.Contains(string(body), "/quake/login") {
-			return nil, 0, errors.New("quake token invalid")
+			return nil, 0, newAPIKeyError(keyErrorInvalid, "quake token invalid")
+		}
+		if resp.StatusCode != http.StatusOK {
+			return nil, 0, httpStatusKeyError(resp.StatusCode, "Quake Search Error:%d", resp.StatusCode)
 		}
 		if strings.Contains(string(body), "暂不支持搜索该内容") {
 			return nil, 0, errors.New("暂不支持搜索该内容")
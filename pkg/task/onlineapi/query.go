@@ -0,0 +1,262 @@
+package onlineapi
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// 统一查询语法：字段="值"、字段!="值"，条件之间使用&&、||及括号组合，!对条件或括号取反；
+// 如：title="后台" && (port="8080" || port="8443") && !body="test"
+// 支持的字段：title、body、cert、icon_hash、domain、ip、port、org、country、after
+
+// queryFieldMap 统一查询语法的字段与SyntaxType的对应关系
+var queryFieldMap = map[string]SyntaxType{
+	"title":     Title,
+	"body":      Body,
+	"cert":      Cert,
+	"icon_hash": IconHash,
+	"domain":    Domain,
+	"ip":        IP,
+	"port":      Port,
+	"org":       Org,
+	"country":   Country,
+	"after":     After,
+}
+
+// QueryNode 统一查询语法解析后的语法树节点
+type QueryNode interface {
+	// negate 返回取反后的节点，取反被下推到条件上，以便不支持对括号取反的搜索引擎也能使用
+	negate() QueryNode
+}
+
+// QueryCondition 查询条件，如title="百度"
+type QueryCondition struct {
+	Field string
+	Not   bool
+	Value string
+}
+
+// QueryLogic 多个查询条件的与、或组合
+type QueryLogic struct {
+	IsOr     bool
+	Children []QueryNode
+}
+
+func (c *QueryCondition) negate() QueryNode {
+	return &QueryCondition{Field: c.Field, Not: !c.Not, Value: c.Value}
+}
+
+func (l *QueryLogic) negate() QueryNode {
+	r := &QueryLogic{IsOr: !l.IsOr}
+	for _, child := range l.Children {
+		r.Children = append(r.Children, child.negate())
+	}
+	return r
+}
+
+// queryParser 统一查询语法的递归下降解析器
+type queryParser struct {
+	input []rune
+	pos   int
+}
+
+// ParseQuery 解析统一查询语法
+func ParseQuery(query string) (node QueryNode, err error) {
+	p := &queryParser{input: []rune(query)}
+	if node, err = p.parseOr(); err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if p.pos < len(p.input) {
+		return nil, fmt.Errorf("unexpected %q at %d", string(p.input[p.pos:]), p.pos)
+	}
+	return node, nil
+}
+
+func (p *queryParser) parseOr() (QueryNode, error) {
+	return p.parseLogic(true)
+}
+
+func (p *queryParser) parseAnd() (QueryNode, error) {
+	return p.parseLogic(false)
+}
+
+// parseLogic 解析以||或&&连接的表达式，&&的优先级高于||
+func (p *queryParser) parseLogic(isOr bool) (QueryNode, error) {
+	op, next := "&&", p.parseUnary
+	if isOr {
+		op, next = "||", p.parseAnd
+	}
+	node, err := next()
+	if err != nil {
+		return nil, err
+	}
+	logic := &QueryLogic{IsOr: isOr, Children: []QueryNode{node}}
+	for p.consume(op) {
+		if node, err = next(); err != nil {
+			return nil, err
+		}
+		logic.Children = append(logic.Children, node)
+	}
+	if len(logic.Children) == 1 {
+		return logic.Children[0], nil
+	}
+	return logic, nil
+}
+
+func (p *queryParser) parseUnary() (QueryNode, error) {
+	if p.consume("!") {
+		node, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return node.negate(), nil
+	}
+	if p.consume("(") {
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.consume(")") {
+			return nil, fmt.Errorf("missing ) at %d", p.pos)
+		}
+		return node, nil
+	}
+	return p.parseCondition()
+}
+
+func (p *queryParser) parseCondition() (QueryNode, error) {
+	p.skipSpace()
+	start := p.pos
+	for p.pos < len(p.input) && (unicode.IsLetter(p.input[p.pos]) || p.input[p.pos] == '_') {
+		p.pos++
+	}
+	field := strings.ToLower(string(p.input[start:p.pos]))
+	if field == "" {
+		return nil, fmt.Errorf("missing field at %d", start)
+	}
+	if _, ok := queryFieldMap[field]; !ok {
+		return nil, fmt.Errorf("unknown field:%s", field)
+	}
+	c := &QueryCondition{Field: field}
+	if p.consume("!=") {
+		c.Not = true
+	} else if !p.consume("=") {
+		return nil, fmt.Errorf("missing = after %s", field)
+	}
+	value, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+	c.Value = value
+	return c, nil
+}
+
+// parseValue 解析条件的值，支持双引号包含（可用\"转义）或不包含空白及括号的值
+func (p *queryParser) parseValue() (string, error) {
+	p.skipSpace()
+	var sb strings.Builder
+	if p.pos < len(p.input) && p.input[p.pos] == '"' {
+		for p.pos++; p.pos < len(p.input); p.pos++ {
+			switch ch := p.input[p.pos]; {
+			case ch == '\\' && p.pos+1 < len(p.input):
+				p.pos++
+				sb.WriteRune(p.input[p.pos])
+			case ch == '"':
+				p.pos++
+				return sb.String(), nil
+			default:
+				sb.WriteRune(ch)
+			}
+		}
+		return "", errors.New("missing closing quote")
+	}
+	for ; p.pos < len(p.input); p.pos++ {
+		ch := p.input[p.pos]
+		if unicode.IsSpace(ch) || ch == '(' || ch == ')' || ch == '&' || ch == '|' {
+			break
+		}
+		sb.WriteRune(ch)
+	}
+	if sb.Len() == 0 {
+		return "", fmt.Errorf("missing value at %d", p.pos)
+	}
+	return sb.String(), nil
+}
+
+func (p *queryParser) skipSpace() {
+	for p.pos < len(p.input) && unicode.IsSpace(p.input[p.pos]) {
+		p.pos++
+	}
+}
+
+// consume 跳过空白后如果是指定的符号则读取
+func (p *queryParser) consume(token string) bool {
+	p.skipSpace()
+	t := []rune(token)
+	if p.pos+len(t) > len(p.input) || string(p.input[p.pos:p.pos+len(t)]) != token {
+		return false
+	}
+	// 避免将!=中的!作为取反
+	if token == "!" && p.pos+1 < len(p.input) && p.input[p.pos+1] == '=' {
+		return false
+	}
+	p.pos += len(t)
+	return true
+}
+
+// CompileQuery 将统一查询语法编译为搜索引擎的查询语法，搜索引擎不支持的字段返回错误
+func CompileQuery(engine Engine, query string) (string, error) {
+	node, err := ParseQuery(query)
+	if err != nil {
+		return "", err
+	}
+	return compileQueryNode(engine, engine.GetSyntaxMap(), node, false)
+}
+
+func compileQueryNode(engine Engine, syntax map[SyntaxType]string, node QueryNode, isChild bool) (string, error) {
+	switch n := node.(type) {
+	case *QueryCondition:
+		field := queryFieldMap[n.Field]
+		if s, ok := syntax[field]; !ok || s == "" || s == syntaxNotSupport {
+			return "", fmt.Errorf("field %s not supported", n.Field)
+		}
+		if field == After && n.Not {
+			return "", errors.New("field after not support negation")
+		}
+		condition := Equal
+		if n.Not {
+			condition = Not
+		}
+		return engine.MakeSearchSyntax(syntax, condition, field, strings.ReplaceAll(n.Value, `"`, `\"`)), nil
+	case *QueryLogic:
+		op := syntax[And]
+		if n.IsOr {
+			op = syntax[Or]
+		}
+		var parts []string
+		for _, child := range n.Children {
+			s, err := compileQueryNode(engine, syntax, child, true)
+			if err != nil {
+				return "", err
+			}
+			parts = append(parts, s)
+		}
+		s := strings.Join(parts, joinSyntax(op))
+		if isChild {
+			s = fmt.Sprintf("(%s)", s)
+		}
+		return s, nil
+	}
+	return "", errors.New("invalid query")
+}
+
+// joinSyntax 连接多个条件的分隔符
+func joinSyntax(op string) string {
+	if op == "" {
+		return " "
+	}
+	return fmt.Sprintf(" %s ", op)
+}
@@ -0,0 +1,81 @@
+package onlineapi
+
+import "testing"
+
+func TestParseQuery(t *testing.T) {
+	node, err := ParseQuery(`title="后台 管理" && (port=8080 || port="8443") && !body="te\"st"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	logic, ok := node.(*QueryLogic)
+	if !ok || logic.IsOr || len(logic.Children) != 3 {
+		t.Fatalf("unexpected node:%#v", node)
+	}
+	if c := logic.Children[0].(*QueryCondition); c.Field != "title" || c.Value != "后台 管理" || c.Not {
+		t.Errorf("unexpected condition:%#v", c)
+	}
+	if l := logic.Children[1].(*QueryLogic); !l.IsOr || len(l.Children) != 2 {
+		t.Errorf("unexpected logic:%#v", l)
+	}
+	if c := logic.Children[2].(*QueryCondition); c.Field != "body" || c.Value != `te"st` || !c.Not {
+		t.Errorf("unexpected condition:%#v", c)
+	}
+
+	for _, q := range []string{``, `title`, `title="abc`, `unknown="a"`, `(title="a"`, `title="a" port="80"`} {
+		if _, err = ParseQuery(q); err == nil {
+			t.Errorf("query %q should be invalid", q)
+		}
+	}
+}
+
+func TestCompileQuery(t *testing.T) {
+	query := `title="后台" && !(port="80" || body="test")`
+	tests := []struct {
+		engine Engine
+		want   string
+	}{
+		{new(FOFA), `title="后台" && (port!="80" && body!="test")`},
+		{new(Hunter), `web.title="后台" and (ip.port!="80" and web.body!="test")`},
+		{new(Quake), `title:"后台" AND (NOT port:"80" AND NOT body:"test")`},
+		{new(Shodan), `http.title:"后台" (-port:"80" -http.html:"test")`},
+		{new(Censys), `services.http.response.html_title:"后台" and (not services.port:"80" and not services.http.response.body:"test")`},
+		{new(ZoomEye), `title:"后台" + (-port:"80" + -banner:"test")`},
+		{new(Netlas), `http.title:"后台" AND (NOT port:"80" AND NOT http.body:"test")`},
+	}
+	for _, tt := range tests {
+		got, err := CompileQuery(tt.engine, query)
+		if err != nil {
+			t.Errorf("%T:%v", tt.engine, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%T:got %s, want %s", tt.engine, got, tt.want)
+		}
+	}
+}
+
+func TestCompileQueryNotSupport(t *testing.T) {
+	tests := []struct {
+		engine Engine
+		query  string
+	}{
+		{new(Hunter), `icon_hash="-247388890"`},
+		{new(Hunter), `after="2023-01-01"`},
+		{new(Quake), `title="a" || icon_hash="-247388890"`},
+		{new(Netlas), `org="example"`},
+		{new(ZeroZone), `title="a"`},
+		{new(FOFA), `!after="2023-01-01"`},
+	}
+	for _, tt := range tests {
+		if got, err := CompileQuery(tt.engine, tt.query); err == nil {
+			t.Errorf("%T:%s should not be supported, got %s", tt.engine, tt.query, got)
+		}
+	}
+
+	if got, _ := CompileQuery(new(Shodan), `after="2023-01-31"`); got != `after:"31/01/2023"` {
+		t.Errorf("shodan after:%s", got)
+	}
+	if got, _ := CompileQuery(new(Censys), `after="2023-01-31"`); got != `last_updated_at:[2023-01-31 to *]` {
+		t.Errorf("censys after:%s", got)
+	}
+}
@@ -0,0 +1,447 @@
+package onlineapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/hanc00l/nemo_go/pkg/utils"
+	"io"
+	"math/rand"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// quotaUnknown 未知的剩余额度
+	quotaUnknown = -1
+	// keyMaxErrorCount key连续出错的次数达到后不再使用
+	keyMaxErrorCount = 3
+	// quotaRefreshInterval 剩余额度的刷新间隔
+	quotaRefreshInterval = 10 * time.Minute
+	// keyDisabledResetInterval 没有额度查询接口的API，key不可用超过该时间后重新启用
+	keyDisabledResetInterval = time.Hour
+)
+
+// keyErrorKind 查询出错时key的错误类型
+type keyErrorKind int
+
+const (
+	// keyErrorUnknown 临时的错误（如网络错误、限速、5xx及返回内容解析失败），连续出错达到次数后暂停使用
+	keyErrorUnknown keyErrorKind = iota
+	// keyErrorInvalid key无效或没有权限
+	keyErrorInvalid
+	// keyErrorExhausted 额度用完
+	keyErrorExhausted
+)
+
+// APIKeyError 根据API返回的HTTP状态码或错误码判断的key错误
+type APIKeyError struct {
+	Kind    keyErrorKind
+	Message string
+}
+
+func (e *APIKeyError) Error() string {
+	return e.Message
+}
+
+// QuotaEngine 支持通过账号接口查询剩余额度的搜索引擎
+type QuotaEngine interface {
+	GetQuota(apiKey string, isProxy bool) (remaining int, err error)
+}
+
+// APIKeyStatus 一个API key的使用情况
+type APIKeyStatus struct {
+	API        string    `json:"api"`
+	Key        string    `json:"key"`
+	Remaining  int       `json:"remaining"`
+	Used       int       `json:"used"`
+	ErrorCount int       `json:"errorCount"`
+	LastError  string    `json:"lastError"`
+	Exhausted  bool      `json:"exhausted"`
+	Invalid    bool      `json:"invalid"`
+	UpdateTime time.Time `json:"updateTime"`
+	// DisabledTime key被标记为无效或额度用完的时间
+	DisabledTime time.Time `json:"disabledTime"`
+	// ErrorTime 最近一次查询出错的时间
+	ErrorTime time.Time `json:"errorTime"`
+}
+
+var (
+	apiKeyStatusMutex sync.Mutex
+	apiKeyStatusMap   = make(map[string]*APIKeyStatus)
+	// perRecordPointAPI 按返回的记录数消耗额度的API，其它的API按查询的页数消耗额度
+	perRecordPointAPI = map[string]bool{"fofa": true, "hunter": true, "quake": true, "zoomeye": true}
+	// workerAPIKeyStatusMap server保存的各worker上报的key使用情况：worker -> api|脱敏的key -> 使用情况
+	workerAPIKeyStatusMutex sync.Mutex
+	workerAPIKeyStatusMap   = make(map[string]map[string]APIKeyStatus)
+)
+
+// newAPIKeyError 创建key错误
+func newAPIKeyError(kind keyErrorKind, format string, args ...interface{}) error {
+	return &APIKeyError{Kind: kind, Message: fmt.Sprintf(format, args...)}
+}
+
+// httpStatusKeyError 根据HTTP状态码判断key错误：401、403为key无效，402为额度用完，其它（如429限速、5xx）为临时错误
+func httpStatusKeyError(statusCode int, format string, args ...interface{}) error {
+	kind := keyErrorUnknown
+	switch statusCode {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		kind = keyErrorInvalid
+	case http.StatusPaymentRequired:
+		kind = keyErrorExhausted
+	}
+	return newAPIKeyError(kind, format, args...)
+}
+
+// hasQuotaAPI API是否有查询额度的账号接口
+func hasQuotaAPI(apiName string) bool {
+	_, ok := newSearchEngine(apiName).(QuotaEngine)
+	return ok
+}
+
+// disable 标记key为无效或额度用完，记录不可用的时间
+func (status *APIKeyStatus) disable(kind keyErrorKind) {
+	if kind == keyErrorExhausted {
+		status.Exhausted = true
+	} else {
+		status.Invalid = true
+	}
+	if status.DisabledTime.IsZero() {
+		status.DisabledTime = time.Now()
+	}
+}
+
+// isDisabled key是否不可用；没有额度查询接口的API无法通过刷新额度恢复，不可用超过一定时间后重新启用
+func (status *APIKeyStatus) isDisabled() bool {
+	if !status.Invalid && !status.Exhausted {
+		return false
+	}
+	if !hasQuotaAPI(status.API) && time.Since(status.DisabledTime) > keyDisabledResetInterval {
+		status.Invalid = false
+		status.Exhausted = false
+		status.ErrorCount = 0
+		status.Remaining = quotaUnknown
+		status.DisabledTime = time.Time{}
+		return false
+	}
+	return true
+}
+
+// getAPIKeyStatus 获取key的使用情况，调用者需持有锁
+func getAPIKeyStatus(apiName, apiKey string) *APIKeyStatus {
+	k := fmt.Sprintf("%s|%s", apiName, apiKey)
+	status, ok := apiKeyStatusMap[k]
+	if !ok {
+		status = &APIKeyStatus{API: apiName, Key: desensitizationKey(apiKey), Remaining: quotaUnknown}
+		apiKeyStatusMap[k] = status
+	}
+	return status
+}
+
+// updateAPIKeyRemaining 更新key的剩余额度
+func updateAPIKeyRemaining(apiName, apiKey string, remaining int) {
+	apiKeyStatusMutex.Lock()
+	defer apiKeyStatusMutex.Unlock()
+
+	status := getAPIKeyStatus(apiName, apiKey)
+	status.Remaining = remaining
+	status.UpdateTime = time.Now()
+	if remaining <= 0 {
+		status.disable(keyErrorExhausted)
+		return
+	}
+	status.Exhausted = false
+	if !status.Invalid {
+		status.DisabledTime = time.Time{}
+	}
+}
+
+// updateAPIKeyUsage 记录一次成功的查询消耗的额度
+func updateAPIKeyUsage(apiName, apiKey string, points int) {
+	apiKeyStatusMutex.Lock()
+	defer apiKeyStatusMutex.Unlock()
+
+	status := getAPIKeyStatus(apiName, apiKey)
+	status.Used += points
+	status.ErrorCount = 0
+	if status.Remaining != quotaUnknown {
+		if status.Remaining -= points; status.Remaining <= 0 {
+			status.Remaining = 0
+			status.disable(keyErrorExhausted)
+		}
+	}
+}
+
+// updateAPIKeyError 记录key的查询错误，根据API返回的状态码或错误码、或连续出错的次数判断key是否可用
+func updateAPIKeyError(apiName, apiKey string, err error) {
+	apiKeyStatusMutex.Lock()
+	defer apiKeyStatusMutex.Unlock()
+
+	status := getAPIKeyStatus(apiName, apiKey)
+	status.ErrorCount++
+	status.LastError = err.Error()
+	status.ErrorTime = time.Now()
+	var keyErr *APIKeyError
+	if errors.As(err, &keyErr) && keyErr.Kind != keyErrorUnknown {
+		status.disable(keyErr.Kind)
+		return
+	}
+	if status.ErrorCount >= keyMaxErrorCount {
+		status.disable(keyErrorInvalid)
+	}
+}
+
+// refreshAPIKeyQuota 通过账号接口刷新key的剩余额度，force为false时只在超过刷新间隔后刷新
+func refreshAPIKeyQuota(apiName, apiKey string, engine Engine, isProxy, force bool) {
+	quotaEngine, ok := engine.(QuotaEngine)
+	if !ok {
+		return
+	}
+	apiKeyStatusMutex.Lock()
+	status := getAPIKeyStatus(apiName, apiKey)
+	expired := force || time.Since(status.UpdateTime) > quotaRefreshInterval
+	apiKeyStatusMutex.Unlock()
+	if !expired {
+		return
+	}
+	remaining, err := quotaEngine.GetQuota(apiKey, isProxy)
+	if err != nil {
+		// 查询额度失败时不影响key的使用，由查询的结果判断key是否可用
+		apiKeyStatusMutex.Lock()
+		status.LastError = err.Error()
+		status.UpdateTime = time.Now()
+		apiKeyStatusMutex.Unlock()
+		return
+	}
+	updateAPIKeyRemaining(apiName, apiKey, remaining)
+	// 额度恢复（如按月重置）后key可以重新使用
+	apiKeyStatusMutex.Lock()
+	status.Invalid = false
+	status.ErrorCount = 0
+	status.LastError = ""
+	if !status.Exhausted {
+		status.DisabledTime = time.Time{}
+	}
+	apiKeyStatusMutex.Unlock()
+}
+
+// isAPIKeyAvailable key是否可以继续使用
+func isAPIKeyAvailable(apiName, apiKey string) bool {
+	apiKeyStatusMutex.Lock()
+	defer apiKeyStatusMutex.Unlock()
+
+	return !getAPIKeyStatus(apiName, apiKey).isDisabled()
+}
+
+// selectAPIKey 选择一个可用的key：跳过额度用完或无效的key，优先使用剩余额度最多的key，未知额度的key随机选择
+func selectAPIKey(apiName string, keys []string, exclude string) string {
+	apiKeyStatusMutex.Lock()
+	defer apiKeyStatusMutex.Unlock()
+
+	var known, unknown []string
+	remaining := make(map[string]int)
+	for _, key := range keys {
+		if key == "" || key == exclude {
+			continue
+		}
+		status := getAPIKeyStatus(apiName, key)
+		if status.isDisabled() {
+			continue
+		}
+		if status.Remaining == quotaUnknown {
+			unknown = append(unknown, key)
+		} else {
+			known = append(known, key)
+			remaining[key] = status.Remaining
+		}
+	}
+	if len(known) > 0 {
+		sort.SliceStable(known, func(i, j int) bool { return remaining[known[i]] > remaining[known[j]] })
+		return known[0]
+	}
+	if len(unknown) > 0 {
+		r := rand.New(rand.NewSource(time.Now().UnixNano()))
+		return unknown[r.Intn(len(unknown))]
+	}
+	// 没有其它可用的key时继续使用当前的key
+	if exclude != "" {
+		if !getAPIKeyStatus(apiName, exclude).isDisabled() {
+			return exclude
+		}
+	}
+	return ""
+}
+
+// getRemainingQuota 获取所有可用key的剩余额度之和，存在未知额度的key时返回quotaUnknown
+func getRemainingQuota(apiName string, keys []string) int {
+	apiKeyStatusMutex.Lock()
+	defer apiKeyStatusMutex.Unlock()
+
+	var total int
+	for _, key := range keys {
+		status := getAPIKeyStatus(apiName, key)
+		if status.isDisabled() {
+			continue
+		}
+		if status.Remaining == quotaUnknown {
+			return quotaUnknown
+		}
+		total += status.Remaining
+	}
+	return total
+}
+
+// GetAPIKeyStatus 查询API所有key的剩余额度及使用情况
+func GetAPIKeyStatus(apiName, apiKey string, isProxy bool) (result []APIKeyStatus) {
+	engine := newSearchEngine(apiName)
+	if engine == nil {
+		return
+	}
+	for _, key := range strings.Split(apiKey, ",") {
+		if key = strings.TrimSpace(key); key == "" {
+			continue
+		}
+		refreshAPIKeyQuota(apiName, key, engine, isProxy, true)
+		apiKeyStatusMutex.Lock()
+		status := *getAPIKeyStatus(apiName, key)
+		apiKeyStatusMutex.Unlock()
+		// 查询的使用量及错误由worker上报
+		if reported, ok := getWorkerAPIKeyStatus(apiName, status.Key); ok {
+			status.Used = reported.Used
+			status.ErrorCount = reported.ErrorCount
+			status.Invalid = status.Invalid || reported.Invalid
+			status.Exhausted = status.Exhausted || reported.Exhausted
+			if len(reported.LastError) > 0 && reported.ErrorTime.After(status.ErrorTime) {
+				status.LastError = reported.LastError
+				status.ErrorTime = reported.ErrorTime
+			}
+			if status.Remaining == quotaUnknown {
+				status.Remaining = reported.Remaining
+			}
+		}
+		result = append(result, status)
+	}
+	return
+}
+
+// GetAPIKeyStatusReport 获取worker所有key的使用情况，用于上报到server
+func GetAPIKeyStatusReport() (result []APIKeyStatus) {
+	apiKeyStatusMutex.Lock()
+	defer apiKeyStatusMutex.Unlock()
+
+	for _, status := range apiKeyStatusMap {
+		result = append(result, *status)
+	}
+	return
+}
+
+// SaveWorkerAPIKeyStatus server保存worker上报的key使用情况，上报的使用量为worker启动后的累计值
+func SaveWorkerAPIKeyStatus(workerName string, status []APIKeyStatus) {
+	workerAPIKeyStatusMutex.Lock()
+	defer workerAPIKeyStatusMutex.Unlock()
+
+	statusMap, ok := workerAPIKeyStatusMap[workerName]
+	if !ok {
+		statusMap = make(map[string]APIKeyStatus)
+		workerAPIKeyStatusMap[workerName] = statusMap
+	}
+	for _, st := range status {
+		statusMap[fmt.Sprintf("%s|%s", st.API, st.Key)] = st
+	}
+}
+
+// getWorkerAPIKeyStatus 合并各worker上报的一个key的使用情况：使用量及错误次数累加，错误信息及剩余额度取最近的
+func getWorkerAPIKeyStatus(apiName, desensitizedKey string) (merged APIKeyStatus, ok bool) {
+	workerAPIKeyStatusMutex.Lock()
+	defer workerAPIKeyStatusMutex.Unlock()
+
+	k := fmt.Sprintf("%s|%s", apiName, desensitizedKey)
+	merged.Remaining = quotaUnknown
+	for _, statusMap := range workerAPIKeyStatusMap {
+		st, exist := statusMap[k]
+		if !exist {
+			continue
+		}
+		ok = true
+		merged.Used += st.Used
+		merged.ErrorCount += st.ErrorCount
+		merged.Invalid = merged.Invalid || st.Invalid
+		merged.Exhausted = merged.Exhausted || st.Exhausted
+		if len(st.LastError) > 0 && st.ErrorTime.After(merged.ErrorTime) {
+			merged.LastError = st.LastError
+			merged.ErrorTime = st.ErrorTime
+		}
+		if st.Remaining != quotaUnknown && !st.UpdateTime.Before(merged.UpdateTime) {
+			merged.Remaining = st.Remaining
+			merged.UpdateTime = st.UpdateTime
+		}
+	}
+	return
+}
+
+// doQuotaRequest 请求账号额度的接口并解析返回的数据
+func doQuotaRequest(request *http.Request, isProxy bool, v interface{}) (err error) {
+	resp, err := utils.GetProxyHttpClient(isProxy).Do(request)
+	if err != nil {
+		return
+	}
+	content, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return
+	}
+	if err = json.Unmarshal(content, v); err != nil && resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("status code:%d", resp.StatusCode)
+	}
+	return
+}
+
+// pointCost 计算查询消耗的额度：按记录数计费的API为记录数，其它API每页消耗1个额度
+func pointCost(apiName string, records, pages int) int {
+	if perRecordPointAPI[apiName] {
+		return records
+	}
+	return pages
+}
+
+// parseQuotaNumber 从包含数字的额度信息中解析数量，如：剩余积分：49688
+func parseQuotaNumber(s string) (int, bool) {
+	var sb strings.Builder
+	for _, ch := range s {
+		if ch >= '0' && ch <= '9' {
+			sb.WriteRune(ch)
+		} else if sb.Len() > 0 {
+			break
+		}
+	}
+	n, err := strconv.Atoi(sb.String())
+	return n, err == nil
+}
+
+// desensitizationKey 脱敏APIKey，格式为xxxx****xxxx或者xxxx****
+func desensitizationKey(key string) string {
+	l := len(key)
+	if l == 0 {
+		return ""
+	}
+	if l >= 4 {
+		keyPre := key[:4]
+		if l >= 8 {
+			var keyMid, keyEnd string
+			if l >= 12 {
+				keyMid = "****"
+				keyEnd = key[l-4:]
+			} else {
+				keyMid = ""
+				keyEnd = "****"
+			}
+			return fmt.Sprintf("%s%s%s", keyPre, keyMid, keyEnd)
+		}
+		return fmt.Sprintf("%s****", keyPre)
+	}
+	return "****"
+}
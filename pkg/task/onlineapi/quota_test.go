@@ -0,0 +1,169 @@
+package onlineapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSelectAPIKey(t *testing.T) {
+	api := "test-select"
+	keys := []string{"key-a", "key-b", "key-c"}
+	updateAPIKeyRemaining(api, "key-a", 10)
+	updateAPIKeyRemaining(api, "key-b", 100)
+	if key := selectAPIKey(api, keys, ""); key != "key-b" {
+		t.Errorf("select key:%s, want key-b", key)
+	}
+	// 额度用完后跳过
+	updateAPIKeyUsage(api, "key-b", 100)
+	if key := selectAPIKey(api, keys, ""); key != "key-a" {
+		t.Errorf("select key:%s, want key-a", key)
+	}
+	// 临时的错误（如返回内容解析失败）不影响key的使用
+	updateAPIKeyError(api, "key-a", errors.New("invalid character '<' looking for beginning of value"))
+	if key := selectAPIKey(api, keys, ""); key != "key-a" {
+		t.Errorf("select key:%s, want key-a", key)
+	}
+	// 无效的key跳过，剩余未知额度的key
+	updateAPIKeyError(api, "key-a", httpStatusKeyError(401, "401 Unauthorized"))
+	if key := selectAPIKey(api, keys, ""); key != "key-c" {
+		t.Errorf("select key:%s, want key-c", key)
+	}
+	// 连续出错达到次数后跳过
+	for i := 0; i < keyMaxErrorCount; i++ {
+		updateAPIKeyError(api, "key-c", errors.New("timeout"))
+	}
+	if key := selectAPIKey(api, keys, ""); key != "" {
+		t.Errorf("select key:%s, want empty", key)
+	}
+}
+
+func TestAPIKeyDisabledReset(t *testing.T) {
+	// hunter没有额度查询接口，额度用完的key超过一定时间后重新启用
+	updateAPIKeyRemaining("hunter", "reset-key", 0)
+	if isAPIKeyAvailable("hunter", "reset-key") {
+		t.Error("exhausted key should not be available")
+	}
+	apiKeyStatusMutex.Lock()
+	getAPIKeyStatus("hunter", "reset-key").DisabledTime = time.Now().Add(-keyDisabledResetInterval - time.Minute)
+	apiKeyStatusMutex.Unlock()
+	if !isAPIKeyAvailable("hunter", "reset-key") {
+		t.Error("exhausted key should be available after reset interval")
+	}
+	if remaining := getRemainingQuota("hunter", []string{"reset-key"}); remaining != quotaUnknown {
+		t.Errorf("remaining:%d, want unknown", remaining)
+	}
+	// 有额度查询接口的key由刷新额度恢复
+	updateAPIKeyRemaining("shodan", "reset-key", 0)
+	apiKeyStatusMutex.Lock()
+	getAPIKeyStatus("shodan", "reset-key").DisabledTime = time.Now().Add(-keyDisabledResetInterval - time.Minute)
+	apiKeyStatusMutex.Unlock()
+	if isAPIKeyAvailable("shodan", "reset-key") {
+		t.Error("exhausted shodan key should not be reset by time")
+	}
+}
+
+func TestWorkerAPIKeyStatus(t *testing.T) {
+	key := "netlas-report-key"
+	now := time.Now()
+	SaveWorkerAPIKeyStatus("worker-a", []APIKeyStatus{{API: "netlas", Key: desensitizationKey(key), Remaining: quotaUnknown, Used: 3}})
+	SaveWorkerAPIKeyStatus("worker-b", []APIKeyStatus{{API: "netlas", Key: desensitizationKey(key), Remaining: quotaUnknown, Used: 5, ErrorCount: 1, LastError: "timeout", ErrorTime: now}})
+	// 同一个worker再次上报时覆盖之前的累计值
+	SaveWorkerAPIKeyStatus("worker-a", []APIKeyStatus{{API: "netlas", Key: desensitizationKey(key), Remaining: quotaUnknown, Used: 4}})
+
+	status := GetAPIKeyStatus("netlas", key, false)
+	if len(status) != 1 || status[0].Used != 9 || status[0].ErrorCount != 1 || status[0].LastError != "timeout" {
+		t.Errorf("key status:%+v", status)
+	}
+}
+
+func TestFofaKeyError(t *testing.T) {
+	datas := map[string]keyErrorKind{
+		"[-700] Account Invalid":  keyErrorInvalid,
+		"[820031] F点余额不足":         keyErrorExhausted,
+		"[-9] query syntax error": keyErrorUnknown,
+		"unknown error":           keyErrorUnknown,
+	}
+	for msg, kind := range datas {
+		if err := fofaKeyError(msg).(*APIKeyError); err.Kind != kind {
+			t.Errorf("%s: got %d, want %d", msg, err.Kind, kind)
+		}
+	}
+}
+
+func TestParseQuotaNumber(t *testing.T) {
+	if n, ok := parseQuotaNumber("剩余积分：49688"); !ok || n != 49688 {
+		t.Errorf("parse quota:%d %v", n, ok)
+	}
+	if _, ok := parseQuotaNumber(""); ok {
+		t.Error("empty quota should fail")
+	}
+}
+
+func TestOnlineSearch_PointBudget(t *testing.T) {
+	var requests int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api-info" {
+			json.NewEncoder(w).Encode(map[string]interface{}{"query_credits": 1000})
+			return
+		}
+		requests++
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"total":   500,
+			"matches": []map[string]interface{}{{"ip_str": "192.0.2.1", "port": 80}},
+		})
+	}))
+	defer ts.Close()
+	shodanAPIUrl = ts.URL
+
+	s := newTestOnlineSearch("shodan", new(Shodan), "budget-key", shodanPageSize)
+	s.Config.SearchPointBudget = 3
+	s.Query("example.com", nil)
+	// 共5页，第一页后预计超过预算，剩余的页不再查询
+	if requests != 1 || s.pointUsed != 1 {
+		t.Errorf("requests:%d, point used:%d", requests, s.pointUsed)
+	}
+	status := GetAPIKeyStatus("shodan", "budget-key", false)
+	if len(status) != 1 || status[0].Remaining != 1000 {
+		t.Errorf("key status:%+v", status)
+	}
+
+	s.Config.SearchPointBudget = 10
+	s.Query("example.com", nil)
+	if requests != 6 || s.pointUsed != 6 {
+		t.Errorf("requests:%d, point used:%d", requests, s.pointUsed)
+	}
+}
+
+// pageSizeEngine 记录每次查询的页大小，每页返回请求的记录数
+type pageSizeEngine struct {
+	FOFA
+	pageSizes []int
+}
+
+func (e *pageSizeEngine) Run(query string, apiKey string, pageIndex int, pageSize int, config OnlineAPIConfig) (pageResult []onlineSearchResult, sizeTotal int, err error) {
+	e.pageSizes = append(e.pageSizes, pageSize)
+	for i := 0; i < pageSize; i++ {
+		pageResult = append(pageResult, onlineSearchResult{IP: "192.0.2.1", Port: "80"})
+	}
+	return pageResult, 500, nil
+}
+
+func TestOnlineSearch_PerRecordPointBudget(t *testing.T) {
+	engine := &pageSizeEngine{}
+	s := newTestOnlineSearch("fofa", engine, "budget@example.com:fofa-budget-key", 100)
+	s.Config.SearchPointBudget = 30
+	s.Query("example.com", nil)
+	// 预算不足一页时只查询预算内的记录数，剩余的页不再查询
+	if len(engine.pageSizes) != 1 || engine.pageSizes[0] != 30 || s.pointUsed != 30 {
+		t.Errorf("page sizes:%v, point used:%d", engine.pageSizes, s.pointUsed)
+	}
+	// 预算用完后不再查询
+	s.Query("example.org", nil)
+	if len(engine.pageSizes) != 1 {
+		t.Errorf("page sizes:%v", engine.pageSizes)
+	}
+}
@@ -1,6 +1,9 @@
 package onlineapi
 
 import (
+	"bufio"
+	"bytes"
+	"encoding/json"
 	"github.com/hanc00l/nemo_go/pkg/logging"
 	"github.com/hanc00l/nemo_go/pkg/task/custom"
 	"github.com/hanc00l/nemo_go/pkg/task/domainscan"
@@ -22,9 +25,11 @@ type OnlineAPIConfig struct {
 	IsIgnoreCDN        bool   `json:"ignorecdn"`
 	IsIgnoreOutofChina bool   `json:"ignoreoutofchina"`
 	SearchByKeyWord    bool   `json:"keywordsearch"`
+	KeyWordId          int    `json:"keywordid"`
 	SearchStartTime    string `json:"searchstarttime"`
 	SearchLimitCount   int    `json:"searchlimitcount"`
 	SearchPageSize     int    `json:"searchpagesize"`
+	SearchPointBudget  int    `json:"searchpointbudget"`
 	WorkspaceId        int    `json:"workspaceId"`
 	IsProxy            bool   `json:"proxy"`
 }
@@ -167,3 +172,47 @@ func parseDomainIP(domainResult *domainscan.Result, fsr onlineSearchResult, sour
 		})
 	}
 }
+
+// parseJSONContent 解析导出的JSON文件内容，支持JSON数组及每行一个JSON对象两种格式
+func parseJSONContent(content []byte, f func(data json.RawMessage)) {
+	content = bytes.TrimSpace(content)
+	if bytes.HasPrefix(content, []byte("[")) {
+		var rows []json.RawMessage
+		if err := json.Unmarshal(content, &rows); err != nil {
+			logging.RuntimeLog.Error(err)
+			return
+		}
+		for _, row := range rows {
+			f(row)
+		}
+		return
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), len(content)+1)
+	for scanner.Scan() {
+		if line := bytes.TrimSpace(scanner.Bytes()); len(line) > 0 {
+			f(json.RawMessage(line))
+		}
+	}
+}
+
+// makeContentResult 将导入的搜索结果转换为IP和域名结果
+func makeContentResult(results []onlineSearchResult, source string) (ipResult portscan.Result, domainResult domainscan.Result) {
+	ipResult.IPResult = make(map[string]*portscan.IPResult)
+	domainResult.DomainResult = make(map[string]*domainscan.DomainResult)
+	btc := custom.NewBlackTargetCheck(custom.CheckAll)
+	for _, fsr := range results {
+		parseIpPort(&ipResult, fsr, source, btc)
+		parseDomainIP(&domainResult, fsr, source, btc)
+	}
+	return
+}
+
+// IsOnlineAPISource 检查资产属性的来源是否为在线资产搜索引擎
+func IsOnlineAPISource(source string) bool {
+	switch source {
+	case "fofa", "hunter", "quake", "0zone", "shodan", "censys", "zoomeye", "netlas":
+		return true
+	}
+	return false
+}
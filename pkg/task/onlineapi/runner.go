@@ -9,7 +9,6 @@ import (
 	"github.com/hanc00l/nemo_go/pkg/task/custom"
 	"github.com/hanc00l/nemo_go/pkg/task/domainscan"
 	"github.com/hanc00l/nemo_go/pkg/task/portscan"
-	"math/rand"
 	"os"
 	"path/filepath"
 	"strings"
@@ -21,6 +20,16 @@ type OnlineSearch struct {
 	apiKey       string
 	apiKeyInUse  string
 	searchEngine Engine
+	// pointUsed 本次任务已消耗的额度
+	pointUsed int
+	// cacheTTL 查询结果缓存的有效时间（小时），0表示不使用缓存
+	cacheTTL int
+	//Cache 查询结果的缓存
+	Cache SearchCache
+	//CachedPageNum 使用缓存的页数
+	CachedPageNum int
+	//CachedPointSaved 使用缓存节省的额度
+	CachedPointSaved int
 	//Config 配置参数：查询的目标、关联的组织
 	Config OnlineAPIConfig
 	//Result quake api查询后的结果
@@ -32,28 +41,71 @@ type OnlineSearch struct {
 }
 
 func NewOnlineAPISearch(config OnlineAPIConfig, apiName string) *OnlineSearch {
-	s := &OnlineSearch{Config: config, apiName: apiName}
+	s := &OnlineSearch{Config: config, apiName: apiName, searchEngine: newSearchEngine(apiName)}
 	switch apiName {
 	case "fofa":
-		s.searchEngine = new(FOFA)
 		s.apiKey = conf.GlobalWorkerConfig().API.Fofa.Key
 	case "hunter":
-		s.searchEngine = new(Hunter)
 		s.apiKey = conf.GlobalWorkerConfig().API.Hunter.Key
 	case "quake":
-		s.searchEngine = new(Quake)
 		s.apiKey = conf.GlobalWorkerConfig().API.Quake.Key
-	case "0zone":
-		s.searchEngine = new(ZeroZone)
+	case "shodan":
+		s.apiKey = conf.GlobalWorkerConfig().API.Shodan.Key
+	case "censys":
+		s.apiKey = conf.GlobalWorkerConfig().API.Censys.Key
+	case "zoomeye":
+		s.apiKey = conf.GlobalWorkerConfig().API.ZoomEye.Key
+	case "netlas":
+		s.apiKey = conf.GlobalWorkerConfig().API.Netlas.Key
 	}
 	s.Config.SearchLimitCount = conf.GlobalWorkerConfig().API.SearchLimitCount
+	s.cacheTTL = conf.GlobalWorkerConfig().API.CacheTTL
+	if s.Config.SearchPointBudget <= 0 {
+		s.Config.SearchPointBudget = conf.GlobalWorkerConfig().API.SearchPointBudget
+	}
 	if s.Config.SearchPageSize = conf.GlobalWorkerConfig().API.SearchPageSize; s.Config.SearchPageSize <= 0 {
 		s.Config.SearchPageSize = pageSizeDefault
 	}
+	// 部份API每页返回的数量是固定的，按实际的数量计算分页
+	switch apiName {
+	case "shodan":
+		s.Config.SearchPageSize = shodanPageSize
+	case "zoomeye":
+		s.Config.SearchPageSize = zoomEyePageSize
+	case "netlas":
+		s.Config.SearchPageSize = netlasPageSize
+	case "censys":
+		if s.Config.SearchPageSize > censysPageSizeMax {
+			s.Config.SearchPageSize = censysPageSizeMax
+		}
+	}
 
 	return s
 }
 
+// newSearchEngine 根据API名称创建搜索引擎
+func newSearchEngine(apiName string) Engine {
+	switch apiName {
+	case "fofa":
+		return new(FOFA)
+	case "hunter":
+		return new(Hunter)
+	case "quake":
+		return new(Quake)
+	case "0zone":
+		return new(ZeroZone)
+	case "shodan":
+		return new(Shodan)
+	case "censys":
+		return new(Censys)
+	case "zoomeye":
+		return new(ZoomEye)
+	case "netlas":
+		return new(Netlas)
+	}
+	return nil
+}
+
 // Do 执行查询
 func (s *OnlineSearch) Do() {
 	if s.searchEngine == nil {
@@ -88,7 +140,20 @@ func (s *OnlineSearch) Do() {
 // Query 查询一个domain
 func (s *OnlineSearch) Query(domain string, filterKeyword map[string]struct{}) {
 	query := s.searchEngine.GetQueryString(domain, s.Config, filterKeyword)
-	pageResult, sizeTotal, err := s.retriedQuery(query, 1, s.Config.SearchPageSize)
+	cached := s.loadCache(query, 1)
+	pageSize := s.Config.SearchPageSize
+	if cached == nil {
+		pageSize = s.firstPageSize()
+		if err := s.checkPointBudget(pointCost(s.apiName, pageSize, 1)); err != nil || pageSize <= 0 {
+			if err == nil {
+				err = fmt.Errorf("no point left,used:%d,budget:%d", s.pointUsed, s.Config.SearchPointBudget)
+			}
+			logging.RuntimeLog.Warningf("%s search %s refused:%v", s.apiName, domain, err)
+			logging.CLILog.Warningf("%s search %s refused:%v", s.apiName, domain, err)
+			return
+		}
+	}
+	pageResult, sizeTotal, err := s.cachedQuery(query, 1, pageSize, cached)
 	if err != nil {
 		logging.RuntimeLog.Error(err)
 		logging.CLILog.Error(err)
@@ -105,16 +170,92 @@ func (s *OnlineSearch) Query(domain string, filterKeyword map[string]struct{}) {
 	if sizeTotal%s.Config.SearchPageSize > 0 {
 		pageTotalNum++
 	}
+	// 剩余未缓存的页数超过额度预算时不再继续查询
+	cachedPages := make(map[int]*OnlineAPICacheItem)
 	for i := 2; i <= pageTotalNum; i++ {
-		pageResult, _, err = s.retriedQuery(query, i, s.Config.SearchPageSize)
+		if item := s.loadCache(query, i); item != nil {
+			cachedPages[i] = item
+		}
+	}
+	if uncachedPageNum := pageTotalNum - 1 - len(cachedPages); uncachedPageNum > 0 {
+		records := sizeTotal - len(pageResult)
+		if records > uncachedPageNum*s.Config.SearchPageSize {
+			records = uncachedPageNum * s.Config.SearchPageSize
+		}
+		if err = s.checkPointBudget(pointCost(s.apiName, records, uncachedPageNum)); err != nil {
+			msg := fmt.Sprintf("%s search %s result total:%d, rest pages refused:%v", s.apiName, domain, sizeTotal, err)
+			logging.RuntimeLog.Warning(msg)
+			logging.CLILog.Warning(msg)
+			return
+		}
+	}
+	for i := 2; i <= pageTotalNum; i++ {
+		pageResult, _, err = s.cachedQuery(query, i, s.Config.SearchPageSize, cachedPages[i])
 		if err != nil {
 			logging.RuntimeLog.Error(err)
 			logging.CLILog.Error(err)
 			return
 		}
 		s.Result = append(s.Result, pageResult...)
-		time.Sleep(1 * time.Second)
+		if cachedPages[i] == nil {
+			time.Sleep(1 * time.Second)
+		}
+	}
+}
+
+// firstPageSize 第一页查询的记录数：按记录消耗额度的API，在额度预算、剩余额度或限制的数量不足一页时，只查询其范围内的记录数
+func (s *OnlineSearch) firstPageSize() int {
+	pageSize := s.Config.SearchPageSize
+	if !perRecordPointAPI[s.apiName] {
+		return pageSize
+	}
+	if s.Config.SearchLimitCount > 0 && s.Config.SearchLimitCount < pageSize {
+		pageSize = s.Config.SearchLimitCount
+	}
+	if s.Config.SearchPointBudget > 0 && s.Config.SearchPointBudget-s.pointUsed < pageSize {
+		pageSize = s.Config.SearchPointBudget - s.pointUsed
+	}
+	if remaining := getRemainingQuota(s.apiName, s.getAllAPIKeys()); remaining != quotaUnknown && remaining < pageSize {
+		pageSize = remaining
+	}
+	return pageSize
+}
+
+// cachedQuery 查询一页结果：已缓存的使用缓存的结果，否则调用API查询并保存到缓存；不足一页的查询结果不缓存
+func (s *OnlineSearch) cachedQuery(query string, pageIndex int, pageSize int, cached *OnlineAPICacheItem) (pageResult []onlineSearchResult, sizeTotal int, err error) {
+	if cached != nil {
+		s.CachedPageNum++
+		s.CachedPointSaved += cached.Points
+		return cached.Results, cached.SizeTotal, nil
+	}
+	if pageResult, sizeTotal, err = s.retriedQuery(query, pageIndex, pageSize); err != nil {
+		return
+	}
+	if s.isCacheEnabled() && pageSize == s.Config.SearchPageSize {
+		s.Cache.Save(MakeCacheKey(s.apiName, query, pageIndex, s.Config), &OnlineAPICacheItem{
+			Results:   pageResult,
+			SizeTotal: sizeTotal,
+			Points:    pointCost(s.apiName, len(pageResult), 1),
+			CacheTime: time.Now(),
+		})
+	}
+	return
+}
+
+// loadCache 获取一页查询结果的缓存
+func (s *OnlineSearch) loadCache(query string, pageIndex int) *OnlineAPICacheItem {
+	if !s.isCacheEnabled() {
+		return nil
 	}
+	if item, ok := s.Cache.Load(MakeCacheKey(s.apiName, query, pageIndex, s.Config), s.cacheTTL); ok {
+		return item
+	}
+	return nil
+}
+
+// isCacheEnabled 是否使用缓存，censys使用游标分页，跳过缓存的页会导致无法获取后续页的游标，因此不使用缓存
+func (s *OnlineSearch) isCacheEnabled() bool {
+	return s.Cache != nil && s.cacheTTL > 0 && s.apiName != "censys"
 }
 
 // ParseContentResult 从文件内容中导入结果
@@ -125,11 +266,11 @@ func (s *OnlineSearch) ParseContentResult(content []byte) {
 // retriedQuery 执行一次查询，允许重试N次
 func (s *OnlineSearch) retriedQuery(query string, pageIndex int, pageSize int) (pageResult []onlineSearchResult, sizeTotal int, err error) {
 	RETRIED := 3
-	allKeys := strings.Split(s.apiKey, ",")
+	allKeys := s.getAllAPIKeys()
 	if len(allKeys) > 1 {
 		RETRIED = 2 * len(allKeys)
 	}
-	if s.apiKeyInUse == "" {
+	if s.apiKeyInUse == "" || !isAPIKeyAvailable(s.apiName, s.apiKeyInUse) {
 		if s.apiKeyInUse = s.getOneAPIKey(); s.apiKeyInUse == "" {
 			return nil, 0, errors.New(fmt.Sprintf("%s no  key to available", s.apiName))
 		}
@@ -138,11 +279,15 @@ func (s *OnlineSearch) retriedQuery(query string, pageIndex int, pageSize int) (
 	for ; retriedCount < RETRIED; retriedCount++ {
 		pageResult, sizeTotal, err = s.searchEngine.Run(query, s.apiKeyInUse, pageIndex, pageSize, s.Config)
 		if err == nil {
+			points := pointCost(s.apiName, len(pageResult), 1)
+			s.pointUsed += points
+			updateAPIKeyUsage(s.apiName, s.apiKeyInUse, points)
 			return
 		}
 		msg := fmt.Sprintf("api %s with key %s has error:%v", s.apiName, s.desensitizationAPIKey(), err)
 		logging.RuntimeLog.Error(msg)
 		logging.CLILog.Error(msg)
+		updateAPIKeyError(s.apiName, s.apiKeyInUse, err)
 
 		if s.apiKeyInUse = s.getOneAPIKey(); s.apiKeyInUse == "" {
 			return nil, 0, errors.New(fmt.Sprintf("%s no  key to available", s.apiName))
@@ -156,45 +301,37 @@ func (s *OnlineSearch) retriedQuery(query string, pageIndex int, pageSize int) (
 
 // desensitizationAPIKey 脱敏APIKey，格式为xxxx****xxxx或者xxxx****
 func (s *OnlineSearch) desensitizationAPIKey() string {
-	l := len(s.apiKeyInUse)
-	if l == 0 {
-		return ""
-	}
-	if l >= 4 {
-		keyPre := s.apiKeyInUse[:4]
-		if l >= 8 {
-			var keyMid, keyEnd string
-			if l >= 12 {
-				keyMid = "****"
-				keyEnd = s.apiKeyInUse[l-4:]
-			} else {
-				keyMid = ""
-				keyEnd = "****"
-			}
-			return fmt.Sprintf("%s%s%s", keyPre, keyMid, keyEnd)
+	return desensitizationKey(s.apiKeyInUse)
+}
+
+// getAllAPIKeys 获取配置的全部key
+func (s *OnlineSearch) getAllAPIKeys() (keys []string) {
+	for _, key := range strings.Split(s.apiKey, ",") {
+		if key = strings.TrimSpace(key); key != "" {
+			keys = append(keys, key)
 		}
-		return fmt.Sprintf("%s****", keyPre)
 	}
-	return "****"
+	return
 }
 
-// getOneAPIKey 选择一个查询的key
+// getOneAPIKey 选择一个查询的key，跳过额度用完或无效的key
 func (s *OnlineSearch) getOneAPIKey() string {
-	allKeys := strings.Split(s.apiKey, ",")
-
-	if len(allKeys) == 0 {
-		return ""
+	allKeys := s.getAllAPIKeys()
+	for _, key := range allKeys {
+		refreshAPIKeyQuota(s.apiName, key, s.searchEngine, s.Config.IsProxy, false)
 	}
-	if len(allKeys) == 1 {
-		return allKeys[0]
+	return selectAPIKey(s.apiName, allKeys, s.apiKeyInUse)
+}
+
+// checkPointBudget 检查本次查询预计消耗的额度是否超过任务的额度预算及key的剩余额度
+func (s *OnlineSearch) checkPointBudget(points int) error {
+	if s.Config.SearchPointBudget > 0 && s.pointUsed+points > s.Config.SearchPointBudget {
+		return fmt.Errorf("point budget exceeded,used:%d,need:%d,budget:%d", s.pointUsed, points, s.Config.SearchPointBudget)
 	}
-	r := rand.New(rand.NewSource(time.Now().UnixNano()))
-	for {
-		n := r.Intn(len(allKeys))
-		if allKeys[n] != s.apiKeyInUse {
-			return allKeys[n]
-		}
+	if remaining := getRemainingQuota(s.apiName, s.getAllAPIKeys()); remaining != quotaUnknown && points > remaining {
+		return fmt.Errorf("remaining quota not enough,need:%d,remaining:%d", points, remaining)
 	}
+	return nil
 }
 
 // processResult 转换查询的IP和域名结果保存
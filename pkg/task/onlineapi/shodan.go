@@ -0,0 +1,223 @@
+package onlineapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/hanc00l/nemo_go/pkg/logging"
+	"github.com/hanc00l/nemo_go/pkg/task/domainscan"
+	"github.com/hanc00l/nemo_go/pkg/task/portscan"
+	"github.com/hanc00l/nemo_go/pkg/utils"
+	"gopkg.in/errgo.v2/fmt/errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// shodanAPIUrl shodan的API地址
+	shodanAPIUrl = "https://api.shodan.io"
+	// shodanPageSize shodan每页返回的数量固定为100
+	shodanPageSize = 100
+)
+
+type Shodan struct {
+}
+
+// shodanMatch shodan查询结果的一条记录，与导出文件中的记录格式相同
+type shodanMatch struct {
+	IPStr     string   `json:"ip_str"`
+	Port      int      `json:"port"`
+	Transport string   `json:"transport"`
+	Hostnames []string `json:"hostnames"`
+	Domains   []string `json:"domains"`
+	Product   string   `json:"product"`
+	Data      string   `json:"data"`
+	HTTP      *struct {
+		Title  string `json:"title"`
+		Server string `json:"server"`
+		Host   string `json:"host"`
+	} `json:"http"`
+	Location struct {
+		CountryCode string `json:"country_code"`
+		City        string `json:"city"`
+	} `json:"location"`
+}
+
+// ShodanServiceInfo 查询结果的返回数据
+type ShodanServiceInfo struct {
+	Error   string        `json:"error"`
+	Total   int           `json:"total"`
+	Matches []shodanMatch `json:"matches"`
+}
+
+func (s *Shodan) MakeSearchSyntax(syntax map[SyntaxType]string, condition SyntaxType, checkMod SyntaxType, value string) string {
+	// http.title:"百度"、-http.html:"百度"
+	if checkMod == After {
+		// shodan的日期格式为dd/mm/yyyy
+		if t, err := time.Parse("2006-01-02", value); err == nil {
+			value = t.Format("02/01/2006")
+		}
+	}
+	return fmt.Sprintf("%s%s:\"%s\"", syntax[condition], syntax[checkMod], value)
+}
+
+func (s *Shodan) GetSyntaxMap() (syntax map[SyntaxType]string) {
+	syntax = make(map[SyntaxType]string)
+	// shodan多个条件之间默认为AND
+	syntax[And] = ""
+	syntax[Or] = "OR"
+	syntax[Equal] = ""
+	syntax[Not] = "-"
+	syntax[After] = "after"
+	syntax[Title] = "http.title"
+	syntax[Body] = "http.html"
+	syntax[Cert] = "ssl"
+	syntax[IconHash] = "http.favicon.hash"
+	syntax[Domain] = "hostname"
+	syntax[IP] = "net"
+	syntax[Port] = "port"
+	syntax[Org] = "org"
+	syntax[Country] = "country"
+
+	return
+}
+
+func (s *Shodan) GetQueryString(domain string, config OnlineAPIConfig, filterKeyword map[string]struct{}) (query string) {
+	if config.SearchByKeyWord {
+		query = config.Target
+	} else {
+		if utils.CheckIPV4Subnet(domain) {
+			query = fmt.Sprintf("net:\"%s\"", domain)
+		} else if utils.CheckIPOrSubnet(domain) {
+			query = fmt.Sprintf("ip:\"%s\"", domain)
+		} else {
+			query = fmt.Sprintf("hostname:\"%s\"", domain)
+		}
+	}
+	if words := s.getFilterTitleKeyword(filterKeyword); len(words) > 0 {
+		query = fmt.Sprintf("%s %s", query, words)
+	}
+	if config.IsIgnoreOutofChina {
+		query = fmt.Sprintf("%s country:\"CN\"", query)
+	}
+	if len(config.SearchStartTime) > 0 {
+		// shodan的日期格式为dd/mm/yyyy
+		if t, err := time.Parse("2006-01-02", config.SearchStartTime); err == nil {
+			query = fmt.Sprintf("%s after:\"%s\"", query, t.Format("02/01/2006"))
+		}
+	}
+	return
+}
+
+func (s *Shodan) getFilterTitleKeyword(filterKeyword map[string]struct{}) string {
+	var words []string
+	for k := range filterKeyword {
+		words = append(words, fmt.Sprintf("-http.html:\"%s\"", k))
+	}
+
+	return strings.Join(words, " ")
+}
+
+// GetQuota 查询key剩余的query credits
+func (s *Shodan) GetQuota(apiKey string, isProxy bool) (remaining int, err error) {
+	request, err := http.NewRequest(http.MethodGet, shodanAPIUrl+"/api-info", nil)
+	if err != nil {
+		return
+	}
+	request.URL.RawQuery = url.Values{"key": {apiKey}}.Encode()
+	var info struct {
+		Error        string `json:"error"`
+		QueryCredits *int   `json:"query_credits"`
+	}
+	if err = doQuotaRequest(request, isProxy, &info); err != nil {
+		return
+	}
+	if len(info.Error) > 0 || info.QueryCredits == nil {
+		return 0, errors.Newf("Shodan API Info Error:%s", info.Error)
+	}
+	return *info.QueryCredits, nil
+}
+
+func (s *Shodan) Run(query string, apiKey string, pageIndex int, pageSize int, config OnlineAPIConfig) (pageResult []onlineSearchResult, sizeTotal int, err error) {
+	request, err := http.NewRequest(http.MethodGet, shodanAPIUrl+"/shodan/host/search", nil)
+	if err != nil {
+		return
+	}
+	params := make(url.Values)
+	params.Add("key", apiKey)
+	params.Add("query", query)
+	params.Add("page", strconv.Itoa(pageIndex))
+	request.URL.RawQuery = params.Encode()
+	resp, err := utils.GetProxyHttpClient(config.IsProxy).Do(request)
+	if err != nil {
+		return
+	}
+	content, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return
+	}
+	var serviceInfo ShodanServiceInfo
+	if err = json.Unmarshal(content, &serviceInfo); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			err = httpStatusKeyError(resp.StatusCode, "Shodan Search Error:%d", resp.StatusCode)
+		}
+		return
+	}
+	if len(serviceInfo.Error) > 0 || resp.StatusCode != http.StatusOK {
+		err = httpStatusKeyError(resp.StatusCode, "Shodan Search Error:%d %s", resp.StatusCode, serviceInfo.Error)
+		return
+	}
+	sizeTotal = serviceInfo.Total
+	for _, m := range serviceInfo.Matches {
+		pageResult = append(pageResult, m.toSearchResult()...)
+	}
+
+	return
+}
+
+// toSearchResult 转换为搜索结果，每个主机名对应一条记录
+func (m *shodanMatch) toSearchResult() (results []onlineSearchResult) {
+	fsr := onlineSearchResult{
+		IP:      m.IPStr,
+		Port:    fmt.Sprintf("%d", m.Port),
+		Country: m.Location.CountryCode,
+		City:    m.Location.City,
+		Server:  m.Product,
+	}
+	if m.HTTP != nil {
+		fsr.Title = m.HTTP.Title
+		if len(m.HTTP.Server) > 0 {
+			fsr.Server = m.HTTP.Server
+		}
+	} else if line, _, _ := strings.Cut(strings.TrimSpace(m.Data), "\n"); len(line) > 0 {
+		// 非http的服务，只保留banner的第一行
+		fsr.Banner = strings.TrimSpace(line)
+	}
+	if len(m.Hostnames) == 0 {
+		return append(results, fsr)
+	}
+	for _, host := range m.Hostnames {
+		r := fsr
+		r.Domain = host
+		r.Host = host
+		results = append(results, r)
+	}
+	return
+}
+
+func (s *Shodan) ParseContentResult(content []byte) (ipResult portscan.Result, domainResult domainscan.Result) {
+	var results []onlineSearchResult
+	parseJSONContent(content, func(data json.RawMessage) {
+		var m shodanMatch
+		if err := json.Unmarshal(data, &m); err != nil {
+			logging.RuntimeLog.Error(err)
+			return
+		}
+		results = append(results, m.toSearchResult()...)
+	})
+	return makeContentResult(results, "shodan")
+}
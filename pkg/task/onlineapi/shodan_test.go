@@ -0,0 +1,88 @@
+package onlineapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newTestOnlineSearch 创建使用本地模拟API的查询
+func newTestOnlineSearch(apiName string, engine Engine, apiKey string, pageSize int) *OnlineSearch {
+	return &OnlineSearch{
+		apiName:      apiName,
+		apiKey:       apiKey,
+		searchEngine: engine,
+		Config:       OnlineAPIConfig{SearchPageSize: pageSize},
+	}
+}
+
+func TestShodan_Run(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/shodan/host/search" || r.URL.Query().Get("key") != "test-key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error":"Invalid API key"}`))
+			return
+		}
+		page := r.URL.Query().Get("page")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"total": 150,
+			"matches": []map[string]interface{}{
+				{"ip_str": "192.0.2." + page, "port": 443, "hostnames": []string{"www.example.com"}, "http": map[string]string{"title": "Example", "server": "nginx"}},
+				{"ip_str": "192.0.2.10" + page, "port": 22, "data": "SSH-2.0-OpenSSH_8.0\r\nKey type: ssh-rsa"},
+			},
+		})
+	}))
+	defer ts.Close()
+	shodanAPIUrl = ts.URL
+
+	s := newTestOnlineSearch("shodan", new(Shodan), "test-key", shodanPageSize)
+	s.Query("example.com", nil)
+	if len(s.Result) != 4 {
+		t.Fatalf("result count:%d", len(s.Result))
+	}
+	s.processResult()
+	if _, ok := s.IpResult.IPResult["192.0.2.2"]; !ok {
+		t.Error("page 2 not queried")
+	}
+	if !s.DomainResult.HasDomain("www.example.com") {
+		t.Error("domain not parsed")
+	}
+	if banner := s.Result[1].Banner; banner != "SSH-2.0-OpenSSH_8.0" {
+		t.Errorf("banner:%s", banner)
+	}
+
+	_, _, err := new(Shodan).Run("hostname:example.com", "bad-key", 1, shodanPageSize, OnlineAPIConfig{})
+	if err == nil {
+		t.Error("invalid key should fail")
+	}
+}
+
+func TestShodan_GetQueryString(t *testing.T) {
+	s := new(Shodan)
+	query := s.GetQueryString("example.com", OnlineAPIConfig{IsIgnoreOutofChina: true, SearchStartTime: "2023-08-01"}, nil)
+	if query != `hostname:"example.com" country:"CN" after:"01/08/2023"` {
+		t.Error(query)
+	}
+	query = s.GetQueryString("192.0.2.0/24", OnlineAPIConfig{}, nil)
+	if query != `net:"192.0.2.0/24"` {
+		t.Error(query)
+	}
+	syntax := s.GetSyntaxMap()
+	if q := s.MakeSearchSyntax(syntax, Not, Body, "test"); q != `-http.html:"test"` {
+		t.Error(q)
+	}
+}
+
+func TestShodan_ParseContentResult(t *testing.T) {
+	data := `{"ip_str":"192.0.2.1","port":80,"hostnames":["a.example.com"],"http":{"title":"A"}}
+{"ip_str":"192.0.2.2","port":8080,"hostnames":[],"http":{"title":"B"}}`
+	ipResult, domainResult := new(Shodan).ParseContentResult([]byte(data))
+	if len(ipResult.IPResult) != 2 || !ipResult.HasPort("192.0.2.2", 8080) {
+		t.Errorf("ip result:%v", ipResult.IPResult)
+	}
+	if !domainResult.HasDomain("a.example.com") {
+		t.Error("domain not parsed")
+	}
+	t.Log(ipResult.IPResult["192.0.2.1"].Ports[80].PortAttrs)
+}
@@ -0,0 +1,225 @@
+package onlineapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/hanc00l/nemo_go/pkg/logging"
+	"github.com/hanc00l/nemo_go/pkg/task/domainscan"
+	"github.com/hanc00l/nemo_go/pkg/task/portscan"
+	"github.com/hanc00l/nemo_go/pkg/utils"
+	"gopkg.in/errgo.v2/fmt/errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+var (
+	// zoomEyeAPIUrl zoomeye的API地址
+	zoomEyeAPIUrl = "https://api.zoomeye.org"
+	// zoomEyePageSize zoomeye每页返回的数量固定为20
+	zoomEyePageSize = 20
+)
+
+type ZoomEye struct {
+}
+
+// zoomEyeMatch zoomeye查询结果的一条记录，与导出文件中的记录格式相同
+type zoomEyeMatch struct {
+	IP       json.RawMessage `json:"ip"`
+	Rdns     string          `json:"rdns"`
+	PortInfo struct {
+		Port     int      `json:"port"`
+		Service  string   `json:"service"`
+		App      string   `json:"app"`
+		Hostname string   `json:"hostname"`
+		Title    []string `json:"title"`
+		Banner   string   `json:"banner"`
+	} `json:"portinfo"`
+	GeoInfo struct {
+		Country struct {
+			Code string `json:"code"`
+		} `json:"country"`
+		City struct {
+			Names struct {
+				En string `json:"en"`
+			} `json:"names"`
+		} `json:"city"`
+	} `json:"geoinfo"`
+}
+
+// ZoomEyeServiceInfo 查询结果的返回数据
+type ZoomEyeServiceInfo struct {
+	Error   string         `json:"error"`
+	Message string         `json:"message"`
+	Total   int            `json:"total"`
+	Matches []zoomEyeMatch `json:"matches"`
+}
+
+func (z *ZoomEye) MakeSearchSyntax(syntax map[SyntaxType]string, condition SyntaxType, checkMod SyntaxType, value string) string {
+	// title:"百度"、-banner:"百度"
+	return fmt.Sprintf("%s%s:\"%s\"", syntax[condition], syntax[checkMod], value)
+}
+
+func (z *ZoomEye) GetSyntaxMap() (syntax map[SyntaxType]string) {
+	syntax = make(map[SyntaxType]string)
+	// zoomeye使用+表示AND，空格表示OR
+	syntax[And] = "+"
+	syntax[Or] = ""
+	syntax[Equal] = ""
+	syntax[Not] = "-"
+	syntax[After] = "after"
+	syntax[Title] = "title"
+	syntax[Body] = "banner"
+	syntax[Cert] = "ssl"
+	syntax[IconHash] = "iconhash"
+	syntax[Domain] = "hostname"
+	syntax[IP] = "ip"
+	syntax[Port] = "port"
+	syntax[Org] = "org"
+	syntax[Country] = "country"
+
+	return
+}
+
+func (z *ZoomEye) GetQueryString(domain string, config OnlineAPIConfig, filterKeyword map[string]struct{}) (query string) {
+	if config.SearchByKeyWord {
+		query = config.Target
+	} else {
+		if utils.CheckIPV4Subnet(domain) {
+			query = fmt.Sprintf("cidr:\"%s\"", domain)
+		} else if utils.CheckIPOrSubnet(domain) {
+			query = fmt.Sprintf("ip:\"%s\"", domain)
+		} else {
+			query = fmt.Sprintf("hostname:\"%s\"", domain)
+		}
+	}
+	if words := z.getFilterTitleKeyword(filterKeyword); len(words) > 0 {
+		query = fmt.Sprintf("%s +%s", query, words)
+	}
+	if config.IsIgnoreOutofChina {
+		query = fmt.Sprintf("%s +country:\"CN\"", query)
+	}
+	if len(config.SearchStartTime) > 0 {
+		query = fmt.Sprintf("%s +after:\"%s\"", query, config.SearchStartTime)
+	}
+	return
+}
+
+func (z *ZoomEye) getFilterTitleKeyword(filterKeyword map[string]struct{}) string {
+	var words []string
+	for k := range filterKeyword {
+		words = append(words, fmt.Sprintf("-banner:\"%s\"", k))
+	}
+
+	return strings.Join(words, " +")
+}
+
+// GetQuota 查询key剩余的额度
+func (z *ZoomEye) GetQuota(apiKey string, isProxy bool) (remaining int, err error) {
+	request, err := http.NewRequest(http.MethodGet, zoomEyeAPIUrl+"/resources-info", nil)
+	if err != nil {
+		return
+	}
+	request.Header.Set("API-KEY", apiKey)
+	var info struct {
+		Error     string `json:"error"`
+		Message   string `json:"message"`
+		QuotaInfo struct {
+			RemainTotalQuota *int `json:"remain_total_quota"`
+		} `json:"quota_info"`
+	}
+	if err = doQuotaRequest(request, isProxy, &info); err != nil {
+		return
+	}
+	if len(info.Error) > 0 || info.QuotaInfo.RemainTotalQuota == nil {
+		return 0, errors.Newf("ZoomEye Resources Info Error:%s %s", info.Error, info.Message)
+	}
+	return *info.QuotaInfo.RemainTotalQuota, nil
+}
+
+func (z *ZoomEye) Run(query string, apiKey string, pageIndex int, pageSize int, config OnlineAPIConfig) (pageResult []onlineSearchResult, sizeTotal int, err error) {
+	request, err := http.NewRequest(http.MethodGet, zoomEyeAPIUrl+"/host/search", nil)
+	if err != nil {
+		return
+	}
+	params := make(url.Values)
+	params.Add("query", query)
+	params.Add("page", strconv.Itoa(pageIndex))
+	request.URL.RawQuery = params.Encode()
+	request.Header.Set("API-KEY", apiKey)
+	resp, err := utils.GetProxyHttpClient(config.IsProxy).Do(request)
+	if err != nil {
+		return
+	}
+	content, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return
+	}
+	var serviceInfo ZoomEyeServiceInfo
+	if err = json.Unmarshal(content, &serviceInfo); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			err = httpStatusKeyError(resp.StatusCode, "ZoomEye Search Error:%d", resp.StatusCode)
+		}
+		return
+	}
+	if len(serviceInfo.Error) > 0 || resp.StatusCode != http.StatusOK {
+		err = httpStatusKeyError(resp.StatusCode, "ZoomEye Search Error:%d %s %s", resp.StatusCode, serviceInfo.Error, serviceInfo.Message)
+		return
+	}
+	sizeTotal = serviceInfo.Total
+	for _, m := range serviceInfo.Matches {
+		pageResult = append(pageResult, m.toSearchResult())
+	}
+
+	return
+}
+
+// toSearchResult 转换为搜索结果
+func (m *zoomEyeMatch) toSearchResult() onlineSearchResult {
+	fsr := onlineSearchResult{
+		IP:      m.getIP(),
+		Port:    fmt.Sprintf("%d", m.PortInfo.Port),
+		Country: m.GeoInfo.Country.Code,
+		City:    m.GeoInfo.City.Names.En,
+		Server:  m.PortInfo.App,
+	}
+	if len(m.PortInfo.Title) > 0 {
+		fsr.Title = strings.TrimSpace(m.PortInfo.Title[0])
+	}
+	host := m.PortInfo.Hostname
+	if len(host) == 0 {
+		host = m.Rdns
+	}
+	fsr.Domain = host
+	fsr.Host = host
+	return fsr
+}
+
+// getIP 获取记录的IP，zoomeye的ip字段可能为字符串或字符串数组
+func (m *zoomEyeMatch) getIP() string {
+	var ip string
+	if err := json.Unmarshal(m.IP, &ip); err == nil {
+		return ip
+	}
+	var ips []string
+	if err := json.Unmarshal(m.IP, &ips); err == nil && len(ips) > 0 {
+		return ips[0]
+	}
+	return ""
+}
+
+func (z *ZoomEye) ParseContentResult(content []byte) (ipResult portscan.Result, domainResult domainscan.Result) {
+	var results []onlineSearchResult
+	parseJSONContent(content, func(data json.RawMessage) {
+		var m zoomEyeMatch
+		if err := json.Unmarshal(data, &m); err != nil {
+			logging.RuntimeLog.Error(err)
+			return
+		}
+		results = append(results, m.toSearchResult())
+	})
+	return makeContentResult(results, "zoomeye")
+}
@@ -0,0 +1,51 @@
+package onlineapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestZoomEye_Run(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("API-KEY") != "test-key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error":"bad_request","message":"API-KEY is invalid"}`))
+			return
+		}
+		page := r.URL.Query().Get("page")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"total": 25,
+			"matches": []map[string]interface{}{
+				{"ip": "192.0.2." + page, "portinfo": map[string]interface{}{"port": 80, "hostname": "www.example.com", "title": []string{"Example"}, "app": "nginx"}},
+			},
+		})
+	}))
+	defer ts.Close()
+	zoomEyeAPIUrl = ts.URL
+
+	s := newTestOnlineSearch("zoomeye", new(ZoomEye), "test-key", zoomEyePageSize)
+	s.Query("example.com", nil)
+	if len(s.Result) != 2 {
+		t.Fatalf("result count:%d", len(s.Result))
+	}
+	if s.Result[1].IP != "192.0.2.2" || s.Result[0].Title != "Example" || s.Result[0].Domain != "www.example.com" {
+		t.Errorf("result:%v", s.Result)
+	}
+
+	if _, _, err := new(ZoomEye).Run("hostname:example.com", "bad-key", 1, zoomEyePageSize, OnlineAPIConfig{}); err == nil {
+		t.Error("invalid key should fail")
+	}
+}
+
+func TestZoomEye_ParseContentResult(t *testing.T) {
+	data := `{"ip":["192.0.2.1"],"portinfo":{"port":443,"hostname":"a.example.com","title":["A"]}}`
+	ipResult, domainResult := new(ZoomEye).ParseContentResult([]byte(data))
+	if !ipResult.HasPort("192.0.2.1", 443) {
+		t.Errorf("ip result:%v", ipResult.IPResult)
+	}
+	if !domainResult.HasDomain("a.example.com") {
+		t.Error("domain not parsed")
+	}
+}
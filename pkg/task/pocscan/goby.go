@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"github.com/hanc00l/nemo_go/pkg/conf"
+	"github.com/hanc00l/nemo_go/pkg/db"
 	"github.com/hanc00l/nemo_go/pkg/logging"
 	"io"
 	"math/rand"
@@ -164,6 +165,14 @@ type GobyVulnerabilityRequest struct {
 	} `json:"options"`
 }
 
+// gobyLevelSeverity goby的漏洞等级
+var gobyLevelSeverity = map[string]string{
+	"3": db.VulSeverityCritical,
+	"2": db.VulSeverityHigh,
+	"1": db.VulSeverityMedium,
+	"0": db.VulSeverityLow,
+}
+
 // GobyVulnerabilityResponse 扫描结果中的漏洞结果
 type GobyVulnerabilityResponse struct {
 	StatusCode int    `json:"statusCode"`
@@ -477,6 +486,7 @@ func (g *Goby) parseVulnerabilityResult(content []byte) (err error) {
 				Source:      "goby",
 				Extra:       string(extra),
 				WorkspaceId: g.Config.WorkspaceId,
+				Severity:    gobyLevelSeverity[l.Level],
 			})
 		}
 	}
@@ -5,6 +5,7 @@ import (
 	"compress/flate"
 	"encoding/json"
 	"fmt"
+	"github.com/hanc00l/nemo_go/pkg/db"
 	"github.com/hanc00l/nemo_go/pkg/logging"
 	"io"
 	"strings"
@@ -134,6 +135,16 @@ func (v *Vuln) GetDetail() string {
 }
 
 type GOGOResults []*GOGOResult
+
+// gogoSeverityLevel gogo的漏洞等级
+var gogoSeverityLevel = map[int]string{
+	5: db.VulSeverityCritical,
+	4: db.VulSeverityHigh,
+	3: db.VulSeverityMedium,
+	2: db.VulSeverityLow,
+	1: db.VulSeverityInfo,
+}
+
 type Vulns []*Vuln
 type Frameworks map[string]*Framework
 
@@ -191,11 +202,12 @@ func (g *Gogo) ParseContentResult(content []byte) (result []Result) {
 	for _, r := range gogoData.Data {
 		for _, v := range r.Vulns {
 			result = append(result, Result{
-				Target:  r.Ip,
-				Url:     r.Uri,
-				PocFile: v.Name,
-				Source:  "gogo",
-				Extra:   v.String(),
+				Target:   r.Ip,
+				Url:      r.Uri,
+				PocFile:  v.Name,
+				Source:   "gogo",
+				Extra:    v.String(),
+				Severity: gogoSeverityLevel[v.SeverityLevel],
 			})
 		}
 	}
@@ -0,0 +1,213 @@
+package pocscan
+
+import (
+	"fmt"
+	"github.com/hanc00l/nemo_go/pkg/db"
+	"github.com/hanc00l/nemo_go/pkg/utils"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var cveRegexp = regexp.MustCompile(`(?i)cve[-_](\d{4})[-_](\d{4,7})`)
+
+// VulMapping 漏洞的标准化定义，及各扫描引擎对应的poc
+type VulMapping struct {
+	Name     string
+	CVE      []string
+	CWE      []string
+	Severity string
+	Pocs     map[string][]string
+}
+
+// NormalizedVul 标准化后的漏洞
+type NormalizedVul struct {
+	Name     string
+	CVE      []string
+	CWE      []string
+	Severity string
+	// Key 跨扫描引擎的漏洞标识
+	Key string
+}
+
+// VulNormalize 漏洞标准化
+type VulNormalize struct {
+	pocMap map[string]*VulMapping
+	cveMap map[string]*VulMapping
+}
+
+// NewVulNormalize 创建漏洞标准化对象，加载漏洞的映射表
+func NewVulNormalize() *VulNormalize {
+	n := &VulNormalize{}
+	n.loadVulMapping()
+	return n
+}
+
+// loadVulMapping 从数据库加载漏洞的映射表
+func (n *VulNormalize) loadVulMapping() {
+	n.pocMap = make(map[string]*VulMapping)
+	n.cveMap = make(map[string]*VulMapping)
+	mapping := db.VulnerabilityMapping{}
+	var mappings []*VulMapping
+	for _, m := range mapping.GetsAll() {
+		mappings = append(mappings, &VulMapping{
+			Name:     m.Name,
+			CVE:      m.GetCVE(),
+			CWE:      m.GetCWE(),
+			Severity: m.Severity,
+			Pocs:     m.GetPocs(),
+		})
+	}
+	n.setMappings(mappings)
+}
+
+// setMappings 建立poc和cve的索引
+func (n *VulNormalize) setMappings(mappings []*VulMapping) {
+	for _, m := range mappings {
+		m.Severity = normalizeSeverity(m.Severity)
+		for i := range m.CVE {
+			m.CVE[i] = strings.ToUpper(m.CVE[i])
+			n.cveMap[m.CVE[i]] = m
+		}
+		for i := range m.CWE {
+			m.CWE[i] = strings.ToUpper(m.CWE[i])
+		}
+		for source, pocs := range m.Pocs {
+			for _, poc := range pocs {
+				n.pocMap[pocMappingKey(source, poc)] = m
+			}
+		}
+	}
+}
+
+// Normalize 对扫描引擎的漏洞结果进行标准化
+func (n *VulNormalize) Normalize(r Result) (v NormalizedVul) {
+	mapping := n.findMapping(r)
+	cveSet := make(map[string]struct{})
+	cweSet := make(map[string]struct{})
+	if mapping != nil {
+		v.Name = mapping.Name
+		v.Severity = mapping.Severity
+		addToSet(cveSet, mapping.CVE)
+		addToSet(cweSet, mapping.CWE)
+	}
+	addToSet(cveSet, r.CVE)
+	addToSet(cveSet, parseCVE(r.PocFile))
+	addToSet(cweSet, r.CWE)
+	v.CVE = utils.SetToSlice(cveSet)
+	v.CWE = utils.SetToSlice(cweSet)
+	sort.Strings(v.CVE)
+	sort.Strings(v.CWE)
+	// 通过CVE再次匹配映射表
+	if mapping == nil {
+		for _, cve := range v.CVE {
+			if m, ok := n.cveMap[cve]; ok {
+				v.Name = m.Name
+				v.Severity = m.Severity
+				break
+			}
+		}
+	}
+	if v.Severity == "" {
+		v.Severity = normalizeSeverity(r.Severity)
+	}
+	if v.Name == "" {
+		if len(v.CVE) > 0 {
+			v.Name = v.CVE[0]
+		} else {
+			v.Name = r.PocFile
+		}
+	}
+	// 有CVE编号的以排序后的全部CVE作为标识，在映射表中的以名称作为标识，否则只能在同一个扫描引擎内去重
+	if len(v.CVE) > 0 {
+		v.Key = strings.Join(v.CVE, ",")
+	} else if mapping != nil {
+		v.Key = "name:" + strings.ToLower(mapping.Name)
+	} else {
+		v.Key = fmt.Sprintf("%s:%s", r.Source, r.PocFile)
+	}
+	return
+}
+
+// findMapping 根据扫描引擎的poc查找映射表
+func (n *VulNormalize) findMapping(r Result) *VulMapping {
+	if m, ok := n.pocMap[pocMappingKey(r.Source, r.PocFile)]; ok {
+		return m
+	}
+	// fscan使用的是xray的poc
+	if r.Source == "fscan" {
+		if m, ok := n.pocMap[pocMappingKey("xray", r.PocFile)]; ok {
+			return m
+		}
+	}
+	return nil
+}
+
+// MakeVulKey 生成跨扫描引擎去重的漏洞标识：同一目标的同一端口上的同一个漏洞
+func MakeVulKey(target string, vulUrl string, normalizedKey string) string {
+	return utils.MD5(fmt.Sprintf("%s:%d:%s", target, parseVulPort(vulUrl), normalizedKey))
+}
+
+func pocMappingKey(source, poc string) string {
+	return fmt.Sprintf("%s|%s", strings.ToLower(source), strings.ToLower(strings.TrimSpace(poc)))
+}
+
+func addToSet(set map[string]struct{}, values []string) {
+	for _, v := range values {
+		if v = strings.ToUpper(strings.TrimSpace(v)); v != "" {
+			set[v] = struct{}{}
+		}
+	}
+}
+
+// parseCVE 从poc名称中提取CVE编号
+func parseCVE(s string) (cves []string) {
+	for _, m := range cveRegexp.FindAllStringSubmatch(s, -1) {
+		cves = append(cves, fmt.Sprintf("CVE-%s-%s", m[1], m[2]))
+	}
+	return
+}
+
+// parseVulPort 从漏洞的url中获取端口，支持http://host:port/path及host:port格式
+func parseVulPort(vulUrl string) int {
+	vulUrl = strings.TrimSpace(vulUrl)
+	if vulUrl == "" {
+		return 0
+	}
+	if !strings.Contains(vulUrl, "://") {
+		vulUrl = "//" + vulUrl
+	}
+	u, err := url.Parse(vulUrl)
+	if err != nil {
+		return 0
+	}
+	if port, err := strconv.Atoi(u.Port()); err == nil {
+		return port
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "http":
+		return 80
+	case "https":
+		return 443
+	}
+	return 0
+}
+
+// normalizeSeverity 将各扫描引擎的漏洞等级统一为critical、high、medium、low、info
+func normalizeSeverity(severity string) string {
+	switch strings.ToLower(strings.TrimSpace(severity)) {
+	case db.VulSeverityCritical, "严重":
+		return db.VulSeverityCritical
+	case db.VulSeverityHigh, "高危":
+		return db.VulSeverityHigh
+	case db.VulSeverityMedium, "moderate", "中危":
+		return db.VulSeverityMedium
+	case db.VulSeverityLow, "低危":
+		return db.VulSeverityLow
+	case db.VulSeverityInfo, "informational", "信息":
+		return db.VulSeverityInfo
+	}
+	return ""
+}
@@ -0,0 +1,110 @@
+package pocscan
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestVulNormalize_Normalize(t *testing.T) {
+	n := &VulNormalize{pocMap: make(map[string]*VulMapping), cveMap: make(map[string]*VulMapping)}
+	n.setMappings([]*VulMapping{
+		{
+			Name:     "ThinkPHP 5.0.23 远程代码执行漏洞",
+			Severity: "Critical",
+			Pocs: map[string][]string{
+				"xray":   {"poc-yaml-thinkphp5023-method-rce"},
+				"nuclei": {"thinkphp-5023-rce"},
+			},
+		},
+		{
+			Name: "WebLogic XMLDecoder 反序列化漏洞",
+			CVE:  []string{"cve-2017-10271"},
+			Pocs: map[string][]string{"xray": {"poc-yaml-weblogic-cve-2017-10271"}},
+		},
+	})
+	xray := n.Normalize(Result{Source: "xray", PocFile: "poc-yaml-thinkphp5023-method-rce"})
+	nuclei := n.Normalize(Result{Source: "nuclei", PocFile: "thinkphp-5023-rce", Severity: "high"})
+	fscan := n.Normalize(Result{Source: "fscan", PocFile: "poc-yaml-thinkphp5023-method-rce"})
+	if xray.Key != nuclei.Key || xray.Key != fscan.Key {
+		t.Errorf("key mismatch:%s,%s,%s", xray.Key, nuclei.Key, fscan.Key)
+	}
+	if nuclei.Severity != "critical" || nuclei.Name != "ThinkPHP 5.0.23 远程代码执行漏洞" {
+		t.Errorf("mapping not applied:%v", nuclei)
+	}
+	// 映射表之外的poc通过CVE编号关联
+	goby := n.Normalize(Result{Source: "goby", PocFile: "Weblogic RCE (CVE-2017-10271)", Severity: "high"})
+	nuclei = n.Normalize(Result{Source: "nuclei", PocFile: "CVE-2017-10271", CVE: []string{"cve-2017-10271"}, CWE: []string{"cwe-502"}})
+	if goby.Key != "CVE-2017-10271" || goby.Key != nuclei.Key {
+		t.Errorf("cve key mismatch:%s,%s", goby.Key, nuclei.Key)
+	}
+	if goby.Name != "WebLogic XMLDecoder 反序列化漏洞" || goby.Severity != "high" {
+		t.Errorf("cve mapping not applied:%v", goby)
+	}
+	if len(nuclei.CWE) != 1 || nuclei.CWE[0] != "CWE-502" {
+		t.Errorf("cwe:%v", nuclei.CWE)
+	}
+	// 无法标准化的漏洞只在同一扫描引擎内去重
+	unknown := n.Normalize(Result{Source: "gogo", PocFile: "unknown-poc", Severity: "中危"})
+	if unknown.Key != "gogo:unknown-poc" || unknown.Name != "unknown-poc" || unknown.Severity != "medium" {
+		t.Errorf("unknown:%v", unknown)
+	}
+}
+
+func TestVulNormalize_MultiCVEKey(t *testing.T) {
+	n := &VulNormalize{pocMap: make(map[string]*VulMapping), cveMap: make(map[string]*VulMapping)}
+	// 多个CVE编号的漏洞以全部CVE作为标识，不同的CVE组合不会被合并
+	a := n.Normalize(Result{Source: "nuclei", PocFile: "a", CVE: []string{"CVE-2021-45046", "CVE-2021-44228"}})
+	b := n.Normalize(Result{Source: "xray", PocFile: "b", CVE: []string{"cve-2021-44228", "cve-2021-45046"}})
+	c := n.Normalize(Result{Source: "goby", PocFile: "c", CVE: []string{"CVE-2021-44228"}})
+	if a.Key != "CVE-2021-44228,CVE-2021-45046" || a.Key != b.Key {
+		t.Errorf("key mismatch:%s,%s", a.Key, b.Key)
+	}
+	if a.Key == c.Key {
+		t.Errorf("different cve set should have different key:%s", c.Key)
+	}
+}
+
+func TestMakeVulKey(t *testing.T) {
+	if MakeVulKey("127.0.0.1", "http://127.0.0.1/index.php", "CVE-2017-10271") != MakeVulKey("127.0.0.1", "127.0.0.1:80", "CVE-2017-10271") {
+		t.Error("same port should have same key")
+	}
+	if MakeVulKey("127.0.0.1", "http://127.0.0.1:7001/", "CVE-2017-10271") == MakeVulKey("127.0.0.1", "https://127.0.0.1/", "CVE-2017-10271") {
+		t.Error("different port should have different key")
+	}
+}
+
+func TestParseVulPort(t *testing.T) {
+	tests := map[string]int{
+		"http://127.0.0.1/index.php": 80,
+		"https://example.com":        443,
+		"http://127.0.0.1:8080/a":    8080,
+		"127.0.0.1:6379":             6379,
+		"127.0.0.1":                  0,
+		"":                           0,
+	}
+	for u, port := range tests {
+		if p := parseVulPort(u); p != port {
+			t.Errorf("%s:%d,expected:%d", u, p, port)
+		}
+	}
+}
+
+func TestParseCVE(t *testing.T) {
+	cves := parseCVE("poc-yaml-weblogic-cve-2017-10271 and CVE_2021_44228")
+	if len(cves) != 2 || cves[0] != "CVE-2017-10271" || cves[1] != "CVE-2021-44228" {
+		t.Errorf("cves:%v", cves)
+	}
+}
+
+func TestStringSlice_UnmarshalJSON(t *testing.T) {
+	var v struct {
+		A stringSlice `json:"a"`
+		B stringSlice `json:"b"`
+	}
+	if err := json.Unmarshal([]byte(`{"a":"cve-2021-44228","b":["cwe-502","cwe-20"]}`), &v); err != nil {
+		t.Fatal(err)
+	}
+	if len(v.A) != 1 || v.A[0] != "cve-2021-44228" || len(v.B) != 2 {
+		t.Errorf("%v", v)
+	}
+}
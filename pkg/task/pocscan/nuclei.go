@@ -105,6 +105,9 @@ func (n *Nuclei) parseNucleiContentResult(content []byte) {
 		Source:      "nuclei",
 		Extra:       string(pretty.Pretty(content)),
 		WorkspaceId: n.Config.WorkspaceId,
+		Severity:    xr.Info.Severity,
+		CVE:         xr.Info.Classification.CVEID,
+		CWE:         xr.Info.Classification.CWEID,
 	})
 }
 
@@ -1,10 +1,12 @@
 package pocscan
 
 import (
+	"encoding/json"
 	"fmt"
 	"github.com/hanc00l/nemo_go/pkg/conf"
 	"github.com/hanc00l/nemo_go/pkg/db"
 	"github.com/hanc00l/nemo_go/pkg/logging"
+	"github.com/hanc00l/nemo_go/pkg/notify"
 	"github.com/hanc00l/nemo_go/pkg/utils"
 	"strings"
 	"sync"
@@ -37,6 +39,10 @@ type Result struct {
 	Source      string `json:"source"`
 	Extra       string `json:"extra"`
 	WorkspaceId int    `json:"workspaceId"`
+	// 扫描引擎提供的漏洞信息，用于漏洞的标准化
+	Severity string   `json:"severity,omitempty"`
+	CVE      []string `json:"cve,omitempty"`
+	CWE      []string `json:"cwe,omitempty"`
 }
 
 type xrayJSONResult struct {
@@ -77,9 +83,37 @@ type nucleiJSONResult struct {
 	IP string `json:"ip,omitempty"`
 	// Timestamp is the time the result was found at.
 	Timestamp time.Time `json:"timestamp"`
+	// Info contains information block of the template for the result.
+	Info struct {
+		Name           string `json:"name"`
+		Severity       string `json:"severity"`
+		Classification struct {
+			CVEID stringSlice `json:"cve-id"`
+			CWEID stringSlice `json:"cwe-id"`
+		} `json:"classification"`
+	} `json:"info"`
 	// Interaction is the full details of interactsh interaction.
 }
 
+// stringSlice 兼容字符串和字符串数组的JSON格式
+type stringSlice []string
+
+func (s *stringSlice) UnmarshalJSON(data []byte) error {
+	var list []string
+	if err := json.Unmarshal(data, &list); err == nil {
+		*s = list
+		return nil
+	}
+	var str string
+	if err := json.Unmarshal(data, &str); err != nil {
+		return err
+	}
+	if str != "" {
+		*s = strings.Split(str, ",")
+	}
+	return nil
+}
+
 // PortResult 端口结果
 type PortResult struct {
 	Vuls []string
@@ -205,16 +239,24 @@ func (r *DomainscanVulResult) SetDomainVul(domain string, vul string) {
 	r.DomainResult[domain].Vuls = append(r.DomainResult[domain].Vuls, vul)
 }
 
-// SaveResult 保存结果
+// SaveResult 保存结果，各扫描引擎的漏洞经标准化后按漏洞标识去重
 func SaveResult(result []Result) string {
+	msg, _ := SaveResultWithNew(result)
+	return msg
+}
+
+// SaveResultWithNew 保存结果，并返回新增的漏洞（target vulname）
+func SaveResultWithNew(result []Result) (string, []string) {
 	var resultCount int
-	var newVul int
+	var newVul []string
+	normalize := NewVulNormalize()
 	for _, r := range result {
 		target := utils.ParseHost(r.Target)
 		extra := r.Extra
 		if len(r.Extra) > 2000 {
 			extra = r.Extra[:2000] + "..."
 		}
+		nv := normalize.Normalize(r)
 		vul := db.Vulnerability{
 			Target:      target,
 			Url:         r.Url,
@@ -222,18 +264,43 @@ func SaveResult(result []Result) string {
 			Source:      r.Source,
 			Extra:       extra,
 			WorkspaceId: r.WorkspaceId,
+			Severity:    nv.Severity,
+			VulName:     nv.Name,
+			CVE:         strings.Join(nv.CVE, ","),
+			CWE:         strings.Join(nv.CWE, ","),
+			VulKey:      MakeVulKey(target, r.Url, nv.Key),
 		}
 		if ok, isNew := vul.SaveOrUpdate(); ok {
 			resultCount++
 			if isNew {
-				newVul++
+				newVul = append(newVul, fmt.Sprintf("%s %s", target, nv.Name))
+				notify.Publish(notify.Event{Type: notify.EventVulnerability, WorkspaceId: r.WorkspaceId, OrgId: getTargetOrgId(target, r.WorkspaceId), Target: target, Severity: nv.Severity, Content: nv.Name})
 			}
 		}
 	}
 	var sb strings.Builder
 	sb.WriteString(fmt.Sprintf("vulnerability:%d", resultCount))
-	if newVul > 0 {
-		sb.WriteString(fmt.Sprintf(",vulnerabilityNew:%d", newVul))
+	if len(newVul) > 0 {
+		sb.WriteString(fmt.Sprintf(",vulnerabilityNew:%d", len(newVul)))
+	}
+	return sb.String(), newVul
+}
+
+// getTargetOrgId 查询漏洞目标（IP或域名）资产所属的组织，用于按组织匹配通知规则
+func getTargetOrgId(target string, workspaceId int) *int {
+	if utils.CheckIP(target) {
+		if utils.CheckIPV6(target) {
+			target = utils.GetIPV6ParsedFormat(target)
+		}
+		ip := db.Ip{IpName: target, WorkspaceId: workspaceId}
+		if ip.GetByIp() {
+			return ip.OrgId
+		}
+		return nil
+	}
+	domain := db.Domain{DomainName: target, WorkspaceId: workspaceId}
+	if domain.GetByDomain() {
+		return domain.OrgId
 	}
-	return sb.String()
+	return nil
 }
@@ -0,0 +1,162 @@
+package portscan
+
+import (
+	"fmt"
+	"github.com/hanc00l/nemo_go/pkg/db"
+	"github.com/hanc00l/nemo_go/pkg/task/custom"
+	"github.com/hanc00l/nemo_go/pkg/utils"
+	"net"
+	"strings"
+)
+
+// targetScope 扫描目标的范围，支持IP、IP段及子网
+type targetScope struct {
+	ips  map[string]struct{}
+	nets []*net.IPNet
+}
+
+// newTargetScope 解析以逗号分隔的目标
+func newTargetScope(target string) *targetScope {
+	scope := &targetScope{ips: make(map[string]struct{})}
+	for _, t := range strings.Split(target, ",") {
+		t = strings.TrimSpace(t)
+		if t == "" {
+			continue
+		}
+		if strings.Contains(t, "/") {
+			if _, ipNet, err := net.ParseCIDR(t); err == nil {
+				scope.nets = append(scope.nets, ipNet)
+			}
+			continue
+		}
+		for _, ip := range utils.ParseIP(t) {
+			scope.ips[ip] = struct{}{}
+		}
+	}
+	return scope
+}
+
+// Contains 检查IP是否在范围内
+func (s *targetScope) Contains(ip string) bool {
+	if _, ok := s.ips[ip]; ok {
+		return true
+	}
+	if len(s.nets) > 0 {
+		if netIP := net.ParseIP(ip); netIP != nil {
+			for _, ipNet := range s.nets {
+				if ipNet.Contains(netIP) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// Reconcile 对比端口扫描的目标范围与扫描结果，对范围内已有但本次未发现的IP和端口增加未发现次数，
+// 连续未发现的次数达到staleMissCount时标记为失效（端口即为关闭）
+func (r *Result) Reconcile(config Config, staleMissCount int) string {
+	if staleMissCount <= 0 || !config.IsPortscan || config.Target == "" || config.Port == "" {
+		return ""
+	}
+	portScope := utils.ParseAllPort(config.Port)
+	if len(portScope) == 0 {
+		return ""
+	}
+	excludeScope := newTargetScope(config.ExcludeTarget)
+	blackIP := custom.NewBlackTargetCheck(custom.CheckIP)
+
+	var staleIP, stalePort int
+	for _, ip := range getScopeIPs(config.Target, config.WorkspaceId) {
+		if excludeScope.Contains(ip.IpName) || blackIP.CheckBlack(ip.IpName) {
+			continue
+		}
+		ipResult, found := r.IPResult[ip.IpName]
+		// 开放端口过多的结果未保存，不能作为判断依据
+		if found && len(ipResult.Ports) > IpOpenedPortFilterNumber {
+			continue
+		}
+		if !found && increaseIPMissCount(&ip, config, staleMissCount) {
+			staleIP++
+		}
+		port := db.Port{IpId: ip.Id}
+		for _, p := range port.GetsByIPId() {
+			if _, ok := portScope[p.PortNum]; !ok {
+				continue
+			}
+			if found {
+				if _, ok := ipResult.Ports[p.PortNum]; ok {
+					continue
+				}
+			}
+			if increasePortMissCount(&p, ip.IpName, config, staleMissCount) {
+				stalePort++
+			}
+		}
+	}
+	var sb strings.Builder
+	if staleIP > 0 {
+		sb.WriteString(fmt.Sprintf("ipStale:%d", staleIP))
+	}
+	if stalePort > 0 {
+		if sb.Len() > 0 {
+			sb.WriteString(",")
+		}
+		sb.WriteString(fmt.Sprintf("portStale:%d", stalePort))
+	}
+	return sb.String()
+}
+
+// getScopeIPs 获取扫描目标范围内已有的IP记录
+func getScopeIPs(target string, workspaceId int) (results []db.Ip) {
+	ipIdMap := make(map[int]struct{})
+	appendResult := func(ip db.Ip) {
+		if _, ok := ipIdMap[ip.Id]; !ok {
+			ipIdMap[ip.Id] = struct{}{}
+			results = append(results, ip)
+		}
+	}
+	for _, t := range strings.Split(target, ",") {
+		t = strings.TrimSpace(t)
+		if t == "" {
+			continue
+		}
+		if utils.CheckIPV4Subnet(t) {
+			ip := db.Ip{}
+			ips, _ := ip.Gets(map[string]interface{}{"ip": t, "workspace_id": workspaceId}, 0, 0, false)
+			for _, ipRow := range ips {
+				appendResult(ipRow)
+			}
+			continue
+		}
+		for _, ipName := range utils.ParseIP(t) {
+			ip := db.Ip{IpName: ipName, WorkspaceId: workspaceId}
+			if ip.GetByIp() {
+				appendResult(ip)
+			}
+		}
+	}
+	return
+}
+
+// increaseIPMissCount 增加IP连续未发现的次数，返回是否本次被标记为失效
+func increaseIPMissCount(ip *db.Ip, config Config, staleMissCount int) bool {
+	missCount := ip.MissCount + 1
+	wasStale := ip.IsStale
+	if !ip.UpdateMissCount(missCount, missCount >= staleMissCount) || !ip.IsStale || wasStale {
+		return false
+	}
+	db.SaveAssetChange(config.WorkspaceId, db.AssetTypeIP, ip.IpName, 0, db.AssetChangeStale, "", "ip", fmt.Sprintf("miss:%d", missCount), "", config.TaskId)
+	return true
+}
+
+// increasePortMissCount 增加端口连续未发现的次数，返回是否本次被标记为失效
+func increasePortMissCount(port *db.Port, ipName string, config Config, staleMissCount int) bool {
+	missCount := port.MissCount + 1
+	wasStale := port.IsStale
+	if !port.UpdateMissCount(missCount, missCount >= staleMissCount) || !port.IsStale || wasStale {
+		return false
+	}
+	db.SaveAssetChange(config.WorkspaceId, db.AssetTypeIP, ipName, port.PortNum, db.AssetChangeStale, "", "port", fmt.Sprintf("miss:%d", missCount), "", config.TaskId)
+	return true
+}
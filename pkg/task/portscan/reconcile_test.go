@@ -0,0 +1,20 @@
+package portscan
+
+import "testing"
+
+func TestTargetScope_Contains(t *testing.T) {
+	scope := newTargetScope("192.168.1.1,10.0.0.0/24, 172.16.1.1-172.16.1.3")
+	for _, ip := range []string{"192.168.1.1", "10.0.0.1", "10.0.0.255", "172.16.1.2"} {
+		if !scope.Contains(ip) {
+			t.Errorf("%s should be in scope", ip)
+		}
+	}
+	for _, ip := range []string{"192.168.1.2", "10.0.1.1", "172.16.1.4", "example.com"} {
+		if scope.Contains(ip) {
+			t.Errorf("%s should not be in scope", ip)
+		}
+	}
+	if newTargetScope("").Contains("192.168.1.1") {
+		t.Error("empty scope should contain nothing")
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/hanc00l/nemo_go/pkg/conf"
 	"github.com/hanc00l/nemo_go/pkg/db"
 	"github.com/hanc00l/nemo_go/pkg/logging"
+	"github.com/hanc00l/nemo_go/pkg/notify"
 	"github.com/hanc00l/nemo_go/pkg/task/custom"
 	"strings"
 	"sync"
@@ -35,6 +36,7 @@ type Config struct {
 	IsPortscan       bool   `json:"isPortscan"`
 	WorkspaceId      int    `json:"workspaceId"`
 	IsProxy          bool   `json:"proxy"`
+	TaskId           string `json:"-"` // 保存结果的任务ID，用于记录资产变更的来源
 }
 
 // PortAttrResult 端口属性结果
@@ -183,12 +185,13 @@ func (r *Result) SaveResult(config Config) string {
 			Status:      ipResult.Status,
 			WorkspaceId: config.WorkspaceId,
 		}
-		if ok, isNew := ip.SaveOrUpdate(); !ok {
+		ok, isNewIP := ip.SaveOrUpdate()
+		if !ok {
 			continue
-		} else {
-			if isNew {
-				newIP++
-			}
+		}
+		if isNewIP {
+			newIP++
+			db.SaveAssetChange(config.WorkspaceId, db.AssetTypeIP, ipName, 0, db.AssetChangeAdded, "", "ip", ipName, "", config.TaskId)
 		}
 		resultIPCount++
 		// elastic assets
@@ -202,14 +205,30 @@ func (r *Result) SaveResult(config Config) string {
 				PortNum: portNumber,
 				Status:  portResult.Status,
 			}
-			if ok, isNew := port.SaveOrUpdate(); !ok {
+			oldPort := &db.Port{IpId: ip.Id, PortNum: portNumber}
+			if !isNewIP {
+				oldPort.GetByIPPort()
+			}
+			ok, isNewPort := port.SaveOrUpdate()
+			if !ok {
 				continue
-			} else {
-				if isNew {
-					newPort++
-				}
+			}
+			if isNewPort {
+				newPort++
+				db.SaveAssetChange(config.WorkspaceId, db.AssetTypeIP, ipName, portNumber, db.AssetChangeAdded, "", "port", port.Status, "", config.TaskId)
+				notify.Publish(notify.Event{Type: notify.EventPort, WorkspaceId: config.WorkspaceId, OrgId: config.OrgId, Target: ipName, Port: portNumber, Content: port.Status})
+			} else if port.Status != "" && oldPort.Status != port.Status {
+				db.SaveAssetChange(config.WorkspaceId, db.AssetTypeIP, ipName, portNumber, db.AssetChangeModified, "", "status", port.Status, oldPort.Status, config.TaskId)
 			}
 			resultPortCount++
+			// 记录端口属性的变更
+			changeRecorder := db.NewAssetChangeRecorder(config.WorkspaceId, db.AssetTypeIP, ipName, portNumber, config.TaskId)
+			if !isNewPort {
+				oldPortAttr := db.PortAttr{RelatedId: port.Id}
+				for _, attr := range oldPortAttr.GetsByRelatedId() {
+					changeRecorder.SetOldAttr(attr.Source, attr.Tag, attr.Content, attr.UpdateDatetime)
+				}
+			}
 			//save port attribute
 			for _, portAttrResult := range portResult.PortAttrs {
 				portAttr := &db.PortAttr{
@@ -223,7 +242,9 @@ func (r *Result) SaveResult(config Config) string {
 					portAttr.Content = portAttrResult.Content
 				}
 				portAttr.SaveOrUpdate()
+				changeRecorder.SetNewAttr(portAttr.Source, portAttr.Tag, portAttr.Content)
 			}
+			changeRecorder.Save()
 			//save http info
 			for _, httpInfoResult := range portResult.HttpInfo {
 				httpInfo := &db.IpHttp{
@@ -34,6 +34,7 @@ type DomainscanRequestParam struct {
 	OrgId              int    `form:"org_id"`
 	IsSubfinder        bool   `form:"subfinder"`
 	IsSubdomainBrute   bool   `form:"subdomainbrute"`
+	IsSubdomainPermute bool   `form:"subdomainpermute"`
 	IsFldDomain        bool   `form:"fld_domain"`
 	IsHttpx            bool   `form:"httpx"`
 	IsIPPortscan       bool   `form:"portscan"`
@@ -87,6 +88,18 @@ type XScanRequestParam struct {
 	IsNucleiPocscan bool   `form:"nucleipoc"`
 	NucleiPocFile   string `form:"nucleipocfile"`
 	IsGobyPocscan   bool   `form:"gobypoc"`
+	IsDirscan       bool   `form:"dirscan"`
+	IsTaskCron      bool   `form:"taskcron" json:"-"`
+	TaskCronRule    string `form:"cronrule" json:"-"`
+	TaskCronComment string `form:"croncomment" json:"-"`
+	IsProxy         bool   `form:"proxy"`
+}
+
+type WorkflowRequestParam struct {
+	WorkflowId      int    `form:"workflow_id"`
+	Target          string `form:"target"`
+	Port            string `form:"port"`
+	OrgId           int    `form:"org_id"`
 	IsTaskCron      bool   `form:"taskcron" json:"-"`
 	TaskCronRule    string `form:"cronrule" json:"-"`
 	TaskCronComment string `form:"croncomment" json:"-"`
@@ -23,6 +23,11 @@ var (
 
 // SaveCronTask 保存定时任务
 func SaveCronTask(taskName, kwArgs, cronRule, comment string, workspaceId int) (taskId string) {
+	kwArgs, err := saveTargetSet(kwArgs, workspaceId)
+	if err != nil {
+		logging.RuntimeLog.Errorf("save cron task fail:%v", err)
+		return ""
+	}
 	tc := db.TaskCron{
 		TaskId:      uuid.New().String(),
 		TaskName:    taskName,
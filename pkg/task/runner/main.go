@@ -6,10 +6,13 @@ import (
 	"fmt"
 	"github.com/google/uuid"
 	"github.com/hanc00l/nemo_go/pkg/comm"
+	"github.com/hanc00l/nemo_go/pkg/conf"
 	"github.com/hanc00l/nemo_go/pkg/db"
 	"github.com/hanc00l/nemo_go/pkg/logging"
 	"github.com/hanc00l/nemo_go/pkg/notify"
 	"github.com/hanc00l/nemo_go/pkg/task/ampq"
+	"github.com/hanc00l/nemo_go/pkg/task/domainscan"
+	"github.com/hanc00l/nemo_go/pkg/task/workerapi"
 	"strings"
 	"time"
 )
@@ -47,19 +50,21 @@ func SaveMainTask(taskName, configJSON, cronTaskId string, workspaceId int) (tas
 		CronTaskId:  cronTaskId,
 		WorkspaceId: workspaceId,
 	}
-	//kwargs可能因为target很多导致超过数据库中的字段设计长度，因此作一个长度截取
-	const argsLength = 6000
-	if len(task.KwArgs) > argsLength {
-		//kwargs可能因为target很多导致超过数据库中的字段设计长度，直接返回错误，不保存任务信息
-		err = errors.New(fmt.Sprintf("maintask %s:%s arguments too long:%s", taskName, taskId, task.KwArgs))
+	//kwargs可能因为target很多导致超过数据库中的字段设计长度，将target转存为目标集合
+	if task.KwArgs, err = saveTargetSet(configJSON, workspaceId); err != nil {
+		err = errors.New(fmt.Sprintf("maintask %s:%s %s", taskName, taskId, err.Error()))
 		logging.CLILog.Error(err)
-		logging.RuntimeLog.Error(fmt.Sprintf("maintask %s:%s arguments too long:%d", taskName, taskId, len(task.KwArgs)))
+		logging.RuntimeLog.Error(err)
 		return
 	}
 	if !task.Add() {
 		err = errors.New(fmt.Sprintf("save new maintask fail: %s,%s,%s", taskName, taskId, task.KwArgs))
 		logging.CLILog.Error(err)
 		logging.RuntimeLog.Error(err)
+		// 删除本次新建的目标集合
+		if task.KwArgs != configJSON {
+			DeleteTargetSet(task.KwArgs)
+		}
 	}
 	return
 }
@@ -129,6 +134,16 @@ func runMainTask(taskName, taskId, kwArgs string, workspaceId int) (err error) {
 			logging.RuntimeLog.Error(err)
 			return
 		}
+	} else if taskName == "workflow" {
+		var req WorkflowRequestParam
+		if err = json.Unmarshal([]byte(kwArgs), &req); err != nil {
+			logging.RuntimeLog.Error(err)
+			return
+		}
+		if taskRunId, err = StartWorkflowTask(req, taskId, workspaceId); err != nil {
+			logging.RuntimeLog.Error(err)
+			return
+		}
 	} else {
 		logging.RuntimeLog.Errorf("invalid task name:%s in %s...", taskName, taskId)
 		return
@@ -174,7 +189,7 @@ func processStartedTask() (err error) {
 	searchMap := make(map[string]interface{})
 	searchMap["state"] = ampq.STARTED
 	results, _ := task.Gets(searchMap, -1, -1)
-	var finishedTask []string
+	var finishedTask []db.TaskMain
 	for _, t := range results {
 		// 如果数据库中的任务是STARTED状态，但缓存中有结果则重建（服务端重启有可能导致）
 		comm.MainTaskResultMutex.Lock()
@@ -194,7 +209,7 @@ func processStartedTask() (err error) {
 		if totalTask > 0 && createdTask == 0 && startedTask == 0 {
 			updatedState = ampq.SUCCESS
 			updatedResult = checkMainTaskResult(t.TaskId)
-			finishedTask = append(finishedTask, t.TaskId)
+			finishedTask = append(finishedTask, t)
 		}
 		// 如果进度相同则不需要更新
 		if updatedProgress == t.ProgressMessage {
@@ -209,38 +224,192 @@ func processStartedTask() (err error) {
 			}
 		}
 	}
-	// 发送任务通知，从map中移除已完成任务
+	// 从map中移除已完成任务，发送任务通知
+	finishedResult := make(map[string]comm.MainTaskResultMap)
 	comm.MainTaskResultMutex.Lock()
-	for _, taskId := range finishedTask {
-		message := formatNotifyMessage(taskId)
-		go notify.Send(message)
-		delete(comm.MainTaskResult, taskId)
+	for _, t := range finishedTask {
+		if taskObj, exist := comm.MainTaskResult[t.TaskId]; exist {
+			finishedResult[t.TaskId] = taskObj
+		}
+		delete(comm.MainTaskResult, t.TaskId)
 	}
 	comm.MainTaskResultMutex.Unlock()
+	for _, t := range finishedTask {
+		taskObj, exist := finishedResult[t.TaskId]
+		if data, ok := makeNotifyTaskMessage(t.TaskId, taskObj, exist); ok {
+			go notify.SendTask(data)
+		}
+		// 对比所有子任务的结果，标记失效的域名
+		var domains []string
+		for domain := range taskObj.DomainResult {
+			domains = append(domains, domain)
+		}
+		go reconcileMainTaskDomain(t, domains)
+	}
+	return
+}
+
+// reconcileMainTaskDomain 产生域名结果的maintask完成后，对比目标范围与所有子任务（包括各种子域名枚举方式及在线资产平台）的结果，
+// 避免各子任务只对比自己的结果而将其它方式发现的域名作为未发现
+func reconcileMainTaskDomain(task db.TaskMain, domains []string) {
+	staleMissCount := conf.GlobalServerConfig().Task.StaleMissCount
+	if staleMissCount <= 0 {
+		return
+	}
+	domainTargetList, isSubDomain := getMainTaskDomainScope(task)
+	if len(domainTargetList) == 0 {
+		return
+	}
+	config := domainscan.Config{
+		Target:            strings.Join(domainTargetList, ","),
+		IsSubDomainFinder: isSubDomain,
+		WorkspaceId:       task.WorkspaceId,
+		TaskId:            task.TaskId,
+	}
+	result := domainscan.Result{DomainResult: make(map[string]*domainscan.DomainResult)}
+	for _, domain := range domains {
+		result.SetDomain(domain)
+	}
+	if msg := result.Reconcile(config, staleMissCount); msg != "" {
+		logging.RuntimeLog.Infof("maintask %s reconcile:%s", task.TaskId, msg)
+	}
+}
+
+// getMainTaskDomainScope 获取maintask的域名目标范围，以及是否进行了子域名枚举或爆破（范围包括目标的已有子域名）；
+// 按查询语法的在线资产平台任务等没有域名目标范围的任务返回空
+func getMainTaskDomainScope(task db.TaskMain) (domainTargetList []string, isSubDomain bool) {
+	switch task.TaskName {
+	case "domainscan":
+		var req DomainscanRequestParam
+		if err := json.Unmarshal([]byte(task.KwArgs), &req); err != nil {
+			logging.RuntimeLog.Error(err)
+			return
+		}
+		domainTargetList = formatDomainTarget(req.Target)
+		if req.IsFldDomain {
+			domainTargetList = getDomainFLD(domainTargetList)
+		}
+		isSubDomain = req.IsSubfinder || req.IsSubdomainBrute
+	case "xdomainscan":
+		var req XScanRequestParam
+		if err := json.Unmarshal([]byte(task.KwArgs), &req); err != nil {
+			logging.RuntimeLog.Error(err)
+			return
+		}
+		domainTargetList = formatDomainTarget(req.Target)
+		// 子域名枚举及爆破由worker配置决定，见StartXDomainScanTask
+		isSubDomain = conf.GlobalWorkerConfig().Domainscan.IsSubDomainFinder || conf.GlobalWorkerConfig().Domainscan.IsSubDomainBrute
+	case "xorgscan":
+		var req XScanRequestParam
+		if err := json.Unmarshal([]byte(task.KwArgs), &req); err != nil {
+			logging.RuntimeLog.Error(err)
+			return
+		}
+		if !req.IsOrgDomain || req.OrgId == 0 {
+			return
+		}
+		// 组织的已有域名作为目标，不进行子域名枚举
+		domain := db.Domain{}
+		results, _ := domain.Gets(map[string]interface{}{"org_id": req.OrgId, "workspace_id": task.WorkspaceId}, 1, 1000000, false)
+		for _, d := range results {
+			domainTargetList = append(domainTargetList, d.DomainName)
+		}
+	case "workflow":
+		var req WorkflowRequestParam
+		if err := json.Unmarshal([]byte(task.KwArgs), &req); err != nil {
+			logging.RuntimeLog.Error(err)
+			return
+		}
+		workflow := db.Workflow{Id: req.WorkflowId}
+		if !workflow.Get() {
+			return
+		}
+		define, err := workerapi.ParseWorkflow(workflow.Definition)
+		if err != nil {
+			logging.RuntimeLog.Error(err)
+			return
+		}
+		// 只有以任务目标进行域名收集的起始阶段才有域名目标范围
+		var isDomainScan bool
+		for _, stage := range define.RootStages() {
+			switch stage.Task {
+			case "domainscan", "subdomaincrawler":
+				isDomainScan = true
+			case "subfinder", "subdomainbrute":
+				isDomainScan, isSubDomain = true, true
+			}
+		}
+		if isDomainScan {
+			domainTargetList = formatDomainTarget(req.Target)
+		}
+	}
 	return
 }
 
-// formatNotifyMessage 返回发送通知消息内容
-func formatNotifyMessage(taskId string) (message string) {
+// makeNotifyTaskMessage 生成任务通知模板使用的数据，taskObj为已从MainTaskResult中移除的任务结果
+func makeNotifyTaskMessage(taskId string, taskObj comm.MainTaskResultMap, exist bool) (data notify.TaskMessage, ok bool) {
 	task := db.TaskMain{TaskId: taskId}
 	if !task.GetByTaskId() {
 		return
 	}
-	var sb strings.Builder
-	sb.WriteString(task.TaskName)
-	sb.WriteString("->")
-	sb.WriteString("runtime:")
-	sb.WriteString(task.SucceededTime.Sub(*task.StartedTime).Truncate(time.Second).String())
-	sb.WriteString(",runtask:")
-	sb.WriteString(task.ProgressMessage)
-	sb.WriteString("  \n")
-	sb.WriteString("target->")
-	sb.WriteString(strings.ReplaceAll(ParseTargetFromKwArgs(task.TaskName, task.KwArgs), "\n", ","))
-	if len(task.Result) > 0 {
-		sb.WriteString("  \nresult->")
-		sb.WriteString(task.Result)
-	}
-	return sb.String()
+	data.Task = task
+	data.Target = strings.ReplaceAll(ParseTargetFromKwArgs(task.TaskName, task.KwArgs), "\n", ",")
+	if task.StartedTime != nil && task.SucceededTime != nil {
+		data.Runtime = task.SucceededTime.Sub(*task.StartedTime).Truncate(time.Second).String()
+	}
+	if exist {
+		data.Result = makeNotifyTaskResult(taskObj)
+		data.NewVulnerability = taskObj.VulnerabilityNewList
+	}
+	data.NewIP, data.NewPort, data.NewDomain = getMainTaskNewAsset(taskId)
+	if webURL := strings.TrimRight(conf.GlobalServerConfig().Web.URL, "/"); webURL != "" {
+		data.WebURL = webURL
+		data.URL = fmt.Sprintf("%s/task-info-main?task_id=%s", webURL, taskId)
+	}
+	return data, true
+}
+
+// makeNotifyTaskResult 统计任务结果的数量
+func makeNotifyTaskResult(taskObj comm.MainTaskResultMap) (r notify.TaskResult) {
+	r.IP = len(taskObj.IPResult)
+	for _, ports := range taskObj.IPResult {
+		r.Port += len(ports)
+	}
+	r.Domain = len(taskObj.DomainResult)
+	for _, vul := range taskObj.VulResult {
+		r.Vulnerability += len(vul)
+	}
+	r.Screenshot = taskObj.ScreenShotResult
+	r.IPNew = taskObj.IPNew
+	r.PortNew = taskObj.PortNew
+	r.DomainNew = taskObj.DomainNew
+	r.VulnerabilityNew = taskObj.VulnerabilityNew
+	return
+}
+
+// getMainTaskNewAsset 根据资产变更记录获取maintask新增的IP、端口及域名，每种最多MainTaskNewListMax条
+func getMainTaskNewAsset(taskId string) (ips, ports, domains []string) {
+	for _, tag := range []string{"ip", "port", "domain"} {
+		assetChange := db.AssetChange{}
+		searchMap := make(map[string]interface{})
+		searchMap["main_task_id"] = taskId
+		searchMap["change_type"] = db.AssetChangeAdded
+		searchMap["tag"] = tag
+		changes, _ := assetChange.Gets(searchMap, 1, comm.MainTaskNewListMax)
+		// 按时间倒序，转换为发现的顺序
+		for i := len(changes) - 1; i >= 0; i-- {
+			c := changes[i]
+			switch tag {
+			case "ip":
+				ips = append(ips, c.Asset)
+			case "port":
+				ports = append(ports, fmt.Sprintf("%s:%d", c.Asset, c.Port))
+			case "domain":
+				domains = append(domains, c.Asset)
+			}
+		}
+	}
+	return
 }
 
 // checkRunTask 根据maintaskId，获取runtask运行情况
@@ -250,7 +419,8 @@ func checkRunTask(taskId string) (createdTask, startedTask, totalTask int) {
 	searchMapRun["main_id"] = taskId
 	runTasks, _ := taskRun.Gets(searchMapRun, -1, -1)
 	for _, t := range runTasks {
-		if t.State == ampq.CREATED {
+		// 等待重试的任务也作为未开始执行的任务
+		if t.State == ampq.CREATED || t.State == ampq.RETRY {
 			createdTask++
 		} else if t.State == ampq.STARTED {
 			startedTask++
@@ -104,14 +104,17 @@ func StartDomainScanTask(req DomainscanRequestParam, mainTaskId string, workspac
 	}
 	ts.TaskMode = req.TaskMode
 	targets := ts.DoDomainSlice()
+	// NS的域传送及递归解析检查，每个目标只在第一个子任务中执行
+	isDNSCheck := conf.GlobalWorkerConfig().Domainscan.IsDNSCheck
 	for _, t := range targets {
 		// 每个获取子域名的方式采用独立任务，以提高速度
 		var taskStarted bool
 		if req.IsSubfinder {
 			subConfig := req
 			subConfig.IsSubdomainBrute = false
+			subConfig.IsSubdomainPermute = false
 			subConfig.IsCrawler = false
-			if taskId, err = doDomainscan(workspaceId, mainTaskId, t, subConfig, "subfinder"); err != nil {
+			if taskId, err = doDomainscan(workspaceId, mainTaskId, t, subConfig, "subfinder", isDNSCheck && !taskStarted); err != nil {
 				logging.RuntimeLog.Error(err)
 				return
 			}
@@ -120,8 +123,20 @@ func StartDomainScanTask(req DomainscanRequestParam, mainTaskId string, workspac
 		if req.IsSubdomainBrute {
 			subConfig := req
 			subConfig.IsSubfinder = false
+			subConfig.IsSubdomainPermute = false
 			subConfig.IsCrawler = false
-			if taskId, err = doDomainscan(workspaceId, mainTaskId, t, subConfig, "subdomainbrute"); err != nil {
+			if taskId, err = doDomainscan(workspaceId, mainTaskId, t, subConfig, "subdomainbrute", isDNSCheck && !taskStarted); err != nil {
+				logging.RuntimeLog.Error(err)
+				return
+			}
+			taskStarted = true
+		}
+		if req.IsSubdomainPermute {
+			subConfig := req
+			subConfig.IsSubfinder = false
+			subConfig.IsSubdomainBrute = false
+			subConfig.IsCrawler = false
+			if taskId, err = doDomainscan(workspaceId, mainTaskId, t, subConfig, "subdomainpermute", isDNSCheck && !taskStarted); err != nil {
 				logging.RuntimeLog.Error(err)
 				return
 			}
@@ -131,7 +146,8 @@ func StartDomainScanTask(req DomainscanRequestParam, mainTaskId string, workspac
 			subConfig := req
 			subConfig.IsSubfinder = false
 			subConfig.IsSubdomainBrute = false
-			if taskId, err = doDomainscan(workspaceId, mainTaskId, t, subConfig, "subdomaincrawler"); err != nil {
+			subConfig.IsSubdomainPermute = false
+			if taskId, err = doDomainscan(workspaceId, mainTaskId, t, subConfig, "subdomaincrawler", isDNSCheck && !taskStarted); err != nil {
 				logging.RuntimeLog.Error(err)
 				return
 			}
@@ -139,7 +155,7 @@ func StartDomainScanTask(req DomainscanRequestParam, mainTaskId string, workspac
 		}
 		// 如果没有子域名任务，则至少启动一个域名解析任务
 		if !taskStarted {
-			if taskId, err = doDomainscan(workspaceId, mainTaskId, t, req, "domainscan"); err != nil {
+			if taskId, err = doDomainscan(workspaceId, mainTaskId, t, req, "domainscan", isDNSCheck); err != nil {
 				logging.RuntimeLog.Error(err)
 				return
 			}
@@ -181,11 +197,9 @@ func StartDomainScanTask(req DomainscanRequestParam, mainTaskId string, workspac
 // StartPocScanTask pocscan任务
 func StartPocScanTask(req PocscanRequestParam, mainTaskId string, workspaceId int) (taskId string, err error) {
 	var targetList []string
-	for _, t := range strings.Split(req.Target, "\n") {
-		if tt := strings.TrimSpace(t); tt != "" {
-			targetList = append(targetList, tt)
-		}
-	}
+	scanTarget(req.Target, func(tt string) {
+		targetList = append(targetList, tt)
+	})
 	if req.IsXrayVerify && req.XrayPocFile != "" {
 		config := pocscan.Config{Target: strings.Join(targetList, ","), PocFile: req.XrayPocFile, CmdBin: "xray", IsLoadOpenedPort: req.IsLoadOpenedPort, WorkspaceId: workspaceId, IsProxy: req.IsProxy}
 		configJSON, _ := json.Marshal(config)
@@ -226,6 +240,7 @@ func StartXOnlineAPIKeywordTask(req XScanRequestParam, mainTaskId string, worksp
 		IsNucleiPoc:   req.IsNucleiPocscan,
 		NucleiPocFile: req.NucleiPocFile,
 		IsGobyPoc:     req.IsGobyPocscan,
+		IsDirscan:     req.IsDirscan,
 		WorkspaceId:   workspaceId,
 		IsProxy:       req.IsProxy,
 	}
@@ -243,8 +258,16 @@ func StartXOnlineAPIKeywordTask(req XScanRequestParam, mainTaskId string, worksp
 		config.IsHunter = true
 	case "xquake":
 		config.IsQuake = true
-	}
-	config.OnlineAPIKeyword = req.Target
+	case "xshodan":
+		config.IsShodan = true
+	case "xcensys":
+		config.IsCensys = true
+	case "xzoomeye":
+		config.IsZoomEye = true
+	case "xnetlas":
+		config.IsNetlas = true
+	}
+	config.OnlineAPIKeyword = loadTarget(req.Target)
 	config.OnlineAPISearchLimit = conf.GlobalWorkerConfig().API.SearchLimitCount
 	configJSONRun, _ := json.Marshal(config)
 	taskId, err = serverapi.NewRunTask(req.OnlineAPIEngine, string(configJSONRun), mainTaskId, "")
@@ -266,6 +289,7 @@ func StartXOnlineAPIKeywordCustomTask(req XScanRequestParam, mainTaskId string,
 		IsNucleiPoc:   req.IsNucleiPocscan,
 		NucleiPocFile: req.NucleiPocFile,
 		IsGobyPoc:     req.IsGobyPocscan,
+		IsDirscan:     req.IsDirscan,
 		WorkspaceId:   workspaceId,
 		IsProxy:       req.IsProxy,
 	}
@@ -277,31 +301,40 @@ func StartXOnlineAPIKeywordCustomTask(req XScanRequestParam, mainTaskId string,
 	configTaskRuns := makeSearchTaskConfig(config)
 	for _, configRun := range configTaskRuns {
 		configJSONRun, _ := json.Marshal(configRun)
-		if configRun.IsFofa {
-			taskId, err = serverapi.NewRunTask("xfofa", string(configJSONRun), mainTaskId, "")
-			if err != nil {
-				logging.RuntimeLog.Errorf("start xfofa task fail:%s", err.Error())
-				return "", err
-			}
-		}
-		if configRun.IsHunter {
-			taskId, err = serverapi.NewRunTask("xhunter", string(configJSONRun), mainTaskId, "")
-			if err != nil {
-				logging.RuntimeLog.Errorf("start xhunter task fail:%s", err.Error())
-				return "", err
-			}
+		taskName := getOnlineAPITaskName(configRun)
+		if taskName == "" {
+			continue
 		}
-		if configRun.IsQuake {
-			taskId, err = serverapi.NewRunTask("xquake", string(configJSONRun), mainTaskId, "")
-			if err != nil {
-				logging.RuntimeLog.Errorf("start xquake task fail:%s", err.Error())
-				return "", err
-			}
+		taskId, err = serverapi.NewRunTask(taskName, string(configJSONRun), mainTaskId, "")
+		if err != nil {
+			logging.RuntimeLog.Errorf("start %s task fail:%s", taskName, err.Error())
+			return "", err
 		}
 	}
 	return
 }
 
+// getOnlineAPITaskName 根据任务参数获取在线资产搜索的任务名称
+func getOnlineAPITaskName(config workerapi.XScanConfig) string {
+	switch {
+	case config.IsFofa:
+		return "xfofa"
+	case config.IsHunter:
+		return "xhunter"
+	case config.IsQuake:
+		return "xquake"
+	case config.IsShodan:
+		return "xshodan"
+	case config.IsCensys:
+		return "xcensys"
+	case config.IsZoomEye:
+		return "xzoomeye"
+	case config.IsNetlas:
+		return "xnetlas"
+	}
+	return ""
+}
+
 // StartXDomainScanTask xscan任务，域名任务
 func StartXDomainScanTask(req XScanRequestParam, mainTaskId string, workspaceId int) (taskId string, err error) {
 	config := workerapi.XScanConfig{
@@ -313,6 +346,7 @@ func StartXDomainScanTask(req XScanRequestParam, mainTaskId string, workspaceId
 		IsNucleiPoc:   req.IsNucleiPocscan,
 		NucleiPocFile: req.NucleiPocFile,
 		IsGobyPoc:     req.IsGobyPocscan,
+		IsDirscan:     req.IsDirscan,
 		//
 		WorkspaceId: workspaceId,
 		IsProxy:     req.IsProxy,
@@ -328,12 +362,16 @@ func StartXDomainScanTask(req XScanRequestParam, mainTaskId string, workspaceId
 		if utils.CheckIPV4(target) || utils.CheckIPV4Subnet(target) {
 			continue
 		}
+		// NS的域传送及递归解析检查只在第一个子域名任务中执行
+		isDNSCheck := conf.GlobalWorkerConfig().Domainscan.IsDNSCheck
 		// 子域名枚举、爆破、爬虫拆分成为多个任务并行执行
 		if conf.GlobalWorkerConfig().Domainscan.IsSubDomainFinder {
 			configRun := config
 			configRun.Domain = make(map[string]struct{})
 			configRun.Domain[target] = struct{}{}
 			configRun.IsSubDomainFinder = true
+			configRun.IsDNSCheck = isDNSCheck
+			isDNSCheck = false
 			configJSON, _ := json.Marshal(configRun)
 			taskId, err = serverapi.NewRunTask("xsubfinder", string(configJSON), mainTaskId, "")
 			if err != nil {
@@ -346,6 +384,8 @@ func StartXDomainScanTask(req XScanRequestParam, mainTaskId string, workspaceId
 			configRun.Domain = make(map[string]struct{})
 			configRun.Domain[target] = struct{}{}
 			configRun.IsSubDomainBrute = true
+			configRun.IsDNSCheck = isDNSCheck
+			isDNSCheck = false
 			configJSON, _ := json.Marshal(configRun)
 			taskId, err = serverapi.NewRunTask("xsubdomainbrute", string(configJSON), mainTaskId, "")
 			if err != nil {
@@ -358,6 +398,8 @@ func StartXDomainScanTask(req XScanRequestParam, mainTaskId string, workspaceId
 			configRun.Domain = make(map[string]struct{})
 			configRun.Domain[target] = struct{}{}
 			configRun.IsSubDomainCrawler = true
+			configRun.IsDNSCheck = isDNSCheck
+			isDNSCheck = false
 			configJSON, _ := json.Marshal(configRun)
 			taskId, err = serverapi.NewRunTask("xsubdomaincrawler", string(configJSON), mainTaskId, "")
 			if err != nil {
@@ -430,6 +472,7 @@ func StartXPortScanTask(req XScanRequestParam, mainTaskId string, workspaceId in
 		XrayPocFile:   req.XrayPocFile,
 		IsNucleiPoc:   req.IsNucleiPocscan,
 		IsGobyPoc:     req.IsGobyPocscan,
+		IsDirscan:     req.IsDirscan,
 		NucleiPocFile: req.NucleiPocFile,
 		WorkspaceId:   workspaceId,
 		//
@@ -512,6 +555,7 @@ func StartXOrgScanTask(req XScanRequestParam, mainTaskId string, workspaceId int
 		IsNucleiPoc:   req.IsNucleiPocscan,
 		NucleiPocFile: req.NucleiPocFile,
 		IsGobyPoc:     req.IsGobyPocscan,
+		IsDirscan:     req.IsDirscan,
 		//
 		WorkspaceId: workspaceId,
 		IsProxy:     req.IsProxy,
@@ -630,12 +674,13 @@ func doBatchScan(workspaceId int, mainTaskId string, target string, port string,
 }
 
 // doDomainscan 域名任务
-func doDomainscan(workspaceId int, mainTaskId string, target string, req DomainscanRequestParam, taskName string) (taskId string, err error) {
+func doDomainscan(workspaceId int, mainTaskId string, target string, req DomainscanRequestParam, taskName string, isDNSCheck bool) (taskId string, err error) {
 	config := domainscan.Config{
 		Target:             target,
 		OrgId:              &req.OrgId,
 		IsSubDomainFinder:  req.IsSubfinder,
 		IsSubDomainBrute:   req.IsSubdomainBrute,
+		IsSubDomainPermute: req.IsSubdomainPermute,
 		IsCrawler:          req.IsCrawler,
 		IsHttpx:            req.IsHttpx,
 		IsIPPortScan:       req.IsIPPortscan,
@@ -647,6 +692,7 @@ func doDomainscan(workspaceId int, mainTaskId string, target string, req Domains
 		PortTaskMode:       req.PortTaskMode,
 		WorkspaceId:        workspaceId,
 		IsProxy:            req.IsProxy,
+		IsDNSCheck:         isDNSCheck,
 	}
 	// config.OrgId 为int，默认为0
 	// db.Organization.OrgId为指针，默认nil
@@ -770,73 +816,69 @@ func getDomainFLD(domainTargetList []string) (fldDomain []string) {
 	return
 }
 
-// formatIpTarget 将从web端传入的ip参数（以\n分隔）转换为ip列表，对域名进行解析转换为，并保存域名及A记录到数据库中
+// formatIpTarget 将从web端传入的ip参数（以\n分隔或目标集合）逐行转换为ip列表，对域名进行解析转换为，并保存域名及A记录到数据库中
 func formatIpTarget(target string, orgId int) (ipTargetList []string) {
-	for _, t := range strings.Split(target, "\n") {
-		if tt := strings.TrimSpace(t); tt != "" {
-			//192.168.1.1  192.168.1.0/24及ipv6
-			if utils.CheckIPOrSubnet(tt) {
-				ipTargetList = append(ipTargetList, tt)
-				continue
-			}
-			//192.168.1.1-192.168.1.5及ipv6
-			address := strings.Split(tt, "-")
-			if len(address) == 2 && utils.CheckIP(address[0]) && utils.CheckIP(address[1]) {
-				ipTargetList = append(ipTargetList, tt)
-				continue
-			}
-			//域名，将域名转成ip地址
-			_, hosts := domainscan.ResolveDomain(tt)
-			if len(hosts) == 0 {
-				continue
+	scanTarget(target, func(tt string) {
+		//192.168.1.1  192.168.1.0/24及ipv6
+		if utils.CheckIPOrSubnet(tt) {
+			ipTargetList = append(ipTargetList, tt)
+			return
+		}
+		//192.168.1.1-192.168.1.5及ipv6
+		address := strings.Split(tt, "-")
+		if len(address) == 2 && utils.CheckIP(address[0]) && utils.CheckIP(address[1]) {
+			ipTargetList = append(ipTargetList, tt)
+			return
+		}
+		//域名，将域名转成ip地址
+		_, hosts := domainscan.ResolveDomain(tt)
+		if len(hosts) == 0 {
+			return
+		}
+		domainResult := domainscan.Result{DomainResult: make(map[string]*domainscan.DomainResult)}
+		domainResult.SetDomain(tt)
+		for _, h := range hosts {
+			dar := domainscan.DomainAttrResult{
+				Source:  "portscan",
+				Content: h,
 			}
-			domainResult := domainscan.Result{DomainResult: make(map[string]*domainscan.DomainResult)}
-			domainResult.SetDomain(tt)
-			for _, h := range hosts {
-				dar := domainscan.DomainAttrResult{
-					Source:  "portscan",
-					Content: h,
-				}
-				if utils.CheckIPV4(h) {
-					dar.Tag = "A"
-				} else if utils.CheckIPV6(h) {
-					dar.Tag = "AAAA"
-					dar.Content = utils.GetIPV6ParsedFormat(h)
-				}
-				if dar.Tag == "A" || dar.Tag == "AAAA" {
-					ipTargetList = append(ipTargetList, h)
-					domainResult.SetDomainAttr(tt, dar)
-				}
+			if utils.CheckIPV4(h) {
+				dar.Tag = "A"
+			} else if utils.CheckIPV6(h) {
+				dar.Tag = "AAAA"
+				dar.Content = utils.GetIPV6ParsedFormat(h)
 			}
-			config := domainscan.Config{OrgId: &orgId}
-			// config.OrgId 为int，默认为0
-			// db.Organization.OrgId为指针，默认nil
-			if *config.OrgId == 0 {
-				config.OrgId = nil
+			if dar.Tag == "A" || dar.Tag == "AAAA" {
+				ipTargetList = append(ipTargetList, h)
+				domainResult.SetDomainAttr(tt, dar)
 			}
-			domainResult.SaveResult(config)
 		}
-	}
+		config := domainscan.Config{OrgId: &orgId}
+		// config.OrgId 为int，默认为0
+		// db.Organization.OrgId为指针，默认nil
+		if *config.OrgId == 0 {
+			config.OrgId = nil
+		}
+		domainResult.SaveResult(config)
+	})
 
 	return
 }
 
-// formatDomainTarget 将前端web的域名，转换为列表；同时去除非域名的IP地址
+// formatDomainTarget 将前端web的域名（以\n分隔或目标集合）逐行转换为列表；同时去除非域名的IP地址
 func formatDomainTarget(target string) (domainTargetList []string) {
-	for _, t := range strings.Split(target, "\n") {
-		if tt := strings.TrimSpace(t); tt != "" {
-			//192.168.1.1  192.168.1.0/24
-			if utils.CheckIPV4(tt) || utils.CheckIPV4Subnet(tt) {
-				continue
-			}
-			//192.168.1.1-192.168.1.5
-			address := strings.Split(tt, "-")
-			if len(address) == 2 && utils.CheckIPV4(address[0]) && utils.CheckIPV4(address[1]) {
-				continue
-			}
-			domainTargetList = append(domainTargetList, tt)
+	scanTarget(target, func(tt string) {
+		//192.168.1.1  192.168.1.0/24
+		if utils.CheckIPV4(tt) || utils.CheckIPV4Subnet(tt) {
+			return
 		}
-	}
+		//192.168.1.1-192.168.1.5
+		address := strings.Split(tt, "-")
+		if len(address) == 2 && utils.CheckIPV4(address[0]) && utils.CheckIPV4(address[1]) {
+			return
+		}
+		domainTargetList = append(domainTargetList, tt)
+	})
 	return
 }
 
@@ -912,7 +954,7 @@ func ParseTargetFromKwArgs(taskName, args string) (target string) {
 				}
 			}
 			if len(t.Target) > 0 {
-				allTarget = append(allTarget, t.Target)
+				allTarget = append(allTarget, formatTargetSetDisplay(t.Target))
 			}
 			if taskName == "xorgscan" || taskName == "xonlineapi_custom" {
 				orgDb := db.Organization{Id: *t.OrgId}
@@ -928,7 +970,7 @@ func ParseTargetFromKwArgs(taskName, args string) (target string) {
 		if err != nil {
 			target = args
 		} else {
-			target = t.Target
+			target = formatTargetSetDisplay(t.Target)
 		}
 	}
 	if len(target) > displayedLength {
@@ -938,6 +980,9 @@ func ParseTargetFromKwArgs(taskName, args string) (target string) {
 	return
 }
 
+// onlineAPIKeywordEngines 关键词选择全部搜索引擎时使用的搜索引擎
+var onlineAPIKeywordEngines = []string{"xfofa", "xhunter", "xquake", "xshodan", "xcensys", "xzoomeye", "xnetlas"}
+
 func makeSearchTaskConfig(config workerapi.XScanConfig) (configs []workerapi.XScanConfig) {
 	keyWords := db.KeyWord{}
 	//传入org_id
@@ -959,34 +1004,98 @@ func makeSearchTaskConfig(config workerapi.XScanConfig) (configs []workerapi.XSc
 		kw.Update(updateMap)
 		// 根据API生成任务
 		engines := strings.Split(row.Engine, ",")
+		if strings.Contains(fmt.Sprintf(",%s,", row.Engine), ",all,") {
+			engines = onlineAPIKeywordEngines
+		}
 		for _, api := range engines {
-			var engineInterface onlineapi.Engine
+			engineInterface := newKeywordEngine(api)
+			if engineInterface == nil {
+				continue
+			}
 			configRun := config
-			if api == "xfofa" {
+			switch api {
+			case "xfofa":
 				configRun.IsFofa = true
-				engineInterface = new(onlineapi.FOFA)
-			} else if api == "xhunter" {
+			case "xhunter":
 				configRun.IsHunter = true
-				engineInterface = new(onlineapi.Hunter)
-			} else if api == "xquake" {
+			case "xquake":
 				configRun.IsQuake = true
-				engineInterface = new(onlineapi.Quake)
+			case "xshodan":
+				configRun.IsShodan = true
+			case "xcensys":
+				configRun.IsCensys = true
+			case "xzoomeye":
+				configRun.IsZoomEye = true
+			case "xnetlas":
+				configRun.IsNetlas = true
 			}
-			configRun.OnlineAPIKeyword = makeSearchKeyword(engineInterface, api, row.CheckMod, row.KeyWord, row.ExcludeWords, row.SearchTime)
+			searchKeyword, err := makeSearchKeyword(engineInterface, api, row.CheckMod, row.KeyWord, row.ExcludeWords, row.SearchTime)
+			if err != nil {
+				logging.RuntimeLog.Warningf("keyword %d skip %s:%v", row.Id, api, err)
+				continue
+			}
+			configRun.OnlineAPIKeyword = searchKeyword
 			configRun.OnlineAPISearchLimit = row.Count
 			configRun.OnlineAPIStartTime = row.SearchTime
+			configRun.OnlineAPIKeywordId = row.Id
 			configs = append(configs, configRun)
 		}
 	}
 	return
 }
 
-func makeSearchKeyword(engine onlineapi.Engine, engineName, checkMod, keyword, excludeWord, searchTime string) string {
+// newKeywordEngine 根据关键词任务的名称创建搜索引擎，不支持的返回nil
+func newKeywordEngine(api string) onlineapi.Engine {
+	switch api {
+	case "xfofa":
+		return new(onlineapi.FOFA)
+	case "xhunter":
+		return new(onlineapi.Hunter)
+	case "xquake":
+		return new(onlineapi.Quake)
+	case "xshodan":
+		return new(onlineapi.Shodan)
+	case "xcensys":
+		return new(onlineapi.Censys)
+	case "xzoomeye":
+		return new(onlineapi.ZoomEye)
+	case "xnetlas":
+		return new(onlineapi.Netlas)
+	}
+	return nil
+}
+
+// CheckKeywordQuery 检查统一语法的关键词能否转换为每个选择的搜索引擎的查询语法，engine为逗号分隔的任务名称或all
+func CheckKeywordQuery(engine, query string) error {
+	engines := strings.Split(engine, ",")
+	if strings.Contains(fmt.Sprintf(",%s,", engine), ",all,") {
+		engines = onlineAPIKeywordEngines
+	}
+	for _, api := range engines {
+		engineInterface := newKeywordEngine(api)
+		if engineInterface == nil {
+			continue
+		}
+		if _, err := onlineapi.CompileQuery(engineInterface, query); err != nil {
+			return fmt.Errorf("%s:%v", strings.TrimPrefix(api, "x"), err)
+		}
+	}
+	return nil
+}
+
+// makeSearchKeyword 生成搜索引擎的查询语法，统一语法中有搜索引擎不支持的条件时返回错误
+func makeSearchKeyword(engine onlineapi.Engine, engineName, checkMod, keyword, excludeWord, searchTime string) (string, error) {
 	syntaxMap := engine.GetSyntaxMap()
 	//关键字
 	var result []string
 	if checkMod == "self" {
 		result = append(result, fmt.Sprintf("(%s)", keyword))
+	} else if checkMod == "query" {
+		query, err := onlineapi.CompileQuery(engine, keyword)
+		if err != nil {
+			return "", err
+		}
+		result = append(result, fmt.Sprintf("(%s)", query))
 	} else {
 		var cm onlineapi.SyntaxType
 		switch checkMod {
@@ -1011,5 +1120,5 @@ func makeSearchKeyword(engine onlineapi.Engine, engineName, checkMod, keyword, e
 		}
 		result = append(result, fmt.Sprintf("(%s)", strings.Join(rule, fmt.Sprintf(" %s ", syntaxMap[onlineapi.And]))))
 	}
-	return strings.Join(result, fmt.Sprintf(" %s ", syntaxMap[onlineapi.And]))
+	return strings.Join(result, fmt.Sprintf(" %s ", syntaxMap[onlineapi.And])), nil
 }
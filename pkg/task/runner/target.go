@@ -0,0 +1,129 @@
+package runner
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/hanc00l/nemo_go/pkg/db"
+	"github.com/hanc00l/nemo_go/pkg/logging"
+	"strings"
+)
+
+const (
+	// TargetSetPrefix 任务参数中对目标集合的引用前缀
+	TargetSetPrefix = "targetset:"
+	// kwArgsMaxLength 任务参数在数据库中保存的最大长度，超过时将目标转存到目标集合中
+	kwArgsMaxLength = 6000
+	// targetKey 任务参数中目标的字段名
+	targetKey = "Target"
+)
+
+// IsTargetSet 检查目标是否为目标集合的引用
+func IsTargetSet(target string) bool {
+	return strings.HasPrefix(target, TargetSetPrefix)
+}
+
+// saveTargetSet 任务参数超过长度时，将参数中的目标保存为目标集合，参数中只保留对目标集合的引用
+func saveTargetSet(kwArgs string, workspaceId int) (string, error) {
+	if len(kwArgs) <= kwArgsMaxLength {
+		return kwArgs, nil
+	}
+	var args map[string]interface{}
+	decoder := json.NewDecoder(strings.NewReader(kwArgs))
+	decoder.UseNumber()
+	if err := decoder.Decode(&args); err != nil {
+		return "", err
+	}
+	target, ok := args[targetKey].(string)
+	if !ok || target == "" || IsTargetSet(target) {
+		return "", errors.New(fmt.Sprintf("arguments too long:%d", len(kwArgs)))
+	}
+	targetCount := 0
+	scanTargetLines(target, func(string) {
+		targetCount++
+	})
+	taskTarget := db.TaskTarget{
+		TargetId:    uuid.New().String(),
+		Target:      target,
+		TargetCount: targetCount,
+		WorkspaceId: workspaceId,
+	}
+	if !taskTarget.Add() {
+		return "", errors.New("save task target fail")
+	}
+	args[targetKey] = TargetSetPrefix + taskTarget.TargetId
+	kwArgsJSON, err := json.Marshal(args)
+	if err != nil {
+		return "", err
+	}
+	if len(kwArgsJSON) > kwArgsMaxLength {
+		taskTarget.DeleteByTargetId()
+		return "", errors.New(fmt.Sprintf("arguments too long:%d", len(kwArgsJSON)))
+	}
+	return string(kwArgsJSON), nil
+}
+
+// DeleteTargetSet 删除任务参数中引用的目标集合
+func DeleteTargetSet(kwArgs string) bool {
+	var args map[string]interface{}
+	if err := json.Unmarshal([]byte(kwArgs), &args); err != nil {
+		return false
+	}
+	target, ok := args[targetKey].(string)
+	if !ok || !IsTargetSet(target) {
+		return false
+	}
+	taskTarget := db.TaskTarget{TargetId: strings.TrimPrefix(target, TargetSetPrefix)}
+	return taskTarget.DeleteByTargetId()
+}
+
+// getTargetSetCount 获取目标集合的目标数量
+func getTargetSetCount(target string) (count int, ok bool) {
+	taskTarget := db.TaskTarget{TargetId: strings.TrimPrefix(target, TargetSetPrefix)}
+	if !taskTarget.GetCountByTargetId() {
+		return 0, false
+	}
+	return taskTarget.TargetCount, true
+}
+
+// formatTargetSetDisplay 目标集合的引用显示为目标集合的目标数量
+func formatTargetSetDisplay(target string) string {
+	if !IsTargetSet(target) {
+		return target
+	}
+	if count, ok := getTargetSetCount(target); ok {
+		return fmt.Sprintf("目标集合(%d个目标)", count)
+	}
+	return target
+}
+
+// loadTarget 获取任务的目标：如果是目标集合的引用，则从数据库中读取目标集合
+func loadTarget(target string) string {
+	if !IsTargetSet(target) {
+		return target
+	}
+	taskTarget := db.TaskTarget{TargetId: strings.TrimPrefix(target, TargetSetPrefix)}
+	if !taskTarget.GetByTargetId() {
+		logging.RuntimeLog.Errorf("task target:%s not exist", target)
+		return ""
+	}
+	return taskTarget.Target
+}
+
+// scanTarget 逐行读取任务的目标（以\n分隔），忽略空行
+func scanTarget(target string, f func(line string)) {
+	scanTargetLines(loadTarget(target), f)
+}
+
+// scanTargetLines 逐行读取目标，忽略空行
+func scanTargetLines(target string, f func(line string)) {
+	scanner := bufio.NewScanner(strings.NewReader(target))
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), len(target)+1)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			f(line)
+		}
+	}
+}
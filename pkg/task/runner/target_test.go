@@ -0,0 +1,44 @@
+package runner
+
+import (
+	"testing"
+)
+
+func TestScanTargetLines(t *testing.T) {
+	var lines []string
+	scanTargetLines("192.168.1.1\r\n\n  www.example.com \n192.168.1.0/24", func(line string) {
+		lines = append(lines, line)
+	})
+	expected := []string{"192.168.1.1", "www.example.com", "192.168.1.0/24"}
+	if len(lines) != len(expected) {
+		t.Fatalf("invalid lines:%v", lines)
+	}
+	for i := range expected {
+		if lines[i] != expected[i] {
+			t.Errorf("expect %s,got %s", expected[i], lines[i])
+		}
+	}
+}
+
+func TestSaveTargetSet(t *testing.T) {
+	kwArgs := `{"Target":"192.168.1.1\n192.168.1.2","OrgId":0}`
+	result, err := saveTargetSet(kwArgs, 1)
+	if err != nil || result != kwArgs {
+		t.Errorf("short kwargs should not be changed:%s,%v", result, err)
+	}
+	if formatTargetSetDisplay("192.168.1.1") != "192.168.1.1" {
+		t.Error("invalid target display")
+	}
+}
+
+func TestCheckKeywordQuery(t *testing.T) {
+	if err := CheckKeywordQuery("xfofa,xquake", `title="a" && domain="example.com"`); err != nil {
+		t.Errorf("query should be supported:%v", err)
+	}
+	if err := CheckKeywordQuery("xfofa,xhunter", `icon_hash="-247388890"`); err == nil {
+		t.Error("icon_hash should not be supported by hunter")
+	}
+	if err := CheckKeywordQuery("all", `org="example"`); err == nil {
+		t.Error("org should not be supported by netlas")
+	}
+}
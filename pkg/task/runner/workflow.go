@@ -0,0 +1,93 @@
+package runner
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/hanc00l/nemo_go/pkg/conf"
+	"github.com/hanc00l/nemo_go/pkg/db"
+	"github.com/hanc00l/nemo_go/pkg/logging"
+	"github.com/hanc00l/nemo_go/pkg/task/serverapi"
+	"github.com/hanc00l/nemo_go/pkg/task/workerapi"
+	"github.com/hanc00l/nemo_go/pkg/utils"
+)
+
+// StartWorkflowTask 工作流任务：生成工作流起始阶段的任务，后续阶段由worker根据工作流定义及上一阶段的结果依次生成
+func StartWorkflowTask(req WorkflowRequestParam, mainTaskId string, workspaceId int) (taskId string, err error) {
+	workflow := db.Workflow{Id: req.WorkflowId}
+	if !workflow.Get() {
+		return "", fmt.Errorf("workflow:%d not exist", req.WorkflowId)
+	}
+	define, err := workerapi.ParseWorkflow(workflow.Definition)
+	if err != nil {
+		logging.RuntimeLog.Errorf("parse workflow %s fail:%v", workflow.Name, err)
+		return "", err
+	}
+	config := workerapi.XScanConfig{
+		OrgId:       &req.OrgId,
+		WorkspaceId: workspaceId,
+		IsProxy:     req.IsProxy,
+	}
+	// config.OrgId 为int，默认为0
+	// db.Organization.OrgId为指针，默认nil
+	if *config.OrgId == 0 {
+		config.OrgId = nil
+	}
+	for _, stage := range define.RootStages() {
+		stageConfig := define.MakeStageConfig(stage, config)
+		for _, configRun := range makeWorkflowRootTarget(stage, stageConfig, req) {
+			configJSON, _ := json.Marshal(configRun)
+			taskId, err = serverapi.NewRunTask(stage.TaskName(), string(configJSON), mainTaskId, "")
+			if err != nil {
+				logging.RuntimeLog.Errorf("start workflow %s stage %s fail:%s", define.Name, stage.Name, err.Error())
+				return "", err
+			}
+		}
+	}
+	return
+}
+
+// makeWorkflowRootTarget 根据任务的目标，拆分生成起始阶段的子任务
+func makeWorkflowRootTarget(stage workerapi.WorkflowStage, config workerapi.XScanConfig, req WorkflowRequestParam) (configs []workerapi.XScanConfig) {
+	switch stage.Task {
+	case "portscan":
+		port := stage.Options["port"]
+		if port == "" {
+			port = req.Port
+		}
+		if port == "" {
+			port = conf.GlobalWorkerConfig().Portscan.Port
+		}
+		ts := utils.NewTaskSlice()
+		ts.TaskMode = utils.SliceByIP
+		ts.IpTarget = formatIpTarget(req.Target, req.OrgId)
+		ts.Port = port
+		ts.IpSliceNumber = conf.GlobalServerConfig().Task.IpSliceNumber
+		targets, _ := ts.DoIpSlice()
+		for _, t := range targets {
+			configRun := config
+			configRun.IPPortString = map[string]string{t: port}
+			configs = append(configs, configRun)
+		}
+	case "fofa", "hunter", "quake", "shodan", "censys", "zoomeye", "netlas":
+		// 指定了查询语法则按语法查询，否则按目标查询
+		if keyword := stage.Options["keyword"]; keyword != "" {
+			configRun := config
+			configRun.OnlineAPIKeyword = keyword
+			configRun.OnlineAPISearchLimit = conf.GlobalWorkerConfig().API.SearchLimitCount
+			configs = append(configs, configRun)
+			return
+		}
+		scanTarget(req.Target, func(tt string) {
+			configRun := config
+			configRun.OnlineAPITarget = tt
+			configs = append(configs, configRun)
+		})
+	default:
+		for _, domain := range formatDomainTarget(req.Target) {
+			configRun := config
+			configRun.Domain = map[string]struct{}{domain: {}}
+			configs = append(configs, configRun)
+		}
+	}
+	return
+}
@@ -64,8 +64,8 @@ func RevokeUnexcusedTask(taskId string) (isRevoked bool, err error) {
 		logging.RuntimeLog.Warningf("task not exists when revoked:%s", taskId)
 		return false, errors.New("task not exists")
 	}
-	//检查状态，只有CREATED状态的才能取消
-	if task.State == ampq.CREATED {
+	//检查状态，只有CREATED或等待重试的任务才能取消
+	if task.State == ampq.CREATED || task.State == ampq.RETRY {
 		updateRevokedTask(taskId)
 		logging.RuntimeLog.Infof("task revoked:%s", taskId)
 		return true, nil
@@ -24,11 +24,16 @@ var taskMaps = map[string]interface{}{
 	"domainscan":        DomainScan,
 	"subfinder":         DomainScan,
 	"subdomainbrute":    DomainScan,
+	"subdomainpermute":  DomainScan,
 	"subdomaincrawler":  DomainScan,
 	"iplocation":        IPLocation,
 	"fofa":              Fofa,
 	"quake":             Quake,
 	"hunter":            Hunter,
+	"shodan":            Shodan,
+	"censys":            Censys,
+	"zoomeye":           ZoomEye,
+	"netlas":            Netlas,
 	"xray":              PocScan,
 	"nuclei":            PocScan,
 	"goby":              PocScan,
@@ -40,6 +45,10 @@ var taskMaps = map[string]interface{}{
 	"xfofa":             XOnlineAPI,
 	"xquake":            XOnlineAPI,
 	"xhunter":           XOnlineAPI,
+	"xshodan":           XOnlineAPI,
+	"xcensys":           XOnlineAPI,
+	"xzoomeye":          XOnlineAPI,
+	"xnetlas":           XOnlineAPI,
 	"xdomainscan":       XDomainscan,
 	"xsubfinder":        XDomainscan,
 	"xsubdomainbrute":   XDomainscan,
@@ -49,6 +58,7 @@ var taskMaps = map[string]interface{}{
 	"xnuclei":           XNuclei,
 	"xgoby":             XGoby,
 	"xorgscan":          XOrganization,
+	"dirscan":           DirScan,
 	//test:
 	"test": TaskTest,
 }
@@ -86,7 +96,7 @@ func ParseConfig(configJSON string, config interface{}) (err error) {
 // StartWorker 启动worker
 func StartWorker(topicName string, concurrency int) error {
 	server := ampq.GetWorkerAMPQServer(topicName, concurrency)
-	err := server.RegisterTasks(taskMaps)
+	err := server.RegisterTasks(makeRetryTaskMaps(taskMaps))
 	if err != nil {
 		logging.RuntimeLog.Error(err)
 		return err
@@ -113,6 +123,11 @@ func postTaskHandler(signature *tasks.Signature) {
 	WStatus.TaskStartedNumber--
 	WStatus.Unlock()
 
+	//任务已重新投递到队列中等待重试：不需要等待任务的结果
+	if retryResult, ok := getRetryResult(signature); ok {
+		UpdateTaskStatus(signature.UUID, ampq.RETRY, WStatus.WorkerName, retryResult)
+		return
+	}
 	//log.INFO.Println("I am an end of task handler for:", signature.Name)
 	server := ampq.GetWorkerAMPQServer(ampq.GetTopicByMQRoutingKey(signature.RoutingKey), 3)
 	r := result.NewAsyncResult(signature, server.GetBackend())
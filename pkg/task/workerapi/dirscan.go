@@ -0,0 +1,107 @@
+package workerapi
+
+import (
+	"encoding/json"
+	"github.com/hanc00l/nemo_go/pkg/comm"
+	"github.com/hanc00l/nemo_go/pkg/logging"
+	"github.com/hanc00l/nemo_go/pkg/task/dirscan"
+	"github.com/hanc00l/nemo_go/pkg/task/domainscan"
+	"github.com/hanc00l/nemo_go/pkg/task/fingerprint"
+	"github.com/hanc00l/nemo_go/pkg/task/portscan"
+	"sort"
+	"strings"
+)
+
+const (
+	UrlNumberPerDirscanTask = 5
+)
+
+// DirScan 目录扫描任务
+func DirScan(taskId, mainTaskId, configJSON string) (result string, err error) {
+	var ok bool
+	if ok, result, err = CheckTaskStatus(taskId); !ok {
+		return result, err
+	}
+	config := dirscan.Config{}
+	if err = ParseConfig(configJSON, &config); err != nil {
+		logging.RuntimeLog.Error(err)
+		return FailedTask(err.Error()), err
+	}
+	d := dirscan.NewDirScan(config)
+	if err = d.LoadOption(); err != nil {
+		logging.RuntimeLog.Error(err)
+		return FailedTask(err.Error()), err
+	}
+	d.Do()
+	// 保存结果
+	resultArgs := comm.DirscanResultArgs{
+		TaskID:     taskId,
+		MainTaskId: mainTaskId,
+		Config:     config,
+		UrlResult:  d.Result.UrlResult,
+	}
+	err = comm.CallXClient("SaveDirscanResult", &resultArgs, &result)
+	if err != nil {
+		logging.RuntimeLog.Error(err)
+		return FailedTask(err.Error()), err
+	}
+
+	return SucceedTask(result), nil
+}
+
+// MakeDirscanTarget 从指纹识别结果中获取httpx识别的URL
+func MakeDirscanTarget(portScanResult *portscan.Result, domainScanResult *domainscan.Result) (urls []string) {
+	urlMap := make(map[string]struct{})
+	parseUrl := func(source, tag, content string) {
+		if source != "httpx" || tag != "httpx" {
+			return
+		}
+		var httpxResult fingerprint.HttpxResult
+		if err := json.Unmarshal([]byte(content), &httpxResult); err != nil || httpxResult.Url == "" {
+			return
+		}
+		urlMap[strings.TrimSuffix(httpxResult.Url, "/")] = struct{}{}
+	}
+	if portScanResult != nil {
+		for _, ipr := range portScanResult.IPResult {
+			for _, pr := range ipr.Ports {
+				for _, attr := range pr.PortAttrs {
+					parseUrl(attr.Source, attr.Tag, attr.Content)
+				}
+			}
+		}
+	}
+	if domainScanResult != nil {
+		for _, dr := range domainScanResult.DomainResult {
+			for _, attr := range dr.DomainAttrs {
+				parseUrl(attr.Source, attr.Tag, attr.Content)
+			}
+		}
+	}
+	for u := range urlMap {
+		urls = append(urls, u)
+	}
+	sort.Strings(urls)
+	return
+}
+
+// NewDirscanTask 根据URL列表，按拆分规模生成目录扫描子任务
+func NewDirscanTask(taskId, mainTaskId string, urls []string, workspaceId int, isProxy bool) (result string, err error) {
+	for i := 0; i < len(urls); i += UrlNumberPerDirscanTask {
+		end := i + UrlNumberPerDirscanTask
+		if end > len(urls) {
+			end = len(urls)
+		}
+		config := dirscan.Config{
+			Target:      strings.Join(urls[i:end], ","),
+			WorkspaceId: workspaceId,
+			IsProxy:     isProxy,
+		}
+		result, err = sendTask(taskId, mainTaskId, config, "dirscan")
+		if err != nil {
+			logging.RuntimeLog.Error(err)
+			return
+		}
+	}
+	return
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/hanc00l/nemo_go/pkg/logging"
 	"github.com/hanc00l/nemo_go/pkg/task/custom"
 	"github.com/hanc00l/nemo_go/pkg/task/domainscan"
+	"github.com/hanc00l/nemo_go/pkg/task/pocscan"
 	"github.com/hanc00l/nemo_go/pkg/task/portscan"
 	"github.com/hanc00l/nemo_go/pkg/utils"
 	"net/netip"
@@ -26,17 +27,18 @@ func DomainScan(taskId, mainTaskId, configJSON string) (result string, err error
 		logging.RuntimeLog.Error(err)
 		return FailedTask(err.Error()), err
 	}
-	resultDomainScan := doDomainScan(config)
+	resultDomainScan, resultVul := doDomainScan(config)
 	// 如果有端口扫描的选项
 	if config.IsIPPortScan || config.IsIPSubnetPortScan {
 		doPortScanByDomainscan(taskId, mainTaskId, config, resultDomainScan)
 	}
 	// 保存结果
 	resultArgs := comm.ScanResultArgs{
-		TaskID:       taskId,
-		MainTaskId:   mainTaskId,
-		DomainConfig: &config,
-		DomainResult: resultDomainScan.DomainResult,
+		TaskID:              taskId,
+		MainTaskId:          mainTaskId,
+		DomainConfig:        &config,
+		DomainResult:        resultDomainScan.DomainResult,
+		VulnerabilityResult: resultVul,
 	}
 	err = comm.CallXClient("SaveScanResult", &resultArgs, &result)
 	if err != nil {
@@ -59,8 +61,8 @@ func DomainScan(taskId, mainTaskId, configJSON string) (result string, err error
 	return SucceedTask(result), nil
 }
 
-// doDomainScan 域名收集任务
-func doDomainScan(config domainscan.Config) (resultDomainScan *domainscan.Result) {
+// doDomainScan 域名收集任务，返回域名结果及DNS检查发现的漏洞
+func doDomainScan(config domainscan.Config) (resultDomainScan *domainscan.Result, resultVul []pocscan.Result) {
 	// 子域名枚举
 	if config.IsSubDomainFinder {
 		subdomain := domainscan.NewSubFinder(config)
@@ -68,10 +70,20 @@ func doDomainScan(config domainscan.Config) (resultDomainScan *domainscan.Result
 		resultDomainScan = &subdomain.Result
 	}
 	// 子域名爆破
+	var wildcard *domainscan.WildcardFilter
 	if config.IsSubDomainBrute {
 		massdns := domainscan.NewMassdns(config)
 		massdns.Do()
 		resultDomainScan = &massdns.Result
+		wildcard = massdns.Wildcard
+	}
+	// 已有子域名的变换
+	if config.IsSubDomainPermute {
+		permute := domainscan.NewPermute(config)
+		permute.KnownDomains = loadSubDomain(config)
+		permute.Do()
+		resultDomainScan = &permute.Result
+		wildcard = permute.Wildcard
 	}
 	//  Crawler
 	if config.IsCrawler {
@@ -81,7 +93,7 @@ func doDomainScan(config domainscan.Config) (resultDomainScan *domainscan.Result
 	}
 	// 域名解析
 	resolve := domainscan.NewResolve(config)
-	if !config.IsSubDomainFinder && !config.IsSubDomainBrute && !config.IsCrawler {
+	if !config.IsSubDomainFinder && !config.IsSubDomainBrute && !config.IsSubDomainPermute && !config.IsCrawler {
 		// 对config中Target进行域名解析
 		resolve.Do()
 		resultDomainScan = &resolve.Result
@@ -90,12 +102,34 @@ func doDomainScan(config domainscan.Config) (resultDomainScan *domainscan.Result
 		resolve.Result.DomainResult = resultDomainScan.DomainResult
 		resolve.Do()
 	}
+	// 过滤解析结果与泛解析相同的子域名，并在泛解析的域名中记录泛解析属性
+	if wildcard != nil {
+		wildcard.Filter(resultDomainScan)
+	}
+	// NS的域传送及递归解析检查，域传送获取的子域名加入到结果中
+	if config.IsDNSCheck {
+		dnsCheck := domainscan.NewDNSCheck(config)
+		dnsCheck.Do(resultDomainScan)
+		resultVul = dnsCheck.VulResult
+	}
 	// 去除结果中无域名解析A或CNAME记录的域名
 	checkDomainResolveResult(resultDomainScan)
 	// 对域名结果中同一个IP对应太多进行过滤
 	domainscan.FilterDomainResult(resultDomainScan)
 
-	return resultDomainScan
+	return resultDomainScan, resultVul
+}
+
+// loadSubDomain 读取工作空间中目标域名已有的子域名
+func loadSubDomain(config domainscan.Config) (domains []string) {
+	args := comm.LoadSubDomainArgs{
+		WorkspaceId: config.WorkspaceId,
+		Target:      config.Target,
+	}
+	if err := comm.CallXClient("LoadSubDomain", &args, &domains); err != nil {
+		logging.RuntimeLog.Error(err)
+	}
+	return
 }
 
 // doPortScanByDomainscan 对IP进行端口扫描
@@ -224,7 +258,7 @@ func isHaveResolveRecord(domainAttrs *[]domainscan.DomainAttrResult) bool {
 		return false
 	}
 	for _, dar := range *domainAttrs {
-		if dar.Tag == "A" || dar.Tag == "AAAA" || dar.Tag == "CNAME" {
+		if dar.Tag == "A" || dar.Tag == "AAAA" || dar.Tag == "CNAME" || dar.Tag == domainscan.WildcardTag {
 			return true
 		}
 	}
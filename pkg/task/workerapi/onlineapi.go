@@ -2,6 +2,7 @@ package workerapi
 
 import (
 	"bufio"
+	"fmt"
 	"github.com/hanc00l/nemo_go/pkg/comm"
 	"github.com/hanc00l/nemo_go/pkg/conf"
 	"github.com/hanc00l/nemo_go/pkg/logging"
@@ -30,7 +31,27 @@ func Hunter(taskId, mainTaskId, configJSON string) (result string, err error) {
 	return doOnlineAPI(taskId, mainTaskId, configJSON, "hunter")
 }
 
-// doOnlineAPI 执行fofa、hunter及quake的资产搜索任务
+// Shodan Shodan任务
+func Shodan(taskId, mainTaskId, configJSON string) (result string, err error) {
+	return doOnlineAPI(taskId, mainTaskId, configJSON, "shodan")
+}
+
+// Censys Censys任务
+func Censys(taskId, mainTaskId, configJSON string) (result string, err error) {
+	return doOnlineAPI(taskId, mainTaskId, configJSON, "censys")
+}
+
+// ZoomEye ZoomEye任务
+func ZoomEye(taskId, mainTaskId, configJSON string) (result string, err error) {
+	return doOnlineAPI(taskId, mainTaskId, configJSON, "zoomeye")
+}
+
+// Netlas Netlas任务
+func Netlas(taskId, mainTaskId, configJSON string) (result string, err error) {
+	return doOnlineAPI(taskId, mainTaskId, configJSON, "netlas")
+}
+
+// doOnlineAPI 执行fofa、hunter及quake等的资产搜索任务
 func doOnlineAPI(taskId string, mainTaskId string, configJSON string, apiName string) (result string, err error) {
 	// 检查任务状态
 	var ok bool
@@ -68,7 +89,9 @@ func doOnlineAPI(taskId string, mainTaskId string, configJSON string, apiName st
 // doOnlineAPIAndSave 执行fofa、hunter及quake的资产搜索，并保存结果
 func doOnlineAPIAndSave(taskId string, mainTaskId string, apiName string, config onlineapi.OnlineAPIConfig) (ipResult *portscan.Result, domainResult *domainscan.Result, result string, err error) {
 	s := onlineapi.NewOnlineAPISearch(config, apiName)
+	s.Cache = onlineAPIRPCCache{}
 	s.Do()
+	saveAPIKeyStatus()
 	ipResult = &s.IpResult
 	domainResult = &s.DomainResult
 	checkIgnoreResult(ipResult, domainResult, config)
@@ -89,9 +112,75 @@ func doOnlineAPIAndSave(taskId string, mainTaskId string, apiName string, config
 	if err != nil {
 		logging.RuntimeLog.Error(err)
 	}
+	if s.CachedPageNum > 0 {
+		result = fmt.Sprintf("%s,cache:%d,savedPoint:%d", result, s.CachedPageNum, s.CachedPointSaved)
+	}
+	// 关键词监控：比较本次检索的资产，记录历史并通知新增的资产
+	if config.KeyWordId > 0 {
+		result = fmt.Sprintf("%s,%s", result, saveKeyWordMonitorResult(taskId, apiName, config, ipResult, domainResult))
+	}
+	return
+}
+
+// saveKeyWordMonitorResult 将关键词检索得到的IP:端口及域名发送到server进行比较
+func saveKeyWordMonitorResult(taskId string, apiName string, config onlineapi.OnlineAPIConfig, ipResult *portscan.Result, domainResult *domainscan.Result) (result string) {
+	args := comm.KeyWordMonitorArgs{
+		KeyWordId:   config.KeyWordId,
+		WorkspaceId: config.WorkspaceId,
+		Engine:      apiName,
+		TaskId:      taskId,
+	}
+	for ip, ipr := range ipResult.IPResult {
+		for port := range ipr.Ports {
+			args.IPPort = append(args.IPPort, fmt.Sprintf("%s:%d", ip, port))
+		}
+	}
+	for domain := range domainResult.DomainResult {
+		args.Domain = append(args.Domain, domain)
+	}
+	if err := comm.CallXClient("SaveKeyWordMonitorResult", &args, &result); err != nil {
+		logging.RuntimeLog.Error(err)
+	}
 	return
 }
 
+// saveAPIKeyStatus 将worker的key使用量及错误上报到server
+func saveAPIKeyStatus() {
+	args := comm.APIKeyStatusArgs{
+		WorkerName: WStatus.WorkerName,
+		Status:     onlineapi.GetAPIKeyStatusReport(),
+	}
+	if len(args.Status) == 0 {
+		return
+	}
+	var result string
+	if err := comm.CallXClient("SaveAPIKeyStatus", &args, &result); err != nil {
+		logging.RuntimeLog.Error(err)
+	}
+}
+
+// onlineAPIRPCCache 通过RPC使用server的在线API查询结果缓存
+type onlineAPIRPCCache struct{}
+
+func (onlineAPIRPCCache) Load(key string, ttl int) (item *onlineapi.OnlineAPICacheItem, ok bool) {
+	item = &onlineapi.OnlineAPICacheItem{}
+	if err := comm.CallXClient("LoadOnlineAPICache", &comm.OnlineAPICacheArgs{Key: key, TTL: ttl}, item); err != nil {
+		logging.RuntimeLog.Error(err)
+		return nil, false
+	}
+	if item.CacheTime.IsZero() {
+		return nil, false
+	}
+	return item, true
+}
+
+func (onlineAPIRPCCache) Save(key string, item *onlineapi.OnlineAPICacheItem) {
+	var result string
+	if err := comm.CallXClient("SaveOnlineAPICache", &comm.OnlineAPICacheArgs{Key: key, Item: item}, &result); err != nil {
+		logging.RuntimeLog.Error(err)
+	}
+}
+
 // ICPQuery ICP备案查询任务
 func ICPQuery(taskId, mainTaskId, configJSON string) (result string, err error) {
 	var ok bool
@@ -106,6 +195,7 @@ func ICPQuery(taskId, mainTaskId, configJSON string) (result string, err error)
 
 	icp := onlineapi.NewICPQuery(config)
 	icp.Do()
+	saveAPIKeyStatus()
 	// 保存结果
 	err = comm.CallXClient("SaveICPResult", &icp.QueriedICPInfo, &result)
 	if err != nil {
@@ -166,7 +256,7 @@ func checkIgnoreResult(portScanResult *portscan.Result, domainScanResult *domain
 				for port := range ipInfo.Ports {
 					portInfo := ipInfo.Ports[port]
 					for _, attr := range portInfo.PortAttrs {
-						if (attr.Source == "fofa" || attr.Source == "hunter" || attr.Source == "quake") && attr.Tag == "title" {
+						if onlineapi.IsOnlineAPISource(attr.Source) && attr.Tag == "title" {
 							if len(attr.Content) > 100 {
 								needDelete = true
 								break
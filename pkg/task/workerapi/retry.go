@@ -0,0 +1,180 @@
+package workerapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/RichardKnop/machinery/v2/tasks"
+	"github.com/hanc00l/nemo_go/pkg/conf"
+	"github.com/hanc00l/nemo_go/pkg/logging"
+	"github.com/hanc00l/nemo_go/pkg/task/ampq"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// RetryDefaultPolicy 未单独配置重试策略的任务使用的默认策略名称
+	RetryDefaultPolicy = "default"
+
+	retryAttemptHeader = "nemo_retry_attempt"
+	retryResultHeader  = "nemo_retry_result"
+	retryDefaultDelay  = 30
+)
+
+// nonRetryableErrors 重试也无法恢复的错误：任务不存在、已完成或参数错误
+var nonRetryableErrors = []string{"task not exist", "task has finished", "no org id", "ports error"}
+
+// TaskFunc worker执行的任务
+type TaskFunc func(taskId, mainTaskId, configJSON string) (result string, err error)
+
+// RetryTask 任务重试的状态和消息
+func RetryTask(msg string) string {
+	r := ampq.TaskResult{Status: ampq.RETRY, Msg: msg}
+	js, _ := json.Marshal(r)
+	return string(js)
+}
+
+// getRetryPolicy 获取任务的重试策略，未配置的任务使用default策略
+func getRetryPolicy(taskName string) (policy conf.Retry, ok bool) {
+	retry := conf.GlobalWorkerConfig().Retry
+	if policy, ok = retry[taskName]; ok {
+		return
+	}
+	policy, ok = retry[RetryDefaultPolicy]
+	return
+}
+
+// retryBackoff 计算第attempt次重试前的等待时间：按delay*2^(attempt-1)指数退避，且不超过maxDelay
+func retryBackoff(policy conf.Retry, attempt int) time.Duration {
+	delay := policy.Delay
+	if delay <= 0 {
+		delay = retryDefaultDelay
+	}
+	maxDelay := time.Duration(policy.MaxDelay) * time.Second
+	d := time.Duration(delay) * time.Second
+	for i := 1; i < attempt; i++ {
+		if maxDelay > 0 && d >= maxDelay {
+			break
+		}
+		d *= 2
+	}
+	if maxDelay > 0 && d > maxDelay {
+		d = maxDelay
+	}
+	return d
+}
+
+// isRetryableError 检查错误是否可以重试；策略中未指定错误时，除参数错误等外的所有错误都可重试
+func isRetryableError(policy conf.Retry, err error) bool {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &syntaxErr) || errors.As(err, &typeErr) {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	for _, e := range nonRetryableErrors {
+		if strings.Contains(msg, e) {
+			return false
+		}
+	}
+	if len(policy.Errors) == 0 {
+		return true
+	}
+	for _, e := range policy.Errors {
+		if e != "" && strings.Contains(msg, strings.ToLower(e)) {
+			return true
+		}
+	}
+	return false
+}
+
+// getRetryAttempt 获取任务已经重试的次数
+func getRetryAttempt(signature *tasks.Signature) int {
+	if signature.Headers == nil {
+		return 0
+	}
+	switch v := signature.Headers[retryAttemptHeader].(type) {
+	case int:
+		return v
+	case float64:
+		return int(v)
+	case json.Number:
+		n, _ := v.Int64()
+		return int(n)
+	case string:
+		n, _ := strconv.Atoi(v)
+		return n
+	}
+	return 0
+}
+
+// getRetryResult 获取任务重新投递时的结果，任务未重试则返回false
+func getRetryResult(signature *tasks.Signature) (result string, ok bool) {
+	if signature.Headers == nil {
+		return
+	}
+	result, ok = signature.Headers[retryResultHeader].(string)
+	return
+}
+
+// callTask 执行任务，将任务的panic转换为错误
+func callTask(taskFunc TaskFunc, taskId, mainTaskId, configJSON string) (result string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("task panic:%v", r)
+			result = FailedTask(err.Error())
+		}
+	}()
+	return taskFunc(taskId, mainTaskId, configJSON)
+}
+
+// retryTaskWrapper 包装任务：任务执行出错时，根据任务的重试策略延时后通过machinery重新投递到队列中
+func retryTaskWrapper(taskName string, taskFunc TaskFunc) func(ctx context.Context, taskId, mainTaskId, configJSON string) (string, error) {
+	return func(ctx context.Context, taskId, mainTaskId, configJSON string) (result string, err error) {
+		signature := tasks.SignatureFromContext(ctx)
+		if signature != nil && signature.Headers != nil {
+			delete(signature.Headers, retryResultHeader)
+		}
+		result, err = callTask(taskFunc, taskId, mainTaskId, configJSON)
+		if err == nil || signature == nil {
+			return
+		}
+		if policy, ok := getRetryPolicy(taskName); ok {
+			if retryIn, retry := setTaskRetry(signature, policy, err); retry {
+				logging.RuntimeLog.Warningf("task %s:%s failed,retry in %s:%v", taskName, taskId, retryIn, err)
+				return result, tasks.NewErrRetryTaskLater(err.Error(), retryIn)
+			}
+		}
+		return
+	}
+}
+
+// setTaskRetry 根据重试策略检查任务是否需要重试，需要重试则在signature中记录重试次数和结果
+func setTaskRetry(signature *tasks.Signature, policy conf.Retry, err error) (retryIn time.Duration, retry bool) {
+	attempt := getRetryAttempt(signature) + 1
+	if attempt >= policy.MaxAttempts || !isRetryableError(policy, err) {
+		return
+	}
+	retryIn = retryBackoff(policy, attempt)
+	if signature.Headers == nil {
+		signature.Headers = make(tasks.Headers)
+	}
+	signature.Headers[retryAttemptHeader] = attempt
+	signature.Headers[retryResultHeader] = RetryTask(fmt.Sprintf("retry %d/%d in %s:%s", attempt, policy.MaxAttempts-1, retryIn, err.Error()))
+	return retryIn, true
+}
+
+// makeRetryTaskMaps 为标准的任务包装重试处理，生成注册到machinery的任务
+func makeRetryTaskMaps(taskMaps map[string]interface{}) map[string]interface{} {
+	retryTaskMaps := make(map[string]interface{})
+	for name, task := range taskMaps {
+		if f, ok := task.(func(string, string, string) (string, error)); ok {
+			retryTaskMaps[name] = retryTaskWrapper(name, f)
+		} else {
+			retryTaskMaps[name] = task
+		}
+	}
+	return retryTaskMaps
+}
@@ -0,0 +1,88 @@
+package workerapi
+
+import (
+	"errors"
+	"github.com/RichardKnop/machinery/v2/tasks"
+	"github.com/hanc00l/nemo_go/pkg/conf"
+	"testing"
+	"time"
+)
+
+func TestRetryBackoff(t *testing.T) {
+	policy := conf.Retry{MaxAttempts: 5, Delay: 10, MaxDelay: 60}
+	expected := []time.Duration{10 * time.Second, 20 * time.Second, 40 * time.Second, 60 * time.Second, 60 * time.Second}
+	for i, d := range expected {
+		if r := retryBackoff(policy, i+1); r != d {
+			t.Errorf("attempt %d:expect %s,got %s", i+1, d, r)
+		}
+	}
+}
+
+func TestIsRetryableError(t *testing.T) {
+	policy := conf.Retry{Errors: []string{"timeout", "EOF"}}
+	if !isRetryableError(policy, errors.New("rpc call: i/o timeout")) {
+		t.Error("timeout should be retryable")
+	}
+	if isRetryableError(policy, errors.New("exit status 1")) {
+		t.Error("exit status should not be retryable")
+	}
+	if !isRetryableError(conf.Retry{}, errors.New("exit status 1")) {
+		t.Error("all errors should be retryable")
+	}
+	if isRetryableError(conf.Retry{}, errors.New("task not exist")) {
+		t.Error("task not exist should not be retryable")
+	}
+}
+
+func TestSetTaskRetry(t *testing.T) {
+	policy := conf.Retry{MaxAttempts: 3, Delay: 5, Errors: []string{"timeout"}}
+	signature := &tasks.Signature{UUID: "test"}
+	for attempt := 1; attempt < policy.MaxAttempts; attempt++ {
+		retryIn, retry := setTaskRetry(signature, policy, errors.New("timeout"))
+		if !retry || retryIn != retryBackoff(policy, attempt) {
+			t.Fatalf("attempt %d:expect retry,got %v %s", attempt, retry, retryIn)
+		}
+		result, ok := getRetryResult(signature)
+		if !ok {
+			t.Fatal("no retry result")
+		}
+		t.Log(result)
+	}
+	if _, retry := setTaskRetry(signature, policy, errors.New("timeout")); retry {
+		t.Error("exceed max attempts")
+	}
+	// 重新投递后headers经过json序列化
+	signature.Headers = tasks.Headers{retryAttemptHeader: float64(1)}
+	if getRetryAttempt(signature) != 1 {
+		t.Error("invalid retry attempt")
+	}
+}
+
+func TestRetryTaskWrapper(t *testing.T) {
+	wrapper := retryTaskWrapper("test", func(taskId, mainTaskId, configJSON string) (string, error) {
+		return SucceedTask(taskId), nil
+	})
+	signature := &tasks.Signature{
+		UUID:    "test",
+		Args:    []tasks.Arg{{Type: "string", Value: "test"}, {Type: "string", Value: ""}, {Type: "string", Value: "{}"}},
+		Headers: tasks.Headers{retryAttemptHeader: float64(1), retryResultHeader: RetryTask("")},
+	}
+	task, err := tasks.NewWithSignature(wrapper, signature)
+	if err != nil {
+		t.Fatal(err)
+	}
+	results, err := task.Call()
+	if err != nil || len(results) != 1 {
+		t.Fatalf("expect succeed,got %v", err)
+	}
+	if _, ok := getRetryResult(signature); ok {
+		t.Error("retry result should be removed")
+	}
+	// 任务panic转换为错误
+	_, err = callTask(func(taskId, mainTaskId, configJSON string) (string, error) {
+		panic("tool crashed")
+	}, "test", "", "{}")
+	if err == nil {
+		t.Error("expect panic error")
+	}
+}
@@ -0,0 +1,453 @@
+package workerapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/hanc00l/nemo_go/pkg/conf"
+	"github.com/hanc00l/nemo_go/pkg/logging"
+	"github.com/hanc00l/nemo_go/pkg/task/domainscan"
+	"github.com/hanc00l/nemo_go/pkg/task/portscan"
+	"github.com/hanc00l/nemo_go/pkg/utils"
+	"gopkg.in/yaml.v2"
+	"strconv"
+	"strings"
+)
+
+const (
+	WorkflowInputAll    = "all"
+	WorkflowInputIP     = "ip"
+	WorkflowInputDomain = "domain"
+	WorkflowInputUrl    = "url" // 指纹识别获取的URL，只用于dirscan
+
+	WorkflowConditionAlways = "always"
+	WorkflowConditionIP     = "ip"
+	WorkflowConditionDomain = "domain"
+	WorkflowConditionPort   = "port:"
+)
+
+// WorkflowStage 工作流的一个执行阶段
+type WorkflowStage struct {
+	// 阶段名称，在同一个工作流中唯一
+	Name string `json:"name" yaml:"name"`
+	// 阶段执行的任务，见workflowTaskDefine
+	Task string `json:"task" yaml:"task"`
+	// 依赖的阶段：为空则为起始阶段（输入为任务的目标），否则在依赖的阶段完成后以其结果作为输入；
+	// 阶段的子任务分布在多个worker上执行，无法等待多个阶段全部完成，因此只能依赖一个阶段
+	Depends []string `json:"depends,omitempty" yaml:"depends,omitempty"`
+	// 从依赖阶段的结果中选取的输入：all、ip、domain
+	Input string `json:"input,omitempty" yaml:"input,omitempty"`
+	// 执行条件：always、ip（有IP结果）、domain（有域名结果）、port:80,443（有指定的开放端口，并只以这些端口作为输入）
+	Condition string `json:"condition,omitempty" yaml:"condition,omitempty"`
+	// 任务的参数，如port、pocfile、keyword
+	Options map[string]string `json:"options,omitempty" yaml:"options,omitempty"`
+}
+
+// WorkflowDefine 工作流的定义
+type WorkflowDefine struct {
+	Name        string          `json:"name" yaml:"name"`
+	Description string          `json:"description,omitempty" yaml:"description,omitempty"`
+	Stages      []WorkflowStage `json:"stages" yaml:"stages"`
+}
+
+// workflowTask 工作流阶段可执行的任务
+type workflowTask struct {
+	TaskName string
+	IsRoot   bool
+	Input    []string
+	Output   []string
+}
+
+// workflowTaskDefine 工作流阶段的任务与worker任务的对应关系，以及任务接受的输入和产生的输出
+var workflowTaskDefine = map[string]workflowTask{
+	"portscan":         {TaskName: "xportscan", IsRoot: true, Input: []string{WorkflowInputIP, WorkflowInputDomain}, Output: []string{WorkflowInputIP}},
+	"domainscan":       {TaskName: "xdomainscan", IsRoot: true, Input: []string{WorkflowInputDomain}, Output: []string{WorkflowInputDomain}},
+	"subfinder":        {TaskName: "xsubfinder", IsRoot: true, Input: []string{WorkflowInputDomain}, Output: []string{WorkflowInputDomain}},
+	"subdomainbrute":   {TaskName: "xsubdomainbrute", IsRoot: true, Input: []string{WorkflowInputDomain}, Output: []string{WorkflowInputDomain}},
+	"subdomaincrawler": {TaskName: "xsubdomaincralwer", IsRoot: true, Input: []string{WorkflowInputDomain}, Output: []string{WorkflowInputDomain}},
+	"fofa":             {TaskName: "xfofa", IsRoot: true, Input: []string{WorkflowInputIP, WorkflowInputDomain}, Output: []string{WorkflowInputIP, WorkflowInputDomain}},
+	"hunter":           {TaskName: "xhunter", IsRoot: true, Input: []string{WorkflowInputIP, WorkflowInputDomain}, Output: []string{WorkflowInputIP, WorkflowInputDomain}},
+	"quake":            {TaskName: "xquake", IsRoot: true, Input: []string{WorkflowInputIP, WorkflowInputDomain}, Output: []string{WorkflowInputIP, WorkflowInputDomain}},
+	"shodan":           {TaskName: "xshodan", IsRoot: true, Input: []string{WorkflowInputIP, WorkflowInputDomain}, Output: []string{WorkflowInputIP, WorkflowInputDomain}},
+	"censys":           {TaskName: "xcensys", IsRoot: true, Input: []string{WorkflowInputIP, WorkflowInputDomain}, Output: []string{WorkflowInputIP, WorkflowInputDomain}},
+	"zoomeye":          {TaskName: "xzoomeye", IsRoot: true, Input: []string{WorkflowInputIP, WorkflowInputDomain}, Output: []string{WorkflowInputIP, WorkflowInputDomain}},
+	"netlas":           {TaskName: "xnetlas", IsRoot: true, Input: []string{WorkflowInputIP, WorkflowInputDomain}, Output: []string{WorkflowInputIP, WorkflowInputDomain}},
+	"fingerprint":      {TaskName: "xfingerprint", Input: []string{WorkflowInputIP, WorkflowInputDomain}, Output: []string{WorkflowInputIP, WorkflowInputDomain, WorkflowInputUrl}},
+	"xray":             {TaskName: "xxray", Input: []string{WorkflowInputIP, WorkflowInputDomain}},
+	"nuclei":           {TaskName: "xnuclei", Input: []string{WorkflowInputIP, WorkflowInputDomain}},
+	"goby":             {TaskName: "xgoby", Input: []string{WorkflowInputIP, WorkflowInputDomain}},
+	"dirscan":          {TaskName: "dirscan", Input: []string{WorkflowInputUrl}},
+}
+
+// ParseWorkflow 解析YAML或JSON格式的工作流定义并校验
+func ParseWorkflow(content string) (w *WorkflowDefine, err error) {
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return nil, errors.New("empty workflow define")
+	}
+	w = &WorkflowDefine{}
+	if strings.HasPrefix(content, "{") {
+		err = json.Unmarshal([]byte(content), w)
+	} else {
+		err = yaml.Unmarshal([]byte(content), w)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err = w.Validate(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// Validate 校验工作流定义：阶段名称、任务、依赖关系、输入输出及执行条件
+func (w *WorkflowDefine) Validate() error {
+	if len(w.Stages) == 0 {
+		return errors.New("workflow has no stage")
+	}
+	stages := make(map[string]WorkflowStage)
+	for _, s := range w.Stages {
+		if s.Name == "" {
+			return errors.New("stage name is empty")
+		}
+		if _, ok := stages[s.Name]; ok {
+			return fmt.Errorf("duplicate stage name:%s", s.Name)
+		}
+		if _, ok := workflowTaskDefine[s.Task]; !ok {
+			return fmt.Errorf("stage %s: invalid task:%s", s.Name, s.Task)
+		}
+		if s.Input != "" && s.Input != WorkflowInputAll && s.Input != WorkflowInputIP && s.Input != WorkflowInputDomain {
+			return fmt.Errorf("stage %s: invalid input:%s", s.Name, s.Input)
+		}
+		if _, err := parseConditionPorts(s.Condition); err != nil {
+			return fmt.Errorf("stage %s: %v", s.Name, err)
+		}
+		stages[s.Name] = s
+	}
+	hasRoot := false
+	for _, s := range w.Stages {
+		task := workflowTaskDefine[s.Task]
+		if len(s.Depends) == 0 {
+			if !task.IsRoot {
+				return fmt.Errorf("stage %s: task %s can not be a start stage", s.Name, s.Task)
+			}
+			hasRoot = true
+			continue
+		}
+		if len(s.Depends) > 1 {
+			return fmt.Errorf("stage %s: only one depend stage is supported", s.Name)
+		}
+		for _, d := range s.Depends {
+			depend, ok := stages[d]
+			if !ok {
+				return fmt.Errorf("stage %s: depend stage %s not exist", s.Name, d)
+			}
+			if d == s.Name {
+				return fmt.Errorf("stage %s: depend on itself", s.Name)
+			}
+			// 依赖阶段的输出必须能满足当前阶段的输入
+			if len(intersectInput(s.acceptInput(), workflowTaskDefine[depend.Task].Output)) == 0 {
+				return fmt.Errorf("stage %s: output of %s(%s) can not be used as input", s.Name, d, depend.Task)
+			}
+		}
+	}
+	if !hasRoot {
+		return errors.New("workflow has no start stage")
+	}
+	// 检查是否有循环依赖
+	visited := make(map[string]int)
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch visited[name] {
+		case 1:
+			return fmt.Errorf("stage %s: circular dependency", name)
+		case 2:
+			return nil
+		}
+		visited[name] = 1
+		for _, d := range stages[name].Depends {
+			if err := visit(d); err != nil {
+				return err
+			}
+		}
+		visited[name] = 2
+		return nil
+	}
+	for _, s := range w.Stages {
+		if err := visit(s.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RootStages 获取工作流的起始阶段
+func (w *WorkflowDefine) RootStages() (stages []WorkflowStage) {
+	for _, s := range w.Stages {
+		if len(s.Depends) == 0 {
+			stages = append(stages, s)
+		}
+	}
+	return
+}
+
+// NextStages 获取依赖于指定阶段的后续阶段
+func (w *WorkflowDefine) NextStages(name string) (stages []WorkflowStage) {
+	for _, s := range w.Stages {
+		for _, d := range s.Depends {
+			if d == name {
+				stages = append(stages, s)
+				break
+			}
+		}
+	}
+	return
+}
+
+// TaskName 阶段对应的worker任务名称
+func (s WorkflowStage) TaskName() string {
+	return workflowTaskDefine[s.Task].TaskName
+}
+
+// MakeStageConfig 根据阶段的任务和参数，生成阶段任务的XScanConfig（不含目标）
+func (w *WorkflowDefine) MakeStageConfig(stage WorkflowStage, base XScanConfig) (config XScanConfig) {
+	config = XScanConfig{
+		OrgId:         base.OrgId,
+		WorkspaceId:   base.WorkspaceId,
+		IsProxy:       base.IsProxy,
+		Workflow:      w,
+		WorkflowStage: stage.Name,
+	}
+	switch stage.Task {
+	case "subfinder":
+		config.IsSubDomainFinder = true
+	case "subdomainbrute":
+		config.IsSubDomainBrute = true
+	case "subdomaincrawler":
+		config.IsSubDomainCrawler = true
+	case "fofa":
+		config.IsFofa = true
+	case "hunter":
+		config.IsHunter = true
+	case "quake":
+		config.IsQuake = true
+	case "shodan":
+		config.IsShodan = true
+	case "censys":
+		config.IsCensys = true
+	case "zoomeye":
+		config.IsZoomEye = true
+	case "netlas":
+		config.IsNetlas = true
+	case "xray":
+		config.IsXrayPoc = true
+		config.XrayPocFile = stage.Options["pocfile"]
+	case "nuclei":
+		config.IsNucleiPoc = true
+		config.NucleiPocFile = stage.Options["pocfile"]
+	case "goby":
+		config.IsGobyPoc = true
+	}
+	return
+}
+
+// acceptInput 阶段实际接受的输入类型
+func (s WorkflowStage) acceptInput() []string {
+	task := workflowTaskDefine[s.Task]
+	if s.Input == "" || s.Input == WorkflowInputAll {
+		return task.Input
+	}
+	return intersectInput(task.Input, []string{s.Input})
+}
+
+// selectInput 根据阶段的输入类型及执行条件，从上一阶段的结果中选取本阶段的输入；不满足执行条件时返回false
+func (s WorkflowStage) selectInput(resultIP *portscan.Result, resultDomain *domainscan.Result) (ipResult *portscan.Result, domainResult *domainscan.Result, ok bool) {
+	for _, input := range s.acceptInput() {
+		if input == WorkflowInputIP && resultIP != nil && len(resultIP.IPResult) > 0 {
+			ipResult = resultIP
+		}
+		if input == WorkflowInputDomain && resultDomain != nil && len(resultDomain.DomainResult) > 0 {
+			domainResult = resultDomain
+		}
+		// URL位于IP与域名的指纹识别结果中
+		if input == WorkflowInputUrl {
+			if resultIP != nil && len(resultIP.IPResult) > 0 {
+				ipResult = resultIP
+			}
+			if resultDomain != nil && len(resultDomain.DomainResult) > 0 {
+				domainResult = resultDomain
+			}
+		}
+	}
+	ports, _ := parseConditionPorts(s.Condition)
+	switch {
+	case len(ports) > 0:
+		if ipResult == nil {
+			return nil, nil, false
+		}
+		// 只保留指定的开放端口
+		filtered := &portscan.Result{IPResult: make(map[string]*portscan.IPResult)}
+		for ip, ipr := range ipResult.IPResult {
+			for port, pr := range ipr.Ports {
+				if _, ok := ports[port]; !ok {
+					continue
+				}
+				if _, ok := filtered.IPResult[ip]; !ok {
+					filtered.IPResult[ip] = &portscan.IPResult{OrgId: ipr.OrgId, Ports: make(map[int]*portscan.PortResult)}
+				}
+				filtered.IPResult[ip].Ports[port] = pr
+			}
+		}
+		if len(filtered.IPResult) == 0 {
+			return nil, nil, false
+		}
+		return filtered, nil, true
+	case s.Condition == WorkflowConditionIP:
+		return ipResult, domainResult, ipResult != nil
+	case s.Condition == WorkflowConditionDomain:
+		return ipResult, domainResult, domainResult != nil
+	}
+	return ipResult, domainResult, ipResult != nil || domainResult != nil
+}
+
+// parseConditionPorts 解析执行条件，条件为port:时返回端口列表
+func parseConditionPorts(condition string) (ports map[int]struct{}, err error) {
+	switch condition {
+	case "", WorkflowConditionAlways, WorkflowConditionIP, WorkflowConditionDomain:
+		return
+	}
+	if !strings.HasPrefix(condition, WorkflowConditionPort) {
+		return nil, fmt.Errorf("invalid condition:%s", condition)
+	}
+	ports = make(map[int]struct{})
+	for _, p := range strings.Split(strings.TrimPrefix(condition, WorkflowConditionPort), ",") {
+		port, err := strconv.Atoi(strings.TrimSpace(p))
+		if err != nil || port <= 0 || port > 65535 {
+			return nil, fmt.Errorf("invalid condition port:%s", p)
+		}
+		ports[port] = struct{}{}
+	}
+	return
+}
+
+// intersectInput 输入输出类型的交集
+func intersectInput(a, b []string) (result []string) {
+	for _, x := range a {
+		for _, y := range b {
+			if x == y {
+				result = append(result, x)
+			}
+		}
+	}
+	return
+}
+
+// NextWorkflowStage 按工作流的定义，以当前阶段的结果生成后续阶段的任务
+func (x *XScan) NextWorkflowStage(taskId, mainTaskId string) (result string, err error) {
+	if x.Config.Workflow == nil {
+		return
+	}
+	for _, stage := range x.Config.Workflow.NextStages(x.Config.WorkflowStage) {
+		ipResult, domainResult, ok := stage.selectInput(x.ResultIP, x.ResultDomain)
+		if !ok {
+			logging.RuntimeLog.Debugf("workflow %s stage %s: condition not matched,skip...", x.Config.Workflow.Name, stage.Name)
+			continue
+		}
+		// dirscan不是XScan任务，以指纹识别的URL生成任务
+		if stage.Task == "dirscan" {
+			result, err = NewDirscanTask(taskId, mainTaskId, MakeDirscanTarget(ipResult, domainResult), x.Config.WorkspaceId, x.Config.IsProxy)
+			if err != nil {
+				return
+			}
+			continue
+		}
+		config := x.Config.Workflow.MakeStageConfig(stage, x.Config)
+		for _, configRun := range makeWorkflowStageTarget(stage, config, ipResult, domainResult) {
+			result, err = sendTask(taskId, mainTaskId, configRun, stage.TaskName())
+			if err != nil {
+				logging.RuntimeLog.Error(err)
+				return
+			}
+		}
+	}
+	return
+}
+
+// makeWorkflowStageTarget 根据上一阶段的结果，拆分生成阶段的子任务
+func makeWorkflowStageTarget(stage WorkflowStage, config XScanConfig, ipResult *portscan.Result, domainResult *domainscan.Result) (configs []XScanConfig) {
+	switch stage.Task {
+	case "portscan":
+		port := stage.Options["port"]
+		var ipList []string
+		if ipResult != nil {
+			if port == "" {
+				// 未指定端口，则对上一阶段发现的端口进行扫描
+				ipTarget, _ := MakeSubTaskTarget(ipResult, nil)
+				for _, t := range ipTarget {
+					configRun := config
+					configRun.IPPort = t
+					configs = append(configs, configRun)
+				}
+			} else {
+				for ip := range ipResult.IPResult {
+					ipList = append(ipList, ip)
+				}
+			}
+		}
+		if domainResult != nil {
+			ips, _ := getResultIPList(domainResult)
+			ipList = append(ipList, ips...)
+		}
+		if len(ipList) > 0 {
+			if port == "" {
+				port = conf.GlobalWorkerConfig().Portscan.Port
+			}
+			ts := utils.NewTaskSlice()
+			ts.TaskMode = utils.SliceByIP
+			ts.IpTarget = ipList
+			ts.Port = port
+			ts.IpSliceNumber = utils.DefaultIpSliceNumber
+			targets, _ := ts.DoIpSlice()
+			for _, t := range targets {
+				configRun := config
+				configRun.IPPortString = map[string]string{t: port}
+				configs = append(configs, configRun)
+			}
+		}
+	case "domainscan", "subfinder", "subdomainbrute", "subdomaincrawler":
+		_, domainTarget := MakeSubTaskTarget(nil, domainResult)
+		for _, t := range domainTarget {
+			configRun := config
+			configRun.Domain = t
+			configs = append(configs, configRun)
+		}
+	case "fofa", "hunter", "quake", "shodan", "censys", "zoomeye", "netlas":
+		if ipResult != nil {
+			for ip := range ipResult.IPResult {
+				configRun := config
+				configRun.OnlineAPITarget = ip
+				configs = append(configs, configRun)
+			}
+		}
+		if domainResult != nil {
+			for domain := range domainResult.DomainResult {
+				configRun := config
+				configRun.OnlineAPITarget = domain
+				configs = append(configs, configRun)
+			}
+		}
+	default:
+		ipTarget, domainTarget := MakeSubTaskTarget(ipResult, domainResult)
+		for _, t := range ipTarget {
+			configRun := config
+			configRun.IPPort = t
+			configs = append(configs, configRun)
+		}
+		for _, t := range domainTarget {
+			configRun := config
+			configRun.Domain = t
+			configs = append(configs, configRun)
+		}
+	}
+	return
+}
@@ -0,0 +1,116 @@
+package workerapi
+
+import (
+	"github.com/hanc00l/nemo_go/pkg/task/domainscan"
+	"github.com/hanc00l/nemo_go/pkg/task/portscan"
+	"testing"
+)
+
+func TestParseWorkflow(t *testing.T) {
+	content := `
+name: test
+stages:
+  - name: portscan
+    task: portscan
+    options:
+      port: 80,443,8080
+  - name: fingerprint
+    task: fingerprint
+    depends: [portscan]
+  - name: nuclei
+    task: nuclei
+    depends: [fingerprint]
+    condition: port:80,443
+`
+	w, err := ParseWorkflow(content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if roots := w.RootStages(); len(roots) != 1 || roots[0].TaskName() != "xportscan" {
+		t.Errorf("invalid root stages:%v", roots)
+	}
+	if next := w.NextStages("fingerprint"); len(next) != 1 || next[0].Name != "nuclei" {
+		t.Errorf("invalid next stages:%v", next)
+	}
+	// dirscan
+	_, err = ParseWorkflow(content + "  - name: dirscan\n    task: dirscan\n    depends: [fingerprint]\n")
+	if err != nil {
+		t.Error(err)
+	}
+	// json
+	_, err = ParseWorkflow(`{"name":"json","stages":[{"name":"s","task":"subfinder"},{"name":"f","task":"fingerprint","depends":["s"],"input":"domain"}]}`)
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestParseWorkflowInvalid(t *testing.T) {
+	invalids := map[string]string{
+		"no stage":      `name: empty`,
+		"unknown task":  "stages:\n  - name: a\n    task: unknown\n",
+		"not root":      "stages:\n  - name: a\n    task: fingerprint\n",
+		"depend":        "stages:\n  - name: a\n    task: portscan\n  - name: b\n    task: xray\n    depends: [c]\n",
+		"circular":      "stages:\n  - name: r\n    task: portscan\n  - name: a\n    task: fingerprint\n    depends: [b]\n  - name: b\n    task: fingerprint\n    depends: [a]\n",
+		"multi depends": "stages:\n  - name: a\n    task: subfinder\n  - name: b\n    task: portscan\n    depends: [a]\n  - name: c\n    task: fingerprint\n    depends: [a, b]\n",
+		"no output":     "stages:\n  - name: a\n    task: portscan\n  - name: b\n    task: xray\n    depends: [a]\n  - name: c\n    task: goby\n    depends: [b]\n",
+		"input":         "stages:\n  - name: a\n    task: portscan\n  - name: b\n    task: subfinder\n    depends: [a]\n",
+		"condition":     "stages:\n  - name: a\n    task: portscan\n  - name: b\n    task: xray\n    depends: [a]\n    condition: port:http\n",
+		"duplicate":     "stages:\n  - name: a\n    task: portscan\n  - name: a\n    task: subfinder\n",
+		"invalid input": "stages:\n  - name: a\n    task: portscan\n  - name: b\n    task: xray\n    depends: [a]\n    input: url\n",
+		"dirscan":       "stages:\n  - name: a\n    task: portscan\n  - name: b\n    task: dirscan\n    depends: [a]\n",
+	}
+	for name, content := range invalids {
+		if _, err := ParseWorkflow(content); err == nil {
+			t.Errorf("%s: expect error", name)
+		} else {
+			t.Log(name, err)
+		}
+	}
+}
+
+func TestWorkflowStageSelectInput(t *testing.T) {
+	resultIP := &portscan.Result{IPResult: map[string]*portscan.IPResult{
+		"192.168.1.1": {Ports: map[int]*portscan.PortResult{80: {}, 22: {}}},
+		"192.168.1.2": {Ports: map[int]*portscan.PortResult{3306: {}}},
+	}}
+	stage := WorkflowStage{Name: "xray", Task: "xray", Condition: "port:80,443"}
+	ipResult, _, ok := stage.selectInput(resultIP, nil)
+	if !ok || len(ipResult.IPResult) != 1 || len(ipResult.IPResult["192.168.1.1"].Ports) != 1 {
+		t.Errorf("invalid filter result:%v", ipResult)
+	}
+	stage.Condition = "port:8080"
+	if _, _, ok = stage.selectInput(resultIP, nil); ok {
+		t.Error("condition should not be matched")
+	}
+	stage.Condition = WorkflowConditionDomain
+	if _, _, ok = stage.selectInput(resultIP, nil); ok {
+		t.Error("condition should not be matched")
+	}
+	configs := makeWorkflowStageTarget(stage, XScanConfig{}, resultIP, nil)
+	if len(configs) != 1 || len(configs[0].IPPort) != 2 {
+		t.Errorf("invalid stage target:%v", configs)
+	}
+}
+
+func TestMakeDirscanTarget(t *testing.T) {
+	resultIP := &portscan.Result{IPResult: map[string]*portscan.IPResult{
+		"192.168.1.1": {Ports: map[int]*portscan.PortResult{
+			80:  {PortAttrs: []portscan.PortAttrResult{{Source: "httpx", Tag: "httpx", Content: `{"url":"http://192.168.1.1:80"}`}}},
+			443: {PortAttrs: []portscan.PortAttrResult{{Source: "httpx", Tag: "title", Content: "test"}}},
+		}},
+	}}
+	resultDomain := &domainscan.Result{DomainResult: map[string]*domainscan.DomainResult{
+		"www.example.com": {DomainAttrs: []domainscan.DomainAttrResult{
+			{Source: "httpx", Tag: "httpx", Content: `{"url":"https://www.example.com:443/"}`},
+			{Source: "httpx", Tag: "httpx", Content: `{"url":"http://www.example.com:80"}`},
+		}},
+	}}
+	urls := MakeDirscanTarget(resultIP, resultDomain)
+	if len(urls) != 3 || urls[0] != "http://192.168.1.1:80" || urls[2] != "https://www.example.com:443" {
+		t.Errorf("invalid dirscan target:%v", urls)
+	}
+	stage := WorkflowStage{Name: "dirscan", Task: "dirscan"}
+	if ipResult, domainResult, ok := stage.selectInput(resultIP, resultDomain); !ok || ipResult == nil || domainResult == nil {
+		t.Error("dirscan should select both ip and domain result")
+	}
+}
@@ -28,10 +28,15 @@ type XScanConfig struct {
 	OnlineAPITarget      string `json:"onlineapiTarget,omitempty"`
 	OnlineAPIKeyword     string `json:"onlineapiKeyword,omitempty"`
 	OnlineAPISearchLimit int    `json:"onlineapiSearchLimit,omitempty"`
+	OnlineAPIKeywordId   int    `json:"onlineapiKeywordId,omitempty"`
 	// xonlineapi 任务需要区分是哪一个api
-	IsFofa   bool `json:"fofa,omitempty"`
-	IsHunter bool `json:"hunter,omitempty"`
-	IsQuake  bool `json:"quake,omitempty"`
+	IsFofa    bool `json:"fofa,omitempty"`
+	IsHunter  bool `json:"hunter,omitempty"`
+	IsQuake   bool `json:"quake,omitempty"`
+	IsShodan  bool `json:"shodan,omitempty"`
+	IsCensys  bool `json:"censys,omitempty"`
+	IsZoomEye bool `json:"zoomeye,omitempty"`
+	IsNetlas  bool `json:"netlas,omitempty"`
 	// portscan
 	IPPort       map[string][]int  `json:"ipport,omitempty"`       //IP:PORT列表
 	IPPortString map[string]string `json:"ipportstring,omitempty"` //格式为ip列表，port可以为多种形式，如"80,443,8000-9000"、"--top-port 100"
@@ -40,6 +45,7 @@ type XScanConfig struct {
 	IsSubDomainFinder  bool                `json:"subfinder,omitempty"`
 	IsSubDomainBrute   bool                `json:"subdomainBrute,omitempty"`
 	IsSubDomainCrawler bool                `json:"subdomainCrawler,omitempty"`
+	IsDNSCheck         bool                `json:"dnscheck,omitempty"`
 	// fingerprint
 	IsFingerprint bool `json:"fingerprint,omitempty"`
 	// xraypoc
@@ -50,8 +56,13 @@ type XScanConfig struct {
 	NucleiPocFile string `json:"nucleipocfile,omitempty"`
 	// gobypoc
 	IsGobyPoc bool `json:"gobypoc,omitempty"`
+	// dirscan
+	IsDirscan bool `json:"dirscan,omitempty"`
 	//
 	IsProxy bool `json:"proxy,omitempty"`
+	// workflow：工作流定义及当前执行的阶段
+	Workflow      *WorkflowDefine `json:"workflow,omitempty"`
+	WorkflowStage string          `json:"workflowStage,omitempty"`
 }
 
 type XScan struct {
@@ -175,6 +186,14 @@ func XOnlineAPI(taskId, mainTaskId, configJSON string) (result string, err error
 		logging.RuntimeLog.Error(err)
 		return FailedTask(err.Error()), err
 	}
+	// 工作流任务：由工作流定义生成后续的任务
+	if config.Workflow != nil {
+		if _, err = scan.NextWorkflowStage(taskId, mainTaskId); err != nil {
+			logging.RuntimeLog.Error(err)
+			return FailedTask(err.Error()), err
+		}
+		return SucceedTask(result), nil
+	}
 	// 执行portscan与domainscan
 	ipPortMap, domainMap := MakeSubTaskTarget(scan.ResultIP, scan.ResultDomain)
 	_, err = scan.NewPortScan(taskId, mainTaskId, ipPortMap, nil)
@@ -212,6 +231,14 @@ func XPortScan(taskId, mainTaskId, configJSON string) (result string, err error)
 		logging.RuntimeLog.Error(err)
 		return FailedTask(err.Error()), err
 	}
+	// 工作流任务：由工作流定义生成后续的任务
+	if config.Workflow != nil {
+		if _, err = scan.NextWorkflowStage(taskId, mainTaskId); err != nil {
+			logging.RuntimeLog.Error(err)
+			return FailedTask(err.Error()), err
+		}
+		return SucceedTask(result), nil
+	}
 	// 启动指纹识别任务：
 	if config.IsFingerprint {
 		_, err = scan.NewFingerprintScan(taskId, mainTaskId)
@@ -244,6 +271,14 @@ func XDomainscan(taskId, mainTaskId, configJSON string) (result string, err erro
 		logging.RuntimeLog.Error(err)
 		return FailedTask(err.Error()), err
 	}
+	// 工作流任务：由工作流定义生成后续的任务
+	if config.Workflow != nil {
+		if _, err = scan.NextWorkflowStage(taskId, mainTaskId); err != nil {
+			logging.RuntimeLog.Error(err)
+			return FailedTask(err.Error()), err
+		}
+		return SucceedTask(result), nil
+	}
 	// 启动指纹识别任务：
 	if config.IsFingerprint {
 		_, err = scan.NewFingerprintScan(taskId, mainTaskId)
@@ -275,6 +310,14 @@ func XFingerPrint(taskId, mainTaskId, configJSON string) (result string, err err
 		logging.RuntimeLog.Error(err)
 		return FailedTask(err.Error()), err
 	}
+	// 工作流任务：由工作流定义生成后续的任务
+	if config.Workflow != nil {
+		if _, err = scan.NextWorkflowStage(taskId, mainTaskId); err != nil {
+			logging.RuntimeLog.Error(err)
+			return FailedTask(err.Error()), err
+		}
+		return SucceedTask(result), nil
+	}
 	// 启动XrayPoc任务
 	if config.IsXrayPoc {
 		_, err = scan.NewXrayScan(taskId, mainTaskId)
@@ -301,6 +344,15 @@ func XFingerPrint(taskId, mainTaskId, configJSON string) (result string, err err
 			return FailedTask(err.Error()), err
 		}
 	}
+
+	// 启动目录扫描任务
+	if config.IsDirscan {
+		_, err = scan.NewDirscan(taskId, mainTaskId)
+		if err != nil {
+			logging.RuntimeLog.Error(err)
+			return FailedTask(err.Error()), err
+		}
+	}
 	return SucceedTask(result), nil
 }
 
@@ -473,15 +525,17 @@ func (x *XScan) doPortscan(swg *sizedwaitgroup.SizedWaitGroup, config portscan.C
 func (x *XScan) doDomainscan(swg *sizedwaitgroup.SizedWaitGroup, config domainscan.Config) {
 	defer swg.Done()
 
-	var result *domainscan.Result
 	//扫描
-	result = doDomainScan(config)
+	result, resultVul := doDomainScan(config)
 	//合并结果
 	x.ResultDomain.Lock()
 	for k, v := range result.DomainResult {
 		x.ResultDomain.DomainResult[k] = v
 	}
 	x.ResultDomain.Unlock()
+	x.vulMutex.Lock()
+	x.ResultVul = append(x.ResultVul, resultVul...)
+	x.vulMutex.Unlock()
 }
 
 // doXrayscan 调用一次Xray
@@ -541,11 +595,12 @@ func (x *XScan) OnlineAPISearch(taskId string, mainTaskId string) (result string
 	}
 	//fofa任务支持两种模式：
 	//一种是关键词，需设置SearchByKeyWord为true，只支持fofa
-	//另一种是ip/domain，同时支持fofa、quake、hunter
+	//另一种是ip/domain，同时支持fofa、quake、hunter、shodan、censys、zoomeye、netlas
 	if len(x.Config.OnlineAPIKeyword) > 0 {
 		config.SearchByKeyWord = true
 		config.Target = x.Config.OnlineAPIKeyword
 		config.SearchLimitCount = x.Config.OnlineAPISearchLimit
+		config.KeyWordId = x.Config.OnlineAPIKeywordId
 		//x.ResultIP, x.ResultDomain, result, err = doOnlineAPIAndSave(taskId, mainTaskId, x.Config.On, config)
 	} else if len(x.Config.OnlineAPITarget) > 0 {
 		config.Target = x.Config.OnlineAPITarget
@@ -559,6 +614,18 @@ func (x *XScan) OnlineAPISearch(taskId string, mainTaskId string) (result string
 	if x.Config.IsHunter {
 		x.ResultIP, x.ResultDomain, result, err = doOnlineAPIAndSave(taskId, mainTaskId, "hunter", config)
 	}
+	if x.Config.IsShodan {
+		x.ResultIP, x.ResultDomain, result, err = doOnlineAPIAndSave(taskId, mainTaskId, "shodan", config)
+	}
+	if x.Config.IsCensys {
+		x.ResultIP, x.ResultDomain, result, err = doOnlineAPIAndSave(taskId, mainTaskId, "censys", config)
+	}
+	if x.Config.IsZoomEye {
+		x.ResultIP, x.ResultDomain, result, err = doOnlineAPIAndSave(taskId, mainTaskId, "zoomeye", config)
+	}
+	if x.Config.IsNetlas {
+		x.ResultIP, x.ResultDomain, result, err = doOnlineAPIAndSave(taskId, mainTaskId, "netlas", config)
+	}
 	return
 }
 
@@ -574,10 +641,12 @@ func (x *XScan) Domainscan(taskId string, mainTaskId string) (result string, err
 		IsSubDomainFinder: x.Config.IsSubDomainFinder,
 		IsSubDomainBrute:  x.Config.IsSubDomainBrute,
 		IsCrawler:         x.Config.IsSubDomainCrawler,
+		IsDNSCheck:        x.Config.IsDNSCheck,
 		//
 		IsIgnoreCDN:        conf.GlobalWorkerConfig().Domainscan.IsIgnoreCDN,
 		IsIgnoreOutofChina: conf.GlobalWorkerConfig().Domainscan.IsIgnoreOutofChina,
-		IsIPPortScan:       conf.GlobalWorkerConfig().Domainscan.IsPortScan,
+		// 工作流任务的端口扫描由工作流的阶段定义
+		IsIPPortScan: conf.GlobalWorkerConfig().Domainscan.IsPortScan && x.Config.Workflow == nil,
 
 		WorkspaceId: x.Config.WorkspaceId,
 		IsProxy:     x.Config.IsProxy,
@@ -595,10 +664,11 @@ func (x *XScan) Domainscan(taskId string, mainTaskId string) (result string, err
 	}
 	// 保存结果
 	resultArgs := comm.ScanResultArgs{
-		TaskID:       taskId,
-		MainTaskId:   mainTaskId,
-		DomainConfig: &domainscan.Config{OrgId: config.OrgId, WorkspaceId: x.Config.WorkspaceId},
-		DomainResult: x.ResultDomain.DomainResult,
+		TaskID:              taskId,
+		MainTaskId:          mainTaskId,
+		DomainConfig:        &domainscan.Config{OrgId: config.OrgId, WorkspaceId: x.Config.WorkspaceId},
+		DomainResult:        x.ResultDomain.DomainResult,
+		VulnerabilityResult: x.ResultVul,
 	}
 	if err = comm.CallXClient("SaveScanResult", &resultArgs, &result); err != nil {
 		logging.RuntimeLog.Error(err)
@@ -616,6 +686,7 @@ func (x *XScan) NewPortScan(taskId, mainTaskId string, ipPortMap []map[string][]
 		IsNucleiPoc:   x.Config.IsNucleiPoc,
 		NucleiPocFile: x.Config.NucleiPocFile,
 		IsGobyPoc:     x.Config.IsGobyPoc,
+		IsDirscan:     x.Config.IsDirscan,
 		WorkspaceId:   x.Config.WorkspaceId,
 		IsProxy:       x.Config.IsProxy,
 	}
@@ -682,6 +753,7 @@ func (x *XScan) NewDomainScan(taskId, mainTaskId string, domainMap []map[string]
 		IsNucleiPoc:       x.Config.IsNucleiPoc,
 		NucleiPocFile:     x.Config.NucleiPocFile,
 		IsGobyPoc:         x.Config.IsGobyPoc,
+		IsDirscan:         x.Config.IsDirscan,
 		WorkspaceId:       x.Config.WorkspaceId,
 		IsProxy:           x.Config.IsProxy,
 	}
@@ -728,6 +800,7 @@ func (x *XScan) NewFingerprintScan(taskId, mainTaskId string) (result string, er
 		IsNucleiPoc:   x.Config.IsNucleiPoc,
 		NucleiPocFile: x.Config.NucleiPocFile,
 		IsGobyPoc:     x.Config.IsGobyPoc,
+		IsDirscan:     x.Config.IsDirscan,
 		WorkspaceId:   x.Config.WorkspaceId,
 		IsProxy:       x.Config.IsProxy,
 	}
@@ -801,6 +874,12 @@ func (x *XScan) NewGobyScan(taskId, mainTaskId string) (result string, err error
 	return
 }
 
+// NewDirscan 根据指纹识别获取的URL，生成目录扫描任务
+func (x *XScan) NewDirscan(taskId, mainTaskId string) (result string, err error) {
+	urls := MakeDirscanTarget(x.ResultIP, x.ResultDomain)
+	return NewDirscanTask(taskId, mainTaskId, urls, x.Config.WorkspaceId, x.Config.IsProxy)
+}
+
 // NucleiScan 调用执行Nuclei扫描任务
 func (x *XScan) NucleiScan(taskId string, mainTaskId string) (result string, err error) {
 	// 生成扫描参数
@@ -0,0 +1,148 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+	"strings"
+	"unicode"
+)
+
+const (
+	PasswordAlgorithmArgon2id = "argon2id"
+	PasswordAlgorithmBcrypt   = "bcrypt"
+	PasswordAlgorithmMD5      = "md5" // 旧版本的MD5(password+salt)，仅用于兼容验证
+
+	argon2idTime    = 1
+	argon2idMemory  = 64 * 1024
+	argon2idThreads = 4
+	argon2idKeyLen  = 32
+	argon2idSaltLen = 16
+
+	md5PasswordLength = 48 // 32位hash+16位salt
+)
+
+// HashPassword 使用指定的算法（argon2id或bcrypt）生成密码的hash
+func HashPassword(password, algorithm string) (string, error) {
+	switch algorithm {
+	case PasswordAlgorithmBcrypt:
+		hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+		if err != nil {
+			return "", err
+		}
+		return string(hash), nil
+	case PasswordAlgorithmArgon2id, "":
+		salt := make([]byte, argon2idSaltLen)
+		if _, err := rand.Read(salt); err != nil {
+			return "", err
+		}
+		key := argon2.IDKey([]byte(password), salt, argon2idTime, argon2idMemory, argon2idThreads, argon2idKeyLen)
+		return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s", argon2.Version, argon2idMemory, argon2idTime, argon2idThreads,
+			base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
+	}
+	return "", fmt.Errorf("invalid password algorithm:%s", algorithm)
+}
+
+// GetPasswordAlgorithm 获取密码hash使用的算法
+func GetPasswordAlgorithm(hashedPassword string) string {
+	switch {
+	case strings.HasPrefix(hashedPassword, "$argon2id$"):
+		return PasswordAlgorithmArgon2id
+	case strings.HasPrefix(hashedPassword, "$2a$"), strings.HasPrefix(hashedPassword, "$2b$"), strings.HasPrefix(hashedPassword, "$2y$"):
+		return PasswordAlgorithmBcrypt
+	case len(hashedPassword) == md5PasswordLength:
+		return PasswordAlgorithmMD5
+	}
+	return ""
+}
+
+// VerifyPassword 校验明文密码与保存的hash是否一致，支持argon2id、bcrypt及旧版本的MD5
+func VerifyPassword(password, hashedPassword string) bool {
+	switch GetPasswordAlgorithm(hashedPassword) {
+	case PasswordAlgorithmArgon2id:
+		return verifyArgon2id(password, hashedPassword)
+	case PasswordAlgorithmBcrypt:
+		return bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password)) == nil
+	case PasswordAlgorithmMD5:
+		hash := hashedPassword[:32]
+		salt := hashedPassword[32:]
+		return subtle.ConstantTimeCompare([]byte(MD5V3(password+salt)), []byte(hash)) == 1
+	}
+	return false
+}
+
+// PasswordNeedsRehash 检查保存的hash是否需要使用指定的算法重新生成
+func PasswordNeedsRehash(hashedPassword, algorithm string) bool {
+	if algorithm == "" {
+		algorithm = PasswordAlgorithmArgon2id
+	}
+	if GetPasswordAlgorithm(hashedPassword) != algorithm {
+		return true
+	}
+	if algorithm == PasswordAlgorithmBcrypt {
+		cost, err := bcrypt.Cost([]byte(hashedPassword))
+		return err != nil || cost < bcrypt.DefaultCost
+	}
+	return !strings.HasPrefix(hashedPassword, fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$", argon2.Version, argon2idMemory, argon2idTime, argon2idThreads))
+}
+
+// verifyArgon2id 按hash中的参数计算argon2id并比较
+func verifyArgon2id(password, hashedPassword string) bool {
+	// $argon2id$v=19$m=65536,t=1,p=4$<salt>$<key>
+	parts := strings.Split(hashedPassword, "$")
+	if len(parts) != 6 {
+		return false
+	}
+	var version int
+	var memory, time uint32
+	var threads uint8
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return false
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &memory, &time, &threads); err != nil {
+		return false
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return false
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return false
+	}
+	checkedKey := argon2.IDKey([]byte(password), salt, time, memory, threads, uint32(len(key)))
+	return subtle.ConstantTimeCompare(key, checkedKey) == 1
+}
+
+// CheckPasswordStrength 检查密码是否满足最小长度，及至少包含complexity种字符类型（大写字母、小写字母、数字、特殊字符）
+func CheckPasswordStrength(password string, minLength, complexity int) error {
+	if len([]rune(password)) < minLength {
+		return fmt.Errorf("密码长度不能少于%d位", minLength)
+	}
+	var upper, lower, digit, special bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			special = true
+		}
+	}
+	types := 0
+	for _, b := range []bool{upper, lower, digit, special} {
+		if b {
+			types++
+		}
+	}
+	if types < complexity {
+		return fmt.Errorf("密码需至少包含大写字母、小写字母、数字、特殊字符中的%d种", complexity)
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package utils
+
+import "testing"
+
+func TestHashPassword(t *testing.T) {
+	for _, algorithm := range []string{PasswordAlgorithmArgon2id, PasswordAlgorithmBcrypt} {
+		hash, err := HashPassword("nemo@2024", algorithm)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Log(hash)
+		if GetPasswordAlgorithm(hash) != algorithm {
+			t.Errorf("%s:invalid algorithm", algorithm)
+		}
+		if !VerifyPassword("nemo@2024", hash) || VerifyPassword("nemo@2023", hash) {
+			t.Errorf("%s:verify password fail", algorithm)
+		}
+		if PasswordNeedsRehash(hash, algorithm) {
+			t.Errorf("%s:should not rehash", algorithm)
+		}
+	}
+}
+
+func TestVerifyMD5Password(t *testing.T) {
+	// 默认的nemo用户密码
+	hash := "648ce596dba3b408b523d3d1189b15070123456789abcdef"
+	if !VerifyPassword("nemo", hash) || VerifyPassword("nemo2", hash) {
+		t.Error("verify md5 password fail")
+	}
+	if !PasswordNeedsRehash(hash, PasswordAlgorithmArgon2id) {
+		t.Error("md5 password should rehash")
+	}
+	if VerifyPassword("nemo", "") {
+		t.Error("empty hash should not verify")
+	}
+}
+
+func TestCheckPasswordStrength(t *testing.T) {
+	if CheckPasswordStrength("Ab1!", 8, 3) == nil {
+		t.Error("password too short")
+	}
+	if CheckPasswordStrength("abcdefgh1", 8, 3) == nil {
+		t.Error("password too simple")
+	}
+	if err := CheckPasswordStrength("abcdefgh1!", 8, 3); err != nil {
+		t.Error(err)
+	}
+}
@@ -57,13 +57,13 @@ func (t *TaskSlice) DoIpSlice() (target []string, port []string) {
 		target = sliceIP(ipv4IntMap, ipv6BigIntMap, t.IpSliceNumber)
 		port = []string{t.Port}
 	case SliceByPort:
-		portIntMap := parseAllPort(t.Port)
+		portIntMap := ParseAllPort(t.Port)
 		port = slicePort(portIntMap, t.PortSliceNumber)
 		target = t.IpTarget
 	case SliceByIPAndPort:
 		ipv4IntMap, ipv6BigIntMap := parseAllIP(t.IpTarget)
 		target = sliceIP(ipv4IntMap, ipv6BigIntMap, t.IpSliceNumber)
-		portIntMap := parseAllPort(t.Port)
+		portIntMap := ParseAllPort(t.Port)
 		port = slicePort(portIntMap, t.PortSliceNumber)
 	default:
 		target = []string{strings.Join(t.IpTarget, ",")}
@@ -260,8 +260,8 @@ func slicePort(portIntMap map[int]struct{}, sliceNumber int) (ports []string) {
 	return
 }
 
-// parseAllPort 解析Port，返回int的端口列表
-func parseAllPort(portstr string) (portIntMap map[int]struct{}) {
+// ParseAllPort 解析Port，返回int的端口列表
+func ParseAllPort(portstr string) (portIntMap map[int]struct{}) {
 	portIntMap = make(map[int]struct{})
 	if strings.HasPrefix(portstr, "--top-ports") {
 		switch portstr {
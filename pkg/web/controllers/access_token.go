@@ -0,0 +1,218 @@
+package controllers
+
+import (
+	"github.com/hanc00l/nemo_go/pkg/db"
+	"github.com/hanc00l/nemo_go/pkg/logging"
+	"strings"
+	"time"
+)
+
+type AccessTokenController struct {
+	BaseController
+}
+
+type AccessTokenData struct {
+	Id               int    `json:"id" form:"id"`
+	Index            int    `json:"index" form:"-"`
+	TokenName        string `json:"name" form:"name"`
+	TokenPrefix      string `json:"prefix" form:"-"`
+	Workspace        string `json:"workspace" form:"workspace"`
+	State            string `json:"state" form:"-"`
+	ExpireDays       int    `json:"-" form:"expire_days"`
+	ExpireDatetime   string `json:"expire_time" form:"-"`
+	LastUsedDatetime string `json:"last_used_time" form:"-"`
+	LastUsedIP       string `json:"last_used_ip" form:"-"`
+	CreateDatetime   string `json:"create_time" form:"-"`
+}
+
+const (
+	// accessTokenPrefixLength 列表中显示的令牌前缀长度，便于用户识别令牌
+	accessTokenPrefixLength = len(AccessTokenPrefix) + 8
+	// accessTokenMaxExpireDays 令牌最长的有效期（天）
+	accessTokenMaxExpireDays = 3650
+)
+
+// IndexAction 显示列表页面
+func (c *AccessTokenController) IndexAction() {
+	c.Layout = "base.html"
+	c.TplName = "access-token-list.html"
+}
+
+// ListAction 当前用户的访问令牌列表数据
+func (c *AccessTokenController) ListAction() {
+	defer c.ServeJSON()
+
+	req := DatableRequestParam{}
+	err := c.ParseForm(&req)
+	if err != nil {
+		logging.RuntimeLog.Error(err.Error())
+	}
+	resp := DataTableResponseData{Draw: req.Draw}
+	user := db.User{UserName: c.GetCurrentUser()}
+	if user.UserName != "" && user.GetByUsername() {
+		userToken := db.UserToken{}
+		for i, t := range userToken.GetsByUserId(user.Id) {
+			resp.Data = append(resp.Data, c.makeAccessTokenData(i+1, t))
+		}
+	}
+	if resp.Data == nil {
+		resp.Data = make([]interface{}, 0)
+	}
+	resp.RecordsTotal = len(resp.Data)
+	resp.RecordsFiltered = len(resp.Data)
+	c.Data["json"] = resp
+}
+
+// AddSaveAction 为当前用户生成一个新的访问令牌，令牌只在生成时返回一次
+func (c *AccessTokenController) AddSaveAction() {
+	defer c.ServeJSON()
+	// 不允许使用访问令牌再生成新的访问令牌
+	if c.IsAccessTokenRequest() {
+		c.FailedStatus("当前用户权限不允许！")
+		return
+	}
+
+	data := AccessTokenData{}
+	err := c.ParseForm(&data)
+	if err != nil {
+		logging.RuntimeLog.Error(err.Error())
+		c.FailedStatus(err.Error())
+		return
+	}
+	data.TokenName = strings.TrimSpace(data.TokenName)
+	if data.TokenName == "" {
+		c.FailedStatus("令牌名称不能为空！")
+		return
+	}
+	if data.ExpireDays < 0 || data.ExpireDays > accessTokenMaxExpireDays {
+		c.FailedStatus("令牌有效期错误！")
+		return
+	}
+	user := db.User{UserName: c.GetCurrentUser()}
+	if user.UserName == "" || !user.GetByUsername() || user.State != "enable" {
+		c.FailedStatus("当前用户权限不允许！")
+		return
+	}
+	workspaceIds := ParseAccessTokenWorkspace(data.Workspace)
+	if len(workspaceIds) == 0 {
+		c.FailedStatus("未选择授权的工作空间！")
+		return
+	}
+	for _, id := range workspaceIds {
+		if !c.checkUserWorkspace(user, id) {
+			c.FailedStatus("当前用户没有工作空间的权限！")
+			return
+		}
+	}
+	token, tokenHash, err := GenerateAccessToken()
+	if err != nil {
+		logging.RuntimeLog.Error(err.Error())
+		c.FailedStatus("生成令牌失败！")
+		return
+	}
+	userToken := db.UserToken{
+		UserId:      user.Id,
+		TokenName:   data.TokenName,
+		TokenHash:   tokenHash,
+		TokenPrefix: token[:accessTokenPrefixLength],
+		Workspace:   FormatAccessTokenWorkspace(workspaceIds),
+		State:       "enable",
+	}
+	if data.ExpireDays > 0 {
+		expire := time.Now().AddDate(0, 0, data.ExpireDays)
+		userToken.ExpireDatetime = &expire
+	}
+	if !userToken.Add() {
+		c.FailedStatus("生成令牌失败！")
+		return
+	}
+	c.SucceededStatus(token)
+}
+
+// RevokeAction 撤销当前用户的一个访问令牌
+func (c *AccessTokenController) RevokeAction() {
+	defer c.ServeJSON()
+
+	userToken, ok := c.getUserToken()
+	if !ok {
+		return
+	}
+	c.MakeStatusResponse(userToken.Update(map[string]interface{}{"state": "disable"}))
+}
+
+// DeleteAction 删除当前用户的一个访问令牌
+func (c *AccessTokenController) DeleteAction() {
+	defer c.ServeJSON()
+
+	userToken, ok := c.getUserToken()
+	if !ok {
+		return
+	}
+	c.MakeStatusResponse(userToken.Delete())
+}
+
+// getUserToken 获取请求的访问令牌，并检查是否属于当前用户
+func (c *AccessTokenController) getUserToken() (userToken db.UserToken, ok bool) {
+	id, err := c.GetInt("id")
+	if err != nil {
+		logging.RuntimeLog.Error(err.Error())
+		c.FailedStatus(err.Error())
+		return
+	}
+	user := db.User{UserName: c.GetCurrentUser()}
+	if user.UserName == "" || !user.GetByUsername() {
+		c.FailedStatus("当前用户权限不允许！")
+		return
+	}
+	userToken = db.UserToken{Id: id}
+	if !userToken.Get() || userToken.UserId != user.Id {
+		c.FailedStatus("令牌不存在！")
+		return
+	}
+	return userToken, true
+}
+
+// checkUserWorkspace 检查用户是否有工作空间的权限
+func (c *AccessTokenController) checkUserWorkspace(user db.User, workspaceId int) bool {
+	workspace := db.Workspace{Id: workspaceId}
+	if !workspace.Get() || workspace.State != "enable" {
+		return false
+	}
+	if user.UserRole == SuperAdmin {
+		return true
+	}
+	userWorkspace := db.UserWorkspace{UserId: user.Id, WorkspaceId: workspaceId}
+	return userWorkspace.GetByUserAndWorkspaceId()
+}
+
+// makeAccessTokenData 生成访问令牌的列表数据，不包含令牌的hash
+func (c *AccessTokenController) makeAccessTokenData(index int, t db.UserToken) AccessTokenData {
+	data := AccessTokenData{
+		Id:             t.Id,
+		Index:          index,
+		TokenName:      t.TokenName,
+		TokenPrefix:    t.TokenPrefix + "...",
+		State:          t.State,
+		ExpireDatetime: "永不过期",
+		LastUsedIP:     t.LastUsedIP,
+		CreateDatetime: FormatDateTime(t.CreateDatetime),
+	}
+	if t.ExpireDatetime != nil {
+		data.ExpireDatetime = FormatDateTime(*t.ExpireDatetime)
+		if t.State == "enable" && time.Now().After(*t.ExpireDatetime) {
+			data.State = "expired"
+		}
+	}
+	if t.LastUsedDatetime != nil {
+		data.LastUsedDatetime = FormatDateTime(*t.LastUsedDatetime)
+	}
+	var workspaceNames []string
+	for _, id := range ParseAccessTokenWorkspace(t.Workspace) {
+		workspace := db.Workspace{Id: id}
+		if workspace.Get() {
+			workspaceNames = append(workspaceNames, workspace.WorkspaceName)
+		}
+	}
+	data.Workspace = strings.Join(workspaceNames, ",")
+	return data
+}
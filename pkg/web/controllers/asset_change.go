@@ -0,0 +1,72 @@
+package controllers
+
+import (
+	"github.com/hanc00l/nemo_go/pkg/db"
+	"time"
+)
+
+const assetTimelineDisplayNumber = 200
+
+// AssetChangeInfo 资产的一条变更记录
+type AssetChangeInfo struct {
+	Id         int    `json:"id"`
+	Port       int    `json:"port"`
+	ChangeType string `json:"changeType"`
+	Source     string `json:"source"`
+	Tag        string `json:"tag"`
+	Content    string `json:"content"`
+	OldContent string `json:"oldContent"`
+	TaskId     string `json:"taskId"`
+	CreateTime string `json:"createTime"`
+}
+
+// AssetTimelineData 资产的变更时间线
+type AssetTimelineData struct {
+	Asset    string            `json:"asset"`
+	Total    int               `json:"total"`
+	Timeline []AssetChangeInfo `json:"timeline"`
+}
+
+// getAssetTimeline 获取资产的变更记录，按时间倒序；since为空时返回全部的变更记录
+func getAssetTimeline(workspaceId int, assetType, asset string, since string, page, rowsPerPage int) (r AssetTimelineData) {
+	r.Asset = asset
+	searchMap := map[string]interface{}{
+		"workspace_id": workspaceId,
+		"asset_type":   assetType,
+		"asset":        asset,
+	}
+	if since != "" {
+		if t, err := time.ParseInLocation("2006-01-02", since, time.Local); err == nil {
+			searchMap["since"] = t
+		}
+	}
+	assetChange := db.AssetChange{}
+	results, total := assetChange.Gets(searchMap, page, rowsPerPage)
+	r.Total = total
+	for _, row := range results {
+		r.Timeline = append(r.Timeline, AssetChangeInfo{
+			Id:         row.Id,
+			Port:       row.Port,
+			ChangeType: row.ChangeType,
+			Source:     row.Source,
+			Tag:        row.Tag,
+			Content:    row.Content,
+			OldContent: row.OldContent,
+			TaskId:     row.TaskId,
+			CreateTime: FormatDateTime(row.CreateDatetime),
+		})
+	}
+	return
+}
+
+// setStaleSearchMap 生成失效资产及最后发现时间的查询条件
+func setStaleSearchMap(searchMap map[string]interface{}, selectStale bool, lastSeenBefore string) {
+	if selectStale {
+		searchMap["stale"] = true
+	}
+	if lastSeenBefore != "" {
+		if t, err := time.ParseInLocation("2006-01-02", lastSeenBefore, time.Local); err == nil {
+			searchMap["last_seen_before"] = t
+		}
+	}
+}
@@ -13,7 +13,9 @@ import (
 
 type BaseController struct {
 	web.Controller
-	IsServerAPI bool //server工作模式是否是api方式
+	IsServerAPI   bool //server工作模式是否是api方式
+	tokenData     *TokenData
+	isTokenParsed bool
 }
 
 const (
@@ -237,7 +239,7 @@ func (c *BaseController) CheckMultiAccessRequest(roleList []RequestRole, forceRe
 func (c *BaseController) GetCurrentWorkspace() (workspaceId int) {
 	workspaceId = -1
 	if c.IsServerAPI {
-		jwtData := ValidToken(c.GetJWTTokenValue())
+		jwtData := c.getTokenData()
 		if jwtData != nil && jwtData.Workspace > 0 {
 			workspaceId = jwtData.Workspace
 		}
@@ -265,7 +267,7 @@ func (c *BaseController) GetCurrentWorkspaceGUID() (workspaceGUID string) {
 // GetCurrentUser 获取保存在session或jwt中的username
 func (c *BaseController) GetCurrentUser() (userName string) {
 	if c.IsServerAPI {
-		jwtData := ValidToken(c.GetJWTTokenValue())
+		jwtData := c.getTokenData()
 		if jwtData != nil && jwtData.Workspace > 0 {
 			userName = jwtData.User
 		}
@@ -280,3 +282,17 @@ func (c *BaseController) GetJWTTokenValue() string {
 	//Authorization: Bearer <token>
 	return GetTokenValueFromHeader(c.Ctx.Input.Header("Authorization"))
 }
+
+// IsAccessTokenRequest 检查请求是否是使用用户访问令牌
+func (c *BaseController) IsAccessTokenRequest() bool {
+	return c.IsServerAPI && IsAccessToken(c.GetJWTTokenValue())
+}
+
+// getTokenData 获取并验证请求中的token（JWT或用户访问令牌），同一请求中只验证一次
+func (c *BaseController) getTokenData() *TokenData {
+	if !c.isTokenParsed {
+		c.tokenData = ValidRequestToken(c.GetJWTTokenValue(), c.Ctx.Input.Header(AccessTokenWorkspaceHeader), c.Ctx.Input.IP())
+		c.isTokenParsed = true
+	}
+	return c.tokenData
+}
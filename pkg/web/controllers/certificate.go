@@ -0,0 +1,282 @@
+package controllers
+
+import (
+	"fmt"
+	"github.com/hanc00l/nemo_go/pkg/db"
+	"github.com/hanc00l/nemo_go/pkg/logging"
+	"time"
+)
+
+type CertificateController struct {
+	BaseController
+}
+
+// certificateRequestParam 请求参数
+type certificateRequestParam struct {
+	DatableRequestParam
+	Domain     string `form:"cert_domain"`
+	Issuer     string `form:"cert_issuer"`
+	Host       string `form:"cert_host"`
+	ExpireDays int    `form:"expire_days"`
+	Expired    bool   `form:"expired"`
+	DateDelta  int    `form:"date_delta"`
+}
+
+// CertificateData 证书列表的数据
+type CertificateData struct {
+	Id          int    `json:"id"`
+	Index       int    `json:"index"`
+	SubjectCN   string `json:"subject_cn"`
+	SubjectOrg  string `json:"subject_org"`
+	Issuer      string `json:"issuer"`
+	SAN         string `json:"san"`
+	NotAfter    string `json:"not_after"`
+	ExpireDays  int    `json:"expire_days"`
+	HostCount   int    `json:"host_count"`
+	UpdateTime  string `json:"update_datetime"`
+	WorkspaceId int    `json:"workspace"`
+}
+
+// CertificateInfo 证书的详情
+type CertificateInfo struct {
+	Id          int
+	Fingerprint string
+	Serial      string
+	SubjectCN   string
+	SubjectDN   string
+	SubjectOrg  string
+	IssuerCN    string
+	IssuerDN    string
+	IssuerOrg   string
+	SAN         []string
+	NotBefore   string
+	NotAfter    string
+	ExpireDays  int
+	Hosts       []CertificateHostInfo
+	CreateTime  string
+	UpdateTime  string
+	Workspace   int
+}
+
+// CertificateHostInfo 使用证书的主机，或主机使用的证书
+type CertificateHostInfo struct {
+	CertId     int
+	HostType   string
+	Host       string
+	Port       int
+	SubjectCN  string
+	Issuer     string
+	NotAfter   string
+	ExpireDays int
+	HostCount  int
+	UpdateTime string
+}
+
+func (c *CertificateController) IndexAction() {
+	c.Layout = "base.html"
+	c.TplName = "certificate-list.html"
+}
+
+// ListAction 证书列表的数据
+func (c *CertificateController) ListAction() {
+	defer c.ServeJSON()
+
+	req := certificateRequestParam{}
+	err := c.ParseForm(&req)
+	if err != nil {
+		logging.RuntimeLog.Error(err)
+		logging.CLILog.Error(err)
+	}
+	c.validateRequestParam(&req)
+	c.Data["json"] = c.getCertificateListData(req)
+}
+
+// InfoAction 显示一个证书的详情及使用证书的全部主机
+func (c *CertificateController) InfoAction() {
+	var certInfo CertificateInfo
+
+	certId, err := c.GetInt("id")
+	if err != nil {
+		logging.RuntimeLog.Error(err)
+		logging.CLILog.Error(err)
+	} else {
+		certInfo = getCertificateInfo(certId)
+	}
+	if c.IsServerAPI {
+		c.Data["json"] = certInfo
+		c.ServeJSON()
+	} else {
+		c.Data["cert_info"] = certInfo
+		c.Layout = "base.html"
+		c.TplName = "certificate-info.html"
+	}
+}
+
+// DeleteAction 删除一个证书
+func (c *CertificateController) DeleteAction() {
+	defer c.ServeJSON()
+	if c.CheckMultiAccessRequest([]RequestRole{SuperAdmin, Admin}, false) == false {
+		c.FailedStatus("当前用户权限不允许！")
+		return
+	}
+
+	id, err := c.GetInt("id")
+	if err != nil {
+		logging.RuntimeLog.Error(err)
+		logging.CLILog.Error(err)
+		c.FailedStatus(err.Error())
+		return
+	}
+	cert := db.Certificate{Id: id}
+	c.MakeStatusResponse(cert.Delete())
+}
+
+// validateRequestParam 校验请求的参数
+func (c *CertificateController) validateRequestParam(req *certificateRequestParam) {
+	if req.Length <= 0 {
+		req.Length = 50
+	}
+	if req.Start < 0 {
+		req.Start = 0
+	}
+}
+
+// getSearchMap 根据查询参数生成查询条件
+func (c *CertificateController) getSearchMap(req certificateRequestParam) (searchMap map[string]interface{}) {
+	searchMap = make(map[string]interface{})
+
+	workspaceId := c.GetCurrentWorkspace()
+	if workspaceId > 0 {
+		searchMap["workspace_id"] = workspaceId
+	}
+	if req.Domain != "" {
+		searchMap["domain"] = req.Domain
+	}
+	if req.Issuer != "" {
+		searchMap["issuer"] = req.Issuer
+	}
+	if req.Host != "" {
+		searchMap["host"] = req.Host
+	}
+	if req.Expired {
+		searchMap["expired"] = true
+	} else if req.ExpireDays > 0 {
+		searchMap["expire_days"] = req.ExpireDays
+	}
+	if req.DateDelta > 0 {
+		searchMap["date_delta"] = req.DateDelta
+	}
+	return
+}
+
+// getCertificateListData 获取列显示的数据
+func (c *CertificateController) getCertificateListData(req certificateRequestParam) (resp DataTableResponseData) {
+	cert := db.Certificate{}
+	searchMap := c.getSearchMap(req)
+	startPage := req.Start/req.Length + 1
+	results, total := cert.Gets(searchMap, startPage, req.Length)
+	now := time.Now()
+	for i, row := range results {
+		certHost := db.CertificateHost{CertId: row.Id}
+		resp.Data = append(resp.Data, CertificateData{
+			Id:          row.Id,
+			Index:       req.Start + i + 1,
+			SubjectCN:   row.SubjectCN,
+			SubjectOrg:  row.SubjectOrg,
+			Issuer:      formatCertificateIssuer(row),
+			SAN:         row.SAN,
+			NotAfter:    formatCertificateTime(row.NotAfter),
+			ExpireDays:  row.ExpireDays(now),
+			HostCount:   certHost.CountByCertId(),
+			UpdateTime:  FormatDateTime(row.UpdateDatetime),
+			WorkspaceId: row.WorkspaceId,
+		})
+	}
+	resp.Draw = req.Draw
+	resp.RecordsTotal = total
+	resp.RecordsFiltered = total
+	if resp.Data == nil {
+		resp.Data = make([]interface{}, 0)
+	}
+	return
+}
+
+// getCertificateInfo 获取一个证书的详情
+func getCertificateInfo(certId int) (r CertificateInfo) {
+	cert := db.Certificate{Id: certId}
+	if !cert.Get() {
+		return
+	}
+	r.Id = cert.Id
+	r.Fingerprint = cert.Fingerprint
+	r.Serial = cert.Serial
+	r.SubjectCN = cert.SubjectCN
+	r.SubjectDN = cert.SubjectDN
+	r.SubjectOrg = cert.SubjectOrg
+	r.IssuerCN = cert.IssuerCN
+	r.IssuerDN = cert.IssuerDN
+	r.IssuerOrg = cert.IssuerOrg
+	r.SAN = cert.SANs()
+	r.NotBefore = formatCertificateTime(cert.NotBefore)
+	r.NotAfter = formatCertificateTime(cert.NotAfter)
+	r.ExpireDays = cert.ExpireDays(time.Now())
+	r.CreateTime = FormatDateTime(cert.CreateDatetime)
+	r.UpdateTime = FormatDateTime(cert.UpdateDatetime)
+	r.Workspace = cert.WorkspaceId
+	certHost := db.CertificateHost{CertId: certId}
+	for _, h := range certHost.GetsByCertId() {
+		r.Hosts = append(r.Hosts, CertificateHostInfo{
+			CertId:     certId,
+			HostType:   h.HostType,
+			Host:       h.Host,
+			Port:       h.Port,
+			UpdateTime: FormatDateTime(h.UpdateDatetime),
+		})
+	}
+	return
+}
+
+// getHostCertificate 获取IP或域名使用的证书，以及使用相同证书的主机数量
+func getHostCertificate(workspaceId int, host string) (r []CertificateHostInfo) {
+	certHost := db.CertificateHost{WorkspaceId: workspaceId, Host: host}
+	now := time.Now()
+	for _, h := range certHost.GetsByHost() {
+		cert := db.Certificate{Id: h.CertId}
+		if !cert.Get() {
+			continue
+		}
+		sharedHost := db.CertificateHost{CertId: h.CertId}
+		r = append(r, CertificateHostInfo{
+			CertId:     h.CertId,
+			HostType:   h.HostType,
+			Host:       h.Host,
+			Port:       h.Port,
+			SubjectCN:  cert.SubjectCN,
+			Issuer:     formatCertificateIssuer(cert),
+			NotAfter:   formatCertificateTime(cert.NotAfter),
+			ExpireDays: cert.ExpireDays(now),
+			HostCount:  sharedHost.CountByCertId(),
+			UpdateTime: FormatDateTime(h.UpdateDatetime),
+		})
+	}
+	return
+}
+
+// formatCertificateIssuer 证书的颁发者
+func formatCertificateIssuer(cert db.Certificate) string {
+	if cert.IssuerOrg != "" && cert.IssuerCN != "" {
+		return fmt.Sprintf("%s(%s)", cert.IssuerCN, cert.IssuerOrg)
+	}
+	if cert.IssuerCN != "" {
+		return cert.IssuerCN
+	}
+	return cert.IssuerOrg
+}
+
+// formatCertificateTime 证书的有效期时间
+func formatCertificateTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return FormatDateTime(*t)
+}
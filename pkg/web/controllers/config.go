@@ -15,9 +15,11 @@ import (
 	"github.com/hanc00l/nemo_go/pkg/task/portscan"
 	"github.com/hanc00l/nemo_go/pkg/utils"
 	"github.com/remeh/sizedwaitgroup"
+	"gopkg.in/yaml.v2"
 	"os"
 	"path"
 	"path/filepath"
+	"sort"
 	"strings"
 )
 
@@ -52,6 +54,7 @@ type DefaultConfig struct {
 	//task
 	IpSliceNumber   int    `json:"ipslicenumber" form:"ipslicenumber"`
 	PortSliceNumber int    `json:"portslicenumber" form:"portslicenumber"`
+	StaleMissCount  int    `json:"stalemisscount" form:"stalemisscount"`
 	Version         string `json:"version" form:"version"`
 	TaskWorkspace   string `json:"taskworkspace" form:"taskworkspace"`
 	//fingerprint
@@ -61,18 +64,37 @@ type DefaultConfig struct {
 	IsIconHash       bool `json:"iconhash" form:"iconhash"`
 	IsFingerprintx   bool `json:"fingerprintx" form:"fingerprintx"`
 	// onlineapi
-	IsFofa           bool   `json:"fofa" form:"fofa"`
-	IsQuake          bool   `json:"quake" form:"quake"`
-	IsHunter         bool   `json:"hunter" form:"hunter"`
-	ServerChanToken  string `json:"serverchan" form:"serverchan"`
-	DingTalkToken    string `json:"dingtalk" form:"dingtalk"`
-	FeishuToken      string `json:"feishu" form:"feishu"`
-	FofaToken        string `json:"fofatoken" form:"fofatoken"`
-	HunterToken      string `json:"huntertoken" form:"huntertoken"`
-	QuakeToken       string `json:"quaketoken" form:"quaketoken"`
-	ChinazToken      string `json:"chinaztoken" form:"chinaztoken"`
-	SearchPageSize   int    `json:"pagesize" form:"pagesize"`
-	SearchLimitCount int    `json:"limitcount" form:"limitcount"`
+	IsFofa            bool   `json:"fofa" form:"fofa"`
+	IsQuake           bool   `json:"quake" form:"quake"`
+	IsHunter          bool   `json:"hunter" form:"hunter"`
+	ServerChanToken   string `json:"serverchan" form:"serverchan"`
+	DingTalkToken     string `json:"dingtalk" form:"dingtalk"`
+	FeishuToken       string `json:"feishu" form:"feishu"`
+	WeComToken        string `json:"wecom" form:"wecom"`
+	SlackURL          string `json:"slackurl" form:"slackurl"`
+	TelegramToken     string `json:"telegram" form:"telegram"`
+	TelegramChatId    string `json:"telegramchatid" form:"telegramchatid"`
+	WebhookURL        string `json:"webhookurl" form:"webhookurl"`
+	WebhookSecret     string `json:"webhooksecret" form:"webhooksecret"`
+	SMTPHost          string `json:"smtphost" form:"smtphost"`
+	SMTPPort          int    `json:"smtpport" form:"smtpport"`
+	SMTPUsername      string `json:"smtpusername" form:"smtpusername"`
+	SMTPPassword      string `json:"smtppassword" form:"smtppassword"`
+	SMTPFrom          string `json:"smtpfrom" form:"smtpfrom"`
+	SMTPRecipients    string `json:"smtprecipients" form:"smtprecipients"`
+	NotifyRule        string `json:"notifyrule" form:"notifyrule"`
+	FofaToken         string `json:"fofatoken" form:"fofatoken"`
+	HunterToken       string `json:"huntertoken" form:"huntertoken"`
+	QuakeToken        string `json:"quaketoken" form:"quaketoken"`
+	ShodanToken       string `json:"shodantoken" form:"shodantoken"`
+	CensysToken       string `json:"censystoken" form:"censystoken"`
+	ZoomEyeToken      string `json:"zoomeyetoken" form:"zoomeyetoken"`
+	NetlasToken       string `json:"netlastoken" form:"netlastoken"`
+	ChinazToken       string `json:"chinaztoken" form:"chinaztoken"`
+	SearchPageSize    int    `json:"pagesize" form:"pagesize"`
+	SearchLimitCount  int    `json:"limitcount" form:"limitcount"`
+	SearchPointBudget int    `json:"pointbudget" form:"pointbudget"`
+	CacheTTL          int    `json:"cachettl" form:"cachettl"`
 	// domainscan
 	Wordlist           string `json:"wordlist" form:"wordlist"`
 	IsSubDomainFinder  bool   `json:"subfinder" form:"subfinder"`
@@ -83,6 +105,8 @@ type DefaultConfig struct {
 	IsPortscan         bool   `json:"portscan" form:"portscan"`
 	IsWhois            bool   `json:"whois" form:"whois"`
 	IsICP              bool   `json:"icp" form:"icp"`
+	IsDNSRecord        bool   `json:"dnsrecord" form:"dnsrecord"`
+	IsDNSCheck         bool   `json:"dnscheck" form:"dnscheck"`
 	// proxy
 	ProxyList string `json:"proxyList" form:"proxyList"`
 	// wiki:feishu
@@ -198,10 +222,14 @@ func (c *ConfigController) LoadWorkerConfigAction() {
 		IsIconHash:       fingerprint.IsIconHash,
 		IsFingerprintx:   fingerprint.IsFingerprintx,
 		//
-		FofaToken:   apiConfig.Fofa.Key,
-		HunterToken: apiConfig.Hunter.Key,
-		QuakeToken:  apiConfig.Quake.Key,
-		ChinazToken: apiConfig.ICP.Key,
+		FofaToken:    apiConfig.Fofa.Key,
+		HunterToken:  apiConfig.Hunter.Key,
+		QuakeToken:   apiConfig.Quake.Key,
+		ShodanToken:  apiConfig.Shodan.Key,
+		CensysToken:  apiConfig.Censys.Key,
+		ZoomEyeToken: apiConfig.ZoomEye.Key,
+		NetlasToken:  apiConfig.Netlas.Key,
+		ChinazToken:  apiConfig.ICP.Key,
 		//
 		Wordlist:           domainscan.Wordlist,
 		IsSubDomainFinder:  domainscan.IsSubDomainFinder,
@@ -212,12 +240,16 @@ func (c *ConfigController) LoadWorkerConfigAction() {
 		IsPortscan:         domainscan.IsPortScan,
 		IsWhois:            domainscan.IsWhois,
 		IsICP:              domainscan.IsICP,
+		IsDNSRecord:        domainscan.IsDNSRecord,
+		IsDNSCheck:         domainscan.IsDNSCheck,
 		//onlineAPI:
-		IsFofa:           onlineAPI.IsFofa,
-		IsHunter:         onlineAPI.IsHunter,
-		IsQuake:          onlineAPI.IsQuake,
-		SearchPageSize:   apiConfig.SearchPageSize,
-		SearchLimitCount: apiConfig.SearchLimitCount,
+		IsFofa:            onlineAPI.IsFofa,
+		IsHunter:          onlineAPI.IsHunter,
+		IsQuake:           onlineAPI.IsQuake,
+		SearchPageSize:    apiConfig.SearchPageSize,
+		SearchLimitCount:  apiConfig.SearchLimitCount,
+		SearchPointBudget: apiConfig.SearchPointBudget,
+		CacheTTL:          apiConfig.CacheTTL,
 		//
 		MaxPortPerIP:   filter.MaxPortPerIp,
 		MaxDomainPerIP: filter.MaxDomainPerIp,
@@ -251,10 +283,24 @@ func (c *ConfigController) LoadServerConfigAction() {
 	data := DefaultConfig{
 		IpSliceNumber:   task.IpSliceNumber,
 		PortSliceNumber: task.PortSliceNumber,
+		StaleMissCount:  task.StaleMissCount,
 		//
 		ServerChanToken: notifyToken["serverchan"].Token,
 		DingTalkToken:   notifyToken["dingtalk"].Token,
 		FeishuToken:     notifyToken["feishu"].Token,
+		WeComToken:      notifyToken["wecom"].Token,
+		SlackURL:        notifyToken["slack"].URL,
+		TelegramToken:   notifyToken["telegram"].Token,
+		TelegramChatId:  strings.Join(notifyToken["telegram"].Recipients, ","),
+		WebhookURL:      notifyToken["webhook"].URL,
+		WebhookSecret:   notifyToken["webhook"].Secret,
+		SMTPHost:        notifyToken["smtp"].Host,
+		SMTPPort:        notifyToken["smtp"].Port,
+		SMTPUsername:    notifyToken["smtp"].Username,
+		SMTPPassword:    notifyToken["smtp"].Password,
+		SMTPFrom:        notifyToken["smtp"].From,
+		SMTPRecipients:  strings.Join(notifyToken["smtp"].Recipients, ","),
+		NotifyRule:      formatNotifyRule(conf.GlobalServerConfig().NotifyRule),
 		//
 		FeishuAppId:        feishu.AppId,
 		FeishuAppSecret:    feishu.AppSecret,
@@ -300,6 +346,10 @@ func (c *ConfigController) ChangePasswordAction() {
 		c.FailedStatus("密码为空！")
 		return
 	}
+	if err := CheckPasswordPolicy(newPass); err != nil {
+		c.FailedStatus(err.Error())
+		return
+	}
 	userName := c.GetCurrentUser()
 	if len(userName) == 0 {
 		c.FailedStatus("修改密码失败！")
@@ -379,7 +429,8 @@ func (c *ConfigController) SaveTaskSliceNumberAction() {
 
 	ipSliceNumber, err1 := c.GetInt("ipslicenumber", utils.DefaultIpSliceNumber)
 	portSliceNumber, err2 := c.GetInt("portslicenumber", utils.DefaultPortSliceNumber)
-	if err1 != nil || err2 != nil {
+	staleMissCount, err3 := c.GetInt("stalemisscount", 0)
+	if err1 != nil || err2 != nil || err3 != nil || staleMissCount < 0 {
 		c.FailedStatus("数量错误")
 		return
 	}
@@ -390,6 +441,7 @@ func (c *ConfigController) SaveTaskSliceNumberAction() {
 	}
 	conf.GlobalServerConfig().Task.IpSliceNumber = ipSliceNumber
 	conf.GlobalServerConfig().Task.PortSliceNumber = portSliceNumber
+	conf.GlobalServerConfig().Task.StaleMissCount = staleMissCount
 	err = conf.GlobalServerConfig().WriteConfig()
 	if err != nil {
 		c.FailedStatus(err.Error())
@@ -436,6 +488,25 @@ func (c *ConfigController) SaveTaskNotifyAction() {
 	serverChanToken := c.GetString("token_serverchan", "")
 	dingtalkToken := c.GetString("token_dingtalk", "")
 	feishuToken := c.GetString("token_feishu", "")
+	wecomToken := c.GetString("token_wecom", "")
+	slack := conf.Notify{URL: c.GetString("url_slack", "")}
+	telegram := conf.Notify{
+		Token:      c.GetString("token_telegram", ""),
+		Recipients: splitNotifyRecipients(c.GetString("chatid_telegram", "")),
+	}
+	webhook := conf.Notify{
+		URL:    c.GetString("url_webhook", ""),
+		Secret: c.GetString("secret_webhook", ""),
+	}
+	smtpPort, _ := c.GetInt("smtp_port", 0)
+	smtp := conf.Notify{
+		Host:       c.GetString("smtp_host", ""),
+		Port:       smtpPort,
+		Username:   c.GetString("smtp_username", ""),
+		Password:   c.GetString("smtp_password", ""),
+		From:       c.GetString("smtp_from", ""),
+		Recipients: splitNotifyRecipients(c.GetString("smtp_recipients", "")),
+	}
 
 	err := conf.GlobalServerConfig().ReloadConfig()
 	if err != nil {
@@ -445,9 +516,23 @@ func (c *ConfigController) SaveTaskNotifyAction() {
 	if conf.GlobalServerConfig().Notify == nil {
 		conf.GlobalServerConfig().Notify = make(map[string]conf.Notify)
 	}
-	conf.GlobalServerConfig().Notify["serverchan"] = conf.Notify{Token: serverChanToken}
-	conf.GlobalServerConfig().Notify["dingtalk"] = conf.Notify{Token: dingtalkToken}
-	conf.GlobalServerConfig().Notify["feishu"] = conf.Notify{Token: feishuToken}
+	notifyConfig := map[string]conf.Notify{
+		"serverchan": {Token: serverChanToken},
+		"dingtalk":   {Token: dingtalkToken},
+		"feishu":     {Token: feishuToken},
+		"wecom":      {Token: wecomToken},
+		"slack":      slack,
+		"telegram":   telegram,
+		"webhook":    webhook,
+		"smtp":       smtp,
+	}
+	for name, n := range notifyConfig {
+		// 消息模板只在配置文件中设置，保留原有的配置
+		if old, ok := conf.GlobalServerConfig().Notify[name]; ok {
+			n.Title, n.Template, n.Format = old.Title, old.Template, old.Format
+		}
+		conf.GlobalServerConfig().Notify[name] = n
+	}
 
 	err = conf.GlobalServerConfig().WriteConfig()
 	if err != nil {
@@ -456,6 +541,52 @@ func (c *ConfigController) SaveTaskNotifyAction() {
 	c.SucceededStatus("保存配置成功")
 }
 
+// SaveNotifyRuleAction 保存事件通知规则
+func (c *ConfigController) SaveNotifyRuleAction() {
+	defer c.ServeJSON()
+	if c.CheckMultiAccessRequest([]RequestRole{SuperAdmin, Admin}, false) == false {
+		c.FailedStatus("当前用户权限不允许！")
+		return
+	}
+
+	var rules []conf.NotifyRule
+	if err := yaml.Unmarshal([]byte(c.GetString("notifyrule", "")), &rules); err != nil {
+		c.FailedStatus(fmt.Sprintf("规则格式错误：%v", err))
+		return
+	}
+	for i, rule := range rules {
+		if err := notify.CheckRule(rule); err != nil {
+			c.FailedStatus(fmt.Sprintf("第%d条规则错误：%v", i+1, err))
+			return
+		}
+	}
+	err := conf.GlobalServerConfig().ReloadConfig()
+	if err != nil {
+		c.FailedStatus(err.Error())
+		return
+	}
+	conf.GlobalServerConfig().NotifyRule = rules
+	err = conf.GlobalServerConfig().WriteConfig()
+	if err != nil {
+		c.FailedStatus(err.Error())
+		return
+	}
+	c.SucceededStatus("保存配置成功")
+}
+
+// formatNotifyRule 将事件通知规则转换为YAML格式
+func formatNotifyRule(rules []conf.NotifyRule) string {
+	if len(rules) == 0 {
+		return ""
+	}
+	content, err := yaml.Marshal(rules)
+	if err != nil {
+		logging.RuntimeLog.Error(err)
+		return ""
+	}
+	return string(content)
+}
+
 // TestTaskNotifyAction 测试任务通知
 func (c *ConfigController) TestTaskNotifyAction() {
 	defer c.ServeJSON()
@@ -465,9 +596,45 @@ func (c *ConfigController) TestTaskNotifyAction() {
 	}
 
 	message := "这是一个测试消息，来自Nemo的配置管理！"
-	notify.Send(message)
+	// 指定了消息通知时只测试该通知，否则测试全部已配置的通知
+	if sender := c.GetString("sender", ""); sender != "" {
+		if err := notify.SendTo(sender, message); err != nil {
+			c.FailedStatus(fmt.Sprintf("%s:%v", sender, err))
+			return
+		}
+		c.SucceededStatus("已发送测试通知，请确认消息是否正确！")
+		return
+	}
+	results := notify.SendAll(message)
+	if len(results) == 0 {
+		c.FailedStatus("没有已配置的消息通知！")
+		return
+	}
+	var senders, failed []string
+	for sender := range results {
+		senders = append(senders, sender)
+	}
+	sort.Strings(senders)
+	for _, sender := range senders {
+		if results[sender] != nil {
+			failed = append(failed, fmt.Sprintf("%s:%v", sender, results[sender]))
+		}
+	}
+	if len(failed) > 0 {
+		c.FailedStatus(strings.Join(failed, "\n"))
+		return
+	}
+	c.SucceededStatus(fmt.Sprintf("已发送测试通知（%s），请确认消息是否正确！", strings.Join(senders, ",")))
+}
 
-	c.SucceededStatus("已发送测试通知，请确认消息是否正确！")
+// splitNotifyRecipients 分割以逗号或换行分隔的收件人
+func splitNotifyRecipients(s string) (recipients []string) {
+	for _, r := range strings.FieldsFunc(s, func(c rune) bool { return c == ',' || c == '\n' || c == ';' }) {
+		if r = strings.TrimSpace(r); r != "" {
+			recipients = append(recipients, r)
+		}
+	}
+	return
 }
 
 // SaveAPITokenAction 保存API的Token
@@ -497,9 +664,15 @@ func (c *ConfigController) SaveAPITokenAction() {
 	conf.GlobalWorkerConfig().API.Fofa.Key = data.FofaToken
 	conf.GlobalWorkerConfig().API.Hunter.Key = data.HunterToken
 	conf.GlobalWorkerConfig().API.Quake.Key = data.QuakeToken
+	conf.GlobalWorkerConfig().API.Shodan.Key = data.ShodanToken
+	conf.GlobalWorkerConfig().API.Censys.Key = data.CensysToken
+	conf.GlobalWorkerConfig().API.ZoomEye.Key = data.ZoomEyeToken
+	conf.GlobalWorkerConfig().API.Netlas.Key = data.NetlasToken
 	conf.GlobalWorkerConfig().API.ICP.Key = data.ChinazToken
 	conf.GlobalWorkerConfig().API.SearchLimitCount = data.SearchLimitCount
 	conf.GlobalWorkerConfig().API.SearchPageSize = data.SearchPageSize
+	conf.GlobalWorkerConfig().API.SearchPointBudget = data.SearchPointBudget
+	conf.GlobalWorkerConfig().API.CacheTTL = data.CacheTTL
 	err = conf.GlobalWorkerConfig().WriteConfig()
 	if err != nil {
 		logging.RuntimeLog.Error("save config file error:", err)
@@ -544,6 +717,22 @@ func (c *ConfigController) TestOnlineAPIKeyAction() {
 		swg.Add()
 		go testOnineAPI("quake", &swg, msgChan)
 	}
+	if len(apiKeys.Shodan.Key) > 0 {
+		swg.Add()
+		go testOnineAPI("shodan", &swg, msgChan)
+	}
+	if len(apiKeys.Censys.Key) > 0 {
+		swg.Add()
+		go testOnineAPI("censys", &swg, msgChan)
+	}
+	if len(apiKeys.ZoomEye.Key) > 0 {
+		swg.Add()
+		go testOnineAPI("zoomeye", &swg, msgChan)
+	}
+	if len(apiKeys.Netlas.Key) > 0 {
+		swg.Add()
+		go testOnineAPI("netlas", &swg, msgChan)
+	}
 	if len(apiKeys.ICP.Key) > 0 {
 		swg.Add()
 		go func(swg *sizedwaitgroup.SizedWaitGroup, testMsgChan chan string) {
@@ -566,6 +755,61 @@ func (c *ConfigController) TestOnlineAPIKeyAction() {
 	}
 }
 
+// LoadOnlineAPIQuotaAction 查询API每个key的剩余额度
+func (c *ConfigController) LoadOnlineAPIQuotaAction() {
+	defer c.ServeJSON()
+	if c.CheckMultiAccessRequest([]RequestRole{SuperAdmin, Admin}, false) == false {
+		c.FailedStatus("当前用户权限不允许！")
+		return
+	}
+	apiKeys := conf.GlobalWorkerConfig().API
+	sb := strings.Builder{}
+	for _, api := range []struct {
+		name string
+		key  string
+	}{
+		{"fofa", apiKeys.Fofa.Key}, {"hunter", apiKeys.Hunter.Key}, {"quake", apiKeys.Quake.Key},
+		{"shodan", apiKeys.Shodan.Key}, {"censys", apiKeys.Censys.Key}, {"zoomeye", apiKeys.ZoomEye.Key},
+		{"netlas", apiKeys.Netlas.Key},
+	} {
+		for _, status := range onlineapi.GetAPIKeyStatus(api.name, api.key, false) {
+			sb.WriteString(fmt.Sprintf("%s %s: ", api.name, status.Key))
+			if status.Remaining < 0 {
+				sb.WriteString("剩余额度不支持查询")
+			} else {
+				sb.WriteString(fmt.Sprintf("剩余额度%d", status.Remaining))
+			}
+			// 使用量及错误为各worker上报的统计
+			sb.WriteString(fmt.Sprintf(",已使用%d", status.Used))
+			if status.Invalid {
+				sb.WriteString(",不可用")
+			} else if status.Exhausted {
+				sb.WriteString(",额度用完")
+			}
+			if len(status.LastError) > 0 {
+				sb.WriteString(fmt.Sprintf(",error:%s", status.LastError))
+			}
+			sb.WriteString("\n")
+		}
+	}
+	if sb.Len() > 0 {
+		c.SucceededStatus(sb.String())
+	} else {
+		c.FailedStatus("api接口没有可用的key！")
+	}
+}
+
+// ClearOnlineAPICacheAction 清除在线API查询结果的缓存
+func (c *ConfigController) ClearOnlineAPICacheAction() {
+	defer c.ServeJSON()
+	if c.CheckMultiAccessRequest([]RequestRole{SuperAdmin, Admin}, false) == false {
+		c.FailedStatus("当前用户权限不允许！")
+		return
+	}
+	count := onlineapi.GetOnlineAPICache().Invalidate(c.GetString("api", ""))
+	c.SucceededStatus(fmt.Sprintf("已清除%d条缓存", count))
+}
+
 // SaveFingerprintAction 保存默认指纹设置
 func (c *ConfigController) SaveFingerprintAction() {
 	defer c.ServeJSON()
@@ -664,6 +908,8 @@ func (c *ConfigController) SaveDomainscanAction() {
 	conf.GlobalWorkerConfig().Domainscan.IsPortScan = data.IsPortscan
 	conf.GlobalWorkerConfig().Domainscan.IsICP = data.IsICP
 	conf.GlobalWorkerConfig().Domainscan.IsWhois = data.IsWhois
+	conf.GlobalWorkerConfig().Domainscan.IsDNSRecord = data.IsDNSRecord
+	conf.GlobalWorkerConfig().Domainscan.IsDNSCheck = data.IsDNSCheck
 	err = conf.GlobalWorkerConfig().WriteConfig()
 	if err != nil {
 		logging.RuntimeLog.Error("save config file error:", err)
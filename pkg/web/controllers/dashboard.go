@@ -77,7 +77,7 @@ func (c *DashboardController) GetStatisticDataAction() {
 	defer comm.WorkerStatusMutex.Unlock()
 	for _, v := range comm.WorkerStatus {
 		if time.Now().Sub(v.UpdateTime).Minutes() > 5 {
-			delete(comm.WorkerStatus, v.WorkerName)
+			comm.RemoveWorkerStatus(v.WorkerName)
 			continue
 		}
 	}
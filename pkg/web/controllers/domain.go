@@ -11,6 +11,7 @@ import (
 	"github.com/hanc00l/nemo_go/pkg/db"
 	"github.com/hanc00l/nemo_go/pkg/logging"
 	"github.com/hanc00l/nemo_go/pkg/task/custom"
+	"github.com/hanc00l/nemo_go/pkg/task/dirscan"
 	"github.com/hanc00l/nemo_go/pkg/task/domainscan"
 	"github.com/hanc00l/nemo_go/pkg/task/fingerprint"
 	"github.com/hanc00l/nemo_go/pkg/task/onlineapi"
@@ -44,6 +45,8 @@ type domainRequestParam struct {
 	OrderByDate        bool   `form:"select_order_by_date"`
 	DomainHttp         string `form:"domain_http"`
 	WikiDocs           string `form:"wiki_docs"`
+	SelectStale        bool   `form:"select_stale"`
+	LastSeenBefore     string `form:"last_seen_before"`
 }
 
 // DomainListData datable显示的每一行数据
@@ -70,6 +73,7 @@ type DomainListData struct {
 	WorkspaceGUID  string         `json:"workspace_guid"`
 	PinIndex       int            `json:"pinindex"`
 	WikiDocs       string         `json:"wiki_docs"`
+	Stale          bool           `json:"stale"`
 }
 
 // DomainInfo domain详细数据聚合
@@ -103,6 +107,9 @@ type DomainInfo struct {
 	PinIndex      string
 	Source        []string
 	WikiDocs      []DocumentInfo
+	Timeline      []AssetChangeInfo
+	Certificate   []CertificateHostInfo
+	DNSRecord     []DomainAttrInfo
 }
 
 // DomainAttrInfo domain属性
@@ -129,6 +136,7 @@ type DomainAttrFullInfo struct {
 	SourceSet     map[string]struct{}
 	DomainCDN     string
 	DomainCNAME   string
+	DNSRecord     []DomainAttrInfo
 }
 
 // DomainStatisticInfo domain统计信息
@@ -210,14 +218,39 @@ func (c *DomainController) InfoAction() {
 					domainInfo.PortAttr[i].TableBackgroundSet = tableBackgroundSet
 				}
 			}
+			domainInfo.Timeline = getAssetTimeline(workspaceId, db.AssetTypeDomain, domain.DomainName, "", 1, assetTimelineDisplayNumber).Timeline
+			domainInfo.Certificate = getHostCertificate(workspaceId, domain.DomainName)
 		}
 	}
+	if c.IsServerAPI {
+		c.Data["json"] = domainInfo
+		c.ServeJSON()
+		return
+	}
 	domainInfo.DisableFofa = disableFofa
 	c.Data["domain_info"] = domainInfo
 	c.Layout = "base.html"
 	c.TplName = "domain-info.html"
 }
 
+// TimelineAction 一个域名的资产变更记录
+func (c *DomainController) TimelineAction() {
+	defer c.ServeJSON()
+
+	domainName := c.GetString("domain")
+	workspaceId, err := c.GetInt("workspace")
+	if domainName == "" || err != nil || workspaceId <= 0 {
+		c.FailedStatus("参数错误！")
+		return
+	}
+	start, _ := c.GetInt("start", 0)
+	length, _ := c.GetInt("length", assetTimelineDisplayNumber)
+	if length <= 0 {
+		length = assetTimelineDisplayNumber
+	}
+	c.Data["json"] = getAssetTimeline(workspaceId, db.AssetTypeDomain, domainName, c.GetString("since"), start/length+1, length)
+}
+
 // DeleteDomainAction 删除一个记录
 func (c *DomainController) DeleteDomainAction() {
 	defer c.ServeJSON()
@@ -240,7 +273,12 @@ func (c *DomainController) DeleteDomainAction() {
 			ss := fingerprint.NewScreenShot()
 			ss.Delete(workspace.WorkspaceGUID, domain.DomainName)
 		}
-		c.MakeStatusResponse(domain.Delete())
+		if domain.Delete() {
+			db.SaveAssetChange(domain.WorkspaceId, db.AssetTypeDomain, domain.DomainName, 0, db.AssetChangeRemoved, "", "domain", "", domain.DomainName, "")
+			c.MakeStatusResponse(true)
+		} else {
+			c.MakeStatusResponse(false)
+		}
 	} else {
 		c.MakeStatusResponse(false)
 	}
@@ -262,7 +300,15 @@ func (c *DomainController) DeleteDomainAttrAction() {
 		return
 	}
 	domainAttr := db.DomainAttr{Id: id}
-	c.MakeStatusResponse(domainAttr.Delete())
+	if !domainAttr.Get() || !domainAttr.Delete() {
+		c.MakeStatusResponse(false)
+		return
+	}
+	domain := db.Domain{Id: domainAttr.RelatedId}
+	if domain.Get() {
+		db.SaveAssetChange(domain.WorkspaceId, db.AssetTypeDomain, domain.DomainName, 0, db.AssetChangeRemoved, domainAttr.Source, domainAttr.Tag, "", domainAttr.Content, "")
+	}
+	c.MakeStatusResponse(true)
 }
 
 // DeleteDomainOnlineAPIAttrAction 删除fofa等属性
@@ -280,7 +326,7 @@ func (c *DomainController) DeleteDomainOnlineAPIAttrAction() {
 		c.MakeStatusResponse(false)
 		return
 	}
-	for _, source := range []string{"fofa", "hunter", "quake", "0zone"} {
+	for _, source := range []string{"fofa", "hunter", "quake", "0zone", "shodan", "censys", "zoomeye", "netlas"} {
 		domainAttr := db.DomainAttr{RelatedId: id, Source: source}
 		c.MakeStatusResponse(domainAttr.DeleteByRelatedIDAndSource())
 	}
@@ -524,6 +570,7 @@ func (c *DomainController) getSearchMap(req domainRequestParam) (searchMap map[s
 	if req.WikiDocs != "" {
 		searchMap["wiki_docs"] = req.WikiDocs
 	}
+	setStaleSearchMap(searchMap, req.SelectStale, req.LastSeenBefore)
 	return
 }
 
@@ -545,6 +592,7 @@ func (c *DomainController) getDomainListData(req domainRequestParam) (resp DataT
 		domainData.Domain = domainRow.DomainName
 		domainData.FldDomain = fld.ExtractFLD(domainRow.DomainName)
 		domainData.PinIndex = domainRow.PinIndex
+		domainData.Stale = domainRow.IsStale
 		domainData.WorkspaceId = domainRow.WorkspaceId
 		if _, ok := workspaceCacheMap[domainData.WorkspaceId]; !ok {
 			workspace := db.Workspace{Id: domainData.WorkspaceId}
@@ -738,6 +786,7 @@ func getDomainInfo(domain *db.Domain, portInfoCacheMap map[int]PortInfo, disable
 	r.TlsData = utils.SetToSlice(domainAttrInfo.TlsData)
 	r.DomainCDN = domainAttrInfo.DomainCDN
 	r.DomainCNAME = domainAttrInfo.DomainCNAME
+	r.DNSRecord = domainAttrInfo.DNSRecord
 	for hash, image := range domainAttrInfo.IconImageSet {
 		r.IconHashes = append(r.IconHashes, IconHashWithFofa{
 			IconHash:  hash,
@@ -761,6 +810,19 @@ func getDomainInfo(domain *db.Domain, portInfoCacheMap map[int]PortInfo, disable
 		}
 		r.DomainAttr = append(r.DomainAttr, dai)
 	}
+	// dirscan info
+	domainHttp = db.DomainHttp{RelatedId: domain.Id, Tag: dirscan.TagDirscan}
+	for _, info := range domainHttp.GetsByRelatedIdAndTag() {
+		r.DomainAttr = append(r.DomainAttr, DomainAttrInfo{
+			Id:         info.Id,
+			DomainId:   info.RelatedId,
+			Port:       info.Port,
+			Tag:        info.Tag,
+			Content:    info.Content,
+			CreateTime: FormatDateTime(info.CreateDatetime),
+			UpdateTime: FormatDateTime(info.UpdateDatetime),
+		})
+	}
 	//wiki document
 	wiki := db.WikiDocs{}
 	for _, doc := range wiki.GetsByIpOrDomain(0, domain.Id) {
@@ -793,10 +855,10 @@ func getDomainAttrFullInfo(workspaceGUID string, id int, disableFofa, disableBan
 	domainAttr := db.DomainAttr{RelatedId: id}
 	domainAttrData := domainAttr.GetsByRelatedId()
 	for _, da := range domainAttrData {
-		if disableFofa && (da.Source == "fofa" || da.Source == "quake" || da.Source == "hunter" || da.Source == "0zone") {
+		if disableFofa && onlineapi.IsOnlineAPISource(da.Source) {
 			continue
 		}
-		if da.Source == "fofa" || da.Source == "quake" || da.Source == "hunter" || da.Source == "0zone" {
+		if onlineapi.IsOnlineAPISource(da.Source) {
 			fofaInfo[da.Tag] = da.Content
 		}
 		if da.Tag == "A" || da.Tag == "AAAA" {
@@ -879,11 +941,20 @@ func getDomainAttrFullInfo(workspaceGUID string, id int, disableFofa, disableBan
 			if _, ok := r.StatusCodeSet[da.Content]; !ok {
 				r.StatusCodeSet[da.Content] = struct{}{}
 			}
+		} else if dnsRecordTagIndex(da.Tag) >= 0 {
+			r.DNSRecord = append(r.DNSRecord, DomainAttrInfo{
+				Id:         da.Id,
+				Tag:        da.Tag,
+				Content:    da.Content,
+				CreateTime: FormatDateTime(da.CreateDatetime),
+				UpdateTime: FormatDateTime(da.UpdateDatetime),
+			})
 		}
 		if _, ok := r.SourceSet[da.Source]; !ok {
 			r.SourceSet[da.Source] = struct{}{}
 		}
 	}
+	sortDNSRecord(r.DNSRecord)
 	if len(fofaInfo) > 0 {
 		fofaContent, _ := json.Marshal(fofaInfo)
 		r.DomainAttr = append(r.DomainAttr, DomainAttrInfo{
@@ -895,6 +966,23 @@ func getDomainAttrFullInfo(workspaceGUID string, id int, disableFofa, disableBan
 	return r
 }
 
+// dnsRecordTagIndex DNS记录类型的显示顺序，不是DNS记录时返回-1
+func dnsRecordTagIndex(tag string) int {
+	for i, t := range domainscan.DNSRecordTags {
+		if t == tag {
+			return i
+		}
+	}
+	return -1
+}
+
+// sortDNSRecord 按记录类型排序DNS记录
+func sortDNSRecord(records []DomainAttrInfo) {
+	sort.SliceStable(records, func(i, j int) bool {
+		return dnsRecordTagIndex(records[i].Tag) < dnsRecordTagIndex(records[j].Tag)
+	})
+}
+
 // getDomainStatisticsData 获取域名的统计信息
 func (c *DomainController) getDomainStatisticsData(req domainRequestParam) DomainStatisticInfo {
 	dsi := DomainStatisticInfo{
@@ -10,6 +10,7 @@ import (
 	"github.com/hanc00l/nemo_go/pkg/db"
 	"github.com/hanc00l/nemo_go/pkg/logging"
 	"github.com/hanc00l/nemo_go/pkg/task/custom"
+	"github.com/hanc00l/nemo_go/pkg/task/dirscan"
 	"github.com/hanc00l/nemo_go/pkg/task/domainscan"
 	"github.com/hanc00l/nemo_go/pkg/task/fingerprint"
 	"github.com/hanc00l/nemo_go/pkg/task/onlineapi"
@@ -50,6 +51,8 @@ type ipRequestParam struct {
 	OrderByDate           bool   `form:"select_order_by_date"`
 	IpHttp                string `form:"ip_http"`
 	WikiDocs              string `form:"wiki_docs"`
+	SelectStale           bool   `form:"select_stale"`
+	LastSeenBefore        string `form:"last_seen_before"`
 }
 
 // IPListData 列表中每一行显示的IP数据
@@ -60,6 +63,7 @@ type IPListData struct {
 	IPFormatted    string         `json:"ipf"`
 	Location       string         `json:"location"`
 	Port           []string       `json:"port"`
+	StalePort      []int          `json:"stale_port"`
 	Title          map[string]int `json:"title"`
 	Banner         map[string]int `json:"banner"`
 	ColorTag       string         `json:"color_tag"`
@@ -110,6 +114,8 @@ type IPInfo struct {
 	WorkspaceGUID string
 	PinIndex      string
 	WikiDocs      []DocumentInfo
+	Timeline      []AssetChangeInfo
+	Certificate   []CertificateHostInfo
 }
 
 // PortAttrInfo 每一个端口的详细数据
@@ -126,6 +132,7 @@ type PortAttrInfo struct {
 	CreateTime         string
 	UpdateTime         string
 	TableBackgroundSet bool
+	Stale              bool
 }
 
 // ScreenshotFileInfo screenshot文件
@@ -139,6 +146,7 @@ type ScreenshotFileInfo struct {
 type PortInfo struct {
 	PortNumbers      []int
 	PortStatus       map[int]string
+	StalePort        map[int]struct{}
 	TitleSet         map[string]int
 	BannerSet        map[string]int
 	PortAttr         []PortAttrInfo
@@ -222,6 +230,8 @@ func (c *IPController) InfoAction() {
 					ipInfo.PortAttr[i].TableBackgroundSet = tableBackgroundSet
 				}
 			}
+			ipInfo.Timeline = getAssetTimeline(workspaceId, db.AssetTypeIP, ip.IpName, "", 1, assetTimelineDisplayNumber).Timeline
+			ipInfo.Certificate = getHostCertificate(workspaceId, ip.IpName)
 		}
 	}
 	if c.IsServerAPI {
@@ -235,6 +245,24 @@ func (c *IPController) InfoAction() {
 	}
 }
 
+// TimelineAction 一个IP的资产变更记录
+func (c *IPController) TimelineAction() {
+	defer c.ServeJSON()
+
+	ipName := c.GetString("ip")
+	workspaceId, err := c.GetInt("workspace")
+	if ipName == "" || err != nil || workspaceId <= 0 {
+		c.FailedStatus("参数错误！")
+		return
+	}
+	start, _ := c.GetInt("start", 0)
+	length, _ := c.GetInt("length", assetTimelineDisplayNumber)
+	if length <= 0 {
+		length = assetTimelineDisplayNumber
+	}
+	c.Data["json"] = getAssetTimeline(workspaceId, db.AssetTypeIP, ipName, c.GetString("since"), start/length+1, length)
+}
+
 // DeleteIPAction 删除一个IP记录
 func (c *IPController) DeleteIPAction() {
 	defer c.ServeJSON()
@@ -257,7 +285,12 @@ func (c *IPController) DeleteIPAction() {
 			ss := fingerprint.NewScreenShot()
 			ss.Delete(workspace.WorkspaceGUID, ip.IpName)
 		}
-		c.MakeStatusResponse(ip.Delete())
+		if ip.Delete() {
+			db.SaveAssetChange(ip.WorkspaceId, db.AssetTypeIP, ip.IpName, 0, db.AssetChangeRemoved, "", "ip", "", ip.IpName, "")
+			c.MakeStatusResponse(true)
+		} else {
+			c.MakeStatusResponse(false)
+		}
 	} else {
 		c.MakeStatusResponse(false)
 	}
@@ -279,7 +312,18 @@ func (c *IPController) DeletePortAttrAction() {
 		return
 	}
 	portAttr := db.PortAttr{Id: id}
-	c.MakeStatusResponse(portAttr.Delete())
+	if !portAttr.Get() || !portAttr.Delete() {
+		c.MakeStatusResponse(false)
+		return
+	}
+	port := db.Port{Id: portAttr.RelatedId}
+	if port.Get() {
+		ip := db.Ip{Id: port.IpId}
+		if ip.Get() {
+			db.SaveAssetChange(ip.WorkspaceId, db.AssetTypeIP, ip.IpName, port.PortNum, db.AssetChangeRemoved, portAttr.Source, portAttr.Tag, "", portAttr.Content, "")
+		}
+	}
+	c.MakeStatusResponse(true)
 }
 
 // StatisticsAction IP的统计信息
@@ -522,7 +566,7 @@ func (c *IPController) ImportPortscanResultAction() {
 		portscan.FilterIPResult(&i.IpResult, false)
 		resultIpPort := i.IpResult.SaveResult(config)
 		result = fmt.Sprintf("%s", resultIpPort)
-	} else if bin == "0zone" || bin == "fofa" || bin == "hunter" {
+	} else if bin == "0zone" || bin == "fofa" || bin == "hunter" || bin == "shodan" || bin == "censys" || bin == "zoomeye" || bin == "netlas" {
 		s := onlineapi.NewOnlineAPISearch(onlineapi.OnlineAPIConfig{}, bin)
 		s.ParseContentResult(fileContent)
 		portscan.FilterIPResult(&s.IpResult, true)
@@ -594,6 +638,7 @@ func (c *IPController) getSearchMap(req ipRequestParam) (searchMap map[string]in
 	if req.WikiDocs != "" {
 		searchMap["wiki_docs"] = req.WikiDocs
 	}
+	setStaleSearchMap(searchMap, req.SelectStale, req.LastSeenBefore)
 	return searchMap
 }
 
@@ -660,10 +705,13 @@ func (c *IPController) GetIPListData(req ipRequestParam) (resp DataTableResponse
 		var ports []string
 		for _, p := range ipPortInfo.PortNumbers {
 			ports = append(ports, fmt.Sprintf("%d", p))
+			portDisplay := fmt.Sprintf("%d", p)
 			if portStatus, ok := ipPortInfo.PortStatus[p]; ok {
-				ipData.Port = append(ipData.Port, fmt.Sprintf("%d[%s]", p, portStatus))
-			} else {
-				ipData.Port = append(ipData.Port, fmt.Sprintf("%d", p))
+				portDisplay = fmt.Sprintf("%d[%s]", p, portStatus)
+			}
+			ipData.Port = append(ipData.Port, portDisplay)
+			if _, ok := ipPortInfo.StalePort[p]; ok {
+				ipData.StalePort = append(ipData.StalePort, p)
 			}
 		}
 		if ipData.Port == nil || len(ipData.Port) == 0 {
@@ -696,6 +744,7 @@ func (c *IPController) GetIPListData(req ipRequestParam) (resp DataTableResponse
 // getPortInfo 获取一个IP的所有端口信息集合
 func getPortInfo(workspaceGUID string, ip string, ipId int, disableFofa, disableBanner bool) (r PortInfo) {
 	r.PortStatus = make(map[int]string)
+	r.StalePort = make(map[int]struct{})
 	r.BannerSet = make(map[string]int)
 	r.TitleSet = make(map[string]int)
 	r.TlsDataSet = make(map[string]struct{})
@@ -705,6 +754,10 @@ func getPortInfo(workspaceGUID string, ip string, ipId int, disableFofa, disable
 	portData := port.GetsByIPId()
 	for _, pd := range portData {
 		r.PortNumbers = append(r.PortNumbers, pd.PortNum)
+		stale := pd.IsStale
+		if stale {
+			r.StalePort[pd.PortNum] = struct{}{}
+		}
 		if pd.Status != "" {
 			if _, err := strconv.Atoi(pd.Status); err == nil {
 				r.PortStatus[pd.PortNum] = pd.Status
@@ -714,7 +767,7 @@ func getPortInfo(workspaceGUID string, ip string, ipId int, disableFofa, disable
 		portAttrData := portAttr.GetsByRelatedId()
 		FirstRow := true
 		for _, pad := range portAttrData {
-			if disableFofa && (pad.Source == "fofa" || pad.Source == "quake" || pad.Source == "hunter" || pad.Source == "0zone") {
+			if disableFofa && onlineapi.IsOnlineAPISource(pad.Source) {
 				continue
 			}
 			pai := PortAttrInfo{}
@@ -732,6 +785,7 @@ func getPortInfo(workspaceGUID string, ip string, ipId int, disableFofa, disable
 				pai.IP = ip
 				pai.IPFormatted = utils.FormatHostUrl("", ip, 0)
 				pai.Port = fmt.Sprintf("%d", pd.PortNum)
+				pai.Stale = stale
 			}
 			if pad.Source == "fofa" {
 				fofaSearch := fmt.Sprintf(`ip = "%s" && port = "%d"`, ip, pd.PortNum)
@@ -778,13 +832,20 @@ func getPortInfo(workspaceGUID string, ip string, ipId int, disableFofa, disable
 				}
 			}
 		}
-		// http header info
-		httpInfo := db.IpHttp{RelatedId: pd.Id, Tag: "header"}
-		if httpInfo.GetByRelatedIdAndTag() {
+		// http header info与dirscan的结果
+		for _, tag := range []string{"header", dirscan.TagDirscan} {
+			httpInfo := db.IpHttp{RelatedId: pd.Id, Tag: tag}
+			if !httpInfo.GetByRelatedIdAndTag() {
+				continue
+			}
+			displayTag := tag
+			if tag == "header" {
+				displayTag = "http_header"
+			}
 			httpPortAttr := PortAttrInfo{
 				Id:         httpInfo.Id,
 				PortId:     httpInfo.RelatedId,
-				Tag:        "http_header",
+				Tag:        displayTag,
 				Content:    httpInfo.Content,
 				Source:     httpInfo.Source,
 				CreateTime: FormatDateTime(httpInfo.CreateDatetime),
@@ -1,8 +1,11 @@
 package controllers
 
 import (
+	"fmt"
 	"github.com/hanc00l/nemo_go/pkg/db"
 	"github.com/hanc00l/nemo_go/pkg/logging"
+	"github.com/hanc00l/nemo_go/pkg/task/onlineapi"
+	"github.com/hanc00l/nemo_go/pkg/task/runner"
 	"strings"
 )
 
@@ -19,6 +22,11 @@ type keyWordInitRequestParam struct {
 	AddFOFA         bool   `form:"fofa"`
 	AddHunter       bool   `form:"hunter"`
 	AddQuake        bool   `form:"quake"`
+	AddShodan       bool   `form:"shodan"`
+	AddCensys       bool   `form:"censys"`
+	AddZoomEye      bool   `form:"zoomeye"`
+	AddNetlas       bool   `form:"netlas"`
+	AddAll          bool   `form:"all"`
 }
 
 type keySearchRequestParam struct {
@@ -53,6 +61,11 @@ type KeyWordInfo struct {
 	IsFofa         bool   `json:"fofa"`
 	IsHunter       bool   `json:"hunter"`
 	IsQuake        bool   `json:"quake"`
+	IsShodan       bool   `json:"shodan"`
+	IsCensys       bool   `json:"censys"`
+	IsZoomEye      bool   `json:"zoomeye"`
+	IsNetlas       bool   `json:"netlas"`
+	IsAll          bool   `json:"all"`
 	SearchTime     string `json:"search_time"`
 	ExcludeWords   string `json:"exclude_words"`
 	CheckMod       string `json:"check_mod"`
@@ -62,6 +75,65 @@ type KeyWordInfo struct {
 	UpdateDatetime string `json:"update_datetime"`
 }
 
+type KeyWordHistoryInfo struct {
+	Id             int    `json:"id"`
+	Engine         string `json:"engine"`
+	TaskId         string `json:"task_id"`
+	Total          int    `json:"total"`
+	NewCount       int    `json:"new_count"`
+	NewAsset       string `json:"new_asset"`
+	IsBaseline     bool   `json:"is_baseline"`
+	CreateDatetime string `json:"create_datetime"`
+}
+
+// keyWordHistoryMaxCount 显示的关键词检索历史的最大数量
+const keyWordHistoryMaxCount = 100
+
+// getEngines 获取选择的搜索引擎，多个以逗号分隔
+func (p *keyWordInitRequestParam) getEngines() string {
+	// 全部搜索引擎时在执行任务时再展开，以便新增的搜索引擎也能使用
+	if p.AddAll {
+		return "all"
+	}
+	var engines []string
+	if p.AddFOFA {
+		engines = append(engines, "xfofa")
+	}
+	if p.AddHunter {
+		engines = append(engines, "xhunter")
+	}
+	if p.AddQuake {
+		engines = append(engines, "xquake")
+	}
+	if p.AddShodan {
+		engines = append(engines, "xshodan")
+	}
+	if p.AddCensys {
+		engines = append(engines, "xcensys")
+	}
+	if p.AddZoomEye {
+		engines = append(engines, "xzoomeye")
+	}
+	if p.AddNetlas {
+		engines = append(engines, "xnetlas")
+	}
+	return strings.Join(engines, ",")
+}
+
+// validateQuery 检查统一语法的关键词是否正确，并能转换为每个选择的搜索引擎的查询语法
+func (p *keyWordInitRequestParam) validateQuery() error {
+	if p.AddCheckMod != "query" {
+		return nil
+	}
+	if _, err := onlineapi.ParseQuery(p.AddKeyWord); err != nil {
+		return fmt.Errorf("统一语法错误：%v", err)
+	}
+	if err := runner.CheckKeywordQuery(p.getEngines(), p.AddKeyWord); err != nil {
+		return fmt.Errorf("统一语法错误：%v", err)
+	}
+	return nil
+}
+
 func (c *KeySearchController) IndexAction() {
 	c.Layout = "base.html"
 	c.TplName = "key-word-list.html"
@@ -92,6 +164,10 @@ func (c *KeySearchController) AddSaveAction() {
 		c.FailedStatus(err.Error())
 		return
 	}
+	if err = keyWordData.validateQuery(); err != nil {
+		c.FailedStatus(err.Error())
+		return
+	}
 	kw := db.KeyWord{}
 	kw.OrgId = keyWordData.AddOrgId
 	kw.KeyWord = keyWordData.AddKeyWord
@@ -100,17 +176,7 @@ func (c *KeySearchController) AddSaveAction() {
 	kw.CheckMod = keyWordData.AddCheckMod
 	kw.Count = keyWordData.AddCount
 	kw.WorkspaceId = workspaceId
-	var engines []string
-	if keyWordData.AddFOFA {
-		engines = append(engines, "xfofa")
-	}
-	if keyWordData.AddHunter {
-		engines = append(engines, "xhunter")
-	}
-	if keyWordData.AddQuake {
-		engines = append(engines, "xquake")
-	}
-	kw.Engine = strings.Join(engines, ",")
+	kw.Engine = keyWordData.getEngines()
 	c.MakeStatusResponse(kw.Add())
 }
 
@@ -169,6 +235,16 @@ func (c *KeySearchController) GetAction() {
 				kwi.IsHunter = true
 			case "quake", "xquake":
 				kwi.IsQuake = true
+			case "shodan", "xshodan":
+				kwi.IsShodan = true
+			case "censys", "xcensys":
+				kwi.IsCensys = true
+			case "zoomeye", "xzoomeye":
+				kwi.IsZoomEye = true
+			case "netlas", "xnetlas":
+				kwi.IsNetlas = true
+			case "all":
+				kwi.IsAll = true
 			}
 		}
 		c.Data["json"] = kwi
@@ -177,6 +253,37 @@ func (c *KeySearchController) GetAction() {
 	}
 }
 
+// HistoryAction 关键词每次检索的结果及新增的资产
+func (c *KeySearchController) HistoryAction() {
+	defer c.ServeJSON()
+
+	id, err := c.GetInt("id")
+	if err != nil {
+		logging.RuntimeLog.Error(err.Error())
+		c.FailedStatus(err.Error())
+		return
+	}
+	var histories []KeyWordHistoryInfo
+	history := db.KeyWordHistory{}
+	results, _ := history.Gets(map[string]interface{}{"key_word_id": id}, 1, keyWordHistoryMaxCount)
+	for _, h := range results {
+		histories = append(histories, KeyWordHistoryInfo{
+			Id:             h.Id,
+			Engine:         h.Engine,
+			TaskId:         h.TaskId,
+			Total:          h.Total,
+			NewCount:       h.NewCount,
+			NewAsset:       h.NewAsset,
+			IsBaseline:     h.IsBaseline,
+			CreateDatetime: FormatDateTime(h.CreateDatetime),
+		})
+	}
+	if histories == nil {
+		histories = make([]KeyWordHistoryInfo, 0)
+	}
+	c.Data["json"] = histories
+}
+
 // UpdateAction 更新记录
 func (c *KeySearchController) UpdateAction() {
 	defer c.ServeJSON()
@@ -200,6 +307,10 @@ func (c *KeySearchController) UpdateAction() {
 		c.FailedStatus(err.Error())
 		return
 	}
+	if err = kwi.validateQuery(); err != nil {
+		c.FailedStatus(err.Error())
+		return
+	}
 	kw := db.KeyWord{Id: id}
 	updateMap := make(map[string]interface{})
 	updateMap["org_id"] = kwi.AddOrgId
@@ -208,17 +319,7 @@ func (c *KeySearchController) UpdateAction() {
 	updateMap["exclude_words"] = kwi.AddExcludeWords
 	updateMap["check_mod"] = kwi.AddCheckMod
 	updateMap["count"] = kwi.AddCount
-	var engines []string
-	if kwi.AddFOFA {
-		engines = append(engines, "xfofa")
-	}
-	if kwi.AddHunter {
-		engines = append(engines, "xhunter")
-	}
-	if kwi.AddQuake {
-		engines = append(engines, "xquake")
-	}
-	updateMap["engine"] = strings.Join(engines, ",")
+	updateMap["engine"] = kwi.getEngines()
 	c.MakeStatusResponse(kw.Update(updateMap))
 }
 
@@ -291,6 +392,8 @@ func (c *KeySearchController) getKeyWordListData(req keySearchRequestParam) (res
 			r.CheckMod = "body"
 		} else if keyWordRow.CheckMod == "self" {
 			r.CheckMod = "自定义"
+		} else if keyWordRow.CheckMod == "query" {
+			r.CheckMod = "统一语法"
 		} else {
 			r.CheckMod = "未知错误"
 		}
@@ -11,12 +11,18 @@ import (
 	"github.com/hanc00l/nemo_go/pkg/logging"
 	"github.com/hanc00l/nemo_go/pkg/utils"
 	"net/http"
+	"time"
 )
 
 type LoginController struct {
 	BaseController
 }
 
+const (
+	defaultLockDuration     = 900 // 默认的用户锁定时长（秒）
+	bcryptMaxPasswordLength = 72  // bcrypt支持的最大密码长度（字节）
+)
+
 var Cpt *captcha.Captcha
 
 func init() {
@@ -109,7 +115,7 @@ func (c *LoginController) LogoutAction() {
 	c.Redirect("/", http.StatusFound)
 }
 
-// ValidLoginUser 校验用户名和密码
+// ValidLoginUser 校验用户名和密码；连续登录失败达到限制的用户将被锁定，使用旧算法的密码在登录成功后重新生成hash
 func ValidLoginUser(username, password string) (bool, db.User) {
 	user := db.User{UserName: username}
 	if user.GetByUsername() == false {
@@ -118,34 +124,87 @@ func ValidLoginUser(username, password string) (bool, db.User) {
 	if user.State != "enable" {
 		return false, user
 	}
+	policy := conf.GlobalServerConfig().Password
+	if IsUserLocked(user) {
+		logging.RuntimeLog.Warningf("user %s is locked,login refused", username)
+		return false, user
+	}
+	if utils.VerifyPassword(password, user.UserPassword) == false {
+		loginFailed(&user, policy)
+		return false, user
+	}
+	if user.LoginFailed > 0 || user.LockedDatetime != nil {
+		user.UpdateLoginFailed(0, nil)
+	}
+	if utils.PasswordNeedsRehash(user.UserPassword, policy.Algorithm) {
+		if hash, err := ProcessPasswordHash(password); err != nil {
+			logging.RuntimeLog.Errorf("rehash password for user %s fail:%v", username, err)
+		} else {
+			updateMap := make(map[string]interface{})
+			updateMap["user_password"] = hash
+			if user.Update(updateMap) {
+				user.UserPassword = hash
+				logging.RuntimeLog.Infof("rehash password for user %s", username)
+			}
+		}
+	}
 
-	configuredPassword := user.UserPassword
-	hash := configuredPassword[:32]
-	salt := configuredPassword[32:]
-	checkedPass := utils.MD5V3(fmt.Sprintf("%s%s", password, salt))
-	if checkedPass == hash {
-		return true, user
+	return true, user
+}
+
+// IsUserLocked 检查用户是否因连续登录失败处于锁定状态
+func IsUserLocked(user db.User) bool {
+	policy := conf.GlobalServerConfig().Password
+	if policy.MaxFailures <= 0 || user.LockedDatetime == nil {
+		return false
+	}
+	lockDuration := policy.LockDuration
+	if lockDuration <= 0 {
+		lockDuration = defaultLockDuration
 	}
+	return time.Now().Before(user.LockedDatetime.Add(time.Duration(lockDuration) * time.Second))
+}
 
-	return false, user
+// loginFailed 记录用户登录失败，达到限制次数则锁定用户
+func loginFailed(user *db.User, policy conf.Password) {
+	failed := user.LoginFailed + 1
+	if policy.MaxFailures > 0 && failed >= policy.MaxFailures {
+		now := time.Now()
+		user.UpdateLoginFailed(0, &now)
+		logging.RuntimeLog.Warningf("user %s login failed %d times,locked", user.UserName, failed)
+		return
+	}
+	user.UpdateLoginFailed(failed, nil)
 }
 
 // UpdatePassword 更新密码
 func UpdatePassword(userName, oldPassword, newPassword string) bool {
 	validOldPassword, user := ValidLoginUser(userName, oldPassword)
 	if validOldPassword {
+		hash, err := ProcessPasswordHash(newPassword)
+		if err != nil {
+			logging.RuntimeLog.Error(err)
+			return false
+		}
 		updateMap := make(map[string]interface{})
-		updateMap["user_password"] = ProcessPasswordHash(newPassword)
+		updateMap["user_password"] = hash
 		return user.Update(updateMap)
 	}
 	return false
 }
 
-// ProcessPasswordHash 根据明文密码生成带salt的hash密码
-func ProcessPasswordHash(password string) string {
-	salt := utils.GetRandomString2(16)
-	hash := utils.MD5V3(fmt.Sprintf("%s%s", password, salt))
-	return fmt.Sprintf("%s%s", hash, salt)
+// ProcessPasswordHash 根据明文密码，使用配置的算法生成hash密码
+func ProcessPasswordHash(password string) (string, error) {
+	return utils.HashPassword(password, conf.GlobalServerConfig().Password.Algorithm)
+}
+
+// CheckPasswordPolicy 检查密码是否满足配置的密码策略
+func CheckPasswordPolicy(password string) error {
+	policy := conf.GlobalServerConfig().Password
+	if policy.Algorithm == utils.PasswordAlgorithmBcrypt && len(password) > bcryptMaxPasswordLength {
+		return fmt.Errorf("密码长度不能超过%d位", bcryptMaxPasswordLength)
+	}
+	return utils.CheckPasswordStrength(password, policy.MinLength, policy.Complexity)
 }
 
 //password:648ce596dba3b408b523d3d1189b15070123456789abcdef -> nemo
@@ -9,6 +9,7 @@ import (
 	"github.com/hanc00l/nemo_go/pkg/task/ampq"
 	"github.com/hanc00l/nemo_go/pkg/task/runner"
 	"github.com/hanc00l/nemo_go/pkg/task/serverapi"
+	"github.com/hanc00l/nemo_go/pkg/task/workerapi"
 	"github.com/hanc00l/nemo_go/pkg/utils"
 	"os"
 	"path"
@@ -253,6 +254,10 @@ func (c *TaskController) DeleteMainAction() {
 			}
 			//同时删除相关的子任务
 			deleteRunTaskByMainTaskId(workspaceGUID, task.TaskId)
+			//定时任务生成的任务共用定时任务的目标集合
+			if task.CronTaskId == "" {
+				runner.DeleteTargetSet(task.KwArgs)
+			}
 		}
 		c.MakeStatusResponse(task.Delete())
 	}
@@ -298,6 +303,7 @@ func (c *TaskController) DeleteCronAction() {
 		task := db.TaskCron{Id: id}
 		if task.Get() {
 			runner.DeleteCronTask(task.TaskId)
+			runner.DeleteTargetSet(task.KwArgs)
 			c.MakeStatusResponse(task.Delete())
 		} else {
 			c.FailedStatus("任务不存在")
@@ -684,6 +690,62 @@ func (c *TaskController) StartXScanTaskAction() {
 	c.SucceededStatus(taskId)
 }
 
+// StartWorkflowTaskAction 按指定的工作流定义启动任务
+func (c *TaskController) StartWorkflowTaskAction() {
+	defer c.ServeJSON()
+	if c.CheckMultiAccessRequest([]RequestRole{SuperAdmin, Admin}, false) == false {
+		c.FailedStatus("当前用户权限不允许！")
+		return
+	}
+	//校验参数
+	req := runner.WorkflowRequestParam{}
+	err := c.ParseForm(&req)
+	if err != nil {
+		logging.RuntimeLog.Error(err)
+		logging.CLILog.Error(err)
+		c.FailedStatus(err.Error())
+		return
+	}
+	if strings.TrimSpace(req.Target) == "" {
+		c.FailedStatus("no target")
+		return
+	}
+	workflow := db.Workflow{Id: req.WorkflowId}
+	if !workflow.Get() {
+		c.FailedStatus("工作流不存在！")
+		return
+	}
+	if _, err = workerapi.ParseWorkflow(workflow.Definition); err != nil {
+		c.FailedStatus("工作流定义错误：" + err.Error())
+		return
+	}
+	workspaceId := c.GetCurrentWorkspace()
+	if workspaceId <= 0 {
+		c.FailedStatus("请选择一个当前的工作空间！（如果是超级管理员，请在右上角进行切换）")
+		return
+	}
+	kwArgs, err := json.Marshal(req)
+	if err != nil {
+		c.FailedStatus(err.Error())
+		return
+	}
+	var taskId string
+	// 计划任务
+	if req.IsTaskCron {
+		if taskId = runner.SaveCronTask("workflow", string(kwArgs), req.TaskCronRule, req.TaskCronComment, workspaceId); taskId == "" {
+			c.FailedStatus("save to db fail")
+			return
+		}
+	} else {
+		// 立即执行的任务
+		if taskId, err = runner.SaveMainTask("workflow", string(kwArgs), "", workspaceId); err != nil {
+			c.FailedStatus(err.Error())
+			return
+		}
+	}
+	c.SucceededStatus(taskId)
+}
+
 // validateRequestParam 校验请求的参数
 func (c *TaskController) validateRequestParam(req *taskRequestParam) {
 	if req.Length <= 0 {
@@ -1046,6 +1108,9 @@ func batchDeleteTaskByState(taskState string, workspaceId int) (total int) {
 			}
 			taskDelete.Delete()
 			deleteRunTaskByMainTaskId(workspaceGUIDCacheMap[taskDelete.Id], taskDelete.TaskId)
+			if taskDelete.CronTaskId == "" {
+				runner.DeleteTargetSet(taskDelete.KwArgs)
+			}
 			total++
 		}
 	}
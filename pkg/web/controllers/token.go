@@ -1,15 +1,25 @@
 package controllers
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/hanc00l/nemo_go/pkg/db"
+	"github.com/hanc00l/nemo_go/pkg/logging"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 )
 
 const (
 	defaultExpireSeconds = 60 * 60 * 1 // 默认过期时间（s）
+
+	AccessTokenPrefix          = "nemo_"     // 用户访问令牌的前缀，用于与JWT区分
+	AccessTokenWorkspaceHeader = "Workspace" // 使用访问令牌时，通过header指定工作空间的GUID
+	accessTokenLastUsedPeriod  = time.Minute // 最近使用时间的更新间隔
 )
 
 var (
@@ -92,3 +102,99 @@ func SetTokenValueToHeader(tokenString string) string {
 	//Authorization: Bearer <token>
 	return fmt.Sprintf("Bearer %s", tokenString)
 }
+
+// GenerateAccessToken 生成一个新的用户访问令牌，返回令牌及保存到数据库中的hash
+func GenerateAccessToken() (token, tokenHash string, err error) {
+	b := make([]byte, 20)
+	if _, err = rand.Read(b); err != nil {
+		return
+	}
+	token = AccessTokenPrefix + hex.EncodeToString(b)
+	tokenHash = HashAccessToken(token)
+	return
+}
+
+// HashAccessToken 计算访问令牌的hash，数据库中只保存令牌的hash
+func HashAccessToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// IsAccessToken 检查是否是用户访问令牌
+func IsAccessToken(tokenString string) bool {
+	return strings.HasPrefix(tokenString, AccessTokenPrefix)
+}
+
+// ParseAccessTokenWorkspace 解析访问令牌授权的工作空间
+func ParseAccessTokenWorkspace(workspace string) (workspaceIds []int) {
+	for _, w := range strings.Split(workspace, ",") {
+		if id, err := strconv.Atoi(strings.TrimSpace(w)); err == nil && id > 0 {
+			workspaceIds = append(workspaceIds, id)
+		}
+	}
+	return
+}
+
+// FormatAccessTokenWorkspace 生成保存到数据库中的授权工作空间
+func FormatAccessTokenWorkspace(workspaceIds []int) string {
+	var workspace []string
+	for _, id := range workspaceIds {
+		workspace = append(workspace, strconv.Itoa(id))
+	}
+	return strings.Join(workspace, ",")
+}
+
+// ValidAccessToken 验证用户访问令牌：令牌未被撤销、未过期，用户有效，并且请求的工作空间在令牌的授权范围内；
+// workspaceGUID为空时使用令牌授权的第一个可用的工作空间
+func ValidAccessToken(tokenString, workspaceGUID, ip string) (data *TokenData) {
+	if !IsAccessToken(tokenString) {
+		return nil
+	}
+	userToken := db.UserToken{TokenHash: HashAccessToken(tokenString)}
+	if !userToken.GetByHash() || userToken.State != "enable" {
+		return nil
+	}
+	if userToken.ExpireDatetime != nil && time.Now().After(*userToken.ExpireDatetime) {
+		return nil
+	}
+	user := db.User{Id: userToken.UserId}
+	if !user.Get() || user.State != "enable" {
+		return nil
+	}
+	workspaceId := 0
+	for _, id := range ParseAccessTokenWorkspace(userToken.Workspace) {
+		workspace := db.Workspace{Id: id}
+		if !workspace.Get() || workspace.State != "enable" {
+			continue
+		}
+		if workspaceGUID != "" && workspace.WorkspaceGUID != workspaceGUID {
+			continue
+		}
+		// 非超级管理员需检查用户当前是否仍有工作空间的权限
+		if user.UserRole != SuperAdmin {
+			userWorkspace := db.UserWorkspace{UserId: user.Id, WorkspaceId: id}
+			if !userWorkspace.GetByUserAndWorkspaceId() {
+				continue
+			}
+		}
+		workspaceId = id
+		break
+	}
+	if workspaceId == 0 {
+		return nil
+	}
+	if userToken.LastUsedDatetime == nil || userToken.LastUsedIP != ip || time.Now().Sub(*userToken.LastUsedDatetime) > accessTokenLastUsedPeriod {
+		if !userToken.UpdateLastUsed(ip) {
+			logging.RuntimeLog.Warningf("update access token last used fail:%d", userToken.Id)
+		}
+	}
+	return &TokenData{User: user.UserName, UserRole: user.UserRole, Workspace: workspaceId}
+}
+
+// ValidRequestToken 验证请求的token，支持JWT及用户访问令牌
+func ValidRequestToken(tokenString, workspaceGUID, ip string) (data *TokenData) {
+	if IsAccessToken(tokenString) {
+		return ValidAccessToken(tokenString, workspaceGUID, ip)
+	}
+	return ValidToken(tokenString)
+}
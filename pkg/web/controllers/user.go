@@ -25,6 +25,7 @@ type UserData struct {
 	UserRole        string `json:"user_role" form:"user_role"`
 	State           string `json:"state" form:"state"`
 	SortOrder       int    `json:"sort_order" form:"sort_order"`
+	Locked          bool   `json:"locked" form:"-"`
 	CreateDatetime  string `json:"create_time" form:"-"`
 	UpdateDatetime  string `json:"update_time" form:"-"`
 }
@@ -85,6 +86,7 @@ func (c *UserController) getUserListData(req userRequestParam) (resp DataTableRe
 		}
 		u.UserDescription = userRow.UserDescription
 		u.SortOrder = userRow.SortOrder
+		u.Locked = IsUserLocked(userRow)
 		u.UpdateDatetime = FormatDateTime(userRow.UpdateDatetime)
 		u.CreateDatetime = FormatDateTime(userRow.CreateDatetime)
 		resp.Data = append(resp.Data, u)
@@ -121,9 +123,18 @@ func (c *UserController) AddSaveAction() {
 		c.FailedStatus(err.Error())
 		return
 	}
+	if err = CheckPasswordPolicy(userData.UserPassword); err != nil {
+		c.FailedStatus(err.Error())
+		return
+	}
 	user := db.User{}
 	user.UserName = userData.UserName
-	user.UserPassword = ProcessPasswordHash(userData.UserPassword)
+	user.UserPassword, err = ProcessPasswordHash(userData.UserPassword)
+	if err != nil {
+		logging.RuntimeLog.Error(err)
+		c.FailedStatus(err.Error())
+		return
+	}
 	user.UserRole = userData.UserRole
 	user.UserDescription = userData.UserDescription
 	user.State = userData.State
@@ -232,10 +243,23 @@ func (c *UserController) ResetPasswordAction() {
 		c.FailedStatus("用户id或密码为空")
 		return
 	}
+	if err = CheckPasswordPolicy(userData.UserPassword); err != nil {
+		c.FailedStatus(err.Error())
+		return
+	}
 	user := db.User{Id: userData.Id}
 	if user.Get() {
+		hash, err := ProcessPasswordHash(userData.UserPassword)
+		if err != nil {
+			logging.RuntimeLog.Error(err)
+			c.FailedStatus(err.Error())
+			return
+		}
+		// 重置密码同时解除用户的锁定
 		updateMap := make(map[string]interface{})
-		updateMap["user_password"] = ProcessPasswordHash(userData.UserPassword)
+		updateMap["user_password"] = hash
+		updateMap["login_failed"] = 0
+		updateMap["locked_datetime"] = nil
 		if user.Update(updateMap) {
 			c.SucceededStatus("重置密码成功！")
 			logging.RuntimeLog.Infof("reset user:%s,type:%s", user.UserName, user.UserRole)
@@ -5,6 +5,7 @@ import (
 	"github.com/hanc00l/nemo_go/pkg/db"
 	"github.com/hanc00l/nemo_go/pkg/logging"
 	"github.com/hanc00l/nemo_go/pkg/task/pocscan"
+	"strings"
 )
 
 type VulController struct {
@@ -18,6 +19,10 @@ type vulRequestParam struct {
 	Target    string `form:"vul_target"`
 	PocFile   string `form:"vul_poc_file"`
 	DateDelta int    `form:"date_delta"`
+	Status    string `form:"vul_status"`
+	Severity  string `form:"vul_severity"`
+	Assignee  string `form:"vul_assignee"`
+	CVE       string `form:"vul_cve"`
 }
 
 type VulnerabilityData struct {
@@ -27,21 +32,46 @@ type VulnerabilityData struct {
 	Url         string `json:"url"`
 	PocFile     string `json:"poc_file"`
 	Source      string `json:"source"`
+	VulName     string `json:"vul_name"`
+	CVE         string `json:"cve"`
+	Engines     string `json:"engines"`
+	Status      string `json:"status"`
+	Severity    string `json:"severity"`
+	Assignee    string `json:"assignee"`
 	CreateTime  string `json:"create_datetime"`
 	UpdateTime  string `json:"update_datetime"`
 	WorkspaceId int    `json:"workspace"`
 }
 
 type VulnerabilityInfo struct {
-	Id         int
-	Target     string
-	Url        string
-	PocFile    string
-	Source     string
-	Extra      string
+	Id           int
+	Target       string
+	Url          string
+	PocFile      string
+	Source       string
+	Extra        string
+	VulName      string
+	CVE          string
+	CWE          string
+	Engines      string
+	Status       string
+	Severity     string
+	Assignee     string
+	NextStatus   []string
+	SeverityList []string
+	Comments     []VulnerabilityCommentInfo
+	CreateTime   string
+	UpdateTime   string
+	Workspace    string
+}
+
+// VulnerabilityCommentInfo 漏洞的一条处理记录
+type VulnerabilityCommentInfo struct {
+	UserName   string
+	OldStatus  string
+	NewStatus  string
+	Content    string
 	CreateTime string
-	UpdateTime string
-	Workspace  string
 }
 
 func (c *VulController) IndexAction() {
@@ -104,6 +134,127 @@ func (c *VulController) DeleteAction() {
 	c.MakeStatusResponse(vul.Delete())
 }
 
+// UpdateStatusAction 变更漏洞的状态，并记录处理过程
+func (c *VulController) UpdateStatusAction() {
+	defer c.ServeJSON()
+	if c.CheckMultiAccessRequest([]RequestRole{SuperAdmin, Admin}, false) == false {
+		c.FailedStatus("当前用户权限不允许！")
+		return
+	}
+
+	id, err := c.GetInt("id")
+	if err != nil {
+		logging.RuntimeLog.Error(err)
+		c.FailedStatus(err.Error())
+		return
+	}
+	status := c.GetString("status")
+	vul := db.Vulnerability{Id: id}
+	if !vul.Get() {
+		c.FailedStatus("漏洞不存在")
+		return
+	}
+	if !db.CheckVulStatusTransition(vul.Status, status) {
+		c.FailedStatus(fmt.Sprintf("漏洞状态不允许从%s变更为%s", vul.Status, status))
+		return
+	}
+	if !vul.Update(map[string]interface{}{"status": status}) {
+		c.FailedStatus("更新漏洞状态失败")
+		return
+	}
+	comment := db.VulnerabilityComment{
+		RelatedId: id,
+		UserName:  c.GetCurrentUser(),
+		OldStatus: vul.Status,
+		NewStatus: status,
+		Content:   strings.TrimSpace(c.GetString("comment")),
+	}
+	c.MakeStatusResponse(comment.Add())
+}
+
+// UpdateAction 更新漏洞的严重程度和处理人
+func (c *VulController) UpdateAction() {
+	defer c.ServeJSON()
+	if c.CheckMultiAccessRequest([]RequestRole{SuperAdmin, Admin}, false) == false {
+		c.FailedStatus("当前用户权限不允许！")
+		return
+	}
+
+	id, err := c.GetInt("id")
+	if err != nil {
+		logging.RuntimeLog.Error(err)
+		c.FailedStatus(err.Error())
+		return
+	}
+	severity := c.GetString("severity")
+	assignee := strings.TrimSpace(c.GetString("assignee"))
+	if severity != "" && !db.CheckVulSeverity(severity) {
+		c.FailedStatus("无效的严重程度")
+		return
+	}
+	vul := db.Vulnerability{Id: id}
+	if !vul.Get() {
+		c.FailedStatus("漏洞不存在")
+		return
+	}
+	updateMap := make(map[string]interface{})
+	var changes []string
+	if severity != vul.Severity {
+		updateMap["severity"] = severity
+		changes = append(changes, fmt.Sprintf("严重程度：%s -> %s", vul.Severity, severity))
+	}
+	if assignee != vul.Assignee {
+		updateMap["assignee"] = assignee
+		changes = append(changes, fmt.Sprintf("处理人：%s -> %s", vul.Assignee, assignee))
+	}
+	if len(updateMap) == 0 {
+		c.SucceededStatus("success")
+		return
+	}
+	if !vul.Update(updateMap) {
+		c.FailedStatus("更新漏洞失败")
+		return
+	}
+	comment := db.VulnerabilityComment{
+		RelatedId: id,
+		UserName:  c.GetCurrentUser(),
+		Content:   strings.Join(changes, "\n"),
+	}
+	c.MakeStatusResponse(comment.Add())
+}
+
+// AddCommentAction 增加漏洞的评论
+func (c *VulController) AddCommentAction() {
+	defer c.ServeJSON()
+	if c.CheckMultiAccessRequest([]RequestRole{SuperAdmin, Admin}, false) == false {
+		c.FailedStatus("当前用户权限不允许！")
+		return
+	}
+
+	id, err := c.GetInt("id")
+	if err != nil {
+		logging.RuntimeLog.Error(err)
+		c.FailedStatus(err.Error())
+		return
+	}
+	content := strings.TrimSpace(c.GetString("comment"))
+	if content == "" {
+		c.FailedStatus("评论内容为空")
+		return
+	}
+	vul := db.Vulnerability{Id: id}
+	if !vul.Get() {
+		c.FailedStatus("漏洞不存在")
+		return
+	}
+	comment := db.VulnerabilityComment{
+		RelatedId: id,
+		UserName:  c.GetCurrentUser(),
+		Content:   content,
+	}
+	c.MakeStatusResponse(comment.Add())
+}
+
 // LoadXrayPocFileAction 获取xray的pocfile列表
 func (c *VulController) LoadXrayPocFileAction() {
 	defer c.ServeJSON()
@@ -154,6 +305,18 @@ func (c *VulController) getSearchMap(req vulRequestParam) (searchMap map[string]
 	if req.DateDelta > 0 {
 		searchMap["date_delta"] = req.DateDelta
 	}
+	if req.Status != "" {
+		searchMap["status"] = req.Status
+	}
+	if req.Severity != "" {
+		searchMap["severity"] = req.Severity
+	}
+	if req.Assignee != "" {
+		searchMap["assignee"] = req.Assignee
+	}
+	if req.CVE != "" {
+		searchMap["cve"] = req.CVE
+	}
 	return
 }
 
@@ -171,6 +334,12 @@ func (c *VulController) getVulnerabilityListData(req vulRequestParam) (resp Data
 		v.Url = vulRow.Url
 		v.PocFile = vulRow.PocFile
 		v.Source = vulRow.Source
+		v.VulName = vulRow.VulName
+		v.CVE = vulRow.CVE
+		v.Engines = vulRow.Engines
+		v.Status = vulRow.Status
+		v.Severity = vulRow.Severity
+		v.Assignee = vulRow.Assignee
 		v.CreateTime = FormatDateTime(vulRow.CreateDatetime)
 		v.UpdateTime = FormatDateTime(vulRow.UpdateDatetime)
 		v.WorkspaceId = vulRow.WorkspaceId
@@ -197,9 +366,28 @@ func getVulnerabilityInfo(vulId int) (r VulnerabilityInfo) {
 	r.Source = vul.Source
 	r.PocFile = vul.PocFile
 	r.Extra = vul.Extra
+	r.VulName = vul.VulName
+	r.CVE = vul.CVE
+	r.CWE = vul.CWE
+	r.Engines = vul.Engines
+	r.Status = vul.Status
+	r.Severity = vul.Severity
+	r.Assignee = vul.Assignee
+	r.NextStatus = db.VulStatusTransition[vul.Status]
+	r.SeverityList = db.VulSeverityList
 	r.CreateTime = FormatDateTime(vul.CreateDatetime)
 	r.UpdateTime = FormatDateTime(vul.UpdateDatetime)
 	r.Workspace = fmt.Sprintf("%d", vul.WorkspaceId)
+	comment := db.VulnerabilityComment{RelatedId: vulId}
+	for _, row := range comment.GetsByRelatedId() {
+		r.Comments = append(r.Comments, VulnerabilityCommentInfo{
+			UserName:   row.UserName,
+			OldStatus:  row.OldStatus,
+			NewStatus:  row.NewStatus,
+			Content:    row.Content,
+			CreateTime: FormatDateTime(row.CreateDatetime),
+		})
+	}
 
 	return
 }
@@ -75,6 +75,30 @@ func (c *WorkerController) ManualReloadWorkerAction() {
 
 }
 
+// ManualWorkerFileSyncAction 同步worker文件
+func (c *WorkerController) ManualWorkerFileSyncAction() {
+	defer c.ServeJSON()
+	if c.CheckMultiAccessRequest([]RequestRole{SuperAdmin, Admin}, false) == false {
+		c.FailedStatus("当前用户权限不允许！")
+		return
+	}
+
+	worker := c.GetString("worker_name")
+	if worker == "" {
+		c.FailedStatus("worker name is empty")
+		return
+	}
+	comm.WorkerStatusMutex.Lock()
+	defer comm.WorkerStatusMutex.Unlock()
+
+	if _, ok := comm.WorkerStatus[worker]; ok {
+		comm.WorkerStatus[worker].ManualFileSyncFlag = true
+		c.SucceededStatus("已设置worker同步标志，等待worker的daemon进程执行！")
+	} else {
+		c.FailedStatus("无效的worker name")
+	}
+}
+
 // EditWorkerAction 更改worker
 func (c *WorkerController) EditWorkerAction() {
 	defer c.ServeJSON()
@@ -0,0 +1,235 @@
+package controllers
+
+import (
+	"github.com/hanc00l/nemo_go/pkg/db"
+	"github.com/hanc00l/nemo_go/pkg/logging"
+	"github.com/hanc00l/nemo_go/pkg/task/workerapi"
+	"strings"
+)
+
+type WorkflowController struct {
+	BaseController
+}
+
+type workflowRequestParam struct {
+	DatableRequestParam
+	Name string `form:"name"`
+}
+
+type WorkflowData struct {
+	Id             int    `json:"id" form:"id"`
+	Index          int    `json:"index" form:"-"`
+	Name           string `json:"name" form:"name"`
+	Description    string `json:"description" form:"description"`
+	Definition     string `json:"definition" form:"definition"`
+	Stages         string `json:"stages" form:"-"`
+	SortOrder      int    `json:"sort_order" form:"sort_order"`
+	CreateDatetime string `json:"create_time" form:"-"`
+	UpdateDatetime string `json:"update_time" form:"-"`
+}
+
+// IndexAction 显示列表页面
+func (c *WorkflowController) IndexAction() {
+	c.Layout = "base.html"
+	c.TplName = "workflow-list.html"
+}
+
+// ListAction 列表的数据
+func (c *WorkflowController) ListAction() {
+	defer c.ServeJSON()
+
+	req := workflowRequestParam{}
+	err := c.ParseForm(&req)
+	if err != nil {
+		logging.RuntimeLog.Error(err.Error())
+	}
+	c.validateRequestParam(&req)
+	c.Data["json"] = c.getListData(req)
+}
+
+// GetAction 获取一个工作流的定义
+func (c *WorkflowController) GetAction() {
+	defer c.ServeJSON()
+
+	id, err := c.GetInt("id")
+	if err != nil {
+		logging.RuntimeLog.Error(err.Error())
+		c.FailedStatus(err.Error())
+		return
+	}
+	workflow := db.Workflow{Id: id}
+	if !workflow.Get() {
+		c.FailedStatus("工作流不存在")
+		return
+	}
+	c.Data["json"] = WorkflowData{
+		Id:          workflow.Id,
+		Name:        workflow.Name,
+		Description: workflow.Description,
+		Definition:  workflow.Definition,
+		SortOrder:   workflow.SortOrder,
+	}
+}
+
+// AddSaveAction 保存新增的工作流
+func (c *WorkflowController) AddSaveAction() {
+	defer c.ServeJSON()
+	if c.CheckMultiAccessRequest([]RequestRole{SuperAdmin, Admin}, false) == false {
+		c.FailedStatus("当前用户权限不允许！")
+		return
+	}
+
+	data := WorkflowData{}
+	err := c.ParseForm(&data)
+	if err != nil {
+		logging.RuntimeLog.Error(err.Error())
+		c.FailedStatus(err.Error())
+		return
+	}
+	if data.Name == "" {
+		c.FailedStatus("工作流名称不能为空！")
+		return
+	}
+	if _, err = workerapi.ParseWorkflow(data.Definition); err != nil {
+		c.FailedStatus("工作流定义错误：" + err.Error())
+		return
+	}
+	workflow := db.Workflow{Name: data.Name}
+	if workflow.GetByName() {
+		c.FailedStatus("工作流名称已存在！")
+		return
+	}
+	workflow.Description = data.Description
+	workflow.Definition = data.Definition
+	workflow.SortOrder = data.SortOrder
+	c.MakeStatusResponse(workflow.Add())
+}
+
+// UpdateAction 更新工作流
+func (c *WorkflowController) UpdateAction() {
+	defer c.ServeJSON()
+	if c.CheckMultiAccessRequest([]RequestRole{SuperAdmin, Admin}, false) == false {
+		c.FailedStatus("当前用户权限不允许！")
+		return
+	}
+
+	data := WorkflowData{}
+	err := c.ParseForm(&data)
+	if err != nil {
+		logging.RuntimeLog.Error(err.Error())
+		c.FailedStatus(err.Error())
+		return
+	}
+	if data.Id <= 0 || data.Name == "" {
+		c.FailedStatus("参数错误！")
+		return
+	}
+	if _, err = workerapi.ParseWorkflow(data.Definition); err != nil {
+		c.FailedStatus("工作流定义错误：" + err.Error())
+		return
+	}
+	sameName := db.Workflow{Name: data.Name}
+	if sameName.GetByName() && sameName.Id != data.Id {
+		c.FailedStatus("工作流名称已存在！")
+		return
+	}
+	workflow := db.Workflow{Id: data.Id}
+	updateMap := make(map[string]interface{})
+	updateMap["name"] = data.Name
+	updateMap["description"] = data.Description
+	updateMap["definition"] = data.Definition
+	updateMap["sort_order"] = data.SortOrder
+	c.MakeStatusResponse(workflow.Update(updateMap))
+}
+
+// DeleteAction 删除工作流
+func (c *WorkflowController) DeleteAction() {
+	defer c.ServeJSON()
+	if c.CheckMultiAccessRequest([]RequestRole{SuperAdmin, Admin}, false) == false {
+		c.FailedStatus("当前用户权限不允许！")
+		return
+	}
+
+	id, err := c.GetInt("id")
+	if err != nil {
+		logging.RuntimeLog.Error(err.Error())
+		c.FailedStatus(err.Error())
+		return
+	}
+	workflow := db.Workflow{Id: id}
+	c.MakeStatusResponse(workflow.Delete())
+}
+
+// GetAllAction 获取全部的工作流，用于任务选择
+func (c *WorkflowController) GetAllAction() {
+	defer c.ServeJSON()
+
+	workflow := db.Workflow{}
+	results, _ := workflow.Gets(make(map[string]interface{}), -1, -1)
+	var data []WorkflowData
+	for _, w := range results {
+		data = append(data, WorkflowData{Id: w.Id, Name: w.Name, Description: w.Description})
+	}
+	if data == nil {
+		data = make([]WorkflowData, 0)
+	}
+	c.Data["json"] = data
+}
+
+// validateRequestParam 校验请求的参数
+func (c *WorkflowController) validateRequestParam(req *workflowRequestParam) {
+	if req.Length <= 0 {
+		req.Length = 50
+	}
+	if req.Start < 0 {
+		req.Start = 0
+	}
+}
+
+// getListData 获取列表数据
+func (c *WorkflowController) getListData(req workflowRequestParam) (resp DataTableResponseData) {
+	workflow := db.Workflow{}
+	searchMap := make(map[string]interface{})
+	if req.Name != "" {
+		searchMap["name"] = req.Name
+	}
+	startPage := req.Start/req.Length + 1
+	results, total := workflow.Gets(searchMap, startPage, req.Length)
+	for i, w := range results {
+		data := WorkflowData{
+			Id:             w.Id,
+			Index:          req.Start + i + 1,
+			Name:           w.Name,
+			Description:    w.Description,
+			SortOrder:      w.SortOrder,
+			CreateDatetime: FormatDateTime(w.CreateDatetime),
+			UpdateDatetime: FormatDateTime(w.UpdateDatetime),
+		}
+		if define, err := workerapi.ParseWorkflow(w.Definition); err == nil {
+			data.Stages = makeWorkflowStagesDescription(define)
+		} else {
+			data.Stages = err.Error()
+		}
+		resp.Data = append(resp.Data, data)
+	}
+	resp.Draw = req.Draw
+	resp.RecordsTotal = total
+	resp.RecordsFiltered = total
+	if resp.Data == nil {
+		resp.Data = make([]interface{}, 0)
+	}
+	return
+}
+
+// makeWorkflowStagesDescription 生成工作流阶段的简要描述，如：portscan(portscan)->fingerprint(fingerprint)
+func makeWorkflowStagesDescription(define *workerapi.WorkflowDefine) string {
+	var stages []string
+	for _, s := range define.Stages {
+		if s.Name == s.Task {
+			stages = append(stages, s.Name)
+		} else {
+			stages = append(stages, s.Name+"("+s.Task+")")
+		}
+	}
+	return strings.Join(stages, "->")
+}
@@ -100,6 +100,10 @@ func (c *WorkspaceController) UserWorkspaceAction() {
 // ChangeWorkspaceSelectAction 切换到指定的workspace、更新JWTData或session
 func (c *WorkspaceController) ChangeWorkspaceSelectAction() {
 	defer c.ServeJSON()
+	if c.IsAccessTokenRequest() {
+		c.FailedStatus("使用访问令牌时，请通过Workspace请求头指定工作空间！")
+		return
+	}
 
 	userName := c.GetCurrentUser()
 	newWorkspaceId, err := c.GetInt("workspace")
@@ -25,8 +25,11 @@ func init() {
 	web.CtrlPost("/config-save-fingerprint", (*controllers.ConfigController).SaveFingerprintAction)
 	web.CtrlPost("/config-upload-poc", (*controllers.ConfigController).UploadPocAction)
 	web.CtrlPost("/config-save-notify", (*controllers.ConfigController).SaveTaskNotifyAction)
+	web.CtrlPost("/config-save-notify-rule", (*controllers.ConfigController).SaveNotifyRuleAction)
 	web.CtrlPost("/config-save-api", (*controllers.ConfigController).SaveAPITokenAction)
 	web.CtrlPost("/config-test-api", (*controllers.ConfigController).TestOnlineAPIKeyAction)
+	web.CtrlPost("/config-api-quota", (*controllers.ConfigController).LoadOnlineAPIQuotaAction)
+	web.CtrlPost("/config-clear-api-cache", (*controllers.ConfigController).ClearOnlineAPICacheAction)
 	web.CtrlPost("/config-test-notify", (*controllers.ConfigController).TestTaskNotifyAction)
 	web.CtrlPost("/config-save-workerproxy", (*controllers.ConfigController).SaveWorkerProxyAction)
 	web.CtrlPost("/config-save-domainscan", (*controllers.ConfigController).SaveDomainscanAction)
@@ -54,6 +57,7 @@ func init() {
 	web.CtrlPost("/ip-import-portscan", (*controllers.IPController).ImportPortscanResultAction)
 	web.CtrlPost("/ip-pin-top", (*controllers.IPController).PinTopAction)
 	web.CtrlPost("/ip-info-http", (*controllers.IPController).InfoHttpAction)
+	web.CtrlPost("/ip-timeline", (*controllers.IPController).TimelineAction)
 	web.CtrlPost("/ip-block", (*controllers.IPController).BlackIPAction)
 	web.CtrlGet("/ip-export", (*controllers.IPController).ExportIPResultAction)
 
@@ -70,6 +74,7 @@ func init() {
 	web.CtrlPost("/domain-color-tag", (*controllers.DomainController).MarkColorTagAction)
 	web.CtrlPost("/domain-pin-top", (*controllers.DomainController).PinTopAction)
 	web.CtrlPost("/domain-info-http", (*controllers.DomainController).InfoHttpAction)
+	web.CtrlPost("/domain-timeline", (*controllers.DomainController).TimelineAction)
 	web.CtrlPost("/domain-block", (*controllers.DomainController).BlockDomainAction)
 	web.CtrlGet("/domain-export", (*controllers.DomainController).ExportDomainResultAction)
 
@@ -77,9 +82,17 @@ func init() {
 	web.CtrlPost("/vulnerability-list", (*controllers.VulController).ListAction)
 	web.CtrlGet("/vulnerability-info", (*controllers.VulController).InfoAction)
 	web.CtrlPost("/vulnerability-delete", (*controllers.VulController).DeleteAction)
+	web.CtrlPost("/vulnerability-status", (*controllers.VulController).UpdateStatusAction)
+	web.CtrlPost("/vulnerability-update", (*controllers.VulController).UpdateAction)
+	web.CtrlPost("/vulnerability-comment", (*controllers.VulController).AddCommentAction)
 	web.CtrlPost("/vulnerability-load-xray-pocfile", (*controllers.VulController).LoadXrayPocFileAction)
 	web.CtrlPost("/vulnerability-load-nuclei-pocfile", (*controllers.VulController).LoadNucleiPocFileAction)
 
+	web.CtrlGet("/certificate-list", (*controllers.CertificateController).IndexAction)
+	web.CtrlPost("/certificate-list", (*controllers.CertificateController).ListAction)
+	web.CtrlGet("/certificate-info", (*controllers.CertificateController).InfoAction)
+	web.CtrlPost("/certificate-delete", (*controllers.CertificateController).DeleteAction)
+
 	web.CtrlGet("/org-list", (*controllers.OrganizationController).IndexAction)
 	web.CtrlPost("/org-list", (*controllers.OrganizationController).ListAction)
 	web.CtrlPost("/org-get", (*controllers.OrganizationController).GetAction)
@@ -100,6 +113,7 @@ func init() {
 	web.CtrlPost("/task-start-vulnerability", (*controllers.TaskController).StartPocScanTaskAction)
 	web.CtrlPost("/task-batch-delete", (*controllers.TaskController).DeleteBatchAction)
 	web.CtrlPost("/task-start-xscan", (*controllers.TaskController).StartXScanTaskAction)
+	web.CtrlPost("/task-start-workflow", (*controllers.TaskController).StartWorkflowTaskAction)
 	web.CtrlGet("/task-info-main", (*controllers.TaskController).InfoMainAction)
 	web.CtrlPost("/task-delete-main", (*controllers.TaskController).DeleteMainAction)
 
@@ -117,6 +131,15 @@ func init() {
 	web.CtrlPost("/key-word-del", (*controllers.KeySearchController).DeleteKeyWordAction)
 	web.CtrlPost("/key-word-get", (*controllers.KeySearchController).GetAction)
 	web.CtrlPost("/key-word-update", (*controllers.KeySearchController).UpdateAction)
+	web.CtrlPost("/key-word-history", (*controllers.KeySearchController).HistoryAction)
+
+	web.CtrlGet("/workflow-list", (*controllers.WorkflowController).IndexAction)
+	web.CtrlPost("/workflow-list", (*controllers.WorkflowController).ListAction)
+	web.CtrlPost("/workflow-get", (*controllers.WorkflowController).GetAction)
+	web.CtrlPost("/workflow-getall", (*controllers.WorkflowController).GetAllAction)
+	web.CtrlPost("/workflow-add", (*controllers.WorkflowController).AddSaveAction)
+	web.CtrlPost("/workflow-update", (*controllers.WorkflowController).UpdateAction)
+	web.CtrlPost("/workflow-delete", (*controllers.WorkflowController).DeleteAction)
 
 	web.CtrlPost("/workspace-user-list", (*controllers.WorkspaceController).UserWorkspaceAction)
 	web.CtrlPost("/workspace-user-change", (*controllers.WorkspaceController).ChangeWorkspaceSelectAction)
@@ -138,6 +161,11 @@ func init() {
 	web.CtrlPost("/user-reset-password", (*controllers.UserController).ResetPasswordAction)
 	web.CtrlPost("/user-workspace-list", (*controllers.UserController).ListUserWorkspaceAction)
 	web.CtrlPost("/user-workspace-update", (*controllers.UserController).UpdateUserWorkspaceAction)
+	web.CtrlGet("/access-token-list", (*controllers.AccessTokenController).IndexAction)
+	web.CtrlPost("/access-token-list", (*controllers.AccessTokenController).ListAction)
+	web.CtrlPost("/access-token-add", (*controllers.AccessTokenController).AddSaveAction)
+	web.CtrlPost("/access-token-revoke", (*controllers.AccessTokenController).RevokeAction)
+	web.CtrlPost("/access-token-delete", (*controllers.AccessTokenController).DeleteAction)
 
 	web.CtrlGet("/runtimelog-list", (*controllers.RuntimeLogController).IndexAction)
 	web.CtrlPost("/runtimelog-list", (*controllers.RuntimeLogController).ListAction)
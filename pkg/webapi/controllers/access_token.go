@@ -0,0 +1,52 @@
+package controllers
+
+import ctrl "github.com/hanc00l/nemo_go/pkg/web/controllers"
+
+type AccessTokenController struct {
+	ctrl.AccessTokenController
+}
+
+// @Title List
+// @Description 获取当前用户的访问令牌列表
+// @Param authorization		header string true "token"
+// @Success 200 {object} models.AccessTokenDataTableResponseData
+// @router /list [post]
+func (c *AccessTokenController) List() {
+	c.IsServerAPI = true
+	c.ListAction()
+}
+
+// @Title Add
+// @Description 生成一个新的访问令牌，令牌只在生成时返回一次（msg）；不允许使用访问令牌调用
+// @Param authorization		header string true "token"
+// @Param name 				formData string true "令牌名称"
+// @Param workspace 		formData string true "授权的工作空间id，多个用逗号分隔"
+// @Param expire_days 		formData int false "有效期（天），0为永不过期"
+// @Success 200 {object} models.StatusResponseData
+// @router /add [post]
+func (c *AccessTokenController) Add() {
+	c.IsServerAPI = true
+	c.AddSaveAction()
+}
+
+// @Title Revoke
+// @Description 撤销一个访问令牌
+// @Param authorization		header string true "token"
+// @Param id 				formData int true "id"
+// @Success 200 {object} models.StatusResponseData
+// @router /revoke [post]
+func (c *AccessTokenController) Revoke() {
+	c.IsServerAPI = true
+	c.RevokeAction()
+}
+
+// @Title Delete
+// @Description 删除一个访问令牌
+// @Param authorization		header string true "token"
+// @Param id 				formData int true "id"
+// @Success 200 {object} models.StatusResponseData
+// @router /delete [post]
+func (c *AccessTokenController) Delete() {
+	c.IsServerAPI = true
+	c.DeleteAction()
+}
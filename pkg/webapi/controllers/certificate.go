@@ -0,0 +1,47 @@
+package controllers
+
+import ctrl "github.com/hanc00l/nemo_go/pkg/web/controllers"
+
+type CertificateController struct {
+	ctrl.CertificateController
+}
+
+// @Title List
+// @Description 根据指定筛选条件，查询TLS证书的数据
+// @Param authorization		header string true "token"
+// @Param start 			formData int true "查询的资产的起始行数"
+// @Param length 			formData int true "返回资产指定的数量"
+// @Param cert_domain 		formData string false "证书的Subject CN或SAN包含的域名"
+// @Param cert_issuer 		formData string false "证书的颁发者"
+// @Param cert_host 		formData string false "使用证书的IP或域名"
+// @Param expire_days 		formData int false "指定天数内过期的证书"
+// @Param expired 			formData bool false "已过期的证书"
+// @Param date_delta 		formData int false "时间间隔"
+// @Success 200 {object} models.CertificateDataTableResponseData
+// @router /list [post]
+func (c *CertificateController) List() {
+	c.IsServerAPI = true
+	c.ListAction()
+}
+
+// @Title Info
+// @Description 显示一个证书的详情及使用证书的主机
+// @Param authorization		header string true "token"
+// @Param id 				formData int true "id"
+// @Success 200 {object} models.CertificateInfo
+// @router /info [post]
+func (c *CertificateController) Info() {
+	c.IsServerAPI = true
+	c.InfoAction()
+}
+
+// @Title DeleteCertificate
+// @Description 删除一个证书
+// @Param authorization	header string true "token"
+// @Param id 			formData int true "id"
+// @Success 200 {object} models.StatusResponseData
+// @router /delete [post]
+func (c *CertificateController) DeleteCertificate() {
+	c.IsServerAPI = true
+	c.DeleteAction()
+}
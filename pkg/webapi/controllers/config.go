@@ -63,6 +63,8 @@ func (c *ConfigController) LoadDefaultConfig() {
 		IsPortscan:         domainscan.IsPortScan,
 		IsWhois:            domainscan.IsWhois,
 		IsICP:              domainscan.IsICP,
+		IsDNSRecord:        domainscan.IsDNSRecord,
+		IsDNSCheck:         domainscan.IsDNSCheck,
 		//onlineAPI:
 		IsFofa:   onlineapi.IsFofa,
 		IsHunter: onlineapi.IsHunter,
@@ -95,6 +97,8 @@ func (c *ConfigController) LoadDefaultConfig() {
 // @Param portscan			formData bool true "是否对域名收集结果的IP进行端口扫描"
 // @Param whois				formData bool true "是否执行whois"
 // @Param icp				formData bool true "是否执行icp备案查询"
+// @Param dnsRecord		formData bool true "是否收集主域名的MX、TXT、NS、SOA、SRV及CAA记录"
+// @Param dnsCheck		formData bool true "是否检查NS的域传送及开放递归解析"
 // @Param httpx				formData bool true "是否使用httpx获取指纹"
 // @Param fingerprinthub	formData bool true "是否使用fingerprinthub获取指纹"
 // @Param screenshot		formData bool true "是否进行屏幕截图"
@@ -158,6 +162,8 @@ func (c *ConfigController) SaveDefaultConfig() {
 	conf.GlobalWorkerConfig().Domainscan.IsPortScan = data.IsPortscan
 	conf.GlobalWorkerConfig().Domainscan.IsICP = data.IsICP
 	conf.GlobalWorkerConfig().Domainscan.IsWhois = data.IsWhois
+	conf.GlobalWorkerConfig().Domainscan.IsDNSRecord = data.IsDNSRecord
+	conf.GlobalWorkerConfig().Domainscan.IsDNSCheck = data.IsDNSCheck
 	//fingerprint
 	conf.GlobalWorkerConfig().Fingerprint.IsHttpx = data.IsHttpx
 	conf.GlobalWorkerConfig().Fingerprint.IsFingerprintHub = data.IsFingerprintHub
@@ -24,6 +24,8 @@ type DomainController struct {
 // @Param select_no_ip 		formData bool false "选择没有解析IP的资产"
 // @Param select_order_by_date 	formData bool false "IP按更新日期排序"
 // @Param domain_http 			formData string false "http协议中的属性"
+// @Param select_stale 		formData bool false "选择连续多次扫描未发现的域名"
+// @Param last_seen_before 	formData string false "最后发现时间早于指定日期（如2023-01-01）"
 // @Success 200 {object} models.DomainDataTableResponseData
 // @router /list [post]
 func (c *DomainController) List() {
@@ -44,6 +46,21 @@ func (c *DomainController) Info() {
 	c.InfoAction()
 }
 
+// @Title Timeline
+// @Description 获取一个domain的资产变更记录（新增、删除、修改），按时间倒序
+// @Param authorization	header string true "token"
+// @Param domain 		formData string true "domain"
+// @Param workspace 	formData int true "所在的workspace id"
+// @Param since 		formData string false "起始日期（如2023-01-01），为空则返回全部的变更记录"
+// @Param start 		formData int false "查询的起始行数"
+// @Param length 		formData int false "返回的数量"
+// @Success 200 {object} models.AssetTimelineData
+// @router /timeline [post]
+func (c *DomainController) Timeline() {
+	c.IsServerAPI = true
+	c.TimelineAction()
+}
+
 // @Title DeleteDomain
 // @Description 删除一个domain
 // @Param authorization	header string true "token"
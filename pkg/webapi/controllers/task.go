@@ -166,6 +166,148 @@ func (c *TaskController) RunCronTask() {
 	c.RunCronTaskAction()
 }
 
+// @Title StartPortScanTask
+// @Description 执行一个端口扫描任务
+// @Param authorization		header string true "token"
+// @Param target 			formData string true "任务目标(ip、ip/掩码或ip范围），多个目标以换行分开"
+// @Param port 				formData string false "扫描的端口，为空则使用默认配置"
+// @Param rate 				formData int false "扫描速率"
+// @Param nmap_tech 		formData string false "nmap扫描技术，如-sS、-sT"
+// @Param bin 				formData string false "扫描程序，可选nmap、masscan、gogo"
+// @Param org_id 			formData int false "关联的组机构"
+// @Param portscan 			formData bool false "是否执行端口扫描"
+// @Param iplocation 		formData bool false "是否查询ip归属地"
+// @Param fofasearch 		formData bool false "是否执行fofa查询"
+// @Param quakesearch 		formData bool false "是否执行quake查询"
+// @Param huntersearch 		formData bool false "是否执行hunter查询"
+// @Param httpx 			formData bool false "是否执行httpx指纹识别"
+// @Param screenshot 		formData bool false "是否截图"
+// @Param fingerprinthub 	formData bool false "是否执行fingerprinthub指纹识别"
+// @Param fingerprintx 		formData bool false "是否执行fingerprintx指纹识别"
+// @Param iconhash 			formData bool false "是否获取iconhash"
+// @Param ping 				formData bool false "扫描前是否ping"
+// @Param exclude 			formData string false "排除的ip"
+// @Param taskmode 			formData int false "任务切分模式：0为单任务，1为每一行目标单独任务，2为按IP切分，3为按端口切分，4为按IP和端口交叉切分"
+// @Param load_opened_port 	formData bool false "是否加载已开放的端口"
+// @Param ignoreoutofchina 	formData bool false "是否忽略中国大陆以外的ip"
+// @Param ignorecdn 		formData bool false "是否忽略CDN"
+// @Param proxy 			formData bool false "是否使用代理"
+// @Param taskcron 			formData bool false "是否为计划任务"
+// @Param cronrule 			formData string false "计划任务的规则"
+// @Param croncomment 		formData string false "计划任务的名称"
+// @Success 200 {object} models.StatusResponseData
+// @router /portscan [post]
+func (c *TaskController) StartPortScanTask() {
+	c.IsServerAPI = true
+	c.StartPortScanTaskAction()
+}
+
+// @Title StartBatchScanTask
+// @Description 执行一个探测+扫描任务
+// @Param authorization		header string true "token"
+// @Param target 			formData string true "任务目标(ip、ip/掩码或ip范围），多个目标以换行分开"
+// @Param port 				formData string false "探测及扫描的端口，格式为：探测端口|扫描端口"
+// @Param rate 				formData int false "扫描速率"
+// @Param nmap_tech 		formData string false "nmap扫描技术，如-sS、-sT"
+// @Param bin 				formData string false "扫描程序，可选nmap、masscan、gogo"
+// @Param org_id 			formData int false "关联的组机构"
+// @Param portscan 			formData bool false "是否对探测存活的ip执行端口扫描"
+// @Param iplocation 		formData bool false "是否查询ip归属地"
+// @Param httpx 			formData bool false "是否执行httpx指纹识别"
+// @Param screenshot 		formData bool false "是否截图"
+// @Param fingerprinthub 	formData bool false "是否执行fingerprinthub指纹识别"
+// @Param fingerprintx 		formData bool false "是否执行fingerprintx指纹识别"
+// @Param iconhash 			formData bool false "是否获取iconhash"
+// @Param ping 				formData bool false "是否ping"
+// @Param exclude 			formData string false "排除的ip"
+// @Param taskmode 			formData int false "任务切分模式：0为单任务，1为每一行目标单独任务，2为按IP切分，3为按端口切分，4为按IP和端口交叉切分"
+// @Param proxy 			formData bool false "是否使用代理"
+// @Param taskcron 			formData bool false "是否为计划任务"
+// @Param cronrule 			formData string false "计划任务的规则"
+// @Param croncomment 		formData string false "计划任务的名称"
+// @Success 200 {object} models.StatusResponseData
+// @router /batchscan [post]
+func (c *TaskController) StartBatchScanTask() {
+	c.IsServerAPI = true
+	c.StartBatchScanTaskAction()
+}
+
+// @Title StartDomainScanTask
+// @Description 执行一个域名扫描任务
+// @Param authorization		header string true "token"
+// @Param target 			formData string true "任务目标（域名），多个目标以换行分开"
+// @Param org_id 			formData int false "关联的组机构"
+// @Param subfinder 		formData bool false "是否执行subfinder子域名枚举"
+// @Param subdomainbrute 	formData bool false "是否执行子域名爆破"
+// @Param subdomainpermute 	formData bool false "是否执行已有子域名的变换"
+// @Param crawler 			formData bool false "是否执行子域名爬虫"
+// @Param fld_domain 		formData bool false "是否对目标的主域名进行扫描"
+// @Param portscan 			formData bool false "是否对域名解析的ip执行端口扫描"
+// @Param networkscan 		formData bool false "是否对域名解析的ip所在C段执行端口扫描"
+// @Param fofasearch 		formData bool false "是否执行fofa查询"
+// @Param quakesearch 		formData bool false "是否执行quake查询"
+// @Param huntersearch 		formData bool false "是否执行hunter查询"
+// @Param icpquery 			formData bool false "是否执行ICP备案查询"
+// @Param whoisquery 		formData bool false "是否执行whois查询"
+// @Param httpx 			formData bool false "是否执行httpx指纹识别"
+// @Param screenshot 		formData bool false "是否截图"
+// @Param fingerprinthub 	formData bool false "是否执行fingerprinthub指纹识别"
+// @Param fingerprintx 		formData bool false "是否执行fingerprintx指纹识别"
+// @Param iconhash 			formData bool false "是否获取iconhash"
+// @Param taskmode 			formData int false "任务切分模式：0为单任务，1为每一行目标单独任务"
+// @Param porttaskmode 		formData int false "端口扫描的任务切分模式：0为单任务，2为按IP切分，3为按端口切分，4为按IP和端口交叉切分"
+// @Param ignoreoutofchina 	formData bool false "是否忽略中国大陆以外的ip"
+// @Param ignorecdn 		formData bool false "是否忽略CDN"
+// @Param proxy 			formData bool false "是否使用代理"
+// @Param taskcron 			formData bool false "是否为计划任务"
+// @Param cronrule 			formData string false "计划任务的规则"
+// @Param croncomment 		formData string false "计划任务的名称"
+// @Success 200 {object} models.StatusResponseData
+// @router /domainscan [post]
+func (c *TaskController) StartDomainScanTask() {
+	c.IsServerAPI = true
+	c.StartDomainScanTaskAction()
+}
+
+// @Title StartPocScanTask
+// @Description 执行一个漏洞验证任务
+// @Param authorization		header string true "token"
+// @Param target 			formData string true "任务目标（ip:port、域名或url），多个目标以换行分开"
+// @Param xrayverify 		formData bool false "是否执行xray验证"
+// @Param xray_poc_file 	formData string false "xray使用的pocfile，格式为：poc类型|poc文件名；poc类型为default或custom"
+// @Param nucleiverify 		formData bool false "是否执行nuclei验证"
+// @Param nuclei_poc_file 	formData string false "nuclei使用的pocfile"
+// @Param gobyverify 		formData bool false "是否执行goby验证"
+// @Param load_opened_port 	formData bool false "是否加载已开放的端口"
+// @Param proxy 			formData bool false "是否使用代理"
+// @Param taskcron 			formData bool false "是否为计划任务"
+// @Param cronrule 			formData string false "计划任务的规则"
+// @Param croncomment 		formData string false "计划任务的名称"
+// @Success 200 {object} models.StatusResponseData
+// @router /pocscan [post]
+func (c *TaskController) StartPocScanTask() {
+	c.IsServerAPI = true
+	c.StartPocScanTaskAction()
+}
+
+// @Title StartWorkflowTask
+// @Description 执行一个工作流任务
+// @Param authorization		header string true "token"
+// @Param workflow_id 		formData int true "工作流ID"
+// @Param target 			formData string true "任务目标(ip、ip/掩码或域名），多个目标以换行分开"
+// @Param port 				formData string false "端口扫描的端口，为空则使用默认配置"
+// @Param org_id 			formData int false "关联的组机构"
+// @Param proxy 			formData bool false "是否使用代理"
+// @Param taskcron 			formData bool false "是否为计划任务"
+// @Param cronrule 			formData string false "计划任务的规则"
+// @Param croncomment 		formData string false "计划任务的名称"
+// @Success 200 {object} models.StatusResponseData
+// @router /workflow [post]
+func (c *TaskController) StartWorkflowTask() {
+	c.IsServerAPI = true
+	c.StartWorkflowTaskAction()
+}
+
 /*
 type XScanRequestParam struct {
 	XScanType       string `form:"xscan_type"`
@@ -195,6 +337,7 @@ type XScanRequestParam struct {
 // @Param xraypocfile 	formData string false "xraypoc使用的pocfile，格式为\"poc类型|poc文件名\"；poc类型为default或custom，poc文件名可为空（全部poc）或xray支持的模糊匹配方式"
 // @Param nucleipoc 	formData bool false "是否要执行nuclei扫描"
 // @Param nucleipocfile formData string false "nucleipoc使用的pocfile"
+// @Param dirscan 		formData bool false "是否要对指纹识别获取的URL执行目录扫描"
 // @Param taskcron 		formData bool false "是否为计划任务"
 // @Param cronrule 		formData string false "计划任务的规则"
 // @Param croncomment 	formData string false "计划任务的名称"
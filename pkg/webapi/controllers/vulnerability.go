@@ -15,6 +15,10 @@ type VulController struct {
 // @Param vul_target 		formData string false "漏洞目标"
 // @Param vul_poc_file 		formData string false "漏洞的poc"
 // @Param date_delta 		formData int false "时间间隔"
+// @Param vul_status 		formData string false "漏洞状态(new、confirmed、false-positive、fixed、risk-accepted）"
+// @Param vul_severity 		formData string false "漏洞严重程度(critical、high、medium、low、info）"
+// @Param vul_assignee 		formData string false "漏洞处理人"
+// @Param vul_cve 			formData string false "漏洞的CVE编号"
 // @Success 200 {object} models.VulDataTableResponseData
 // @router /list [post]
 func (c *VulController) List() {
@@ -44,6 +48,44 @@ func (c *VulController) DeleteVul() {
 	c.DeleteAction()
 }
 
+// @Title UpdateStatus
+// @Description 变更漏洞的状态
+// @Param authorization	header string true "token"
+// @Param id 			formData int true "id"
+// @Param status 		formData string true "变更后的状态(new、confirmed、false-positive、fixed、risk-accepted）"
+// @Param comment 		formData string false "状态变更的说明"
+// @Success 200 {object} models.StatusResponseData
+// @router /status [post]
+func (c *VulController) UpdateStatus() {
+	c.IsServerAPI = true
+	c.UpdateStatusAction()
+}
+
+// @Title Update
+// @Description 更新漏洞的严重程度和处理人
+// @Param authorization	header string true "token"
+// @Param id 			formData int true "id"
+// @Param severity 		formData string false "严重程度(critical、high、medium、low、info），为空则清除"
+// @Param assignee 		formData string false "处理人，为空则清除"
+// @Success 200 {object} models.StatusResponseData
+// @router /update [post]
+func (c *VulController) Update() {
+	c.IsServerAPI = true
+	c.UpdateAction()
+}
+
+// @Title AddComment
+// @Description 增加漏洞的评论
+// @Param authorization	header string true "token"
+// @Param id 			formData int true "id"
+// @Param comment 		formData string true "评论内容"
+// @Success 200 {object} models.StatusResponseData
+// @router /comment [post]
+func (c *VulController) AddComment() {
+	c.IsServerAPI = true
+	c.AddCommentAction()
+}
+
 // @Title LoadXrayPocFile
 // @Description 获取xray的pocfile列表
 // @Param authorization	header string true "token"
@@ -0,0 +1,41 @@
+package controllers
+
+import ctrl "github.com/hanc00l/nemo_go/pkg/web/controllers"
+
+type WorkerController struct {
+	ctrl.WorkerController
+}
+
+// @Title WorkerAliveList
+// @Description 获取worker数据，用于worker列表显示
+// @Param authorization		header string true "token"
+// @Param start 			formData int true "查询起始行数"
+// @Param length 			formData int true "返回指定的数量"
+// @Success 200 {object} models.WorkerDataTableResponseData
+// @router /list [post]
+func (c *WorkerController) WorkerAliveList() {
+	c.IsServerAPI = true
+	c.ListAction()
+}
+
+// @Title ManualReloadWorker
+// @Description 重启worker
+// @Param authorization		header string true "token"
+// @Param worker_name		formData string true "worker name"
+// @Success 200 {object} models.StatusResponseData
+// @router /reload [post]
+func (c *WorkerController) ManualReloadWorker() {
+	c.IsServerAPI = true
+	c.ManualReloadWorkerAction()
+}
+
+// @Title ManualWorkerFileSync
+// @Description 同步worker文件
+// @Param authorization		header string true "token"
+// @Param worker_name		formData string true "worker name"
+// @Success 200 {object} models.StatusResponseData
+// @router /filesync [post]
+func (c *WorkerController) ManualWorkerFileSync() {
+	c.IsServerAPI = true
+	c.ManualWorkerFileSyncAction()
+}
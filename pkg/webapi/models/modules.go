@@ -15,6 +15,7 @@ type IPListData struct {
 	IP             string   `json:"ip"`
 	Location       string   `json:"location"`
 	Port           []string `json:"port"`
+	StalePort      []int    `json:"stale_port"`
 	Title          string   `json:"title"`
 	Banner         string   `json:"banner"`
 	ColorTag       string   `json:"color_tag"`
@@ -50,6 +51,7 @@ type PortAttrInfo struct {
 	CreateTime         string
 	UpdateTime         string
 	TableBackgroundSet bool
+	Stale              bool
 }
 
 // ScreenshotFileInfo screenshot文件
@@ -72,15 +74,34 @@ type PortInfo struct {
 
 // VulnerabilityInfo 漏洞信息
 type VulnerabilityInfo struct {
-	Id         int
-	Target     string
-	Url        string
-	PocFile    string
-	Source     string
-	Extra      string
+	Id           int
+	Target       string
+	Url          string
+	PocFile      string
+	Source       string
+	Extra        string
+	VulName      string
+	CVE          string
+	CWE          string
+	Engines      string
+	Status       string
+	Severity     string
+	Assignee     string
+	NextStatus   []string
+	SeverityList []string
+	Comments     []VulnerabilityCommentInfo
+	CreateTime   string
+	UpdateTime   string
+	Workspace    string
+}
+
+// VulnerabilityCommentInfo 漏洞的一条处理记录
+type VulnerabilityCommentInfo struct {
+	UserName   string
+	OldStatus  string
+	NewStatus  string
+	Content    string
 	CreateTime string
-	UpdateTime string
-	Workspace  string
 }
 
 // IconHashWithFofa iconhash信息
@@ -114,6 +135,28 @@ type IPInfo struct {
 	Workspace     string
 	WorkspaceGUID string
 	PinIndex      string
+	Timeline      []AssetChangeInfo
+	Certificate   []CertificateHostInfo
+}
+
+// AssetChangeInfo 资产的一条变更记录
+type AssetChangeInfo struct {
+	Id         int    `json:"id"`
+	Port       int    `json:"port"`
+	ChangeType string `json:"changeType"`
+	Source     string `json:"source"`
+	Tag        string `json:"tag"`
+	Content    string `json:"content"`
+	OldContent string `json:"oldContent"`
+	TaskId     string `json:"taskId"`
+	CreateTime string `json:"createTime"`
+}
+
+// AssetTimelineData 资产的变更时间线
+type AssetTimelineData struct {
+	Asset    string            `json:"asset"`
+	Total    int               `json:"total"`
+	Timeline []AssetChangeInfo `json:"timeline"`
 }
 
 // TaskListData 任务的列表显示数据
@@ -146,7 +189,7 @@ type TaskDataTableResponseData struct {
 type TaskCronListData struct {
 	Id          int    `json:"id"`
 	Index       int    `json:"index"`
-	TaskId      string `json:"task_id""`
+	TaskId      string `json:"task_id"`
 	TaskName    string `json:"task_name"`
 	Status      string `json:"status"`
 	KwArgs      string `json:"kwargs"`
@@ -215,6 +258,7 @@ type DomainListData struct {
 	WorkspaceId    int      `json:"workspace"`
 	WorkspaceGUID  string   `json:"workspace_guid"`
 	PinIndex       int      `json:"pinindex"`
+	Stale          bool     `json:"stale"`
 }
 
 // DomainDataTableResponseData 域名资产的列表返回数据
@@ -250,6 +294,9 @@ type DomainInfo struct {
 	Workspace     string
 	WorkspaceGUID string
 	PinIndex      string
+	Timeline      []AssetChangeInfo
+	Certificate   []CertificateHostInfo
+	DNSRecord     []DomainAttrInfo
 }
 
 // DomainAttrInfo domain属性
@@ -270,6 +317,12 @@ type VulnerabilityData struct {
 	Url         string `json:"url"`
 	PocFile     string `json:"poc_file"`
 	Source      string `json:"source"`
+	VulName     string `json:"vul_name"`
+	CVE         string `json:"cve"`
+	Engines     string `json:"engines"`
+	Status      string `json:"status"`
+	Severity    string `json:"severity"`
+	Assignee    string `json:"assignee"`
 	CreateTime  string `json:"create_datetime"`
 	UpdateTime  string `json:"update_datetime"`
 	WorkspaceId int    `json:"workspace"`
@@ -283,6 +336,64 @@ type VulDataTableResponseData struct {
 	Data            []VulnerabilityData `json:"data"`
 }
 
+// CertificateData 证书列表的数据
+type CertificateData struct {
+	Id          int    `json:"id"`
+	Index       int    `json:"index"`
+	SubjectCN   string `json:"subject_cn"`
+	SubjectOrg  string `json:"subject_org"`
+	Issuer      string `json:"issuer"`
+	SAN         string `json:"san"`
+	NotAfter    string `json:"not_after"`
+	ExpireDays  int    `json:"expire_days"`
+	HostCount   int    `json:"host_count"`
+	UpdateTime  string `json:"update_datetime"`
+	WorkspaceId int    `json:"workspace"`
+}
+
+// CertificateDataTableResponseData DataTable列表的返回数据
+type CertificateDataTableResponseData struct {
+	Draw            int               `json:"draw"`
+	RecordsTotal    int               `json:"recordsTotal"`
+	RecordsFiltered int               `json:"recordsFiltered"`
+	Data            []CertificateData `json:"data"`
+}
+
+// CertificateInfo 证书的详情
+type CertificateInfo struct {
+	Id          int
+	Fingerprint string
+	Serial      string
+	SubjectCN   string
+	SubjectDN   string
+	SubjectOrg  string
+	IssuerCN    string
+	IssuerDN    string
+	IssuerOrg   string
+	SAN         []string
+	NotBefore   string
+	NotAfter    string
+	ExpireDays  int
+	Hosts       []CertificateHostInfo
+	CreateTime  string
+	UpdateTime  string
+	Workspace   int
+}
+
+// CertificateHostInfo 使用证书的主机，或主机使用的证书
+type CertificateHostInfo struct {
+	CertId     int
+	HostType   string
+	Host       string
+	Port       int
+	SubjectCN  string
+	Issuer     string
+	NotAfter   string
+	ExpireDays int
+	HostCount  int
+	UpdateTime string
+}
+
 type PocFileList []string
 
 type OrganizationData struct {
@@ -320,6 +431,7 @@ type UserData struct {
 	UserRole        string `json:"user_role" form:"user_role"`
 	State           string `json:"state" form:"state"`
 	SortOrder       int    `json:"sort_order" form:"sort_order"`
+	Locked          bool   `json:"locked" form:"-"`
 	CreateDatetime  string `json:"create_time" form:"-"`
 	UpdateDatetime  string `json:"update_time" form:"-"`
 }
@@ -334,6 +446,27 @@ type UserDataTableResponseData struct {
 
 type UserWorkspaceData []WorkspaceInfoData
 
+type AccessTokenData struct {
+	Id               int    `json:"id" form:"id"`
+	Index            int    `json:"index" form:"-"`
+	TokenName        string `json:"name" form:"name"`
+	TokenPrefix      string `json:"prefix" form:"-"`
+	Workspace        string `json:"workspace" form:"workspace"`
+	State            string `json:"state" form:"-"`
+	ExpireDatetime   string `json:"expire_time" form:"-"`
+	LastUsedDatetime string `json:"last_used_time" form:"-"`
+	LastUsedIP       string `json:"last_used_ip" form:"-"`
+	CreateDatetime   string `json:"create_time" form:"-"`
+}
+
+// AccessTokenDataTableResponseData DataTable列表的返回数据
+type AccessTokenDataTableResponseData struct {
+	Draw            int               `json:"draw"`
+	RecordsTotal    int               `json:"recordsTotal"`
+	RecordsFiltered int               `json:"recordsFiltered"`
+	Data            []AccessTokenData `json:"data"`
+}
+
 type WorkspaceData struct {
 	Id                   int    `json:"id" form:"id"`
 	Index                int    `json:"index" form:"-"`
@@ -382,6 +515,8 @@ type DefaultConfigData struct {
 	IsPortscan         bool   `json:"portscan"`
 	IsWhois            bool   `json:"whois"`
 	IsICP              bool   `json:"icp"`
+	IsDNSRecord        bool   `json:"dnsRecord"`
+	IsDNSCheck         bool   `json:"dnsCheck"`
 	// fingerprint
 	IsHttpx          bool `json:"httpx"`
 	IsScreenshot     bool `json:"screenshot"`
@@ -409,12 +544,25 @@ type DashboardStatisticData struct {
 type WorkerStatusData struct {
 	Index                    int    `json:"index"`
 	WorkName                 string `json:"worker_name"`
+	WorkerTopic              string `json:"worker_topic"`
 	CreateTime               string `json:"create_time"`
 	UpdateTime               string `json:"update_time"`
 	TaskExecutedNumber       int    `json:"task_number"`
+	TaskStartedNumber        int    `json:"started_number"`
+	CPULoad                  string `json:"cpu_load"`
+	MemUsage                 string `json:"mem_used"`
 	EnableManualReloadFlag   bool   `json:"enable_manual_reload_flag"`
 	EnableManualFileSyncFlag bool   `json:"enable_manual_file_sync_flag"`
 	HeartColor               string `json:"heart_color"`
+	IsDaemonProcess          bool   `json:"daemon_process"`
+}
+
+// WorkerDataTableResponseData DataTable列表的返回数据
+type WorkerDataTableResponseData struct {
+	Draw            int                `json:"draw"`
+	RecordsTotal    int                `json:"recordsTotal"`
+	RecordsFiltered int                `json:"recordsFiltered"`
+	Data            []WorkerStatusData `json:"data"`
 }
 
 type TaskInfoData struct {
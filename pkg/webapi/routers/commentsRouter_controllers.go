@@ -7,55 +7,91 @@ import (
 
 func init() {
 
-    beego.GlobalControllerRouter["github.com/hanc00l/nemo_go/pkg/webapi/controllers:ConfigController"] = append(beego.GlobalControllerRouter["github.com/hanc00l/nemo_go/pkg/webapi/controllers:ConfigController"],
+    beego.GlobalControllerRouter["github.com/hanc00l/nemo_go/pkg/webapi/controllers:AccessTokenController"] = append(beego.GlobalControllerRouter["github.com/hanc00l/nemo_go/pkg/webapi/controllers:AccessTokenController"],
         beego.ControllerComments{
-            Method: "ChangePassword",
-            Router: `/changepass`,
+            Method: "Add",
+            Router: `/add`,
             AllowHTTPMethods: []string{"post"},
             MethodParams: param.Make(),
             Filters: nil,
             Params: nil})
 
-    beego.GlobalControllerRouter["github.com/hanc00l/nemo_go/pkg/webapi/controllers:ConfigController"] = append(beego.GlobalControllerRouter["github.com/hanc00l/nemo_go/pkg/webapi/controllers:ConfigController"],
+    beego.GlobalControllerRouter["github.com/hanc00l/nemo_go/pkg/webapi/controllers:AccessTokenController"] = append(beego.GlobalControllerRouter["github.com/hanc00l/nemo_go/pkg/webapi/controllers:AccessTokenController"],
         beego.ControllerComments{
-            Method: "LoadDefaultConfig",
-            Router: `/load-default`,
+            Method: "Delete",
+            Router: `/delete`,
             AllowHTTPMethods: []string{"post"},
             MethodParams: param.Make(),
             Filters: nil,
             Params: nil})
 
-    beego.GlobalControllerRouter["github.com/hanc00l/nemo_go/pkg/webapi/controllers:ConfigController"] = append(beego.GlobalControllerRouter["github.com/hanc00l/nemo_go/pkg/webapi/controllers:ConfigController"],
+    beego.GlobalControllerRouter["github.com/hanc00l/nemo_go/pkg/webapi/controllers:AccessTokenController"] = append(beego.GlobalControllerRouter["github.com/hanc00l/nemo_go/pkg/webapi/controllers:AccessTokenController"],
         beego.ControllerComments{
-            Method: "SaveDefaultConfig",
-            Router: `/save-default`,
+            Method: "List",
+            Router: `/list`,
             AllowHTTPMethods: []string{"post"},
             MethodParams: param.Make(),
             Filters: nil,
             Params: nil})
 
-    beego.GlobalControllerRouter["github.com/hanc00l/nemo_go/pkg/webapi/controllers:DashboardController"] = append(beego.GlobalControllerRouter["github.com/hanc00l/nemo_go/pkg/webapi/controllers:DashboardController"],
+    beego.GlobalControllerRouter["github.com/hanc00l/nemo_go/pkg/webapi/controllers:AccessTokenController"] = append(beego.GlobalControllerRouter["github.com/hanc00l/nemo_go/pkg/webapi/controllers:AccessTokenController"],
         beego.ControllerComments{
-            Method: "GetStatisticData",
-            Router: `/statistic`,
+            Method: "Revoke",
+            Router: `/revoke`,
             AllowHTTPMethods: []string{"post"},
             MethodParams: param.Make(),
             Filters: nil,
             Params: nil})
 
-    beego.GlobalControllerRouter["github.com/hanc00l/nemo_go/pkg/webapi/controllers:DashboardController"] = append(beego.GlobalControllerRouter["github.com/hanc00l/nemo_go/pkg/webapi/controllers:DashboardController"],
+    beego.GlobalControllerRouter["github.com/hanc00l/nemo_go/pkg/webapi/controllers:CertificateController"] = append(beego.GlobalControllerRouter["github.com/hanc00l/nemo_go/pkg/webapi/controllers:CertificateController"],
         beego.ControllerComments{
-            Method: "GetTaskInfo",
-            Router: `/task`,
+            Method: "DeleteCertificate",
+            Router: `/delete`,
             AllowHTTPMethods: []string{"post"},
             MethodParams: param.Make(),
             Filters: nil,
             Params: nil})
 
-    beego.GlobalControllerRouter["github.com/hanc00l/nemo_go/pkg/webapi/controllers:DashboardController"] = append(beego.GlobalControllerRouter["github.com/hanc00l/nemo_go/pkg/webapi/controllers:DashboardController"],
+    beego.GlobalControllerRouter["github.com/hanc00l/nemo_go/pkg/webapi/controllers:CertificateController"] = append(beego.GlobalControllerRouter["github.com/hanc00l/nemo_go/pkg/webapi/controllers:CertificateController"],
         beego.ControllerComments{
-            Method: "OnlineUserList",
-            Router: `/user`,
+            Method: "Info",
+            Router: `/info`,
+            AllowHTTPMethods: []string{"post"},
+            MethodParams: param.Make(),
+            Filters: nil,
+            Params: nil})
+
+    beego.GlobalControllerRouter["github.com/hanc00l/nemo_go/pkg/webapi/controllers:CertificateController"] = append(beego.GlobalControllerRouter["github.com/hanc00l/nemo_go/pkg/webapi/controllers:CertificateController"],
+        beego.ControllerComments{
+            Method: "List",
+            Router: `/list`,
+            AllowHTTPMethods: []string{"post"},
+            MethodParams: param.Make(),
+            Filters: nil,
+            Params: nil})
+
+    beego.GlobalControllerRouter["github.com/hanc00l/nemo_go/pkg/webapi/controllers:ConfigController"] = append(beego.GlobalControllerRouter["github.com/hanc00l/nemo_go/pkg/webapi/controllers:ConfigController"],
+        beego.ControllerComments{
+            Method: "ChangePassword",
+            Router: `/changepass`,
+            AllowHTTPMethods: []string{"post"},
+            MethodParams: param.Make(),
+            Filters: nil,
+            Params: nil})
+
+    beego.GlobalControllerRouter["github.com/hanc00l/nemo_go/pkg/webapi/controllers:ConfigController"] = append(beego.GlobalControllerRouter["github.com/hanc00l/nemo_go/pkg/webapi/controllers:ConfigController"],
+        beego.ControllerComments{
+            Method: "LoadDefaultConfig",
+            Router: `/load-default`,
+            AllowHTTPMethods: []string{"post"},
+            MethodParams: param.Make(),
+            Filters: nil,
+            Params: nil})
+
+    beego.GlobalControllerRouter["github.com/hanc00l/nemo_go/pkg/webapi/controllers:ConfigController"] = append(beego.GlobalControllerRouter["github.com/hanc00l/nemo_go/pkg/webapi/controllers:ConfigController"],
+        beego.ControllerComments{
+            Method: "SaveDefaultConfig",
+            Router: `/save-default`,
             AllowHTTPMethods: []string{"post"},
             MethodParams: param.Make(),
             Filters: nil,
@@ -63,8 +99,8 @@ func init() {
 
     beego.GlobalControllerRouter["github.com/hanc00l/nemo_go/pkg/webapi/controllers:DashboardController"] = append(beego.GlobalControllerRouter["github.com/hanc00l/nemo_go/pkg/webapi/controllers:DashboardController"],
         beego.ControllerComments{
-            Method: "ManualWorkerFileSync",
-            Router: `/worker/filesync`,
+            Method: "GetStatisticData",
+            Router: `/statistic`,
             AllowHTTPMethods: []string{"post"},
             MethodParams: param.Make(),
             Filters: nil,
@@ -72,8 +108,8 @@ func init() {
 
     beego.GlobalControllerRouter["github.com/hanc00l/nemo_go/pkg/webapi/controllers:DashboardController"] = append(beego.GlobalControllerRouter["github.com/hanc00l/nemo_go/pkg/webapi/controllers:DashboardController"],
         beego.ControllerComments{
-            Method: "WorkerAliveList",
-            Router: `/worker/list`,
+            Method: "GetTaskInfo",
+            Router: `/task`,
             AllowHTTPMethods: []string{"post"},
             MethodParams: param.Make(),
             Filters: nil,
@@ -81,8 +117,8 @@ func init() {
 
     beego.GlobalControllerRouter["github.com/hanc00l/nemo_go/pkg/webapi/controllers:DashboardController"] = append(beego.GlobalControllerRouter["github.com/hanc00l/nemo_go/pkg/webapi/controllers:DashboardController"],
         beego.ControllerComments{
-            Method: "ManualReloadWorker",
-            Router: `/worker/reload`,
+            Method: "OnlineUserList",
+            Router: `/user`,
             AllowHTTPMethods: []string{"post"},
             MethodParams: param.Make(),
             Filters: nil,
@@ -178,6 +214,15 @@ func init() {
             Filters: nil,
             Params: nil})
 
+    beego.GlobalControllerRouter["github.com/hanc00l/nemo_go/pkg/webapi/controllers:DomainController"] = append(beego.GlobalControllerRouter["github.com/hanc00l/nemo_go/pkg/webapi/controllers:DomainController"],
+        beego.ControllerComments{
+            Method: "Timeline",
+            Router: `/timeline`,
+            AllowHTTPMethods: []string{"post"},
+            MethodParams: param.Make(),
+            Filters: nil,
+            Params: nil})
+
     beego.GlobalControllerRouter["github.com/hanc00l/nemo_go/pkg/webapi/controllers:IPController"] = append(beego.GlobalControllerRouter["github.com/hanc00l/nemo_go/pkg/webapi/controllers:IPController"],
         beego.ControllerComments{
             Method: "MarkColor",
@@ -268,6 +313,15 @@ func init() {
             Filters: nil,
             Params: nil})
 
+    beego.GlobalControllerRouter["github.com/hanc00l/nemo_go/pkg/webapi/controllers:IPController"] = append(beego.GlobalControllerRouter["github.com/hanc00l/nemo_go/pkg/webapi/controllers:IPController"],
+        beego.ControllerComments{
+            Method: "Timeline",
+            Router: `/timeline`,
+            AllowHTTPMethods: []string{"post"},
+            MethodParams: param.Make(),
+            Filters: nil,
+            Params: nil})
+
     beego.GlobalControllerRouter["github.com/hanc00l/nemo_go/pkg/webapi/controllers:LoginController"] = append(beego.GlobalControllerRouter["github.com/hanc00l/nemo_go/pkg/webapi/controllers:LoginController"],
         beego.ControllerComments{
             Method: "Capture",
@@ -349,6 +403,15 @@ func init() {
             Filters: nil,
             Params: nil})
 
+    beego.GlobalControllerRouter["github.com/hanc00l/nemo_go/pkg/webapi/controllers:TaskController"] = append(beego.GlobalControllerRouter["github.com/hanc00l/nemo_go/pkg/webapi/controllers:TaskController"],
+        beego.ControllerComments{
+            Method: "StartBatchScanTask",
+            Router: `/batchscan`,
+            AllowHTTPMethods: []string{"post"},
+            MethodParams: param.Make(),
+            Filters: nil,
+            Params: nil})
+
     beego.GlobalControllerRouter["github.com/hanc00l/nemo_go/pkg/webapi/controllers:TaskController"] = append(beego.GlobalControllerRouter["github.com/hanc00l/nemo_go/pkg/webapi/controllers:TaskController"],
         beego.ControllerComments{
             Method: "DeleteCronTask",
@@ -403,6 +466,15 @@ func init() {
             Filters: nil,
             Params: nil})
 
+    beego.GlobalControllerRouter["github.com/hanc00l/nemo_go/pkg/webapi/controllers:TaskController"] = append(beego.GlobalControllerRouter["github.com/hanc00l/nemo_go/pkg/webapi/controllers:TaskController"],
+        beego.ControllerComments{
+            Method: "StartDomainScanTask",
+            Router: `/domainscan`,
+            AllowHTTPMethods: []string{"post"},
+            MethodParams: param.Make(),
+            Filters: nil,
+            Params: nil})
+
     beego.GlobalControllerRouter["github.com/hanc00l/nemo_go/pkg/webapi/controllers:TaskController"] = append(beego.GlobalControllerRouter["github.com/hanc00l/nemo_go/pkg/webapi/controllers:TaskController"],
         beego.ControllerComments{
             Method: "DeleteMainTask",
@@ -430,6 +502,24 @@ func init() {
             Filters: nil,
             Params: nil})
 
+    beego.GlobalControllerRouter["github.com/hanc00l/nemo_go/pkg/webapi/controllers:TaskController"] = append(beego.GlobalControllerRouter["github.com/hanc00l/nemo_go/pkg/webapi/controllers:TaskController"],
+        beego.ControllerComments{
+            Method: "StartPocScanTask",
+            Router: `/pocscan`,
+            AllowHTTPMethods: []string{"post"},
+            MethodParams: param.Make(),
+            Filters: nil,
+            Params: nil})
+
+    beego.GlobalControllerRouter["github.com/hanc00l/nemo_go/pkg/webapi/controllers:TaskController"] = append(beego.GlobalControllerRouter["github.com/hanc00l/nemo_go/pkg/webapi/controllers:TaskController"],
+        beego.ControllerComments{
+            Method: "StartPortScanTask",
+            Router: `/portscan`,
+            AllowHTTPMethods: []string{"post"},
+            MethodParams: param.Make(),
+            Filters: nil,
+            Params: nil})
+
     beego.GlobalControllerRouter["github.com/hanc00l/nemo_go/pkg/webapi/controllers:TaskController"] = append(beego.GlobalControllerRouter["github.com/hanc00l/nemo_go/pkg/webapi/controllers:TaskController"],
         beego.ControllerComments{
             Method: "DeleteRunTask",
@@ -457,6 +547,15 @@ func init() {
             Filters: nil,
             Params: nil})
 
+    beego.GlobalControllerRouter["github.com/hanc00l/nemo_go/pkg/webapi/controllers:TaskController"] = append(beego.GlobalControllerRouter["github.com/hanc00l/nemo_go/pkg/webapi/controllers:TaskController"],
+        beego.ControllerComments{
+            Method: "StartWorkflowTask",
+            Router: `/workflow`,
+            AllowHTTPMethods: []string{"post"},
+            MethodParams: param.Make(),
+            Filters: nil,
+            Params: nil})
+
     beego.GlobalControllerRouter["github.com/hanc00l/nemo_go/pkg/webapi/controllers:TaskController"] = append(beego.GlobalControllerRouter["github.com/hanc00l/nemo_go/pkg/webapi/controllers:TaskController"],
         beego.ControllerComments{
             Method: "StartXScanTask",
@@ -538,6 +637,15 @@ func init() {
             Filters: nil,
             Params: nil})
 
+    beego.GlobalControllerRouter["github.com/hanc00l/nemo_go/pkg/webapi/controllers:VulController"] = append(beego.GlobalControllerRouter["github.com/hanc00l/nemo_go/pkg/webapi/controllers:VulController"],
+        beego.ControllerComments{
+            Method: "AddComment",
+            Router: `/comment`,
+            AllowHTTPMethods: []string{"post"},
+            MethodParams: param.Make(),
+            Filters: nil,
+            Params: nil})
+
     beego.GlobalControllerRouter["github.com/hanc00l/nemo_go/pkg/webapi/controllers:VulController"] = append(beego.GlobalControllerRouter["github.com/hanc00l/nemo_go/pkg/webapi/controllers:VulController"],
         beego.ControllerComments{
             Method: "DeleteVul",
@@ -574,6 +682,24 @@ func init() {
             Filters: nil,
             Params: nil})
 
+    beego.GlobalControllerRouter["github.com/hanc00l/nemo_go/pkg/webapi/controllers:VulController"] = append(beego.GlobalControllerRouter["github.com/hanc00l/nemo_go/pkg/webapi/controllers:VulController"],
+        beego.ControllerComments{
+            Method: "UpdateStatus",
+            Router: `/status`,
+            AllowHTTPMethods: []string{"post"},
+            MethodParams: param.Make(),
+            Filters: nil,
+            Params: nil})
+
+    beego.GlobalControllerRouter["github.com/hanc00l/nemo_go/pkg/webapi/controllers:VulController"] = append(beego.GlobalControllerRouter["github.com/hanc00l/nemo_go/pkg/webapi/controllers:VulController"],
+        beego.ControllerComments{
+            Method: "Update",
+            Router: `/update`,
+            AllowHTTPMethods: []string{"post"},
+            MethodParams: param.Make(),
+            Filters: nil,
+            Params: nil})
+
     beego.GlobalControllerRouter["github.com/hanc00l/nemo_go/pkg/webapi/controllers:VulController"] = append(beego.GlobalControllerRouter["github.com/hanc00l/nemo_go/pkg/webapi/controllers:VulController"],
         beego.ControllerComments{
             Method: "LoadXrayPocFile",
@@ -583,6 +709,33 @@ func init() {
             Filters: nil,
             Params: nil})
 
+    beego.GlobalControllerRouter["github.com/hanc00l/nemo_go/pkg/webapi/controllers:WorkerController"] = append(beego.GlobalControllerRouter["github.com/hanc00l/nemo_go/pkg/webapi/controllers:WorkerController"],
+        beego.ControllerComments{
+            Method: "WorkerAliveList",
+            Router: `/list`,
+            AllowHTTPMethods: []string{"post"},
+            MethodParams: param.Make(),
+            Filters: nil,
+            Params: nil})
+
+    beego.GlobalControllerRouter["github.com/hanc00l/nemo_go/pkg/webapi/controllers:WorkerController"] = append(beego.GlobalControllerRouter["github.com/hanc00l/nemo_go/pkg/webapi/controllers:WorkerController"],
+        beego.ControllerComments{
+            Method: "ManualWorkerFileSync",
+            Router: `/filesync`,
+            AllowHTTPMethods: []string{"post"},
+            MethodParams: param.Make(),
+            Filters: nil,
+            Params: nil})
+
+    beego.GlobalControllerRouter["github.com/hanc00l/nemo_go/pkg/webapi/controllers:WorkerController"] = append(beego.GlobalControllerRouter["github.com/hanc00l/nemo_go/pkg/webapi/controllers:WorkerController"],
+        beego.ControllerComments{
+            Method: "ManualReloadWorker",
+            Router: `/reload`,
+            AllowHTTPMethods: []string{"post"},
+            MethodParams: param.Make(),
+            Filters: nil,
+            Params: nil})
+
     beego.GlobalControllerRouter["github.com/hanc00l/nemo_go/pkg/webapi/controllers:WorkspaceController"] = append(beego.GlobalControllerRouter["github.com/hanc00l/nemo_go/pkg/webapi/controllers:WorkspaceController"],
         beego.ControllerComments{
             Method: "ChangeWorkspaceSelect",
@@ -34,6 +34,11 @@ func init() {
 				&controllers.VulController{},
 			),
 		),
+		beego.NSNamespace("/certificate",
+			beego.NSInclude(
+				&controllers.CertificateController{},
+			),
+		),
 		beego.NSNamespace("/org",
 			beego.NSInclude(
 				&controllers.OrganizationController{},
@@ -49,6 +54,11 @@ func init() {
 				&controllers.UserController{},
 			),
 		),
+		beego.NSNamespace("/token",
+			beego.NSInclude(
+				&controllers.AccessTokenController{},
+			),
+		),
 		beego.NSNamespace("/workspace",
 			beego.NSInclude(
 				&controllers.WorkspaceController{},
@@ -63,6 +73,17 @@ func init() {
 			beego.NSInclude(
 				&controllers.DashboardController{},
 			),
+			// 兼容原来的/dashboard/worker接口
+			beego.NSNamespace("/worker",
+				beego.NSInclude(
+					&controllers.WorkerController{},
+				),
+			),
+		),
+		beego.NSNamespace("/worker",
+			beego.NSInclude(
+				&controllers.WorkerController{},
+			),
 		),
 	)
 	beego.AddNamespace(ns)
@@ -0,0 +1,58 @@
+-- MySQL dump 10.13  Distrib 5.7.44, for osx10.19 (x86_64)
+--
+-- Host: 127.0.0.1    Database: nemo
+-- ------------------------------------------------------
+-- Server version	5.7.44
+
+/*!40101 SET @OLD_CHARACTER_SET_CLIENT=@@CHARACTER_SET_CLIENT */;
+/*!40101 SET @OLD_CHARACTER_SET_RESULTS=@@CHARACTER_SET_RESULTS */;
+/*!40101 SET @OLD_COLLATION_CONNECTION=@@COLLATION_CONNECTION */;
+/*!40101 SET NAMES utf8 */;
+/*!40103 SET @OLD_TIME_ZONE=@@TIME_ZONE */;
+/*!40103 SET TIME_ZONE='+00:00' */;
+/*!40014 SET @OLD_UNIQUE_CHECKS=@@UNIQUE_CHECKS, UNIQUE_CHECKS=0 */;
+/*!40014 SET @OLD_FOREIGN_KEY_CHECKS=@@FOREIGN_KEY_CHECKS, FOREIGN_KEY_CHECKS=0 */;
+/*!40101 SET @OLD_SQL_MODE=@@SQL_MODE, SQL_MODE='NO_AUTO_VALUE_ON_ZERO' */;
+/*!40111 SET @OLD_SQL_NOTES=@@SQL_NOTES, SQL_NOTES=0 */;
+
+--
+-- Table structure for table `vulnerability_mapping`
+--
+
+DROP TABLE IF EXISTS `vulnerability_mapping`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `vulnerability_mapping` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `name` varchar(200) NOT NULL,
+  `cve` varchar(200) NOT NULL DEFAULT '',
+  `cwe` varchar(200) NOT NULL DEFAULT '',
+  `severity` varchar(20) NOT NULL DEFAULT '',
+  `pocs` varchar(4000) NOT NULL DEFAULT '',
+  `create_datetime` datetime NOT NULL,
+  `update_datetime` datetime NOT NULL,
+  PRIMARY KEY (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Dumping data for table `vulnerability_mapping`
+--
+
+LOCK TABLES `vulnerability_mapping` WRITE;
+/*!40000 ALTER TABLE `vulnerability_mapping` DISABLE KEYS */;
+INSERT INTO `vulnerability_mapping` VALUES (1,'Apache Log4j2 远程代码执行漏洞','CVE-2021-44228','CWE-502','critical','{"xray":["poc-yaml-log4j-rce"],"nuclei":["CVE-2021-44228"],"goby":["Apache Log4j2 Remote Code Execution Vulnerability (CVE-2021-44228)"]}','2024-06-01 10:00:00','2024-06-01 10:00:00'),(2,'WebLogic XMLDecoder 反序列化漏洞','CVE-2017-10271','CWE-502','critical','{"xray":["poc-yaml-weblogic-cve-2017-10271"],"nuclei":["CVE-2017-10271"],"goby":["Weblogic wls-wsat XMLDecoder deserialization RCE (CVE-2017-10271)"]}','2024-06-01 10:00:00','2024-06-01 10:00:00'),(3,'Spring Framework 远程代码执行漏洞(Spring4Shell)','CVE-2022-22965','CWE-94','critical','{"xray":["poc-yaml-spring-cve-2022-22965"],"nuclei":["CVE-2022-22965"]}','2024-06-01 10:00:00','2024-06-01 10:00:00'),(4,'Apache Struts2 S2-045 远程代码执行漏洞','CVE-2017-5638','CWE-20','critical','{"xray":["poc-yaml-struts2_045"],"nuclei":["CVE-2017-5638"],"goby":["Struts2 S2-045 RCE (CVE-2017-5638)"]}','2024-06-01 10:00:00','2024-06-01 10:00:00'),(5,'Atlassian Confluence OGNL 远程代码执行漏洞','CVE-2022-26134','CWE-917','critical','{"xray":["poc-yaml-confluence-cve-2022-26134"],"nuclei":["CVE-2022-26134"]}','2024-06-01 10:00:00','2024-06-01 10:00:00'),(6,'ThinkPHP 5.0.23 远程代码执行漏洞','','CWE-77','critical','{"xray":["poc-yaml-thinkphp5023-method-rce"],"nuclei":["thinkphp-5023-rce"],"goby":["ThinkPHP 5.0.23 RCE"]}','2024-06-01 10:00:00','2024-06-01 10:00:00'),(7,'ThinkPHP 5 控制器远程代码执行漏洞','','CWE-77','critical','{"xray":["poc-yaml-thinkphp5-controller-rce"],"nuclei":["thinkphp-5022-rce"],"goby":["ThinkPHP 5.0.22/5.1.29 RCE"]}','2024-06-01 10:00:00','2024-06-01 10:00:00'),(8,'DNS域传送漏洞','','CWE-200','high','{"dnscheck":["dns-zone-transfer"]}','2024-06-01 10:00:00','2024-06-01 10:00:00'),(9,'DNS服务器开放递归解析','','CWE-406','medium','{"dnscheck":["dns-open-resolver"]}','2024-06-01 10:00:00','2024-06-01 10:00:00');
+/*!40000 ALTER TABLE `vulnerability_mapping` ENABLE KEYS */;
+UNLOCK TABLES;
+
+/*!40103 SET TIME_ZONE=@OLD_TIME_ZONE */;
+
+/*!40101 SET SQL_MODE=@OLD_SQL_MODE */;
+/*!40014 SET FOREIGN_KEY_CHECKS=@OLD_FOREIGN_KEY_CHECKS */;
+/*!40014 SET UNIQUE_CHECKS=@OLD_UNIQUE_CHECKS */;
+/*!40101 SET CHARACTER_SET_CLIENT=@OLD_CHARACTER_SET_CLIENT */;
+/*!40101 SET CHARACTER_SET_RESULTS=@OLD_CHARACTER_SET_RESULTS */;
+/*!40101 SET COLLATION_CONNECTION=@OLD_COLLATION_CONNECTION */;
+/*!40111 SET SQL_NOTES=@OLD_SQL_NOTES */;
+
+-- Dump completed on 2024-06-01 10:21:35
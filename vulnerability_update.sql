@@ -0,0 +1,57 @@
+-- MySQL dump 10.13  Distrib 5.7.43, for osx10.18 (x86_64)
+--
+-- Host: 127.0.0.1    Database: nemo
+-- ------------------------------------------------------
+-- Server version	5.7.43
+
+/*!40101 SET @OLD_CHARACTER_SET_CLIENT=@@CHARACTER_SET_CLIENT */;
+/*!40101 SET @OLD_CHARACTER_SET_RESULTS=@@CHARACTER_SET_RESULTS */;
+/*!40101 SET @OLD_COLLATION_CONNECTION=@@COLLATION_CONNECTION */;
+/*!40101 SET NAMES utf8 */;
+/*!40103 SET @OLD_TIME_ZONE=@@TIME_ZONE */;
+/*!40103 SET TIME_ZONE='+00:00' */;
+/*!40014 SET @OLD_UNIQUE_CHECKS=@@UNIQUE_CHECKS, UNIQUE_CHECKS=0 */;
+/*!40014 SET @OLD_FOREIGN_KEY_CHECKS=@@FOREIGN_KEY_CHECKS, FOREIGN_KEY_CHECKS=0 */;
+/*!40101 SET @OLD_SQL_MODE=@@SQL_MODE, SQL_MODE='NO_AUTO_VALUE_ON_ZERO' */;
+/*!40111 SET @OLD_SQL_NOTES=@@SQL_NOTES, SQL_NOTES=0 */;
+
+--
+-- Table structure for table `vulnerability`
+--
+
+alter table vulnerability add status varchar(20) not null default 'new' after hash;
+alter table vulnerability add severity varchar(20) not null default '' after status;
+alter table vulnerability add assignee varchar(100) not null default '' after severity;
+
+--
+-- Table structure for table `vulnerability_comment`
+--
+
+DROP TABLE IF EXISTS `vulnerability_comment`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `vulnerability_comment` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `r_id` int(10) unsigned NOT NULL,
+  `user_name` varchar(100) NOT NULL DEFAULT '',
+  `old_status` varchar(20) NOT NULL DEFAULT '',
+  `new_status` varchar(20) NOT NULL DEFAULT '',
+  `content` varchar(4000) NOT NULL DEFAULT '',
+  `create_datetime` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  KEY `fk_vulnerability_comment_r_id` (`r_id`),
+  CONSTRAINT `fk_vulnerability_comment_r_id` FOREIGN KEY (`r_id`) REFERENCES `vulnerability` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+/*!40101 SET character_set_client = @saved_cs_client */;
+/*!40103 SET TIME_ZONE=@OLD_TIME_ZONE */;
+
+/*!40101 SET SQL_MODE=@OLD_SQL_MODE */;
+/*!40014 SET FOREIGN_KEY_CHECKS=@OLD_FOREIGN_KEY_CHECKS */;
+/*!40014 SET UNIQUE_CHECKS=@OLD_UNIQUE_CHECKS */;
+/*!40101 SET CHARACTER_SET_CLIENT=@OLD_CHARACTER_SET_CLIENT */;
+/*!40101 SET CHARACTER_SET_RESULTS=@OLD_CHARACTER_SET_RESULTS */;
+/*!40101 SET COLLATION_CONNECTION=@OLD_COLLATION_CONNECTION */;
+/*!40111 SET SQL_NOTES=@OLD_SQL_NOTES */;
+
+-- Dump completed on 2023-08-19 20:11:18
@@ -0,0 +1,145 @@
+$(function () {
+    $('#token_table').DataTable(
+        {
+            "paging": false,
+            "serverSide": true,
+            "autowidth": false,
+            "sort": false,
+            "dom": '<i><t>',
+            "ajax": {
+                "url": "/access-token-list",
+                "type": "post",
+            },
+            columns: [
+                {data: "index", title: "序号", width: "5%"},
+                {data: "name", title: "令牌名称", width: "12%"},
+                {data: "prefix", title: "令牌", width: "12%"},
+                {data: "workspace", title: "工作空间", width: "15%"},
+                {
+                    data: "state", title: "状态", width: "6%",
+                    "render": function (data, type, row) {
+                        if (data === "enable") return "有效";
+                        if (data === "expired") return "<span class='text-danger'>已过期</span>";
+                        return "<span class='text-muted'>已撤销</span>";
+                    }
+                },
+                {data: "expire_time", title: "过期时间", width: "12%"},
+                {
+                    data: "last_used_time", title: "最近使用", width: "18%",
+                    "render": function (data, type, row) {
+                        if (!data) return "";
+                        return data + "<br>" + row["last_used_ip"];
+                    }
+                },
+                {data: "create_time", title: "创建时间", width: "12%"},
+                {
+                    title: "操作",
+                    "render": function (data, type, row, meta) {
+                        let strButton = "";
+                        if (row["state"] === "enable") {
+                            strButton += "<a class=\"btn btn-sm btn-warning\" href=javascript:revoke_token(\"" + row["id"] + "\") role=\"button\" title=\"Revoke\"><i class=\"fa fa-ban\"></i></a>&nbsp;";
+                        }
+                        strButton += "<a class=\"btn btn-sm btn-danger\" href=javascript:delete_token(\"" + row["id"] + "\") role=\"button\" title=\"Delete\"><i class=\"fa fa-trash\"></i></a>";
+                        return strButton;
+                    }
+                }
+            ]
+        }
+    );//end datatable
+});
+
+//新建令牌窗口
+$("#create_token").click(function () {
+    $('#edit_token').modal('toggle');
+    $('#token_name').val("");
+    $('#token_expire_days').val("90");
+    load_workspace_list();
+});
+
+$("#token_save").click(function () {
+    if (!$('#token_name').val()) {
+        swal('Warning', '令牌名称不能为空', 'error');
+        return;
+    }
+    let workspace = [];
+    $("#token_workspace_list input:checkbox:checked").each(function () {
+        workspace.push($(this).val());
+    });
+    if (workspace.length === 0) {
+        swal('Warning', '请选择授权的工作空间', 'error');
+        return;
+    }
+    $.post("/access-token-add",
+        {
+            "name": $('#token_name').val(),
+            "expire_days": $('#token_expire_days').val(),
+            "workspace": workspace.join(","),
+        }, function (res, e) {
+            if (e === "success" && res['status'] === "success") {
+                $('#edit_token').modal('hide');
+                $('#token_value').val(res['msg']);
+                $('#show_token').modal('show');
+                $('#token_table').DataTable().draw(false);
+            } else {
+                swal('Warning', '生成令牌失败！' + res['msg'], 'error');
+            }
+        });
+});
+
+function load_workspace_list() {
+    $("#token_workspace_list").empty();
+    $.post("/workspace-user-list", function (data) {
+        for (let i = 0; i < data.WorkspaceInfoList.length; i++) {
+            const w = data.WorkspaceInfoList[i];
+            if (w.workspaceId === "0") continue;
+            const checked = w.workspaceId === data.CurrentWorkspace ? " checked" : "";
+            $("#token_workspace_list").append("<div class=\"form-check\"><label class=\"form-check-label\"><input class=\"form-check-input\" type=\"checkbox\" value=\"" + w.workspaceId + "\"" + checked + ">" + w.workspaceName + "</label></div>");
+        }
+    });
+}
+
+function revoke_token(id) {
+    swal({
+            title: "确定要撤销?",
+            text: "撤销后使用该令牌的请求将无法通过验证，请确认！",
+            type: "warning",
+            showCancelButton: true,
+            confirmButtonColor: "#DD6B55",
+            confirmButtonText: "确认撤销",
+            cancelButtonText: "取消",
+            closeOnConfirm: true
+        },
+        function () {
+            $.post("/access-token-revoke",
+                {
+                    "id": id,
+                }, function (data, e) {
+                    if (e === "success") {
+                        $('#token_table').DataTable().draw(false);
+                    }
+                });
+        });
+}
+
+function delete_token(id) {
+    swal({
+            title: "确定要删除?",
+            text: "该操作会删除当前令牌，请确认！",
+            type: "warning",
+            showCancelButton: true,
+            confirmButtonColor: "#DD6B55",
+            confirmButtonText: "确认删除",
+            cancelButtonText: "取消",
+            closeOnConfirm: true
+        },
+        function () {
+            $.post("/access-token-delete",
+                {
+                    "id": id,
+                }, function (data, e) {
+                    if (e === "success") {
+                        $('#token_table').DataTable().draw(false);
+                    }
+                });
+        });
+}
@@ -0,0 +1,210 @@
+$(function () {
+    //$('#btnsiderbar').click();
+    $('#certificate_table').DataTable(
+        {
+            "paging": true,
+            "serverSide": true,
+            "autowidth": false,
+            "sort": false,
+            "pagingType": "full_numbers",//分页样式
+            'iDisplayLength': 50,
+            "dom": '<i><t><"bottom"lp>',
+            "ajax": {
+                "url": "/certificate-list",
+                "type": "post",
+                "data": function (d) {
+                    init_dataTables_defaultParam(d);
+                    return $.extend({}, d, {
+                        "cert_domain": $('#cert_domain').val(),
+                        "cert_issuer": $('#cert_issuer').val(),
+                        "cert_host": $('#cert_host').val(),
+                        "expire_days": $('#expire_days').val() > 0 ? $('#expire_days').val() : 0,
+                        "expired": $('#expire_days').val() < 0,
+                        "date_delta": $('#date_delta').val()
+                    });
+                }
+            },
+            columns: [
+                {
+                    data: "id",
+                    width: "5%",
+                    className: "dt-body-center",
+                    title: '<input  type="checkbox" class="checkall" />',
+                    "render": function (data, type, row) {
+                        const strData = '<input type="checkbox" class="checkchild" value="' + row['id'] + '"/>';
+                        return strData;
+                    }
+                },
+                {
+                    data: "index",
+                    title: "序号",
+                    width: "5%"
+                },
+                {
+                    data: "subject_cn",
+                    title: "Subject CN",
+                    width: "15%",
+                    render: function (data, type, row, meta) {
+                        let strData = '<a href="/certificate-info?id=' + row['id'] + '" target="_blank">' + (data ? data : '-') + '</a>';
+                        if (row['subject_org']) strData += '<br>' + row['subject_org'];
+                        return strData;
+                    }
+                },
+                {
+                    data: "san",
+                    title: "SAN",
+                    width: "25%",
+                    render: function (data, type, row, meta) {
+                        if (!data) return '';
+                        const sans = data.split('\n');
+                        let strData = sans.slice(0, 5).join('<br>');
+                        if (sans.length > 5) strData += '<br>...(' + sans.length + ')';
+                        return strData;
+                    }
+                },
+                {data: 'issuer', title: '颁发者', width: '15%'},
+                {
+                    data: 'not_after', title: '过期时间', width: '12%',
+                    render: function (data, type, row, meta) {
+                        if (!data) return '';
+                        if (row['expire_days'] < 0) return '<span class="badge badge-danger">' + data + '</span>';
+                        if (row['expire_days'] < 30) return '<span class="badge badge-warning">' + data + '</span>';
+                        return data;
+                    }
+                },
+                {
+                    data: 'host_count', title: '主机数', width: '5%',
+                    render: function (data, type, row, meta) {
+                        return '<a href="/certificate-info?id=' + row['id'] + '" target="_blank">' + data + '</a>';
+                    }
+                },
+                {
+                    data: 'update_datetime', title: '更新时间', width: '10%'
+                },
+                {
+                    title: "操作",
+                    width: "8%",
+                    "render": function (data, type, row, meta) {
+                        const strDelete = "<a class=\"btn btn-sm btn-danger\" href=javascript:delete_certificate(\"" + row["id"] + "\") role=\"button\" title=\"Delete\"><i class=\"fa fa-trash-o\"></i></a>";
+                        return strDelete;
+                    }
+                }
+            ],
+            infoCallback: function (settings, start, end, max, total, pre) {
+                return "共<b>" + total + "</b>条记录，当前显示" + start + "到" + end + "记录";
+            },
+            drawCallback: function (setting) {
+                var _this = $(this);
+                var tableId = _this.attr('id');
+                var pageDiv = $('#' + tableId + '_paginate');
+                pageDiv.append(
+                    '<i class="fa fa-arrow-circle-o-right fa-lg" aria-hidden="true"></i><input id="' + tableId + '_gotoPage" type="text" style="height:20px;line-height:20px;width:40px;"/>' +
+                    '<a class="paginate_button" aria-controls="' + tableId + '" tabindex="0" id="' + tableId + '_goto">Go</a>')
+                $('#' + tableId + '_goto').click(function (obj) {
+                    var page = $('#' + tableId + '_gotoPage').val();
+                    var thisDataTable = $('#' + tableId).DataTable();
+                    var pageInfo = thisDataTable.page.info();
+                    if (isNaN(page)) {
+                        $('#' + tableId + '_gotoPage').val('');
+                        return;
+                    } else {
+                        var maxPage = pageInfo.pages;
+                        var page = Number(page) - 1;
+                        if (page < 0) {
+                            page = 0;
+                        } else if (page >= maxPage) {
+                            page = maxPage - 1;
+                        }
+                        $('#' + tableId + '_gotoPage').val(page + 1);
+                        thisDataTable.page(page).draw('page');
+                    }
+                })
+            }
+        }
+    );//end datatable
+    $(".checkall").click(function () {
+        var check = $(this).prop("checked");
+        $(".checkchild").prop("checked", check);
+    });
+    $('[data-toggle="tooltip"]').tooltip();
+    //搜索
+    $("#search").click(function () {
+        $("#certificate_table").DataTable().draw(true);
+    });
+    //批量删除
+    $("#batch_delete").click(function () {
+        batch_delete('#certificate_table', '/certificate-delete');
+    });
+});
+
+/**
+ * 移除 dataTables默认参数，并设置分页值
+ * @param param
+ */
+function init_dataTables_defaultParam(param) {
+    for (var key in param) {
+        if (key.indexOf("columns") == 0 || key.indexOf("order") == 0 || key.indexOf("search") == 0) { //以columns开头的参数删除
+            delete param[key];
+        }
+    }
+    param.pageSize = param.length;
+    param.pageNum = (param.start / param.length) + 1;
+}
+
+
+/**
+ * 删除一个证书
+ * @param id
+ */
+function delete_certificate(id) {
+    swal({
+            title: "确定要删除?",
+            text: "删除当前证书！",
+            type: "warning",
+            showCancelButton: true,
+            confirmButtonColor: "#DD6B55",
+            confirmButtonText: "确认删除",
+            cancelButtonText: "取消",
+            closeOnConfirm: true
+        },
+        function () {
+            $.post("/certificate-delete",
+                {
+                    "id": id,
+                }, function (data, e) {
+                    if (e === "success") {
+                        $('#certificate_table').DataTable().draw(false);
+                    }
+                });
+        });
+}
+
+
+//批量删除
+function batch_delete(dataTableId, url) {
+    swal({
+            title: "确定要批量删除选定的目标?",
+            text: "该操作会删除所有选定目标的所有信息！",
+            type: "warning",
+            showCancelButton: true,
+            confirmButtonColor: "#DD6B55",
+            confirmButtonText: "确认删除",
+            cancelButtonText: "取消",
+            closeOnConfirm: true
+        },
+        function () {
+            $(dataTableId).DataTable().$('input[type=checkbox]:checked').each(function (i) {
+                let id = $(this).val().split("|")[0];
+                $.ajax({
+                    type: 'post',
+                    async: false,
+                    url: url + '?id=' + id,
+                    success: function (data) {
+                    },
+                    error: function (xhr, type) {
+                    }
+                });
+            });
+            $(dataTableId).DataTable().draw(false);
+        });
+}
@@ -11,6 +11,7 @@ $(function () {
             {
                 "portslicenumber": $('#input_portslicenumber').val(),
                 "ipslicenumber": $('#input_ipslicenumber').val(),
+                "stalemisscount": $('#input_stalemisscount').val(),
             }, function (data, e) {
                 if (e === "success" && data['status'] === 'success') {
                     swal({
@@ -33,6 +34,38 @@ $(function () {
                 "token_serverchan": $('#input_serverchan').val(),
                 "token_dingtalk": $('#input_dingtalk').val(),
                 "token_feishu": $('#input_feishu').val(),
+                "token_wecom": $('#input_wecom').val(),
+                "url_slack": $('#input_slack_url').val(),
+                "token_telegram": $('#input_telegram').val(),
+                "chatid_telegram": $('#input_telegram_chatid').val(),
+                "url_webhook": $('#input_webhook_url').val(),
+                "secret_webhook": $('#input_webhook_secret').val(),
+                "smtp_host": $('#input_smtp_host').val(),
+                "smtp_port": $('#input_smtp_port').val(),
+                "smtp_username": $('#input_smtp_username').val(),
+                "smtp_password": $('#input_smtp_password').val(),
+                "smtp_from": $('#input_smtp_from').val(),
+                "smtp_recipients": $('#input_smtp_recipients').val(),
+            }, function (data, e) {
+                if (e === "success" && data['status'] === 'success') {
+                    swal({
+                        title: "保存成功！",
+                        text: "",
+                        type: "success",
+                        confirmButtonText: "确定",
+                        confirmButtonColor: "#41b883",
+                        closeOnConfirm: true,
+                        timer: 3000
+                    });
+                } else {
+                    swal('Warning', data['msg'], 'error');
+                }
+            });
+    });
+    $("#buttonSaveNotifyRule").click(function () {
+        $.post("/config-save-notify-rule",
+            {
+                "notifyrule": $('#text_notify_rule').val(),
             }, function (data, e) {
                 if (e === "success" && data['status'] === 'success') {
                     swal({
@@ -199,11 +232,25 @@ function load_config_server() {
     $.post("/config-list-server", function (data) {
         $('#input_ipslicenumber').val(data['ipslicenumber']);
         $('#input_portslicenumber').val(data['portslicenumber']);
+        $('#input_stalemisscount').val(data['stalemisscount']);
         $('#nemo_version').html(data['version']);
 
         $('#input_serverchan').val(data['serverchan']);
         $('#input_dingtalk').val(data['dingtalk']);
         $('#input_feishu').val(data['feishu']);
+        $('#input_wecom').val(data['wecom']);
+        $('#input_slack_url').val(data['slackurl']);
+        $('#input_telegram').val(data['telegram']);
+        $('#input_telegram_chatid').val(data['telegramchatid']);
+        $('#input_webhook_url').val(data['webhookurl']);
+        $('#input_webhook_secret').val(data['webhooksecret']);
+        $('#input_smtp_host').val(data['smtphost']);
+        $('#input_smtp_port').val(data['smtpport']);
+        $('#input_smtp_username').val(data['smtpusername']);
+        $('#input_smtp_password').val(data['smtppassword']);
+        $('#input_smtp_from').val(data['smtpfrom']);
+        $('#input_smtp_recipients').val(data['smtprecipients']);
+        $('#text_notify_rule').val(data['notifyrule']);
 
         $('#input_feishu_appid').val(data['feishuappid']);
         $('#input_feishu_secret').val(data['feishusecret']);
@@ -58,9 +58,15 @@ $(function () {
                 "fofatoken": $('#input_fofa_token').val(),
                 "huntertoken": $('#input_hunter_token').val(),
                 "quaketoken": $('#input_quake_token').val(),
+                "shodantoken": $('#input_shodan_token').val(),
+                "censystoken": $('#input_censys_token').val(),
+                "zoomeyetoken": $('#input_zoomeye_token').val(),
+                "netlastoken": $('#input_netlas_token').val(),
                 "chinaztoken": $('#input_chinaz_token').val(),
                 "pagesize": $('#input_pagesize').val(),
                 "limitcount": $('#input_limitcount').val(),
+                "pointbudget": $('#input_pointbudget').val(),
+                "cachettl": $('#input_cachettl').val(),
             }, function (data, e) {
                 if (e === "success" && data['status'] === 'success') {
                     swal({
@@ -93,6 +99,50 @@ $(function () {
             }
         });
     });
+    $("#buttonLoadAPIQuota").click(function () {
+        $.post("/config-api-quota", {}, function (data, e) {
+            if (e === "success" && data['status'] === 'success') {
+                swal({
+                    title: "剩余额度",
+                    text: data['msg'],
+                    type: "success",
+                    confirmButtonText: "确定",
+                    confirmButtonColor: "#41b883",
+                    closeOnConfirm: true,
+                });
+            } else {
+                swal('Warning', data['msg'], 'error');
+            }
+        });
+    });
+    $("#buttonClearAPICache").click(function () {
+        swal({
+                title: "确定要清除在线API查询结果的缓存?",
+                type: "warning",
+                showCancelButton: true,
+                confirmButtonText: "确认",
+                cancelButtonText: "取消",
+                confirmButtonColor: "#DD6B55",
+                closeOnConfirm: false
+            },
+            function () {
+                $.post("/config-clear-api-cache", {}, function (data, e) {
+                    if (e === "success" && data['status'] === 'success') {
+                        swal({
+                            title: "清除成功！",
+                            text: data['msg'],
+                            type: "success",
+                            confirmButtonText: "确定",
+                            confirmButtonColor: "#41b883",
+                            closeOnConfirm: true,
+                            timer: 3000
+                        });
+                    } else {
+                        swal('Warning', data['msg'], 'error');
+                    }
+                });
+            });
+    });
     $("#buttonSaveDomainscan").click(function () {
         $.post("/config-save-domainscan",
             {
@@ -101,6 +151,8 @@ $(function () {
                 "subdomaincrawler": $('#checkbox_subdomaincrawler').is(":checked"),
                 "icp": $('#checkbox_icp').is(":checked"),
                 "whois": $('#checkbox_whois').is(":checked"),
+                "dnsrecord": $('#checkbox_dnsrecord').is(":checked"),
+                "dnscheck": $('#checkbox_dnscheck').is(":checked"),
                 "portscan": $('#checkbox_portscan').is(":checked"),
                 "ignorecdn": $('#checkbox_ignorecdn').is(":checked"),
                 "ignoreoutofchina": $('#checkbox_ignoreoutofchina').is(":checked"),
@@ -182,6 +234,10 @@ function load_config_worker() {
         $('#input_fofa_token').val(data['fofatoken']);
         $('#input_hunter_token').val(data['huntertoken']);
         $('#input_quake_token').val(data['quaketoken']);
+        $('#input_shodan_token').val(data['shodantoken']);
+        $('#input_censys_token').val(data['censystoken']);
+        $('#input_zoomeye_token').val(data['zoomeyetoken']);
+        $('#input_netlas_token').val(data['netlastoken']);
         $('#input_chinaz_token').val(data['chinaztoken']);
 
         $('#checkbox_subfinder').prop("checked", data['subfinder']);
@@ -189,6 +245,8 @@ function load_config_worker() {
         $('#checkbox_subdomaincrawler').prop("checked", data['subdomaincrawler']);
         $('#checkbox_icp').prop("checked", data['icp']);
         $('#checkbox_whois').prop("checked", data['whois']);
+        $('#checkbox_dnsrecord').prop("checked", data['dnsrecord']);
+        $('#checkbox_dnscheck').prop("checked", data['dnscheck']);
         $('#checkbox_ignorecdn').prop("checked", data['ignorecdn']);
         $('#checkbox_ignoreoutofchina').prop("checked", data['ignoreoutofchina']);
         $('#checkbox_portscan').prop("checked", data['portscan']);
@@ -199,6 +257,8 @@ function load_config_worker() {
         $('#checkbox_quake').prop("checked", data['quake']);
         $('#input_pagesize').val(data['pagesize']);
         $('#input_limitcount').val(data['limitcount']);
+        $('#input_pointbudget').val(data['pointbudget']);
+        $('#input_cachettl').val(data['cachettl']);
 
         $('#input_maxportperip').val(data['maxportperip']);
         $('#input_maxdomainperip').val(data['maxdomainperip']);
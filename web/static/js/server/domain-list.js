@@ -76,6 +76,7 @@ $(function () {
                     "target": target,
                     'org_id': $('#select_org_id_task').val(),
                     'subdomainbrute': $('#checkbox_subdomainbrute').is(":checked"),
+                    'subdomainpermute': $('#checkbox_subdomainpermute').is(":checked"),
                     'fld_domain': $('#checkbox_fld_domain').is(":checked"),
                     'portscan': $('#checkbox_portscan').is(":checked"),
                     'fofasearch': $('#checkbox_fofasearch').is(":checked"),
@@ -209,14 +210,15 @@ $(function () {
         formData.append("nucleipoc", $('#checkbox_nucleipoc_xscan').is(":checked"));
         formData.append("nucleipocfile", $('#input_nuclei_poc_file_xscan').val());
         formData.append("gobypoc", $('#checkbox_gobypoc_xscan').is(":checked"));
+        formData.append("dirscan", $('#checkbox_dirscan_xscan').is(":checked"));
 
         formData.append("taskcron", $('#checkbox_cron_task_xscan').is(":checked"));
         formData.append("cronrule", cron_rule);
         formData.append("croncomment", $('#input_cron_comment_xscan').val());
         formData.append("proxy", $('#checkbox_proxy_xscan').is(":checked"));
 
-        if ((formData.get("xraypoc") === "true" || formData.get("nucleipoc") === "true" || formData.get("gobypoc") === "true") && formData.get("fingerprint") === "false") {
-            swal('Warning', '漏洞扫描需要开启指纹扫描步骤选项', 'error');
+        if ((formData.get("xraypoc") === "true" || formData.get("nucleipoc") === "true" || formData.get("gobypoc") === "true" || formData.get("dirscan") === "true") && formData.get("fingerprint") === "false") {
+            swal('Warning', '漏洞扫描及目录扫描需要开启指纹扫描步骤选项', 'error');
             return;
         }
 
@@ -332,6 +334,8 @@ $(function () {
                         'select_order_by_date': $('#checkbox_select_order_by_date').is(":checked"),
                         "domain_http": $('#http_content').val(),
                         "wiki_docs": $('#wiki_docs_content').val(),
+                        'select_stale': $('#checkbox_select_stale').is(":checked"),
+                        "last_seen_before": $('#last_seen_before').val(),
                     });
                 }
             },
@@ -372,6 +376,9 @@ $(function () {
                         if (row['wiki_docs']) {
                             strData += '&nbsp;<i class="fa fa-archive" style="color: darkorange" title="' + html2Escape(row['wiki_docs']) + '"></i>';
                         }
+                        if (row['stale']) {
+                            strData += '&nbsp;<span class="badge badge-secondary" title="连续多次扫描未发现">stale</span>';
+                        }
                         if (row['vulnerability']) {
                             strData += '&nbsp;<span class="badge badge-danger" data-toggle="tooltip" data-html="true" title="' + html2Escape(row['vulnerability']) + '"><i class="fa fa-bolt"></span>';
                         }
@@ -532,6 +539,8 @@ function get_export_options() {
     url += "&content=" + encodeURI($('#content').val());
     url += "&create_date_delta=" + encodeURI($('#create_date_delta').val());
     url += "&domain_http=" + encodeURI($('#http_content').val());
+    url += "&select_stale=" + encodeURI($('#checkbox_select_stale').is(":checked"));
+    url += "&last_seen_before=" + encodeURI($('#last_seen_before').val());
 
     return url;
 }
@@ -311,14 +311,15 @@ $(function () {
         formData.append("nucleipoc", $('#checkbox_nucleipoc_xscan').is(":checked"));
         formData.append("nucleipocfile", $('#input_nuclei_poc_file_xscan').val());
         formData.append("gobypoc", $('#checkbox_gobypoc_xscan').is(":checked"));
+        formData.append("dirscan", $('#checkbox_dirscan_xscan').is(":checked"));
 
         formData.append("taskcron", $('#checkbox_cron_task_xscan').is(":checked"));
         formData.append("cronrule", cron_rule);
         formData.append("croncomment", $('#input_cron_comment_xscan').val());
         formData.append("proxy", $('#checkbox_proxy_xscan').is(":checked"));
 
-        if ((formData.get("nucleipoc") === "true" || formData.get("gobypoc") === "true" || formData.get("xraypoc") === "true") && formData.get("fingerprint") === "false") {
-            swal('Warning', '漏洞扫描需要开启指纹扫描步骤选项', 'error');
+        if ((formData.get("nucleipoc") === "true" || formData.get("gobypoc") === "true" || formData.get("xraypoc") === "true" || formData.get("dirscan") === "true") && formData.get("fingerprint") === "false") {
+            swal('Warning', '漏洞扫描及目录扫描需要开启指纹扫描步骤选项', 'error');
             return;
         }
         $.ajax({
@@ -460,6 +461,8 @@ $(function () {
                         'select_order_by_date': $('#checkbox_select_order_by_date').is(":checked"),
                         "ip_http": $('#http_content').val(),
                         "wiki_docs": $('#wiki_docs_content').val(),
+                        'select_stale': $('#checkbox_select_stale').is(":checked"),
+                        "last_seen_before": $('#last_seen_before').val(),
                     });
                 }
             },
@@ -536,6 +539,8 @@ $(function () {
                             strData += '://' + row['ipf'] + ':' + port + '" target="_blank">' + port + '</a>';
                             // 端口状态
                             if (status !== port) strData += "[" + status;
+                            // 连续多次扫描未发现的端口
+                            if (row['stale_port'] && row['stale_port'].indexOf(parseInt(port)) >= 0) strData += '<span class="badge badge-secondary">stale</span>';
 
                             pre_link = ",";
                         }
@@ -754,6 +759,8 @@ function get_export_options() {
     url += '&create_date_delta=' + encodeURI($('#create_date_delta').val());
     url += '&ip_http=' + encodeURI($('#http_content').val());
     url += '&select_order_by_date=' + encodeURI($('#checkbox_select_order_by_date').is(":checked"));
+    url += '&select_stale=' + encodeURI($('#checkbox_select_stale').is(":checked"));
+    url += '&last_seen_before=' + encodeURI($('#last_seen_before').val());
 
     return url;
 }
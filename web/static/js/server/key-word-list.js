@@ -65,6 +65,7 @@ $(function () {
                     width: "8%",
                     "render": function (data, type, row, meta) {
                         let strButton = "<a class=\"btn btn-sm btn-primary\" href=javascript:edit_key_word(\"" + row["id"] + "\") role=\"button\" title=\"Edit\"><i class=\"fa fa-edit\"></i></a>";
+                        strButton += "&nbsp;<a class=\"btn btn-sm btn-info\" href=javascript:show_key_word_history(\"" + row["id"] + "\") role=\"button\" title=\"History\"><i class=\"fa fa-history\"></i></a>";
                         strButton += "&nbsp;<a class=\"btn btn-sm btn-danger\" href=javascript:delete_key_word(\"" + row["id"] + "\") role=\"button\" title=\"Delete\"><i class=\"fa fa-trash\"></i></a>";
                         return strButton;
                     }
@@ -194,14 +195,15 @@ $("#start_xscan_task").click(function () {
     formData.append("nucleipoc", $('#checkbox_nucleipoc_xscan').is(":checked"));
     formData.append("nucleipocfile", $('#input_nuclei_poc_file_xscan').val());
     formData.append("gobypoc", $('#checkbox_gobypoc_xscan').is(":checked"));
+    formData.append("dirscan", $('#checkbox_dirscan_xscan').is(":checked"));
 
     formData.append("taskcron", $('#checkbox_cron_task_xscan').is(":checked"));
     formData.append("cronrule", cron_rule);
     formData.append("croncomment", $('#input_cron_comment_xscan').val());
     formData.append("proxy", $('#checkbox_proxy_xscan').is(":checked"));
 
-    if ((formData.get("nucleipoc") === "true" || formData.get("gobypoc") === "true" || formData.get("xraypoc") === "true") && formData.get("fingerprint") === "false") {
-        swal('Warning', '漏洞扫描需要开启指纹扫描步骤选项', 'error');
+    if ((formData.get("nucleipoc") === "true" || formData.get("gobypoc") === "true" || formData.get("xraypoc") === "true" || formData.get("dirscan") === "true") && formData.get("fingerprint") === "false") {
+        swal('Warning', '漏洞扫描及目录扫描需要开启指纹扫描步骤选项', 'error');
         return;
     }
     $.ajax({
@@ -254,6 +256,11 @@ $("#start_add_key_search_word").click(function () {
     formData.append("fofa", $('#checkbox_fofasearch').is(":checked"));
     formData.append("hunter", $('#checkbox_huntersearch').is(":checked"));
     formData.append("quake", $('#checkbox_quakesearch').is(":checked"));
+    formData.append("shodan", $('#checkbox_shodansearch').is(":checked"));
+    formData.append("censys", $('#checkbox_censyssearch').is(":checked"));
+    formData.append("zoomeye", $('#checkbox_zoomeyesearch').is(":checked"));
+    formData.append("netlas", $('#checkbox_netlassearch').is(":checked"));
+    formData.append("all", $('#checkbox_allsearch').is(":checked"));
 
     $.ajax({
         url: url,
@@ -303,6 +310,32 @@ function edit_key_word(id) {
                     $('#checkbox_fofasearch').prop("checked", data['fofa']);
                     $('#checkbox_huntersearch').prop("checked", data['hunter']);
                     $('#checkbox_quakesearch').prop("checked", data['quake']);
+                    $('#checkbox_shodansearch').prop("checked", data['shodan']);
+                    $('#checkbox_censyssearch').prop("checked", data['censys']);
+                    $('#checkbox_zoomeyesearch').prop("checked", data['zoomeye']);
+                    $('#checkbox_netlassearch').prop("checked", data['netlas']);
+                    $('#checkbox_allsearch').prop("checked", data['all']);
+                }
+            }
+        });
+}
+
+function show_key_word_history(id) {
+    $('#key_word_history_table tbody').empty();
+    $('#key_word_history').modal('toggle');
+    $.post("/key-word-history",
+        {
+            "id": id,
+        }, function (data, e) {
+            if (e === "success") {
+                if (data.length === 0) {
+                    $('#key_word_history_table tbody').append('<tr><td colspan="5">暂无检索历史</td></tr>');
+                    return;
+                }
+                for (let i = 0; i < data.length; i++) {
+                    let newAsset = data[i]["is_baseline"] ? "首次检索（基线）" : data[i]["new_asset"].replace(/\n/g, "<br>");
+                    $('#key_word_history_table tbody').append('<tr><td>' + data[i]["create_datetime"] + '</td><td>' + data[i]["engine"] + '</td><td>' + data[i]["total"] + '</td><td>' + data[i]["new_count"] + '</td>'
+                        + '<td><div style="max-height:150px;overflow-y:auto;word-break:break-all;">' + newAsset + '</div></td></tr>');
                 }
             }
         });
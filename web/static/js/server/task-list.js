@@ -54,7 +54,7 @@ $(function () {
                 {
                     data: "state", title: "状态", width: "8%",
                     "render": function (data, type, row) {
-                        if (row["tasktype"] === "RunTask" && data === 'CREATED') {
+                        if (row["tasktype"] === "RunTask" && (data === 'CREATED' || data === 'RETRY')) {
                             let strData;
                             strData = data;
                             strData += '<button class="btn btn-sm btn-danger" type="button" onclick="stop_task(\'' + row['task_id'] + '\')" >&nbsp;中止&nbsp;</button>';
@@ -36,7 +36,7 @@ $(function () {
                 'user_role': user_role,
                 'user_password': user_password,
             }, function (data, e) {
-                if (e === "success") {
+                if (e === "success" && data['status'] === 'success') {
                     swal({
                             title: "添加用户成功",
                             text: "",
@@ -50,7 +50,7 @@ $(function () {
                             location.href = "/user-list"
                         });
                 } else {
-                    swal('Warning', "添加用户失败!", 'error');
+                    swal('Warning', "添加用户失败!" + data['msg'], 'error');
                 }
             });
 
@@ -37,6 +37,8 @@ $(function () {
                     "render": function (data, type, row, meta) {
                         if (data === "disable") {
                             return '<span class="badge badge-secondary">Disable</span>';
+                        } else if (row.locked) {
+                            return '<span class="badge badge-warning" title="连续登录失败已锁定，重置密码可解除锁定">Locked</span>';
                         } else {
                             return '<span class="badge badge-success">Enable</span>';
                         }
@@ -124,7 +126,7 @@ $(function () {
                 'id': user_id,
                 'user_password': user_password1,
             }, function (data, e) {
-                if (e === "success") {
+                if (e === "success" && data['status'] === 'success') {
                     swal({
                             title: "重置密码成功！",
                             text: "",
@@ -138,7 +140,7 @@ $(function () {
                             $('#resetpassword').modal('hide');
                         });
                 } else {
-                    swal('Warning', "重置密码失败!", 'error');
+                    swal('Warning', "重置密码失败!" + data['msg'], 'error');
                 }
             });
     });
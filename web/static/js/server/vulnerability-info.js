@@ -0,0 +1,45 @@
+$(function () {
+    $("#button_update_status").click(function () {
+        if ($('#select_status').val() === null || $('#select_status').val() === '') {
+            swal('Warning', "请选择变更的状态", 'error');
+            return;
+        }
+        post_vulnerability("/vulnerability-status", {
+            "id": $('#hidden_vul_id').val(),
+            "status": $('#select_status').val(),
+            "comment": $('#text_status_comment').val(),
+        });
+    });
+    $("#button_update_vul").click(function () {
+        post_vulnerability("/vulnerability-update", {
+            "id": $('#hidden_vul_id').val(),
+            "severity": $('#select_severity').val(),
+            "assignee": $('#text_assignee').val(),
+        });
+    });
+    $("#button_add_comment").click(function () {
+        if ($('#text_comment').val().trim() === '') {
+            swal('Warning', "请输入评论内容", 'error');
+            return;
+        }
+        post_vulnerability("/vulnerability-comment", {
+            "id": $('#hidden_vul_id').val(),
+            "comment": $('#text_comment').val(),
+        });
+    });
+});
+
+/**
+ * 提交漏洞的处理，成功后刷新页面
+ * @param url
+ * @param data
+ */
+function post_vulnerability(url, data) {
+    $.post(url, data, function (data, e) {
+        if (e === "success" && data['status'] === 'success') {
+            location.reload();
+        } else {
+            swal('Warning', data['msg'], 'error');
+        }
+    });
+}
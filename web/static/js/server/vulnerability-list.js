@@ -18,6 +18,10 @@ $(function () {
                         "vul_source": $('#vul_source').val(),
                         "vul_target": $('#vul_target').val(),
                         "vul_poc_file": $('#vul_poc_file').val(),
+                        "vul_status": $('#vul_status').val(),
+                        "vul_severity": $('#vul_severity').val(),
+                        "vul_assignee": $('#vul_assignee').val(),
+                        "vul_cve": $('#vul_cve').val(),
                         "date_delta": $('#date_delta').val()
                     });
                 }
@@ -53,16 +57,51 @@ $(function () {
                     data: "url", title: "URL", width: "15%"
                 },
                 {
-                    data: 'poc_file', title: 'Poc文件', width: '25%',
+                    data: 'poc_file', title: '漏洞', width: '20%',
                     render: function (data, type, row, meta) {
-                        var strData;
-                        strData = '<a href="/vulnerability-info?id=' + row['id'] + '" target="_blank">' + data + '</a>';
+                        let strData;
+                        const vulName = row['vul_name'] ? row['vul_name'] : data;
+                        strData = '<a href="/vulnerability-info?id=' + row['id'] + '" target="_blank">' + vulName + '</a>';
+                        if (row['cve']) strData += '<br><span class="badge badge-pill badge-secondary">' + row['cve'].split(',').join('</span>&nbsp;<span class="badge badge-pill badge-secondary">') + '</span>';
                         return strData;
                     }
                 },
-                {data: 'source', title: '验证工具', width: '8%'},
                 {
-                    data: 'update_datetime', title: '更新时间', width: '15%'
+                    data: 'engines', title: '验证工具', width: '6%',
+                    render: function (data, type, row, meta) {
+                        return (data ? data : row['source']).split(',').join('<br>');
+                    }
+                },
+                {
+                    data: 'severity', title: '严重程度', width: '6%',
+                    render: function (data, type, row, meta) {
+                        const severityClass = {
+                            "critical": "badge-danger",
+                            "high": "badge-warning",
+                            "medium": "badge-info",
+                            "low": "badge-secondary",
+                            "info": "badge-light"
+                        };
+                        if (!data) return '';
+                        return '<span class="badge ' + severityClass[data] + '">' + data + '</span>';
+                    }
+                },
+                {
+                    data: 'status', title: '状态', width: '6%',
+                    render: function (data, type, row, meta) {
+                        const statusClass = {
+                            "new": "badge-primary",
+                            "confirmed": "badge-danger",
+                            "false-positive": "badge-secondary",
+                            "fixed": "badge-success",
+                            "risk-accepted": "badge-warning"
+                        };
+                        return '<span class="badge ' + statusClass[data] + '">' + data + '</span>';
+                    }
+                },
+                {data: 'assignee', title: '处理人', width: '6%'},
+                {
+                    data: 'update_datetime', title: '更新时间', width: '10%'
                 },
                 {
                     title: "操作",
@@ -0,0 +1,190 @@
+$(function () {
+    $('#workflow_table').DataTable(
+        {
+            "paging": true,
+            "serverSide": true,
+            "autowidth": false,
+            "sort": false,
+            "pagingType": "full_numbers",//分页样式
+            'iDisplayLength': 50,
+            "dom": '<i><t><"bottom"lp>',
+            "ajax": {
+                "url": "/workflow-list",
+                "type": "post",
+            },
+            columns: [
+                {data: "index", title: "序号", width: "5%"},
+                {data: "name", title: "工作流名称", width: "12%"},
+                {data: "description", title: "描述", width: "20%"},
+                {
+                    data: "stages", title: "阶段", width: "35%",
+                    "render": function (data, type, row) {
+                        return '<div style="width:100%;white-space:normal;word-wrap:break-word;word-break:break-all;">' + data + '</div>'
+                    }
+                },
+                {data: "sort_order", title: "排序号", width: "5%"},
+                {data: "update_time", title: "更新时间", width: "12%"},
+                {
+                    title: "操作",
+                    "render": function (data, type, row, meta) {
+                        let strButton = "<a class=\"btn btn-sm btn-success\" href=javascript:start_workflow(\"" + row["id"] + "\") role=\"button\" title=\"Start\"><i class=\"fa fa-play\"></i></a>";
+                        strButton += "&nbsp;<a class=\"btn btn-sm btn-primary\" href=javascript:edit_workflow(\"" + row["id"] + "\") role=\"button\" title=\"Edit\"><i class=\"fa fa-edit\"></i></a>";
+                        strButton += "&nbsp;<a class=\"btn btn-sm btn-danger\" href=javascript:delete_workflow(\"" + row["id"] + "\") role=\"button\" title=\"Delete\"><i class=\"fa fa-trash\"></i></a>";
+                        return strButton;
+                    }
+                }
+            ]
+        }
+    );//end datatable
+    load_org_list();
+    $("#checkbox_cron_task_workflow").click(function () {
+        $("#input_cron_rule_workflow").prop("disabled", !this.checked);
+        $("#input_cron_comment_workflow").prop("disabled", !this.checked);
+    });
+});
+
+//新建工作流窗口
+$("#create_workflow").click(function () {
+    $('#edit_workflow').modal('toggle');
+    $('#workflowActionType').html("新建工作流");
+    $('#workflow_id').val("0");
+    $('#workflow_name').val("");
+    $('#workflow_description').val("");
+    $('#workflow_sort_order').val("100");
+    $('#workflow_definition').val("stages:\n  - name: portscan\n    task: portscan\n  - name: fingerprint\n    task: fingerprint\n    depends: [portscan]\n  - name: nuclei\n    task: nuclei\n    depends: [fingerprint]\n");
+});
+
+$("#workflow_save").click(function () {
+    if (!$('#workflow_name').val()) {
+        swal('Warning', '工作流名称不能为空', 'error');
+        return;
+    }
+    let url = "/workflow-add";
+    if ($('#workflow_id').val() !== "0") {
+        url = "/workflow-update";
+    }
+    $.post(url,
+        {
+            "id": $('#workflow_id').val(),
+            "name": $('#workflow_name').val(),
+            "description": $('#workflow_description').val(),
+            "sort_order": $('#workflow_sort_order').val(),
+            "definition": $('#workflow_definition').val(),
+        }, function (res, e) {
+            if (e === "success" && res['status'] === "success") {
+                swal({
+                        title: "保存成功！",
+                        text: res['msg'],
+                        type: "success",
+                        confirmButtonText: "确定",
+                        confirmButtonColor: "#41b883",
+                        closeOnConfirm: true,
+                    },
+                    function () {
+                        $('#edit_workflow').modal('hide');
+                        $('#workflow_table').DataTable().draw(false);
+                    });
+            } else {
+                swal('Warning', '保存失败！' + res['msg'], 'error');
+            }
+        });
+});
+
+$("#start_workflow_task").click(function () {
+    const target = $.trim($('#text_target_workflow').val());
+    if (!target) {
+        swal('Warning', '请输入目标', 'error');
+        return;
+    }
+    let cron_rule = "";
+    if ($('#checkbox_cron_task_workflow').is(":checked")) {
+        cron_rule = $('#input_cron_rule_workflow').val();
+        if (!cron_rule) {
+            swal('Warning', '请输入定时任务规则', 'error');
+            return;
+        }
+    }
+    $.post("/task-start-workflow",
+        {
+            "workflow_id": $('#start_workflow_id').val(),
+            "target": target,
+            "port": $('#input_port_workflow').val(),
+            "org_id": $('#select_org_id_task').val(),
+            "proxy": $('#checkbox_proxy_workflow').is(":checked"),
+            "taskcron": $('#checkbox_cron_task_workflow').is(":checked"),
+            "cronrule": cron_rule,
+            "croncomment": $('#input_cron_comment_workflow').val(),
+        }, function (res, e) {
+            if (e === "success" && res['status'] === "success") {
+                swal({
+                        title: "新建任务成功！",
+                        text: res['msg'],
+                        type: "success",
+                        confirmButtonText: "确定",
+                        confirmButtonColor: "#41b883",
+                        closeOnConfirm: true,
+                    },
+                    function () {
+                        $('#start_workflow').modal('hide');
+                    });
+            } else {
+                swal('Warning', '新建任务失败！' + res['msg'], 'error');
+            }
+        });
+});
+
+function start_workflow(id) {
+    $('#start_workflow').modal('toggle');
+    $('#start_workflow_id').val(id);
+}
+
+function edit_workflow(id) {
+    $('#edit_workflow').modal('toggle');
+    $('#workflowActionType').html("编辑工作流");
+    $.post("/workflow-get",
+        {
+            "id": id,
+        }, function (data, e) {
+            if (e === "success") {
+                $('#workflow_id').val(data["id"]);
+                $('#workflow_name').val(data["name"]);
+                $('#workflow_description').val(data["description"]);
+                $('#workflow_sort_order').val(data["sort_order"]);
+                $('#workflow_definition').val(data["definition"]);
+            }
+        });
+}
+
+function delete_workflow(id) {
+    swal({
+            title: "确定要删除?",
+            text: "该操作会删除当前工作流，请确认！",
+            type: "warning",
+            showCancelButton: true,
+            confirmButtonColor: "#DD6B55",
+            confirmButtonText: "确认删除",
+            cancelButtonText: "取消",
+            closeOnConfirm: true
+        },
+        function () {
+            $.post("/workflow-delete",
+                {
+                    "id": id,
+                }, function (data, e) {
+                    if (e === "success") {
+                        $('#workflow_table').DataTable().draw(false);
+                    }
+                });
+        });
+}
+
+function load_org_list() {
+    $("#select_org_id_task").append("<option value=''>--无--</option>")
+    $.post("/org-getall", {}, function (data, e) {
+        if (e === "success") {
+            for (let i = 0; i < data.length; i++) {
+                $("#select_org_id_task").append("<option value='" + data[i].id + "'>" + data[i].name + "</option>")
+            }
+        }
+    });
+}